@@ -0,0 +1,182 @@
+// Package cli runs the explorer operations from the command line, without the GUI.
+// The commands publish the same domain events as the GUI on the in-memory bus,
+// so that they are handled the same way: same recovery markers, same read-only enforcement.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+var ErrUsage = errors.New("invalid usage")
+
+type command struct {
+	usage       string
+	description string
+	// needsSession is false for the commands that don't access a bucket
+	needsSession bool
+	run          func(ctx context.Context, c *CLI, s *session, args []string) error
+}
+
+var commands = map[string]command{
+	"ls": {
+		usage:        "ls [PATH]",
+		description:  "List the content of a directory",
+		needsSession: true,
+		run:          runLs,
+	},
+	"cp": {
+		usage:        "cp [-r] [-n] SRC DST",
+		description:  "Copy files between the local disk and the bucket, or inside the bucket. Remote paths are prefixed with s3:",
+		needsSession: true,
+		run:          runCp,
+	},
+	"mv": {
+		usage:        "mv [-n] SRC DST",
+		description:  "Move files or directories into another directory of the bucket",
+		needsSession: true,
+		run:          runMv,
+	},
+	"rm": {
+		usage:        "rm [-r] PATH...",
+		description:  "Delete files, or directories with their content when -r is set",
+		needsSession: true,
+		run:          runRm,
+	},
+	"cat": {
+		usage:        "cat PATH",
+		description:  "Print the content of a file",
+		needsSession: true,
+		run:          runCat,
+	},
+	"stat": {
+		usage:        "stat PATH",
+		description:  "Show the metadata of a file or the size statistics of a directory",
+		needsSession: true,
+		run:          runStat,
+	},
+	"connections": {
+		usage:       "connections list | export [-no-secrets|-encrypt] [FILE] | import [-from json|aws|rclone] [-merge] [-dry-run] [FILE]",
+		description: "Manage the saved connections. The encrypted exports use the passphrase of " + exportPassphraseEnv,
+		run:         runConnections,
+	},
+}
+
+// CLI parses the command line and runs the matching command against the saved connections.
+type CLI struct {
+	bus         event.Bus
+	connections connection_deck.Repository
+	stdout      io.Writer
+	format      outputFormat
+}
+
+func New(bus event.Bus, connections connection_deck.Repository, stdout io.Writer) *CLI {
+	return &CLI{
+		bus:         bus,
+		connections: connections,
+		stdout:      stdout,
+		format:      formatTable,
+	}
+}
+
+// Run runs the command given by args, without the program name.
+// ErrUsage is returned when the command line is invalid.
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("s3box", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	connName := fs.String("c", "", "name or ID of the connection, the selected one by default")
+	format := fs.String("o", string(formatTable), "output format: table or json")
+	if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+		Usage(c.stdout)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("%w: %s", ErrUsage, err)
+	}
+
+	var err error
+	if c.format, err = parseOutputFormat(*format); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: no command given", ErrUsage)
+	}
+	if fs.Arg(0) == "help" {
+		Usage(c.stdout)
+		return nil
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", ErrUsage, fs.Arg(0))
+	}
+
+	var s *session
+	if cmd.needsSession {
+		conn, err := c.connection(ctx, *connName)
+		if err != nil {
+			return err
+		}
+		s = &session{bus: c.bus, conn: conn}
+	}
+	return cmd.run(ctx, c, s, fs.Args()[1:])
+}
+
+// connection returns the connection with the given name or ID, the selected one when it is empty.
+func (c *CLI) connection(ctx context.Context, nameOrID string) (*connection_deck.Connection, error) {
+	deck, err := c.connections.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if nameOrID == "" {
+		if conn := deck.SelectedConnection(); conn != nil {
+			return conn, nil
+		}
+		return nil, fmt.Errorf("%w: no connection selected, use -c to choose one", ErrUsage)
+	}
+
+	for _, conn := range deck.Get() {
+		if conn.Name() == nameOrID || conn.ID().String() == nameOrID {
+			return conn, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: connection %s", connection_deck.ErrNotFound, nameOrID)
+}
+
+func (c *CLI) print(t table) error {
+	return t.print(c.stdout, c.format)
+}
+
+// Usage writes the help of the command line.
+func Usage(w io.Writer) {
+	var sb strings.Builder
+	sb.WriteString("Usage: s3box [-c CONNECTION] [-o table|json] COMMAND [ARGS]\n\n")
+	sb.WriteString("Without a command, the graphical interface is started.\n")
+	sb.WriteString("The credentials protected by a passphrase are unlocked with " + PassphraseEnv + ".\n\nCommands:\n")
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		cmd := commands[name]
+		u.SkipV(fmt.Fprintf(&sb, "  %s\n      %s\n", cmd.usage, cmd.description))
+	}
+	u.SkipV(io.WriteString(w, sb.String()))
+}
+
+// parseCommandFlags parses the flags of a command, declared by the declare function.
+func parseCommandFlags(name string, args []string, declare func(fs *flag.FlagSet)) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	declare(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUsage, err)
+	}
+	return fs.Args(), nil
+}
@@ -0,0 +1,239 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/it-happened/inmemory"
+	"github.com/thomas-marquis/s3-box/internal/cli"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	mocks_connection_deck "github.com/thomas-marquis/s3-box/mocks/connection_deck"
+	"go.uber.org/mock/gomock"
+)
+
+var fakeLastModified = time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)
+
+type cliFixture struct {
+	ctx    context.Context
+	bus    event.Bus
+	repo   *mocks_connection_deck.MockRepository
+	deck   *connection_deck.Deck
+	stdout *bytes.Buffer
+}
+
+func setupCLI(t *testing.T) *cliFixture {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	f := &cliFixture{
+		ctx:    ctx,
+		bus:    inmemory.NewBus(ctx),
+		repo:   mocks_connection_deck.NewMockRepository(ctrl),
+		deck:   connection_deck.New(),
+		stdout: &bytes.Buffer{},
+	}
+	f.repo.EXPECT().Get(gomock.Any()).Return(f.deck, nil).AnyTimes()
+	return f
+}
+
+// respondToLoad answers the directory loadings with a "docs" subdirectory and a "report.csv" file in each directory.
+func (f *cliFixture) respondToLoad(t *testing.T) {
+	t.Helper()
+	sub := f.bus.Subscribe().
+		On(event.Is(directory.LoadTriggeredType), func(e event.Event) {
+			dir := e.Payload().(directory.LoadTriggered).Directory
+			file, err := directory.NewFile("report.csv", dir,
+				directory.WithFileSize(2048), directory.WithFileLastModified(fakeLastModified))
+			require.NoError(t, err)
+			subDir, err := directory.New(dir.ConnectionID(), "docs", dir)
+			require.NoError(t, err)
+			f.bus.Publish(e.NewFollowup(directory.LoadSucceeded{
+				Directory:      dir,
+				Files:          []*directory.File{file},
+				SubDirectories: []*directory.Directory{subDir},
+			}))
+		})
+	sub.Listen()
+	t.Cleanup(sub.Detach)
+}
+
+// respondToLoadWithError fails the directory loadings with the given error.
+func (f *cliFixture) respondToLoadWithError(t *testing.T, err error) {
+	t.Helper()
+	sub := f.bus.Subscribe().
+		On(event.Is(directory.LoadTriggeredType), func(e event.Event) {
+			f.bus.Publish(e.NewFollowup(directory.LoadFailed{
+				Err:       err,
+				Directory: e.Payload().(directory.LoadTriggered).Directory,
+			}))
+		})
+	sub.Listen()
+	t.Cleanup(sub.Detach)
+}
+
+func (f *cliFixture) newConnection(name string) *connection_deck.Connection {
+	return f.deck.New(name, "AZERTY", "dfhdh2432J4bbhjkb", "test-bucket",
+		connection_deck.AsS3Like("localhost:4566", false)).
+		Payload().(connection_deck.CreateConnectionTriggered).Connection()
+}
+
+func TestCLI_Run(t *testing.T) {
+	t.Run("should list the directory content of the selected connection as a table", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		f.respondToLoad(t)
+		conn := f.newConnection("local")
+		_, err := f.deck.Select(conn.ID())
+		require.NoError(t, err)
+
+		// When
+		err = cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"ls", "/data"})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "TYPE  SIZE    LAST MODIFIED        NAME\n"+
+			"dir                                docs/\n"+
+			"file  2.0 kB  2025-03-14 10:30:00  report.csv\n", f.stdout.String())
+	})
+
+	t.Run("should list the directory content of the given connection as JSON", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		f.respondToLoad(t)
+		f.newConnection("local")
+		f.newConnection("remote")
+
+		// When
+		err := cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"-c", "remote", "-o", "json", "ls"})
+
+		// Then
+		require.NoError(t, err)
+		var res []map[string]any
+		require.NoError(t, json.Unmarshal(f.stdout.Bytes(), &res))
+		require.Len(t, res, 2)
+		assert.Equal(t, "directory", res[0]["type"])
+		assert.Equal(t, "/docs/", res[0]["path"])
+		assert.Equal(t, "file", res[1]["type"])
+		assert.Equal(t, "/report.csv", res[1]["path"])
+		assert.InDelta(t, 2048, res[1]["sizeBytes"], 0)
+	})
+
+	t.Run("should return the error of a failed loading", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		loadErr := errors.New("access denied")
+		f.respondToLoadWithError(t, loadErr)
+		conn := f.newConnection("local")
+		_, err := f.deck.Select(conn.ID())
+		require.NoError(t, err)
+
+		// When
+		err = cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"ls", "/data"})
+
+		// Then
+		assert.ErrorIs(t, err, loadErr)
+		assert.NoError(t, f.ctx.Err())
+	})
+
+	t.Run("should return a usage error when no connection is selected", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		f.newConnection("local")
+
+		// When
+		err := cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"ls"})
+
+		// Then
+		assert.ErrorIs(t, err, cli.ErrUsage)
+	})
+
+	t.Run("should return ErrNotFound when the connection doesn't exist", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		f.newConnection("local")
+
+		// When
+		err := cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"-c", "unknown", "ls"})
+
+		// Then
+		assert.ErrorIs(t, err, connection_deck.ErrNotFound)
+	})
+
+	t.Run("should return a usage error for an unknown command", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+
+		// When
+		err := cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"touch", "/file.txt"})
+
+		// Then
+		assert.ErrorIs(t, err, cli.ErrUsage)
+	})
+
+	t.Run("should refuse to delete a directory without -r", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		f.respondToLoad(t)
+		conn := f.newConnection("local")
+
+		// When
+		err := cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"-c", conn.ID().String(), "rm", "/docs"})
+
+		// Then
+		assert.ErrorIs(t, err, cli.ErrUsage)
+	})
+
+	t.Run("should list the connections without their credentials", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		conn := f.newConnection("local")
+		f.newConnection("remote")
+		_, err := f.deck.Select(conn.ID())
+		require.NoError(t, err)
+
+		// When
+		err = cli.New(f.bus, f.repo, f.stdout).Run(f.ctx, []string{"connections", "list"})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "NAME    BUCKET       PROVIDER  ENDPOINT        READ-ONLY  SELECTED\n"+
+			"local   test-bucket  s3-like   localhost:4566  false      *\n"+
+			"remote  test-bucket  s3-like   localhost:4566  false      \n", f.stdout.String())
+		assert.NotContains(t, f.stdout.String(), "dfhdh2432J4bbhjkb")
+	})
+
+	t.Run("should print the connections to import without saving them in dry run", func(t *testing.T) {
+		// Given
+		f := setupCLI(t)
+		f.newConnection("local")
+		imported := connection_deck.New()
+		imported.New("local", "AZERTY", "dfhdh2432J4bbhjkb", "other-bucket",
+			connection_deck.AsS3Like("localhost:4566", false))
+		imported.New("aws", "AKIA", "secret", "", connection_deck.AsAWS("eu-west-3"))
+		f.repo.EXPECT().
+			Import(gomock.Any(), nil, connection_deck.ImportOptions{Source: connection_deck.ImportFromAWSProfiles}).
+			Return(imported.Get(), nil).
+			Times(1)
+
+		// When
+		err := cli.New(f.bus, f.repo, f.stdout).
+			Run(f.ctx, []string{"connections", "import", "-from", "aws", "-dry-run"})
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "NAME   BUCKET        PROVIDER  ENDPOINT        ACTION\n"+
+			"local  other-bucket  s3-like   localhost:4566  Skip\n"+
+			"aws                  aws       eu-west-3       Add\n", f.stdout.String())
+		assert.Len(t, f.deck.Get(), 1)
+	})
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+// connectionView is the printed connection. It never carries the credentials.
+type connectionView struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Bucket   string `json:"bucket"`
+	Provider string `json:"provider"`
+	Endpoint string `json:"endpoint"`
+	ReadOnly bool   `json:"readOnly"`
+	Selected bool   `json:"selected"`
+}
+
+func newConnectionView(conn, selected *connection_deck.Connection) connectionView {
+	endpoint := conn.Server()
+	if conn.Provider() == connection_deck.AWSProvider {
+		endpoint = conn.Region()
+	}
+	return connectionView{
+		ID:       conn.ID().String(),
+		Name:     conn.Name(),
+		Bucket:   conn.Bucket(),
+		Provider: conn.Provider().String(),
+		Endpoint: endpoint,
+		ReadOnly: conn.ReadOnly(),
+		Selected: selected != nil && selected.Is(conn),
+	}
+}
+
+func runConnections(ctx context.Context, c *CLI, _ *session, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: connections takes a sub command: list, export or import", ErrUsage)
+	}
+	switch sub, args := args[0], args[1:]; sub {
+	case "list":
+		return listConnections(ctx, c)
+	case "export":
+		return exportConnections(ctx, c, args)
+	case "import":
+		return importConnections(ctx, c, args)
+	default:
+		return fmt.Errorf("%w: unknown connections sub command %q", ErrUsage, sub)
+	}
+}
+
+func listConnections(ctx context.Context, c *CLI) error {
+	deck, err := c.connections.Get(ctx)
+	if err != nil {
+		return err
+	}
+	selected := deck.SelectedConnection()
+
+	views := make([]connectionView, 0, len(deck.Get()))
+	for _, conn := range deck.Get() {
+		views = append(views, newConnectionView(conn, selected))
+	}
+
+	t := table{headers: []string{"NAME", "BUCKET", "PROVIDER", "ENDPOINT", "READ-ONLY", "SELECTED"}, value: views}
+	for _, v := range views {
+		sel := ""
+		if v.Selected {
+			sel = "*"
+		}
+		t.rows = append(t.rows, []string{v.Name, v.Bucket, v.Provider, v.Endpoint, strconv.FormatBool(v.ReadOnly), sel})
+	}
+	return c.print(t)
+}
+
+// PassphraseEnv is the environment variable holding the master passphrase of the protected credentials
+const PassphraseEnv = "S3BOX_PASSPHRASE"
+
+// exportPassphraseEnv is the environment variable holding the passphrase of the encrypted exports
+const exportPassphraseEnv = "S3BOX_EXPORT_PASSPHRASE"
+
+// exportConnections writes the saved connections to the file or to the standard output.
+// The credentials are written in clear unless -no-secrets or -encrypt is set.
+func exportConnections(ctx context.Context, c *CLI, args []string) error {
+	var noSecrets, encrypt bool
+	args, err := parseCommandFlags("connections export", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&noSecrets, "no-secrets", false, "leave the credentials out of the export")
+		fs.BoolVar(&encrypt, "encrypt", false, "encrypt the export with the passphrase of "+exportPassphraseEnv)
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: connections export takes at most one file", ErrUsage)
+	}
+
+	var options connection_deck.ExportOptions
+	switch {
+	case noSecrets && encrypt:
+		return fmt.Errorf("%w: -no-secrets and -encrypt can't be set together", ErrUsage)
+	case noSecrets:
+		options.Mode = connection_deck.ExportWithoutSecrets
+	case encrypt:
+		options.Mode = connection_deck.ExportEncrypted
+		if options.Passphrase = os.Getenv(exportPassphraseEnv); options.Passphrase == "" {
+			return fmt.Errorf("%w: %s must be set to encrypt the export", ErrUsage, exportPassphraseEnv)
+		}
+	}
+
+	if len(args) == 0 {
+		return c.connections.Export(ctx, c.stdout, options)
+	}
+
+	f, err := os.OpenFile(args[0], os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer u.SkipD(f.Close)
+	return c.connections.Export(ctx, f, options)
+}
+
+type importEntryView struct {
+	connectionView
+	Action string `json:"action"`
+}
+
+// importConnections adds the connections of a file to the deck, after printing what is imported.
+// The connections already saved, with the same ID or name, are updated only with -merge.
+func importConnections(ctx context.Context, c *CLI, args []string) error {
+	var (
+		source        string
+		merge, dryRun bool
+	)
+	args, err := parseCommandFlags("connections import", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&source, "from", connection_deck.ImportFromJSON.String(), "format of the file: json, aws or rclone")
+		fs.BoolVar(&merge, "merge", false, "update the existing connections with the imported settings")
+		fs.BoolVar(&dryRun, "dry-run", false, "only print what would be imported")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%w: connections import takes at most one file", ErrUsage)
+	}
+
+	// without a file, the connections are read from the default location of the format
+	var file io.Reader
+	if len(args) == 1 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer u.SkipD(f.Close)
+		file = f
+	}
+
+	conns, err := c.connections.Import(ctx, file, connection_deck.ImportOptions{
+		Source:     connection_deck.ImportSource(source),
+		Passphrase: os.Getenv(exportPassphraseEnv),
+	})
+	if err != nil {
+		return err
+	}
+	deck, err := c.connections.Get(ctx)
+	if err != nil {
+		return err
+	}
+	preview := deck.PrepareImport(conns)
+
+	views := make([]importEntryView, 0, len(preview.Entries()))
+	t := table{headers: []string{"NAME", "BUCKET", "PROVIDER", "ENDPOINT", "ACTION"}}
+	for _, e := range preview.Entries() {
+		action := e.Action
+		if action == connection_deck.ImportMerge && !merge {
+			action = connection_deck.ImportSkip
+		}
+		v := importEntryView{connectionView: newConnectionView(e.Connection, nil), Action: action.String()}
+		views = append(views, v)
+		t.rows = append(t.rows, []string{v.Name, v.Bucket, v.Provider, v.Endpoint, v.Action})
+	}
+	t.value = views
+
+	if !dryRun {
+		s := &session{bus: c.bus}
+		followups, err := s.requestAll(ctx, preview.Apply(merge),
+			connection_deck.CreateConnectionSucceededType, connection_deck.CreateConnectionFailedType,
+			connection_deck.UpdateConnectionSucceededType, connection_deck.UpdateConnectionFailedType)
+		if err != nil {
+			return err
+		}
+		if err := joinPayloadErrs(followups); err != nil {
+			return err
+		}
+	}
+	return c.print(t)
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+type entryView struct {
+	Type         string     `json:"type"`
+	Name         string     `json:"name"`
+	Path         string     `json:"path"`
+	SizeBytes    uint64     `json:"sizeBytes,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+}
+
+func runLs(ctx context.Context, c *CLI, s *session, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: ls takes a single path", ErrUsage)
+	}
+	remotePath := "/"
+	if len(args) == 1 {
+		remotePath = args[0]
+	}
+
+	dir, err := s.loadDirectory(ctx, remotePath)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]entryView, 0, len(dir.SubDirectories())+len(dir.Files()))
+	for _, sd := range dir.SubDirectories() {
+		entries = append(entries, entryView{Type: "directory", Name: sd.Name(), Path: sd.Path().String()})
+	}
+	for _, f := range dir.Files() {
+		lastModified := f.LastModified()
+		entries = append(entries, entryView{
+			Type:         "file",
+			Name:         f.Name().String(),
+			Path:         f.FullPath(),
+			SizeBytes:    f.SizeBytes(),
+			LastModified: &lastModified,
+		})
+	}
+	slices.SortFunc(entries, func(a, b entryView) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	t := table{headers: []string{"TYPE", "SIZE", "LAST MODIFIED", "NAME"}, value: entries}
+	for _, e := range entries {
+		if e.Type == "directory" {
+			t.rows = append(t.rows, []string{"dir", "", "", e.Name + "/"})
+			continue
+		}
+		t.rows = append(t.rows, []string{
+			"file", humanize.Bytes(e.SizeBytes), e.LastModified.Format(time.DateTime), e.Name,
+		})
+	}
+	return c.print(t)
+}
+
+func runCat(ctx context.Context, c *CLI, s *session, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: cat takes a single file path", ErrUsage)
+	}
+	file, _, err := s.resolve(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if file == nil {
+		return fmt.Errorf("%s is a directory", args[0])
+	}
+
+	res, err := s.request(ctx, file.Load(s.conn.ID(), event.WithContext(ctx)),
+		directory.LoadFileSucceededType, directory.LoadFileFailedType)
+	if err != nil {
+		return err
+	}
+	if err := payloadErr(res); err != nil {
+		return err
+	}
+
+	content := res.Payload().(directory.LoadFileSucceeded).Content
+	defer u.SkipD(content.Close)
+	_, err = io.Copy(c.stdout, content)
+	return err
+}
+
+type fileStatView struct {
+	Path               string            `json:"path"`
+	SizeBytes          uint64            `json:"sizeBytes"`
+	LastModified       time.Time         `json:"lastModified"`
+	ContentType        string            `json:"contentType,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	ContentLanguage    string            `json:"contentLanguage,omitempty"`
+	ETag               string            `json:"etag,omitempty"`
+	StorageClass       string            `json:"storageClass,omitempty"`
+	UserMetadata       map[string]string `json:"userMetadata,omitempty"`
+}
+
+type sizeEntryView struct {
+	Bytes       uint64 `json:"bytes"`
+	ObjectCount int    `json:"objectCount"`
+}
+
+type directoryStatView struct {
+	Path           string                   `json:"path"`
+	Total          sizeEntryView            `json:"total"`
+	ByStorageClass map[string]sizeEntryView `json:"byStorageClass"`
+	BySubDirectory map[string]sizeEntryView `json:"bySubDirectory"`
+}
+
+func runStat(ctx context.Context, c *CLI, s *session, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: stat takes a single path", ErrUsage)
+	}
+	file, dir, err := s.resolve(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		return statFile(ctx, c, s, file)
+	}
+	return statDirectory(ctx, c, s, dir)
+}
+
+func statFile(ctx context.Context, c *CLI, s *session, file *directory.File) error {
+	res, err := s.request(ctx, file.LoadMetadata(event.WithContext(ctx)),
+		directory.LoadFileMetadataSucceededType, directory.LoadFileMetadataFailedType)
+	if err != nil {
+		return err
+	}
+	if err := payloadErr(res); err != nil {
+		return err
+	}
+
+	md := res.Payload().(directory.LoadFileMetadataSucceeded).Metadata
+	view := fileStatView{
+		Path:               file.FullPath(),
+		SizeBytes:          file.SizeBytes(),
+		LastModified:       file.LastModified(),
+		ContentType:        md.ContentType,
+		CacheControl:       md.CacheControl,
+		ContentEncoding:    md.ContentEncoding,
+		ContentDisposition: md.ContentDisposition,
+		ContentLanguage:    md.ContentLanguage,
+		ETag:               md.ETag,
+		StorageClass:       md.StorageClass,
+		UserMetadata:       md.UserMetadata,
+	}
+
+	t := table{value: view, rows: [][]string{
+		{"Path", view.Path},
+		{"Size", fmt.Sprintf("%s (%d bytes)", humanize.Bytes(view.SizeBytes), view.SizeBytes)},
+		{"Last modified", view.LastModified.Format(time.DateTime)},
+		{"Content type", view.ContentType},
+		{"Cache control", view.CacheControl},
+		{"Content encoding", view.ContentEncoding},
+		{"Content disposition", view.ContentDisposition},
+		{"Content language", view.ContentLanguage},
+		{"ETag", view.ETag},
+		{"Storage class", view.StorageClass},
+	}}
+	for _, key := range slices.Sorted(maps.Keys(view.UserMetadata)) {
+		t.rows = append(t.rows, []string{"x-amz-meta-" + key, view.UserMetadata[key]})
+	}
+	return c.print(t)
+}
+
+func statDirectory(ctx context.Context, c *CLI, s *session, dir *directory.Directory) error {
+	res, err := s.request(ctx, dir.ComputeSize(event.WithContext(ctx)),
+		directory.ComputeSizeSucceededType, directory.ComputeSizeFailedType)
+	if err != nil {
+		return err
+	}
+	if err := payloadErr(res); err != nil {
+		return err
+	}
+
+	stats := res.Payload().(directory.ComputeSizeSucceeded).Stats
+	view := directoryStatView{
+		Path:           dir.Path().String(),
+		Total:          sizeEntryView(stats.Total),
+		ByStorageClass: make(map[string]sizeEntryView, len(stats.ByStorageClass)),
+		BySubDirectory: make(map[string]sizeEntryView, len(stats.BySubDirectory)),
+	}
+	for class, entry := range stats.ByStorageClass {
+		view.ByStorageClass[class] = sizeEntryView(entry)
+	}
+	for name, entry := range stats.BySubDirectory {
+		view.BySubDirectory[name] = sizeEntryView(entry)
+	}
+
+	t := table{value: view, rows: [][]string{
+		{"Path", view.Path},
+		{"Size", fmt.Sprintf("%s (%d bytes)", humanize.Bytes(view.Total.Bytes), view.Total.Bytes)},
+		{"Objects", strconv.Itoa(view.Total.ObjectCount)},
+	}}
+	for _, class := range stats.StorageClasses() {
+		entry := view.ByStorageClass[class]
+		t.rows = append(t.rows, []string{
+			"Storage class " + class, fmt.Sprintf("%s in %d objects", humanize.Bytes(entry.Bytes), entry.ObjectCount),
+		})
+	}
+	return c.print(t)
+}
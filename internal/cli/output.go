@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+)
+
+func parseOutputFormat(s string) (outputFormat, error) {
+	switch f := outputFormat(s); f {
+	case formatTable, formatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: unknown output format %q, expected table or json", ErrUsage, s)
+	}
+}
+
+// table is the result of a command, printed either as aligned columns or as JSON.
+type table struct {
+	headers []string
+	rows    [][]string
+	// value is what is printed in JSON
+	value any
+}
+
+func (t table) print(w io.Writer, format outputFormat) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t.value)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(t.headers) > 0 {
+		if _, err := fmt.Fprintln(tw, strings.Join(t.headers, "\t")); err != nil {
+			return err
+		}
+	}
+	for _, row := range t.rows {
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
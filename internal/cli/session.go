@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+// remotePrefix marks a path of the bucket in the commands accepting both local and remote paths
+const remotePrefix = "s3:"
+
+// session runs the domain operations of a command on a single connection.
+// The events are handled by the same handlers as in the GUI, on the in-memory bus.
+type session struct {
+	bus  event.Bus
+	conn *connection_deck.Connection
+}
+
+// request publishes the event and waits for its first follow-up of one of the given types.
+func (s *session) request(ctx context.Context, evt event.Event, types ...event.Type) (event.Event, error) {
+	res, err := s.requestAll(ctx, []event.Event{evt}, types...)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// requestAll publishes the events and waits for the first follow-up of each of them,
+// returned in the same order as the events.
+func (s *session) requestAll(ctx context.Context, evts []event.Event, types ...event.Type) ([]event.Event, error) {
+	var (
+		mu      sync.Mutex
+		indexes = make(map[int64]int, len(evts))
+		res     = make([]event.Event, len(evts))
+		pending = len(evts)
+		done    = make(chan struct{})
+	)
+	for i, evt := range evts {
+		indexes[evt.ID()] = i
+	}
+	if pending == 0 {
+		return res, nil
+	}
+
+	sub := s.bus.Subscribe().
+		On(event.IsOneOf(types...), func(e event.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			i, ok := indexes[e.ParentID()]
+			if !ok || res[i] != nil {
+				return
+			}
+			res[i] = e
+			if pending--; pending == 0 {
+				close(done)
+			}
+		})
+	sub.Listen()
+	defer sub.Detach()
+
+	for _, evt := range evts {
+		s.bus.Publish(evt)
+	}
+
+	select {
+	case <-done:
+		return res, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load loads all the pages of the directory content.
+func (s *session) load(ctx context.Context, dir *directory.Directory) error {
+	evt, err := dir.Load()
+	if err != nil {
+		return err
+	}
+	res, err := s.request(ctx, evt, directory.LoadSucceededType, directory.LoadFailedType)
+	if err != nil {
+		return err
+	}
+	if err := dir.Notify(res); err != nil {
+		return err
+	}
+	if pl, ok := res.Payload().(directory.LoadFailed); ok {
+		return pl.Err
+	}
+
+	for dir.HasMorePages() {
+		evt, err := dir.LoadNextPage()
+		if err != nil {
+			return err
+		}
+		res, err := s.request(ctx, evt, directory.LoadPageSucceededType, directory.LoadPageFailedType)
+		if err != nil {
+			return err
+		}
+		if err := dir.Notify(res); err != nil {
+			return err
+		}
+		if pl, ok := res.Payload().(directory.LoadPageFailed); ok {
+			return pl.Err
+		}
+	}
+	return nil
+}
+
+// loadDirectory returns the loaded directory at the given remote path.
+// As directories only exist through the keys of their content, a missing directory is loaded empty.
+func (s *session) loadDirectory(ctx context.Context, remotePath string) (*directory.Directory, error) {
+	dir, err := directory.NewFromPath(s.conn.ID(), directory.NewPath(cleanRemotePath(remotePath)))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.load(ctx, dir); err != nil {
+		return nil, err
+	}
+	return dir, nil
+}
+
+// resolve returns the file or the directory at the given remote path, by loading its parent directory.
+// A path ending with a slash can only be a directory. The returned directory is not loaded.
+func (s *session) resolve(ctx context.Context, remotePath string) (*directory.File, *directory.Directory, error) {
+	cleaned := cleanRemotePath(remotePath)
+	if cleaned == "/" {
+		root, err := directory.NewRoot(s.conn.ID())
+		return nil, root, err
+	}
+
+	parent, err := s.loadDirectory(ctx, path.Dir(cleaned))
+	if err != nil {
+		return nil, nil, err
+	}
+	name := path.Base(cleaned)
+
+	if !strings.HasSuffix(strings.TrimPrefix(remotePath, remotePrefix), "/") {
+		if file, err := parent.GetFileByName(directory.FileName(name)); err == nil {
+			return file, nil, nil
+		}
+	}
+	if dir, err := parent.GetSubDirectoryByName(name); err == nil {
+		return nil, dir, nil
+	}
+	return nil, nil, fmt.Errorf("%w: %s", directory.ErrNotFound, remotePath)
+}
+
+// isRemotePath returns true when the path is prefixed with s3:
+func isRemotePath(p string) bool {
+	return strings.HasPrefix(p, remotePrefix)
+}
+
+// cleanRemotePath returns the absolute slash separated path, without the s3: prefix nor a trailing slash.
+func cleanRemotePath(p string) string {
+	return path.Clean("/" + strings.TrimPrefix(p, remotePrefix))
+}
+
+// payloadErr returns the error carried by a failure follow-up, nil for a success.
+func payloadErr(evt event.Event) error {
+	switch pl := evt.Payload().(type) {
+	case directory.LoadFileFailed:
+		return pl.Err
+	case directory.LoadFileMetadataFailed:
+		return pl.Err
+	case directory.UploadFileFailed:
+		return pl.Err
+	case directory.DownloadFileFailed:
+		return pl.Err
+	case directory.DownloadFailed:
+		return pl.Err
+	case directory.DeleteSelectionFailed:
+		return pl.Err
+	case directory.CopySelectionFailed:
+		return pl.Err
+	case directory.ComputeSizeFailed:
+		return pl.Err
+	case connection_deck.CreateConnectionFailed:
+		return pl.Err
+	case connection_deck.UpdateConnectionFailed:
+		return pl.Err
+	}
+	return nil
+}
+
+// joinPayloadErrs returns the errors carried by the failure follow-ups.
+func joinPayloadErrs(evts []event.Event) error {
+	errs := make([]error, 0, len(evts))
+	for _, evt := range evts {
+		errs = append(errs, payloadErr(evt))
+	}
+	return errors.Join(errs...)
+}
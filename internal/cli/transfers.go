@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+type transferView struct {
+	DoneCount    int `json:"doneCount"`
+	SkippedCount int `json:"skippedCount"`
+}
+
+func (v transferView) table(verb string) table {
+	return table{value: v, rows: [][]string{
+		{verb, strconv.Itoa(v.DoneCount)},
+		{"Skipped", strconv.Itoa(v.SkippedCount)},
+	}}
+}
+
+func runCp(ctx context.Context, c *CLI, s *session, args []string) error {
+	var recursive, noClobber bool
+	args, err := parseCommandFlags("cp", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&recursive, "r", false, "copy the directories with their content")
+		fs.BoolVar(&noClobber, "n", false, "keep the existing files")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("%w: cp takes a source and a destination", ErrUsage)
+	}
+	src, dst := args[0], args[1]
+
+	var strategy directory.MaterializeStrategy = directory.MaterializeReplace
+	if noClobber {
+		strategy = directory.MaterializeSkip
+	}
+
+	var res transferView
+	switch {
+	case isRemotePath(src) && isRemotePath(dst):
+		res, err = transferInBucket(ctx, s, src, dst, recursive, strategy, false)
+	case isRemotePath(src):
+		res, err = download(ctx, s, src, dst, recursive, strategy)
+	case isRemotePath(dst):
+		res, err = upload(ctx, s, src, dst, recursive, noClobber)
+	default:
+		return fmt.Errorf("%w: the source or the destination must be a remote path prefixed with %s", ErrUsage, remotePrefix)
+	}
+	if err != nil {
+		return err
+	}
+	return c.print(res.table("Copied"))
+}
+
+func runMv(ctx context.Context, c *CLI, s *session, args []string) error {
+	var noClobber bool
+	args, err := parseCommandFlags("mv", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&noClobber, "n", false, "keep the existing files")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("%w: mv takes a source and a destination", ErrUsage)
+	}
+
+	var strategy directory.MaterializeStrategy = directory.MaterializeReplace
+	if noClobber {
+		strategy = directory.MaterializeSkip
+	}
+
+	res, err := transferInBucket(ctx, s, args[0], args[1], true, strategy, true)
+	if err != nil {
+		return err
+	}
+	return c.print(res.table("Moved"))
+}
+
+func runRm(ctx context.Context, c *CLI, s *session, args []string) error {
+	var recursive bool
+	args, err := parseCommandFlags("rm", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&recursive, "r", false, "delete the directories with their content")
+	})
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: rm takes at least one path", ErrUsage)
+	}
+
+	sel := directory.NewSelection(s.conn.ID())
+	for _, arg := range args {
+		file, dir, err := s.resolve(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if file != nil {
+			err = sel.AddFile(file)
+		} else if !recursive {
+			err = fmt.Errorf("%w: %s is a directory, use -r to delete it with its content", ErrUsage, arg)
+		} else {
+			err = sel.AddDirectory(dir)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	evt, err := sel.Delete(event.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res, err := s.request(ctx, evt, directory.DeleteSelectionSucceededType, directory.DeleteSelectionFailedType)
+	if err != nil {
+		return err
+	}
+	if err := payloadErr(res); err != nil {
+		return err
+	}
+
+	deleted := res.Payload().(directory.DeleteSelectionSucceeded).DeletedCount
+	return c.print(transferView{DoneCount: deleted}.table("Deleted"))
+}
+
+// transferInBucket copies or moves a file or a directory of the bucket into the destination directory.
+func transferInBucket(
+	ctx context.Context,
+	s *session,
+	src, dst string,
+	recursive bool,
+	strategy directory.MaterializeStrategy,
+	move bool,
+) (transferView, error) {
+	file, dir, err := s.resolve(ctx, src)
+	if err != nil {
+		return transferView{}, err
+	}
+
+	sel := directory.NewSelection(s.conn.ID())
+	if file != nil {
+		err = sel.AddFile(file)
+	} else if !recursive {
+		err = fmt.Errorf("%w: %s is a directory, use -r to copy it with its content", ErrUsage, src)
+	} else {
+		err = sel.AddDirectory(dir)
+	}
+	if err != nil {
+		return transferView{}, err
+	}
+
+	dstDir, err := directory.NewFromPath(s.conn.ID(), directory.NewPath(cleanRemotePath(dst)))
+	if err != nil {
+		return transferView{}, err
+	}
+
+	var evt event.Event
+	if move {
+		evt, err = sel.MoveTo(dstDir, strategy, event.WithContext(ctx))
+	} else {
+		evt, err = sel.CopyTo(dstDir, strategy, event.WithContext(ctx))
+	}
+	if err != nil {
+		return transferView{}, err
+	}
+
+	res, err := s.request(ctx, evt, directory.CopySelectionSucceededType, directory.CopySelectionFailedType)
+	if err != nil {
+		return transferView{}, err
+	}
+	if err := payloadErr(res); err != nil {
+		return transferView{}, err
+	}
+	pl := res.Payload().(directory.CopySelectionSucceeded)
+	return transferView{DoneCount: pl.CopiedCount, SkippedCount: pl.SkippedCount}, nil
+}
+
+// download copies a remote file to a local path, or a remote directory into a local directory.
+func download(
+	ctx context.Context,
+	s *session,
+	src, dst string,
+	recursive bool,
+	strategy directory.MaterializeStrategy,
+) (transferView, error) {
+	file, dir, err := s.resolve(ctx, src)
+	if err != nil {
+		return transferView{}, err
+	}
+
+	if file == nil {
+		if !recursive {
+			return transferView{}, fmt.Errorf("%w: %s is a directory, use -r to copy it with its content", ErrUsage, src)
+		}
+		res, err := s.request(ctx, dir.Download(dst, strategy, event.WithContext(ctx)),
+			directory.DownloadSucceededType, directory.DownloadFailedType)
+		if err != nil {
+			return transferView{}, err
+		}
+		if err := payloadErr(res); err != nil {
+			return transferView{}, err
+		}
+		pl := res.Payload().(directory.DownloadSucceeded)
+		return transferView{DoneCount: pl.DownloadedCount, SkippedCount: pl.SkippedCount}, nil
+	}
+
+	localPath := dst
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		localPath = filepath.Join(dst, file.Name().String())
+	}
+	if _, err := os.Stat(localPath); err == nil && strategy == directory.MaterializeSkip {
+		return transferView{SkippedCount: 1}, nil
+	}
+
+	res, err := s.request(ctx, file.Download(s.conn.ID(), localPath, event.WithContext(ctx)),
+		directory.DownloadFileSucceededType, directory.DownloadFileFailedType)
+	if err != nil {
+		return transferView{}, err
+	}
+	if err := payloadErr(res); err != nil {
+		return transferView{}, err
+	}
+	return transferView{DoneCount: 1}, nil
+}
+
+// upload copies a local file into a remote directory, or a local directory with its content under it.
+func upload(ctx context.Context, s *session, src, dst string, recursive, noClobber bool) (transferView, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return transferView{}, err
+	}
+
+	// the local files to upload, indexed by remote directory
+	filesByDir := make(map[string][]string)
+	dstPath := cleanRemotePath(dst)
+	if !info.IsDir() {
+		filesByDir[dstPath] = []string{src}
+	} else {
+		if !recursive {
+			return transferView{}, fmt.Errorf("%w: %s is a directory, use -r to copy it with its content", ErrUsage, src)
+		}
+		root := filepath.Clean(src)
+		walkErr := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(root, filepath.Dir(p))
+			if err != nil {
+				return err
+			}
+			remoteDir := path.Join(dstPath, filepath.Base(root), filepath.ToSlash(rel))
+			filesByDir[remoteDir] = append(filesByDir[remoteDir], p)
+			return nil
+		})
+		if walkErr != nil {
+			return transferView{}, walkErr
+		}
+	}
+
+	var (
+		res  transferView
+		evts []event.Event
+	)
+	for remoteDir, localPaths := range filesByDir {
+		dir, err := s.loadDirectory(ctx, remoteDir)
+		if err != nil {
+			return transferView{}, err
+		}
+		for _, localPath := range localPaths {
+			if noClobber && dir.IsFileExists(directory.FileName(filepath.Base(localPath))) {
+				res.SkippedCount++
+				continue
+			}
+			evt, err := dir.UploadFile(localPath, true)
+			if err != nil {
+				return transferView{}, err
+			}
+			evts = append(evts, evt)
+		}
+	}
+
+	followups, err := s.requestAll(ctx, evts, directory.UploadFileSucceededType, directory.UploadFileFailedType)
+	if err != nil {
+		return transferView{}, err
+	}
+	for _, f := range followups {
+		if payloadErr(f) == nil {
+			res.DoneCount++
+		}
+	}
+	if err := joinPayloadErrs(followups); err != nil {
+		return res, errors.Join(fmt.Errorf("%d files uploaded", res.DoneCount), err)
+	}
+	return res, nil
+}
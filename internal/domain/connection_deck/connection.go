@@ -2,6 +2,7 @@ package connection_deck
 
 import (
 	"github.com/google/uuid"
+	"github.com/thomas-marquis/it-happened/event"
 )
 
 type ConnectionID uuid.UUID
@@ -35,6 +36,14 @@ type Connection struct {
 	readOnly  bool
 	revision  int
 	provider  Provider
+
+	credentialSource CredentialSource
+	transport        TransportOptions
+	// rootPrefix is the key prefix the connection is mounted on, the browsed tree starting there
+	rootPrefix string
+	// accountLevel connections can browse all the buckets of the account,
+	// their bucket is the one currently browsed and can be empty
+	accountLevel bool
 }
 
 func newConnection(
@@ -49,6 +58,8 @@ func newConnection(
 		bucket:    bucket,
 		readOnly:  false,
 		provider:  nilProvider,
+
+		credentialSource: CredentialSource{Type: StaticCredentials},
 	}
 	for _, opt := range options {
 		opt(conn)
@@ -105,6 +116,19 @@ func (c *Connection) UpdateSecretKey(secretKey string) {
 	}
 }
 
+func (c *Connection) CredentialSource() CredentialSource {
+	return c.credentialSource.normalize()
+}
+
+// ChangeCredentialSource updates where the credentials of the connection come from.
+func (c *Connection) ChangeCredentialSource(source CredentialSource) {
+	source = source.normalize()
+	if source != c.credentialSource && !c.readOnly {
+		c.revision++
+		c.credentialSource = source
+	}
+}
+
 func (c *Connection) Bucket() string {
 	return c.bucket
 }
@@ -116,6 +140,33 @@ func (c *Connection) UpdateBucket(bucket string) {
 	}
 }
 
+// Transport returns the options tuning how the requests reach the server.
+func (c *Connection) Transport() TransportOptions {
+	return c.transport
+}
+
+// RootPrefix returns the key prefix the connection is mounted on, as "team-x/", or an empty string
+// when the connection browses the whole bucket.
+func (c *Connection) RootPrefix() string {
+	return c.rootPrefix
+}
+
+// IsAccountLevel returns true when the connection isn't pinned to its bucket
+// and can switch to any bucket of the account.
+func (c *Connection) IsAccountLevel() bool {
+	return c.accountLevel
+}
+
+// ListBuckets returns the event asking the buckets the connection has access to.
+func (c *Connection) ListBuckets() event.Event {
+	return event.New(ListBucketsTriggered{ConnectionPayload{Conn: c}})
+}
+
+// Diagnose returns the event asking to check the connection, from the endpoint resolution to the bucket listing.
+func (c *Connection) Diagnose() event.Event {
+	return event.New(DiagnoseTriggered{ConnectionPayload{Conn: c}})
+}
+
 func (c *Connection) Server() string {
 	return c.server
 }
@@ -144,7 +195,7 @@ func (c *Connection) IsTLSActivated() bool {
 }
 
 func (c *Connection) TurnTLSOn() {
-	if !c.useTLS || c.provider != S3LikeProvider || c.readOnly {
+	if !c.useTLS || c.provider == AWSProvider || c.readOnly {
 		return
 	}
 	c.revision++
@@ -152,7 +203,7 @@ func (c *Connection) TurnTLSOn() {
 }
 
 func (c *Connection) TurnTLSOff() {
-	if c.useTLS || c.provider != S3LikeProvider || c.readOnly {
+	if c.useTLS || c.provider == AWSProvider || c.readOnly {
 		return
 	}
 	c.revision++
@@ -193,7 +244,7 @@ func (c *Connection) AsAWS(region string) {
 
 // AsS3Like turns the connection into an S3-like one if it's not already
 func (c *Connection) AsS3Like(server string, useTLS bool) {
-	if c.provider == S3LikeProvider || c.readOnly {
+	if c.provider != AWSProvider || c.readOnly {
 		return
 	}
 	c.revision++
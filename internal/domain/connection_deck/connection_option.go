@@ -1,5 +1,7 @@
 package connection_deck
 
+import "strings"
+
 type ConnectionOption func(*Connection)
 
 func AsAWS(region string) ConnectionOption {
@@ -20,6 +22,20 @@ func AsS3Like(server string, useTLS bool) ConnectionOption {
 	}
 }
 
+// AsProvider makes the connection one to the server of the provider, signing the requests for the region.
+// The region can be empty to use the default region of the provider.
+func AsProvider(provider Provider, server, region string, useTLS bool) ConnectionOption {
+	if provider == AWSProvider {
+		return AsAWS(region)
+	}
+	return func(c *Connection) {
+		c.provider = provider
+		c.server = server
+		c.useTLS = useTLS
+		c.region = region
+	}
+}
+
 func WithReadOnlyOption(readOnly bool) ConnectionOption {
 	return func(c *Connection) {
 		c.readOnly = readOnly
@@ -53,6 +69,36 @@ func WithCredentials(
 	}
 }
 
+// WithCredentialSource sets where the credentials of the connection come from.
+func WithCredentialSource(source CredentialSource) ConnectionOption {
+	return func(c *Connection) {
+		c.credentialSource = source.normalize()
+	}
+}
+
+// WithTransport sets how the requests of the connection reach the server.
+func WithTransport(transport TransportOptions) ConnectionOption {
+	return func(c *Connection) {
+		transport.AddressingStyle = NewAddressingStyleFromString(string(transport.AddressingStyle))
+		c.transport = transport
+	}
+}
+
+// WithRootPrefix mounts the connection on the key prefix, so that only the objects below it are browsed.
+// The prefix is normalized without a leading slash and with a trailing one.
+func WithRootPrefix(prefix string) ConnectionOption {
+	return func(c *Connection) {
+		c.rootPrefix = normalizeRootPrefix(prefix)
+	}
+}
+
+// WithAccountLevel lets the connection switch to any bucket of the account, its bucket being the default one.
+func WithAccountLevel(accountLevel bool) ConnectionOption {
+	return func(c *Connection) {
+		c.accountLevel = accountLevel
+	}
+}
+
 func WithName(name string) ConnectionOption {
 	return func(c *Connection) {
 		c.name = name
@@ -64,3 +110,11 @@ func WithBucket(bucket string) ConnectionOption {
 		c.bucket = bucket
 	}
 }
+
+func normalizeRootPrefix(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
@@ -0,0 +1,85 @@
+package connection_deck
+
+import "time"
+
+// CredentialSourceType is where the credentials of a connection come from.
+type CredentialSourceType string
+
+const (
+	// StaticCredentials signs the requests with the access key and secret key of the connection
+	StaticCredentials CredentialSourceType = "static"
+	// SessionCredentials adds a session token to the access key and secret key, as for temporary credentials
+	SessionCredentials CredentialSourceType = "session"
+	// ProfileCredentials reads the credentials of a profile of the AWS shared config and credentials files
+	ProfileCredentials CredentialSourceType = "profile"
+	// EnvCredentials reads the credentials from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+	// and AWS_SESSION_TOKEN environment variables
+	EnvCredentials CredentialSourceType = "env"
+	// AssumeRoleCredentials assumes a role with the access key and secret key of the connection,
+	// or with the default credentials when the connection has none
+	AssumeRoleCredentials CredentialSourceType = "assume-role"
+)
+
+// CredentialSourceTypes are all the credential source types, in the order they are offered.
+var CredentialSourceTypes = []CredentialSourceType{
+	StaticCredentials,
+	SessionCredentials,
+	ProfileCredentials,
+	EnvCredentials,
+	AssumeRoleCredentials,
+}
+
+func (t CredentialSourceType) String() string {
+	return string(t)
+}
+
+// NewCredentialSourceTypeFromString returns the type with the given name, StaticCredentials when it is unknown.
+func NewCredentialSourceTypeFromString(s string) CredentialSourceType {
+	for _, t := range CredentialSourceTypes {
+		if string(t) == s {
+			return t
+		}
+	}
+	return StaticCredentials
+}
+
+// CredentialSource describes how to get the credentials of a connection.
+// Only the fields of its type are relevant.
+type CredentialSource struct {
+	Type CredentialSourceType
+	// SessionToken is the token of the temporary credentials, for the SessionCredentials type
+	SessionToken string
+	// Profile is the name of the AWS profile, for the ProfileCredentials type
+	Profile string
+	// RoleARN, ExternalID and SessionDuration describe the role to assume, for the AssumeRoleCredentials type.
+	// The default duration of the role session is used when SessionDuration is zero.
+	RoleARN         string
+	ExternalID      string
+	SessionDuration time.Duration
+}
+
+// normalize keeps only the fields of the source type.
+func (s CredentialSource) normalize() CredentialSource {
+	res := CredentialSource{Type: NewCredentialSourceTypeFromString(string(s.Type))}
+	switch res.Type {
+	case SessionCredentials:
+		res.SessionToken = s.SessionToken
+	case ProfileCredentials:
+		res.Profile = s.Profile
+	case AssumeRoleCredentials:
+		res.RoleARN = s.RoleARN
+		res.ExternalID = s.ExternalID
+		res.SessionDuration = s.SessionDuration
+	}
+	return res
+}
+
+// UsesKeys returns true when the access key and secret key of the connection are used with this source.
+func (s CredentialSource) UsesKeys() bool {
+	switch s.Type {
+	case ProfileCredentials, EnvCredentials:
+		return false
+	default:
+		return true
+	}
+}
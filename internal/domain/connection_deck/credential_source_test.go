@@ -0,0 +1,53 @@
+package connection_deck_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func TestConnection_ChangeCredentialSource(t *testing.T) {
+	t.Run("should use the static keys by default", func(t *testing.T) {
+		// When
+		conn := newImportedConnection("conn 1", "b1")
+
+		// Then
+		assert.Equal(t, connection_deck.StaticCredentials, conn.CredentialSource().Type)
+		assert.True(t, conn.CredentialSource().UsesKeys())
+	})
+
+	t.Run("should keep only the fields of the source type", func(t *testing.T) {
+		// Given
+		conn := newImportedConnection("conn 1", "b1")
+		revision := conn.Revision()
+
+		// When
+		conn.ChangeCredentialSource(connection_deck.CredentialSource{
+			Type:            connection_deck.AssumeRoleCredentials,
+			SessionToken:    "token",
+			RoleARN:         "arn:aws:iam::123456789012:role/reader",
+			SessionDuration: time.Hour,
+		})
+
+		// Then
+		assert.Equal(t, connection_deck.CredentialSource{
+			Type:            connection_deck.AssumeRoleCredentials,
+			RoleARN:         "arn:aws:iam::123456789012:role/reader",
+			SessionDuration: time.Hour,
+		}, conn.CredentialSource())
+		assert.Equal(t, revision+1, conn.Revision())
+	})
+
+	t.Run("should leave a read-only connection unchanged", func(t *testing.T) {
+		// Given
+		conn := newImportedConnection("conn 1", "b1", connection_deck.WithReadOnlyOption(true))
+
+		// When
+		conn.ChangeCredentialSource(connection_deck.CredentialSource{Type: connection_deck.EnvCredentials})
+
+		// Then
+		assert.Equal(t, connection_deck.StaticCredentials, conn.CredentialSource().Type)
+	})
+}
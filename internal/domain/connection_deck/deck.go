@@ -26,12 +26,7 @@ func (d *Deck) New(
 	name, accessKey, secretKey, bucket string,
 	options ...ConnectionOption,
 ) event.Event {
-	conn := newConnection(name, accessKey, secretKey, bucket, options...)
-	d.connections = append(d.connections, conn)
-	return event.New(CreateConnectionTriggered{
-		ConnectionPayload: ConnectionPayload{Conn: conn},
-		Deck:              d,
-	})
+	return d.add(newConnection(name, accessKey, secretKey, bucket, options...))
 }
 
 // Get returns all the connections currently stored in the deck.
@@ -132,6 +127,19 @@ func (d *Deck) Update(connID ConnectionID, options ...ConnectionOption) (event.E
 	}), nil
 }
 
+// SwitchBucket changes the bucket browsed with an account-level connection.
+// The bucket is saved with the connection, so that it's browsed again the next time.
+func (d *Deck) SwitchBucket(connID ConnectionID, bucket string) (event.Event, error) {
+	conn, err := d.GetByID(connID)
+	if err != nil {
+		return nil, err
+	}
+	if !conn.accountLevel {
+		return nil, ErrNotAccountLevel
+	}
+	return d.Update(connID, WithBucket(bucket))
+}
+
 func (d *Deck) Notify(evt event.Event) {
 	switch pl := evt.Payload().(type) {
 	case CreateConnectionFailed:
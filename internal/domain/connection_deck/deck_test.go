@@ -27,6 +27,19 @@ func TestDeck_New(t *testing.T) {
 		assert.Equal(t, "secretkey", pl.Connection().SecretKey())
 		assert.Equal(t, "myBucket", pl.Connection().Bucket())
 	})
+
+	t.Run("should normalize the root prefix of the connection", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+
+		// When
+		res := deck.New("connection 1", "accesskey", "secretkey", "myBucket",
+			connection_deck.WithRootPrefix("/team-x/data"))
+
+		// Then
+		pl := res.Payload().(connection_deck.CreateConnectionTriggered)
+		assert.Equal(t, "team-x/data/", pl.Connection().RootPrefix())
+	})
 }
 
 func TestDeck_GetByID(t *testing.T) {
@@ -253,6 +266,38 @@ func TestDeck_Update(t *testing.T) {
 	})
 }
 
+func TestDeck_SwitchBucket(t *testing.T) {
+	t.Run("should change the bucket of an account-level connection", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+		conn := deck.New("conn 1", "ak", "sk", "", connection_deck.WithAccountLevel(true)).
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+
+		// When
+		evt, err := deck.SwitchBucket(conn.ID(), "other-bucket")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, connection_deck.UpdateConnectionTriggeredType, evt.Type())
+		assert.Equal(t, "other-bucket", conn.Bucket())
+		assert.Equal(t, "", evt.Payload().(connection_deck.UpdateConnectionTriggered).Previous.Bucket())
+	})
+
+	t.Run("should refuse to change the bucket of a connection pinned to its bucket", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+		conn := deck.New("conn 1", "ak", "sk", "b1").
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+
+		// When
+		_, err := deck.SwitchBucket(conn.ID(), "other-bucket")
+
+		// Then
+		assert.ErrorIs(t, err, connection_deck.ErrNotAccountLevel)
+		assert.Equal(t, "b1", conn.Bucket())
+	})
+}
+
 func TestDeck_Notify(t *testing.T) {
 	t.Run("CreateFailureEvent", func(t *testing.T) {
 		t.Run("should remove the connection from the deck", func(t *testing.T) {
@@ -0,0 +1,26 @@
+package connection_deck
+
+// DiagnosticStatus is the outcome of a diagnostic step.
+type DiagnosticStatus string
+
+const (
+	DiagnosticPassed  DiagnosticStatus = "passed"
+	DiagnosticFailed  DiagnosticStatus = "failed"
+	DiagnosticSkipped DiagnosticStatus = "skipped"
+)
+
+// DiagnosticStep is a check of a connection, from the endpoint resolution to the bucket listing.
+type DiagnosticStep struct {
+	Name   string
+	Status DiagnosticStatus
+	// Details explains the outcome of the step in plain words
+	Details string
+}
+
+func (s DiagnosticStep) Passed() bool {
+	return s.Status == DiagnosticPassed
+}
+
+func (s DiagnosticStep) Failed() bool {
+	return s.Status == DiagnosticFailed
+}
@@ -3,6 +3,10 @@ package connection_deck
 import "errors"
 
 var (
-	ErrNotFound  = errors.New("connection not found")
-	ErrTechnical = errors.New("technical error occurred while processing the connection")
+	ErrNotFound          = errors.New("connection not found")
+	ErrTechnical         = errors.New("technical error occurred while processing the connection")
+	ErrSecretNotFound    = errors.New("secret not found")
+	ErrSecretStoreLocked = errors.New("the secret store is locked")
+	ErrWrongPassphrase   = errors.New("wrong passphrase")
+	ErrNotAccountLevel   = errors.New("the connection is pinned to its bucket")
 )
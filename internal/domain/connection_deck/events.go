@@ -199,3 +199,79 @@ func (e UpdateConnectionFailed) EventType() event.Type {
 func (e UpdateConnectionFailed) Error() error {
 	return e.Err
 }
+
+const (
+	ListBucketsTriggeredType event.Type = "deck.connection.buckets.list.triggered"
+	ListBucketsSucceededType event.Type = "deck.connection.buckets.list.succeeded"
+	ListBucketsFailedType    event.Type = "deck.connection.buckets.list.failed"
+)
+
+var (
+	_ ConnectionGetter = (*ListBucketsTriggered)(nil)
+	_ ConnectionGetter = (*ListBucketsSucceeded)(nil)
+	_ ConnectionGetter = (*ListBucketsFailed)(nil)
+	_ ErrorGetter      = (*ListBucketsFailed)(nil)
+)
+
+// ListBucketsTriggered asks the buckets of the connection account.
+// The connection may not be saved yet, as when its settings are being edited.
+type ListBucketsTriggered struct {
+	ConnectionPayload
+}
+
+func (e ListBucketsTriggered) EventType() event.Type {
+	return ListBucketsTriggeredType
+}
+
+type ListBucketsSucceeded struct {
+	ConnectionPayload
+	// Buckets are the names of the buckets, sorted
+	Buckets []string
+}
+
+func (e ListBucketsSucceeded) EventType() event.Type {
+	return ListBucketsSucceededType
+}
+
+type ListBucketsFailed struct {
+	ConnectionPayload
+	Err error
+}
+
+func (e ListBucketsFailed) EventType() event.Type {
+	return ListBucketsFailedType
+}
+
+func (e ListBucketsFailed) Error() error {
+	return e.Err
+}
+
+const (
+	DiagnoseTriggeredType event.Type = "deck.connection.diagnose.triggered"
+	DiagnoseSucceededType event.Type = "deck.connection.diagnose.succeeded"
+)
+
+var (
+	_ ConnectionGetter = (*DiagnoseTriggered)(nil)
+	_ ConnectionGetter = (*DiagnoseSucceeded)(nil)
+)
+
+// DiagnoseTriggered asks to check the connection step by step.
+// The connection may not be saved yet, as when its settings are being edited.
+type DiagnoseTriggered struct {
+	ConnectionPayload
+}
+
+func (e DiagnoseTriggered) EventType() event.Type {
+	return DiagnoseTriggeredType
+}
+
+// DiagnoseSucceeded carries the steps of a diagnostic which ran to the end, even when some of them failed.
+type DiagnoseSucceeded struct {
+	ConnectionPayload
+	Steps []DiagnosticStep
+}
+
+func (e DiagnoseSucceeded) EventType() event.Type {
+	return DiagnoseSucceededType
+}
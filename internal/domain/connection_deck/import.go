@@ -0,0 +1,207 @@
+package connection_deck
+
+import "github.com/thomas-marquis/it-happened/event"
+
+// ImportSource is the format of the connections to import.
+type ImportSource string
+
+const (
+	// ImportFromJSON reads the JSON file written by the connections export
+	ImportFromJSON ImportSource = "json"
+	// ImportFromAWSProfiles reads the profiles of the AWS CLI credentials and config files
+	ImportFromAWSProfiles ImportSource = "aws"
+	// ImportFromRclone reads the s3 remotes of the rclone config file
+	ImportFromRclone ImportSource = "rclone"
+)
+
+func (s ImportSource) String() string {
+	return string(s)
+}
+
+// ImportAction is what is done with an imported connection.
+type ImportAction int
+
+const (
+	// ImportAdd adds the connection to the deck
+	ImportAdd ImportAction = iota
+	// ImportMerge updates the connection of the deck with the same ID or name
+	ImportMerge
+	// ImportSkip ignores the connection, identical to the one of the deck or already imported
+	ImportSkip
+)
+
+func (a ImportAction) String() string {
+	switch a {
+	case ImportAdd:
+		return "Add"
+	case ImportMerge:
+		return "Update"
+	default:
+		return "Skip"
+	}
+}
+
+// ImportEntry is an imported connection with what would be done of it.
+type ImportEntry struct {
+	Connection *Connection
+	// Existing is the connection of the deck matching the imported one, nil when it is added
+	Existing *Connection
+	Action   ImportAction
+}
+
+// ImportPreview compares imported connections with the ones of the deck, before to apply the import.
+// An imported connection matches a connection of the deck with the same ID, or else with the same name.
+type ImportPreview struct {
+	deck    *Deck
+	entries []ImportEntry
+}
+
+// PrepareImport returns the preview of the import of the connections into the deck.
+// The deck is left unchanged until the preview is applied.
+func (d *Deck) PrepareImport(conns []*Connection) *ImportPreview {
+	p := &ImportPreview{deck: d, entries: make([]ImportEntry, 0, len(conns))}
+	seenIDs := make(map[ConnectionID]struct{}, len(conns))
+	seenNames := make(map[string]struct{}, len(conns))
+
+	for _, conn := range conns {
+		entry := ImportEntry{Connection: conn, Action: ImportAdd}
+		_, idSeen := seenIDs[conn.ID()]
+		_, nameSeen := seenNames[conn.Name()]
+		seenIDs[conn.ID()] = struct{}{}
+		seenNames[conn.Name()] = struct{}{}
+
+		switch existing := d.findForImport(conn); {
+		case idSeen || nameSeen:
+			entry.Action = ImportSkip
+			entry.Existing = existing
+		case existing == nil:
+		case existing.hasSameSettings(conn):
+			entry.Action = ImportSkip
+			entry.Existing = existing
+		default:
+			entry.Action = ImportMerge
+			entry.Existing = existing
+		}
+		p.entries = append(p.entries, entry)
+	}
+	return p
+}
+
+// Entries returns the imported connections, in the import order.
+func (p *ImportPreview) Entries() []ImportEntry {
+	return p.entries
+}
+
+// Count returns the number of imported connections with the given action.
+func (p *ImportPreview) Count(action ImportAction) int {
+	count := 0
+	for _, e := range p.entries {
+		if e.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Apply adds the new connections to the deck and, when merge is true,
+// updates the existing ones with the imported settings.
+// All the connections are changed in the deck before the returned events are published,
+// so that each of them carries the whole imported deck.
+func (p *ImportPreview) Apply(merge bool) []event.Event {
+	evts := make([]event.Event, 0, len(p.entries))
+	for _, e := range p.entries {
+		switch {
+		case e.Action == ImportAdd:
+			conn := *e.Connection
+			evts = append(evts, p.deck.add(&conn))
+		case e.Action == ImportMerge && merge:
+			// the existing connection is always found as the deck is not changed by the other entries
+			evt, _ := p.deck.Update(e.Existing.ID(), e.Connection.settings()...)
+			evts = append(evts, evt)
+		}
+	}
+	return evts
+}
+
+func (d *Deck) findForImport(conn *Connection) *Connection {
+	if existing, err := d.GetByID(conn.ID()); err == nil {
+		return existing
+	}
+	for _, existing := range d.connections {
+		if existing.name == conn.name {
+			return existing
+		}
+	}
+	return nil
+}
+
+// add adds an already built connection to the deck.
+func (d *Deck) add(conn *Connection) event.Event {
+	d.connections = append(d.connections, conn)
+	return event.New(CreateConnectionTriggered{
+		ConnectionPayload: ConnectionPayload{Conn: conn},
+		Deck:              d,
+	})
+}
+
+// settings returns the options setting the connection values, except its identity and revision.
+// The credentials are left out when the connection has none, as in the exports without secrets.
+func (c *Connection) settings() []ConnectionOption {
+	provider := AsProvider(c.provider, c.server, c.region, c.useTLS)
+	opts := []ConnectionOption{
+		WithName(c.name),
+		WithBucket(c.bucket),
+		WithReadOnlyOption(c.readOnly),
+		WithAccountLevel(c.accountLevel),
+		WithTransport(c.transport),
+		WithRootPrefix(c.rootPrefix),
+		withImportedCredentialSource(c.credentialSource),
+		provider,
+	}
+	if c.hasCredentials() {
+		opts = append(opts, WithCredentials(c.accessKey, c.secretKey))
+	}
+	return opts
+}
+
+// withImportedCredentialSource sets the credential source, keeping the session token of the updated connection
+// when the imported one has none.
+func withImportedCredentialSource(source CredentialSource) ConnectionOption {
+	return func(c *Connection) {
+		if source.Type == SessionCredentials && source.SessionToken == "" {
+			source.SessionToken = c.credentialSource.SessionToken
+		}
+		WithCredentialSource(source)(c)
+	}
+}
+
+// hasSameCredentialSource compares the credential sources, except the session token left out of the exports without secrets.
+func (c *Connection) hasSameCredentialSource(imported *Connection) bool {
+	source := imported.credentialSource
+	if source.Type == SessionCredentials && source.SessionToken == "" {
+		source.SessionToken = c.credentialSource.SessionToken
+	}
+	return c.credentialSource == source
+}
+
+func (c *Connection) hasCredentials() bool {
+	return c.accessKey != "" || c.secretKey != ""
+}
+
+// hasSameSettings returns true when the imported connection wouldn't change anything.
+func (c *Connection) hasSameSettings(imported *Connection) bool {
+	sameCredentials := !imported.hasCredentials() ||
+		c.accessKey == imported.accessKey && c.secretKey == imported.secretKey
+	return sameCredentials &&
+		c.name == imported.name &&
+		c.bucket == imported.bucket &&
+		c.server == imported.server &&
+		c.region == imported.region &&
+		c.useTLS == imported.useTLS &&
+		c.readOnly == imported.readOnly &&
+		c.accountLevel == imported.accountLevel &&
+		c.transport == imported.transport &&
+		c.rootPrefix == imported.rootPrefix &&
+		c.provider == imported.provider &&
+		c.hasSameCredentialSource(imported)
+}
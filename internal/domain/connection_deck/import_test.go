@@ -0,0 +1,113 @@
+package connection_deck_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func newImportedConnection(name, bucket string, options ...connection_deck.ConnectionOption) *connection_deck.Connection {
+	return connection_deck.New().New(name, "ak", "sk", bucket, options...).
+		Payload().(connection_deck.CreateConnectionTriggered).Connection()
+}
+
+func TestDeck_PrepareImport(t *testing.T) {
+	t.Run("should compare the imported connections with the deck ones by ID then by name", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+		sameID := deck.New("conn 1", "ak", "sk", "b1").
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+		sameName := deck.New("conn 2", "ak", "sk", "b2").
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+		identical := deck.New("conn 3", "ak", "sk", "b3").
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+
+		imported := []*connection_deck.Connection{
+			newImportedConnection("renamed conn 1", "b1", connection_deck.WithID(sameID.ID())),
+			newImportedConnection("conn 2", "other bucket"),
+			newImportedConnection("conn 3", "b3", connection_deck.WithID(identical.ID())),
+			newImportedConnection("conn 4", "b4"),
+		}
+
+		// When
+		res := deck.PrepareImport(imported)
+
+		// Then
+		require.Len(t, res.Entries(), 4)
+		assert.Equal(t, connection_deck.ImportMerge, res.Entries()[0].Action)
+		assert.Equal(t, sameID, res.Entries()[0].Existing)
+		assert.Equal(t, connection_deck.ImportMerge, res.Entries()[1].Action)
+		assert.Equal(t, sameName, res.Entries()[1].Existing)
+		assert.Equal(t, connection_deck.ImportSkip, res.Entries()[2].Action)
+		assert.Equal(t, connection_deck.ImportAdd, res.Entries()[3].Action)
+		assert.Nil(t, res.Entries()[3].Existing)
+		assert.Equal(t, 1, res.Count(connection_deck.ImportAdd))
+		assert.Equal(t, 2, res.Count(connection_deck.ImportMerge))
+		assert.Len(t, deck.Get(), 3)
+	})
+
+	t.Run("should skip the connections imported twice", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+		imported := []*connection_deck.Connection{
+			newImportedConnection("conn 1", "b1"),
+			newImportedConnection("conn 1", "b2"),
+		}
+
+		// When
+		res := deck.PrepareImport(imported)
+
+		// Then
+		require.Len(t, res.Entries(), 2)
+		assert.Equal(t, connection_deck.ImportAdd, res.Entries()[0].Action)
+		assert.Equal(t, connection_deck.ImportSkip, res.Entries()[1].Action)
+	})
+}
+
+func TestImportPreview_Apply(t *testing.T) {
+	t.Run("should add the new connections and update the existing ones when merging", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+		existing := deck.New("conn 1", "ak", "sk", "b1").
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+		added := newImportedConnection("conn 2", "b2", connection_deck.AsS3Like("localhost:9000", false))
+		preview := deck.PrepareImport([]*connection_deck.Connection{
+			newImportedConnection("conn 1", "other bucket"),
+			added,
+		})
+
+		// When
+		res := preview.Apply(true)
+
+		// Then
+		require.Len(t, res, 2)
+		assert.Equal(t, connection_deck.UpdateConnectionTriggeredType, res[0].Type())
+		assert.Equal(t, connection_deck.CreateConnectionTriggeredType, res[1].Type())
+		require.Len(t, deck.Get(), 2)
+		assert.Equal(t, "other bucket", existing.Bucket())
+		newConn := deck.Get()[1]
+		assert.Equal(t, added.ID(), newConn.ID())
+		assert.Equal(t, "localhost:9000", newConn.Server())
+		assert.Equal(t, connection_deck.S3LikeProvider, newConn.Provider())
+	})
+
+	t.Run("should leave the existing connections unchanged when not merging", func(t *testing.T) {
+		// Given
+		deck := connection_deck.New()
+		existing := deck.New("conn 1", "ak", "sk", "b1").
+			Payload().(connection_deck.CreateConnectionTriggered).Connection()
+		preview := deck.PrepareImport([]*connection_deck.Connection{
+			newImportedConnection("conn 1", "other bucket"),
+		})
+
+		// When
+		res := preview.Apply(false)
+
+		// Then
+		assert.Empty(t, res)
+		assert.Equal(t, "b1", existing.Bucket())
+		assert.Len(t, deck.Get(), 1)
+	})
+}
@@ -9,19 +9,137 @@ func (c Provider) String() string {
 }
 
 const (
-	nilProvider     Provider = ""
-	AWSProvider     Provider = "aws"
-	S3LikeProvider  Provider = "s3-like"
-	DefaultProvider Provider = S3LikeProvider
+	nilProvider      Provider = ""
+	AWSProvider      Provider = "aws"
+	S3LikeProvider   Provider = "s3-like"
+	MinIOProvider    Provider = "minio"
+	R2Provider       Provider = "r2"
+	WasabiProvider   Provider = "wasabi"
+	B2Provider       Provider = "b2"
+	OVHProvider      Provider = "ovh"
+	ScalewayProvider Provider = "scaleway"
+	GCSProvider      Provider = "gcs"
+	CephProvider     Provider = "ceph"
+	DefaultProvider  Provider = S3LikeProvider
 )
 
+// Providers are the known providers, AWS then the S3-like ones, the generic one first.
+var Providers = []Provider{
+	AWSProvider,
+	S3LikeProvider,
+	MinIOProvider,
+	R2Provider,
+	WasabiProvider,
+	B2Provider,
+	OVHProvider,
+	ScalewayProvider,
+	GCSProvider,
+	CephProvider,
+}
+
 func NewProviderFromString(s string) Provider {
-	switch strings.ToLower(s) {
-	case AWSProvider.String():
-		return AWSProvider
-	case S3LikeProvider.String():
-		return S3LikeProvider
-	default:
-		return DefaultProvider
+	p := Provider(strings.ToLower(s))
+	if _, ok := providerPresets[p]; ok {
+		return p
+	}
+	return DefaultProvider
+}
+
+// CopySourceEscaping is how the key of the copied object is escaped in the copy source header.
+type CopySourceEscaping int
+
+const (
+	// EscapeCopySourceURL URL-encodes the bucket and the key, the spaces included
+	EscapeCopySourceURL CopySourceEscaping = iota
+	// EscapeCopySourceRawSpaces URL-encodes the bucket and the key but leaves the spaces as is, as OVH expects
+	EscapeCopySourceRawSpaces
+)
+
+// ProviderPreset describes a provider and the quirks of its S3 API.
+type ProviderPreset struct {
+	Label string
+	// EndpointTemplate is the server of the provider, where {region} is replaced by the region
+	// and {account} must be filled in by the user. It is empty for AWS and for the self-hosted servers.
+	EndpointTemplate string
+	DefaultRegion    string
+	// VirtualHostedStyle addresses the bucket in the host name rather than in the path
+	VirtualHostedStyle bool
+	CopySourceEscaping CopySourceEscaping
+	// SupportsACL is true when the object grants can be read, and so kept by the copies
+	SupportsACL bool
+}
+
+// Endpoint returns the server of the provider in the region, empty when the provider has no fixed server.
+func (p ProviderPreset) Endpoint(region string) string {
+	if region == "" {
+		region = p.DefaultRegion
+	}
+	return strings.ReplaceAll(p.EndpointTemplate, "{region}", region)
+}
+
+var providerPresets = map[Provider]ProviderPreset{
+	AWSProvider: {
+		Label:              "AWS",
+		DefaultRegion:      "us-east-1",
+		VirtualHostedStyle: true,
+		// the escaping used before the presets, kept for the existing connections
+		CopySourceEscaping: EscapeCopySourceRawSpaces,
+		SupportsACL:        true,
+	},
+	S3LikeProvider: {
+		Label:              "Other S3-compatible server",
+		DefaultRegion:      "us-east-1",
+		CopySourceEscaping: EscapeCopySourceRawSpaces,
+	},
+	MinIOProvider: {
+		Label:         "MinIO",
+		DefaultRegion: "us-east-1",
+	},
+	R2Provider: {
+		Label:            "Cloudflare R2",
+		EndpointTemplate: "{account}.r2.cloudflarestorage.com",
+		DefaultRegion:    "auto",
+	},
+	WasabiProvider: {
+		Label:            "Wasabi",
+		EndpointTemplate: "s3.{region}.wasabisys.com",
+		DefaultRegion:    "us-east-1",
+		SupportsACL:      true,
+	},
+	B2Provider: {
+		Label:            "Backblaze B2",
+		EndpointTemplate: "s3.{region}.backblazeb2.com",
+		DefaultRegion:    "us-west-004",
+	},
+	OVHProvider: {
+		Label:              "OVHcloud",
+		EndpointTemplate:   "s3.{region}.io.cloud.ovh.net",
+		DefaultRegion:      "gra",
+		CopySourceEscaping: EscapeCopySourceRawSpaces,
+		SupportsACL:        true,
+	},
+	ScalewayProvider: {
+		Label:            "Scaleway",
+		EndpointTemplate: "s3.{region}.scw.cloud",
+		DefaultRegion:    "fr-par",
+		SupportsACL:      true,
+	},
+	GCSProvider: {
+		Label:            "Google Cloud Storage (interoperability)",
+		EndpointTemplate: "storage.googleapis.com",
+		DefaultRegion:    "auto",
+	},
+	CephProvider: {
+		Label:         "Ceph RGW",
+		DefaultRegion: "us-east-1",
+		SupportsACL:   true,
+	},
+}
+
+// Preset returns the description of the provider, the generic S3-like one for an unknown provider.
+func (c Provider) Preset() ProviderPreset {
+	if p, ok := providerPresets[c]; ok {
+		return p
 	}
+	return providerPresets[DefaultProvider]
 }
@@ -0,0 +1,52 @@
+package connection_deck_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func TestNewProviderFromString(t *testing.T) {
+	t.Run("should read a provider with a preset", func(t *testing.T) {
+		// When
+		res := connection_deck.NewProviderFromString("Scaleway")
+
+		// Then
+		assert.Equal(t, connection_deck.ScalewayProvider, res)
+	})
+
+	t.Run("should fall back to the generic S3-like provider", func(t *testing.T) {
+		// When
+		res := connection_deck.NewProviderFromString("unknown")
+
+		// Then
+		assert.Equal(t, connection_deck.S3LikeProvider, res)
+	})
+}
+
+func TestProviderPreset_Endpoint(t *testing.T) {
+	t.Run("should fill in the region of the endpoint template", func(t *testing.T) {
+		// When
+		res := connection_deck.WasabiProvider.Preset().Endpoint("eu-central-1")
+
+		// Then
+		assert.Equal(t, "s3.eu-central-1.wasabisys.com", res)
+	})
+
+	t.Run("should use the default region of the provider", func(t *testing.T) {
+		// When
+		res := connection_deck.OVHProvider.Preset().Endpoint("")
+
+		// Then
+		assert.Equal(t, "s3.gra.io.cloud.ovh.net", res)
+	})
+
+	t.Run("should have no endpoint for a self-hosted server", func(t *testing.T) {
+		// When
+		res := connection_deck.MinIOProvider.Preset().Endpoint("")
+
+		// Then
+		assert.Empty(t, res)
+	})
+}
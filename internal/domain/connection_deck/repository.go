@@ -5,7 +5,38 @@ import (
 	"io"
 )
 
+// ExportMode tells how the credentials of the connections are exported.
+type ExportMode int
+
+const (
+	// ExportWithSecrets writes the credentials in clear
+	ExportWithSecrets ExportMode = iota
+	// ExportWithoutSecrets leaves the credentials out of the export
+	ExportWithoutSecrets
+	// ExportEncrypted encrypts the whole export with a passphrase
+	ExportEncrypted
+)
+
+type ExportOptions struct {
+	Mode ExportMode
+	// Passphrase encrypts the export in the ExportEncrypted mode
+	Passphrase string
+}
+
+type ImportOptions struct {
+	Source ImportSource
+	// Passphrase decrypts an encrypted JSON export
+	Passphrase string
+}
+
 type Repository interface {
 	Get(ctx context.Context) (*Deck, error)
-	Export(ctx context.Context, file io.Writer) error
+	Export(ctx context.Context, file io.Writer, options ExportOptions) error
+	// Import reads the connections of the file in the given format, without saving them.
+	// When file is nil, they are read from the default location of the format, if it has one.
+	Import(ctx context.Context, file io.Reader, options ImportOptions) ([]*Connection, error)
+	// ProtectSecrets moves the credentials of the connections to a secret store created with the passphrase.
+	ProtectSecrets(ctx context.Context, passphrase string) error
+	// SecretsProtected returns true when the credentials are kept in a secret store.
+	SecretsProtected() bool
 }
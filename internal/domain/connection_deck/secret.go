@@ -0,0 +1,23 @@
+package connection_deck
+
+import "context"
+
+// SecretStore keeps the credentials of the connections apart from the saved deck,
+// which then only holds references to them.
+type SecretStore interface {
+	// IsEnabled returns true once the store has been created with its master passphrase.
+	IsEnabled() bool
+
+	// IsLocked returns true until the store is unlocked with its master passphrase.
+	IsLocked() bool
+
+	// Unlock opens the store with its master passphrase. The store is created on its first unlock.
+	// ErrWrongPassphrase is returned when the passphrase doesn't match the one of the store.
+	Unlock(passphrase string) error
+
+	Get(ctx context.Context, ref string) (string, error)
+
+	Set(ctx context.Context, ref, secret string) error
+
+	Delete(ctx context.Context, ref string) error
+}
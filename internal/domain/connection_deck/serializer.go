@@ -9,15 +9,19 @@ var (
 
 func (c *Connection) MarshalJSON() ([]byte, error) {
 	return json.Marshal(map[string]any{
-		"id":       c.ID().String(),
-		"name":     c.Name(),
-		"bucket":   c.Bucket(),
-		"server":   c.Server(),
-		"region":   c.Region(),
-		"provider": c.Provider().String(),
-		"readOnly": c.ReadOnly(),
-		"revision": c.Revision(),
-		"tls":      c.useTLS,
+		"id":               c.ID().String(),
+		"name":             c.Name(),
+		"bucket":           c.Bucket(),
+		"server":           c.Server(),
+		"region":           c.Region(),
+		"provider":         c.Provider().String(),
+		"readOnly":         c.ReadOnly(),
+		"revision":         c.Revision(),
+		"tls":              c.useTLS,
+		"credentialSource": c.credentialSource.Type.String(),
+		"accountLevel":     c.accountLevel,
+		"insecureTLS":      c.transport.InsecureSkipVerify,
+		"rootPrefix":       c.rootPrefix,
 	})
 }
 
@@ -0,0 +1,52 @@
+package connection_deck
+
+import "time"
+
+// AddressingStyle is how the bucket is addressed in the requests URL.
+type AddressingStyle string
+
+const (
+	// AddressingAuto uses the style of the provider
+	AddressingAuto AddressingStyle = ""
+	// AddressingPath puts the bucket in the path, as in https://server/bucket/key
+	AddressingPath AddressingStyle = "path"
+	// AddressingVirtualHosted puts the bucket in the host name, as in https://bucket.server/key
+	AddressingVirtualHosted AddressingStyle = "virtual-hosted"
+)
+
+// AddressingStyles are all the addressing styles, in the order they are offered.
+var AddressingStyles = []AddressingStyle{
+	AddressingAuto,
+	AddressingPath,
+	AddressingVirtualHosted,
+}
+
+func (s AddressingStyle) String() string {
+	return string(s)
+}
+
+// NewAddressingStyleFromString returns the style with the given name, AddressingAuto when it is unknown.
+func NewAddressingStyleFromString(s string) AddressingStyle {
+	switch AddressingStyle(s) {
+	case AddressingPath, AddressingVirtualHosted:
+		return AddressingStyle(s)
+	default:
+		return AddressingAuto
+	}
+}
+
+// TransportOptions tune how the requests of a connection reach the server.
+// The zero value uses the system certificates, the proxy of the environment and the style of the provider.
+type TransportOptions struct {
+	// CABundle is the path of a PEM file, or the PEM content itself, with the certificates
+	// of the authorities trusted in addition to the system ones
+	CABundle string
+	// InsecureSkipVerify accepts any certificate, which exposes the connection to man-in-the-middle attacks
+	InsecureSkipVerify bool
+	// ProxyURL is the URL of an HTTP, HTTPS or SOCKS5 proxy, as in socks5://localhost:1080
+	ProxyURL        string
+	AddressingStyle AddressingStyle
+	// Timeout limits the wait for the server at each request, to connect and to receive the response headers,
+	// but not the transfer of the bodies; no limit when zero
+	Timeout time.Duration
+}
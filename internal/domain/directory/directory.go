@@ -33,6 +33,8 @@ type Directory struct {
 	name         string
 	parent       *Directory
 	isOpen       bool
+	// sizeStats caches the last computed size until the next load
+	sizeStats *SizeStats
 
 	currentState state
 }
@@ -41,6 +43,24 @@ func NewRoot(connectionID connection_deck.ConnectionID) (*Directory, error) {
 	return New(connectionID, RootDirName, nil)
 }
 
+// NewFromPath creates a directory along with all its parents from its path, none of them being loaded.
+// It allows targeting a directory that isn't displayed in the explorer, like a directory of another connection.
+func NewFromPath(connectionID connection_deck.ConnectionID, path Path) (*Directory, error) {
+	d, err := NewRoot(connectionID)
+	if err != nil {
+		return nil, err
+	}
+	if path == RootPath || path == NilParentPath {
+		return d, nil
+	}
+	for _, name := range path.Split() {
+		if d, err = New(connectionID, name, d); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
 // New creates a new S3 directory entity.
 // An error is returned when the directory name is not valid
 func New(
@@ -180,6 +200,7 @@ func (d *Directory) NewFile(name string, overwrite bool, opts ...FileOption) (ev
 		File:         file,
 		Directory:    d,
 		ConnectionID: d.ConnectionID(),
+		Overwrite:    overwrite,
 	}), nil
 }
 
@@ -229,6 +250,63 @@ func (d *Directory) RemoveSubDirectory(name string) (event.Event, error) {
 	), nil
 }
 
+// RemoveSubDirectoryRecursively triggers the deletion of a subdirectory along with all its content.
+// Unlike RemoveSubDirectory, the subdirectory doesn't need to be empty.
+func (d *Directory) RemoveSubDirectoryRecursively(name string, opts ...event.Option) (event.Event, error) {
+	sd, err := d.GetSubDirectoryByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.New(DeleteTriggered{
+		Directory:      d,
+		DeletedDirPath: sd.Path(),
+		Recursive:      true,
+	}, opts...), nil
+}
+
+// Download triggers the download of the directory and all its content into the local directory dstPath.
+// Local files that already exist are kept or overwritten according to the strategy.
+func (d *Directory) Download(dstPath string, strategy MaterializeStrategy, opts ...event.Option) event.Event {
+	return event.New(DownloadTriggered{
+		Directory: d,
+		DstPath:   dstPath,
+		Strategy:  strategy,
+	}, opts...)
+}
+
+// Search triggers a recursive search of the files under the directory matching the query.
+// The results are streamed with SearchProgress events, the search can be canceled through the event context.
+func (d *Directory) Search(query SearchQuery, opts ...event.Option) (event.Event, error) {
+	if _, err := query.Matcher(); err != nil {
+		return nil, err
+	}
+	return event.New(SearchTriggered{
+		Directory: d,
+		Query:     query,
+	}, opts...), nil
+}
+
+// CopyTo triggers the copy of the directory and all its content into the destination directory.
+// The destination may belong to another connection.
+func (d *Directory) CopyTo(dst *Directory, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	sel := NewSelection(d.ConnectionID())
+	if err := sel.AddDirectory(d); err != nil {
+		return nil, err
+	}
+	return sel.CopyTo(dst, strategy, opts...)
+}
+
+// MoveTo triggers the move of the directory and all its content into the destination directory.
+// The destination may belong to another connection.
+func (d *Directory) MoveTo(dst *Directory, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	sel := NewSelection(d.ConnectionID())
+	if err := sel.AddDirectory(d); err != nil {
+		return nil, err
+	}
+	return sel.MoveTo(dst, strategy, opts...)
+}
+
 func (d *Directory) IsEmpty() bool {
 	return len(d.currentState.Files()) == 0 && len(d.currentState.SubDirectories()) == 0
 }
@@ -315,8 +393,41 @@ func (d *Directory) HasError() bool {
 	return d.currentState.Type() == stateTypeError
 }
 
-func (d *Directory) Load() (event.Event, error) {
-	return d.currentState.Load()
+func (d *Directory) Load(opts ...LoadOption) (event.Event, error) {
+	evt, err := d.currentState.Load(opts...)
+	if err == nil {
+		d.sizeStats = nil
+	}
+	return evt, err
+}
+
+// ComputeSize triggers a recursive listing of the directory to compute its size statistics.
+// The computation can be canceled through the event context.
+func (d *Directory) ComputeSize(opts ...event.Option) event.Event {
+	return event.New(ComputeSizeTriggered{Directory: d}, opts...)
+}
+
+// SizeStats returns the last computed size statistics, if not invalidated by a load since then.
+func (d *Directory) SizeStats() (SizeStats, bool) {
+	if d.sizeStats == nil {
+		return SizeStats{}, false
+	}
+	return *d.sizeStats, true
+}
+
+func (d *Directory) SetSizeStats(stats SizeStats) {
+	d.sizeStats = &stats
+}
+
+// LoadNextPage triggers the loading of the next page of a partially loaded directory.
+// Returns ErrNoMorePages when the whole content is already loaded.
+func (d *Directory) LoadNextPage() (event.Event, error) {
+	return d.currentState.LoadNextPage()
+}
+
+// HasMorePages tells if the directory is partially loaded, with more content to load.
+func (d *Directory) HasMorePages() bool {
+	return d.currentState.HasMorePages()
 }
 
 func (d *Directory) IsOpened() bool {
@@ -15,6 +15,19 @@ import (
 )
 
 func TestDirectory(t *testing.T) {
+	t.Run("should ask to load the deleted files when requested", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+
+		// When
+		evt, err := dir.Load(directory.WithDeletedFiles())
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.LoadTriggered)
+		assert.True(t, pl.IncludeDeleted)
+	})
+
 	t.Run("should change directory states", func(t *testing.T) {
 		// Given
 		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
@@ -67,6 +80,30 @@ func TestDirectory(t *testing.T) {
 	})
 }
 
+func TestNewFromPath(t *testing.T) {
+	t.Run("should create the directory with all its parents", func(t *testing.T) {
+		// When
+		dir, err := directory.NewFromPath(tu.FakeAwsConnectionId, "/archive/2024/")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.Path("/archive/2024/"), dir.Path())
+		assert.Equal(t, tu.FakeAwsConnectionId, dir.ConnectionID())
+		assert.Equal(t, directory.Path("/archive/"), dir.Parent().Path())
+		assert.True(t, dir.Parent().Parent().IsRoot())
+		assert.False(t, dir.IsLoaded())
+	})
+
+	t.Run("should create the root directory", func(t *testing.T) {
+		// When
+		dir, err := directory.NewFromPath(tu.FakeAwsConnectionId, directory.RootPath)
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, dir.IsRoot())
+	})
+}
+
 func TestDirectory_Load(t *testing.T) {
 	t.Run("should load then update directory content on success", func(t *testing.T) {
 		// Given
@@ -147,6 +184,93 @@ func TestDirectory_Load(t *testing.T) {
 	})
 }
 
+func TestDirectory_LoadNextPage(t *testing.T) {
+	loadFirstPage := func(t *testing.T, dir *directory.Directory, nextPageToken string) {
+		t.Helper()
+		_, err := dir.Load()
+		require.NoError(t, err)
+		f1, _ := directory.NewFile("a.txt", dir)
+		require.NoError(t, dir.Notify(event.New(directory.LoadSucceeded{
+			Directory:     dir,
+			Files:         []*directory.File{f1},
+			NextPageToken: nextPageToken,
+		})))
+	}
+
+	t.Run("should append the next page to the already loaded content", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		loadFirstPage(t, dir, "token-1")
+		require.True(t, dir.HasMorePages())
+
+		sub, _ := directory.New(connection_deck.NewConnectionID(), "sub", dir)
+		f2, _ := directory.NewFile("b.txt", dir)
+
+		// When
+		evt, err := dir.LoadNextPage()
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.LoadPageTriggeredType, evt.Type())
+		assert.Equal(t, "token-1", evt.Payload().(directory.LoadPageTriggered).PageToken)
+		assert.True(t, dir.IsLoading())
+		assert.Len(t, dir.Files(), 1)
+
+		require.NoError(t, dir.Notify(event.New(directory.LoadPageSucceeded{
+			Directory:      dir,
+			Files:          []*directory.File{f2},
+			SubDirectories: []*directory.Directory{sub},
+		})))
+		assert.True(t, dir.IsLoaded())
+		assert.False(t, dir.HasMorePages())
+		require.Len(t, dir.Files(), 2)
+		assert.Equal(t, directory.FileName("a.txt"), dir.Files()[0].Name())
+		assert.Equal(t, directory.FileName("b.txt"), dir.Files()[1].Name())
+		assert.Len(t, dir.SubDirectories(), 1)
+	})
+
+	t.Run("should keep the loaded content and the cursor when the page loading fails", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		loadFirstPage(t, dir, "token-1")
+		_, err := dir.LoadNextPage()
+		require.NoError(t, err)
+
+		// When
+		err = dir.Notify(event.New(directory.LoadPageFailed{Err: errors.New("boom"), Directory: dir}))
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, dir.IsLoaded())
+		assert.True(t, dir.HasMorePages())
+		assert.Len(t, dir.Files(), 1)
+	})
+
+	t.Run("should return an error when the whole content is already loaded", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		loadFirstPage(t, dir, "")
+
+		// When
+		_, err := dir.LoadNextPage()
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrNoMorePages)
+		assert.False(t, dir.HasMorePages())
+	})
+
+	t.Run("should return an error when the directory is not loaded", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+
+		// When
+		_, err := dir.LoadNextPage()
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrNotLoaded)
+	})
+}
+
 func TestDirectory_NewFile(t *testing.T) {
 	t.Run("should create a file and add it to the directory on success", func(t *testing.T) {
 		// Given
@@ -452,6 +576,86 @@ func TestDirectory_RemoveSubDirectory(t *testing.T) {
 	})
 }
 
+func TestDirectory_RemoveSubDirectoryRecursively(t *testing.T) {
+	t.Run("should return error when subdirectory does not exist", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded())
+
+		// When
+		_, err := dir.RemoveSubDirectoryRecursively("missing")
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrNotFound)
+	})
+
+	t.Run("should trigger a recursive deletion without reloading the subdirectory", func(t *testing.T) {
+		// Given
+		var subdir *directory.Directory
+		dir := tu.MakeDirectory(t, "mydir",
+			tu.WithRootParent(),
+			tu.WithSubDirectory("subdir",
+				tu.To(&subdir),
+				tu.IsLoaded(),
+				tu.WithFile("file1.txt")))
+
+		// When
+		evt, err := dir.RemoveSubDirectoryRecursively("subdir")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, directory.DeleteTriggered{
+			Directory:      dir,
+			DeletedDirPath: "/mydir/subdir/",
+			Recursive:      true,
+		}, evt.Payload())
+	})
+
+	t.Run("should keep the subdirectory when the deletion is uncompleted", func(t *testing.T) {
+		// Given
+		var subdir *directory.Directory
+		dir := tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithSubDirectory("subdir",
+				tu.To(&subdir)))
+
+		failureEvt := event.New(directory.DeleteFailed{
+			Err: directory.UncompletedDelete{
+				DirPath:      subdir.Path(),
+				DeletedCount: 3,
+				FailedKeys:   map[string]error{"subdir/file1.txt": errors.New("ckc")},
+			},
+			Parent:    dir,
+			Directory: subdir,
+		})
+
+		// When
+		assert.NoError(t, dir.Notify(failureEvt))
+
+		// Then
+		assert.Len(t, dir.SubDirectories(), 1)
+	})
+}
+
+func TestDirectory_Download(t *testing.T) {
+	t.Run("should trigger the download of the directory with the chosen strategy", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "mydir",
+			tu.WithRootParent())
+
+		// When
+		evt := dir.Download("/tmp/local", directory.MaterializeReplace)
+
+		// Then
+		assert.Equal(t, directory.DownloadTriggered{
+			Directory: dir,
+			DstPath:   "/tmp/local",
+			Strategy:  directory.MaterializeReplace,
+		}, evt.Payload())
+	})
+}
+
 func TestDirectory_UploadFile(t *testing.T) {
 	t.Run("should emit upload event and add file on success", func(t *testing.T) {
 		// Given
@@ -591,6 +795,26 @@ func TestDirectory_UploadFile(t *testing.T) {
 		// Then
 		assert.ErrorIs(t, err, directory.ErrNotLoaded)
 	})
+
+	t.Run("should leave the existence check to the server on a partially loaded directory", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		_, err := dir.Load()
+		require.NoError(t, err)
+		require.NoError(t, dir.Notify(event.New(directory.LoadSucceeded{
+			Directory:     dir,
+			NextPageToken: "token-1",
+		})))
+		require.True(t, dir.HasMorePages())
+
+		// When
+		evt, err := dir.UploadFile("local/report.csv", false)
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.UploadFileTriggered)
+		assert.False(t, pl.Overwrite)
+	})
 }
 
 func TestDirectory_Rename(t *testing.T) {
@@ -816,4 +1040,37 @@ func TestDirectory_Resume(t *testing.T) {
 		assert.Equal(t, newDir, res.DstDir)
 		assert.Equal(t, oldDir, res.Directory)
 	})
+
+	t.Run("should returns a move recovery event with the path of the other directory when a move between directories has been interrupted", func(t *testing.T) {
+		// Given
+		root := tu.FakeLoadedRootDirectory(t)
+		srcDir := tu.AddSubNotLoadedDirectoryToDirectory(t, root, "mydir")
+		tu.AddSubNotLoadedDirectoryToDirectory(t, root, "mydir2")
+
+		_, err := srcDir.Load()
+		require.NoError(t, err)
+		require.NoError(t, srcDir.Notify(event.New(directory.LoadFailed{
+			Err: directory.UncompletedRename{
+				SourceDirPath:      "/mydir/",
+				DestinationDirPath: "/archive/mydir/",
+			},
+			Directory: srcDir,
+		})))
+
+		status, ok := srcDir.Status().(directory.RenameFailedStatus)
+		require.True(t, ok)
+		assert.True(t, status.IsMove())
+		assert.Equal(t, "Move pending", status.Title())
+
+		// When
+		evt, err := srcDir.Recover(directory.RecoveryChoiceRenameResume)
+
+		// Then
+		require.NoError(t, err)
+		res := evt.Payload().(directory.RenameRecoveryTriggered)
+		assert.Equal(t, srcDir, res.Directory)
+		assert.Nil(t, res.DstDir)
+		assert.Equal(t, directory.Path("/archive/mydir/"), res.OtherDirPath)
+		assert.True(t, srcDir.IsLoading())
+	})
 }
@@ -14,6 +14,14 @@ var (
 	ErrRenameInterrupted = errors.New("rename interrupted")
 	ErrNotEmpty          = errors.New("directory not empty")
 	ErrTimeout           = errors.New("timeout occurred")
+	ErrCanceled          = errors.New("operation canceled")
+	ErrReadOnly          = errors.New("connection is read-only")
+	ErrInvalidMetadata   = errors.New("invalid metadata")
+	ErrInvalidTags       = errors.New("invalid tags")
+	ErrInvalidShareLink  = errors.New("invalid share link")
+	ErrInvalidVersion    = errors.New("invalid file version")
+	ErrNoMorePages       = errors.New("the whole directory content is already loaded")
+	ErrInvalidSearch     = errors.New("invalid search")
 )
 
 type Error struct {
@@ -42,3 +50,91 @@ func (e UncompletedRename) Error() string {
 	}
 	return msg
 }
+
+// UncompletedDelete reports a recursive deletion that stopped before removing every object,
+// either because some objects failed to be deleted or because the operation was canceled.
+type UncompletedDelete struct {
+	DirPath      Path
+	DeletedCount int
+	FailedKeys   map[string]error
+	Wrapped      error
+}
+
+func (e UncompletedDelete) Error() string {
+	msg := fmt.Sprintf("uncompleted deletion of %s: %d objects deleted, %d failed",
+		e.DirPath, e.DeletedCount, len(e.FailedKeys))
+	if e.Wrapped != nil {
+		msg += fmt.Sprintf(": %s", e.Wrapped.Error())
+	}
+	return msg
+}
+
+func (e UncompletedDelete) Unwrap() error {
+	return e.Wrapped
+}
+
+// UncompletedDownload reports a directory download that stopped before fetching every object,
+// either because some objects failed to be downloaded or because the operation was canceled.
+type UncompletedDownload struct {
+	DirPath         Path
+	DownloadedCount int
+	FailedKeys      map[string]error
+	Wrapped         error
+}
+
+func (e UncompletedDownload) Error() string {
+	msg := fmt.Sprintf("uncompleted download of %s: %d objects downloaded, %d failed",
+		e.DirPath, e.DownloadedCount, len(e.FailedKeys))
+	if e.Wrapped != nil {
+		msg += fmt.Sprintf(": %s", e.Wrapped.Error())
+	}
+	return msg
+}
+
+func (e UncompletedDownload) Unwrap() error {
+	return e.Wrapped
+}
+
+// UncompletedSelection reports a bulk operation on a selection that stopped before processing every object,
+// either because some objects failed or because the operation was canceled.
+type UncompletedSelection struct {
+	Operation  string
+	DoneCount  int
+	FailedKeys map[string]error
+	Wrapped    error
+}
+
+func (e UncompletedSelection) Error() string {
+	msg := fmt.Sprintf("uncompleted %s of the selection: %d objects processed, %d failed",
+		e.Operation, e.DoneCount, len(e.FailedKeys))
+	if e.Wrapped != nil {
+		msg += fmt.Sprintf(": %s", e.Wrapped.Error())
+	}
+	return msg
+}
+
+func (e UncompletedSelection) Unwrap() error {
+	return e.Wrapped
+}
+
+// UncompletedSync reports a sync that stopped before applying every change of its plan,
+// either because some files failed to be copied or deleted or because the operation was canceled.
+type UncompletedSync struct {
+	LocalPath   string
+	DoneCount   int
+	FailedPaths map[string]error
+	Wrapped     error
+}
+
+func (e UncompletedSync) Error() string {
+	msg := fmt.Sprintf("uncompleted sync with %s: %d files synced, %d failed",
+		e.LocalPath, e.DoneCount, len(e.FailedPaths))
+	if e.Wrapped != nil {
+		msg += fmt.Sprintf(": %s", e.Wrapped.Error())
+	}
+	return msg
+}
+
+func (e UncompletedSync) Unwrap() error {
+	return e.Wrapped
+}
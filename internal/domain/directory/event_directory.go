@@ -38,6 +38,7 @@ const (
 	DeleteTriggeredType event.Type = "event.directory.delete.triggered"
 	DeleteSucceededType event.Type = "event.directory.delete.succeeded"
 	DeleteFailedType    event.Type = "event.directory.delete.failed"
+	DeleteProgressType  event.Type = "event.directory.delete.progress"
 )
 
 type DeleteTriggered struct {
@@ -46,6 +47,8 @@ type DeleteTriggered struct {
 	// DeletedDirPath is the path of the deleted subdirectory.
 	// The subdirectory must exists.
 	DeletedDirPath Path
+	// Recursive is true when all the objects under the deleted subdirectory must be removed too.
+	Recursive bool
 }
 
 func (e DeleteTriggered) EventType() event.Type {
@@ -57,6 +60,8 @@ type DeleteSucceeded struct {
 	Directory *Directory
 	// Parent is the directory from which the deleted directory was removed
 	Parent *Directory
+	// DeletedCount is the number of objects removed from the bucket
+	DeletedCount int
 }
 
 func (e DeleteSucceeded) EventType() event.Type {
@@ -75,6 +80,60 @@ func (e DeleteFailed) EventType() event.Type {
 	return DeleteFailedType
 }
 
+// DeleteProgress is emitted while a recursive deletion is running, after each batch of objects.
+type DeleteProgress struct {
+	// Parent is the directory from which the subdirectory is being removed
+	Parent *Directory
+	// Directory is the directory being deleted
+	Directory *Directory
+	// DeletedCount is the number of objects removed so far
+	DeletedCount int
+	// FailedCount is the number of objects that could not be removed so far
+	FailedCount int
+}
+
+func (e DeleteProgress) EventType() event.Type {
+	return DeleteProgressType
+}
+
+const (
+	LoadPageTriggeredType event.Type = "event.directory.load.page.triggered"
+	LoadPageSucceededType event.Type = "event.directory.load.page.succeeded"
+	LoadPageFailedType    event.Type = "event.directory.load.page.failed"
+)
+
+// LoadPageTriggered asks to load the next page of a partially loaded directory.
+type LoadPageTriggered struct {
+	Directory *Directory
+	PageToken string
+}
+
+func (e LoadPageTriggered) EventType() event.Type {
+	return LoadPageTriggeredType
+}
+
+// LoadPageSucceeded holds the content of a page, to be appended to the already loaded content of the directory.
+type LoadPageSucceeded struct {
+	Directory      *Directory
+	Files          []*File
+	SubDirectories []*Directory
+	// NextPageToken is empty when this page was the last one
+	NextPageToken string
+}
+
+func (e LoadPageSucceeded) EventType() event.Type {
+	return LoadPageSucceededType
+}
+
+type LoadPageFailed struct {
+	Err       error
+	Directory *Directory
+}
+
+func (e LoadPageFailed) EventType() event.Type {
+	return LoadPageFailedType
+}
+
 const (
 	LoadTriggeredType event.Type = "event.directory.load.triggered"
 	LoadSucceededType event.Type = "event.directory.load.succeeded"
@@ -83,6 +142,10 @@ const (
 
 type LoadTriggered struct {
 	Directory *Directory
+	// IncludeDeleted asks to also list the files whose latest version is a delete marker
+	IncludeDeleted bool
+	// AllPages asks to list the whole directory instead of its first page
+	AllPages bool
 }
 
 func (e LoadTriggered) EventType() event.Type {
@@ -93,6 +156,9 @@ type LoadSucceeded struct {
 	Directory      *Directory
 	Files          []*File
 	SubDirectories []*Directory
+	// NextPageToken is set when the directory content is too large to be loaded at once.
+	// The next page is loaded with Directory.LoadNextPage.
+	NextPageToken string
 }
 
 func (e LoadSucceeded) EventType() event.Type {
@@ -150,6 +216,10 @@ type RenameRecoveryTriggered struct {
 	Directory *Directory
 	DstDir    *Directory
 	Choice    RecoveryChoice
+
+	// OtherDirPath is set when an interrupted move is recovered: the other directory of the operation
+	// lives under another parent, so it is given by its path and the matching Directory or DstDir is nil.
+	OtherDirPath Path
 }
 
 func (e RenameRecoveryTriggered) EventType() event.Type {
@@ -219,3 +289,223 @@ type UploadSucceeded LoadSucceeded
 func (e UploadSucceeded) EventType() event.Type {
 	return UploadSucceededType
 }
+
+const (
+	DownloadTriggeredType event.Type = "event.directory.download.triggered"
+	DownloadProgressType  event.Type = "event.directory.download.progress"
+	DownloadSucceededType event.Type = "event.directory.download.succeeded"
+	DownloadFailedType    event.Type = "event.directory.download.failed"
+)
+
+type DownloadTriggered struct {
+	Directory *Directory
+	// DstPath is the local directory in which the downloaded directory is recreated
+	DstPath string
+	// Strategy tells what to do with the local files that already exist
+	Strategy MaterializeStrategy
+}
+
+func (e DownloadTriggered) EventType() event.Type {
+	return DownloadTriggeredType
+}
+
+// DownloadProgress is emitted while a directory is being downloaded, after each batch of objects.
+type DownloadProgress struct {
+	Directory       *Directory
+	DownloadedCount int
+	SkippedCount    int
+	FailedCount     int
+}
+
+func (e DownloadProgress) EventType() event.Type {
+	return DownloadProgressType
+}
+
+type DownloadSucceeded struct {
+	Directory *Directory
+	// DstPath is the local path of the downloaded directory
+	DstPath         string
+	DownloadedCount int
+	SkippedCount    int
+}
+
+func (e DownloadSucceeded) EventType() event.Type {
+	return DownloadSucceededType
+}
+
+type DownloadFailed struct {
+	Err       error
+	Directory *Directory
+}
+
+func (e DownloadFailed) EventType() event.Type {
+	return DownloadFailedType
+}
+
+const (
+	SearchTriggeredType event.Type = "event.directory.search.triggered"
+	SearchProgressType  event.Type = "event.directory.search.progress"
+	SearchSucceededType event.Type = "event.directory.search.succeeded"
+	SearchFailedType    event.Type = "event.directory.search.failed"
+)
+
+type SearchTriggered struct {
+	Directory *Directory
+	Query     SearchQuery
+}
+
+func (e SearchTriggered) EventType() event.Type {
+	return SearchTriggeredType
+}
+
+// SearchProgress is emitted while the search is running, after each listed page of objects.
+// Hits only holds the files found in the last page.
+type SearchProgress struct {
+	Directory    *Directory
+	Hits         []SearchHit
+	ScannedCount int
+}
+
+func (e SearchProgress) EventType() event.Type {
+	return SearchProgressType
+}
+
+type SearchSucceeded struct {
+	Directory    *Directory
+	HitCount     int
+	ScannedCount int
+}
+
+func (e SearchSucceeded) EventType() event.Type {
+	return SearchSucceededType
+}
+
+type SearchFailed struct {
+	Err       error
+	Directory *Directory
+}
+
+func (e SearchFailed) EventType() event.Type {
+	return SearchFailedType
+}
+
+const (
+	ComputeSizeTriggeredType event.Type = "event.directory.size.compute.triggered"
+	ComputeSizeProgressType  event.Type = "event.directory.size.compute.progress"
+	ComputeSizeSucceededType event.Type = "event.directory.size.compute.succeeded"
+	ComputeSizeFailedType    event.Type = "event.directory.size.compute.failed"
+)
+
+type ComputeSizeTriggered struct {
+	Directory *Directory
+}
+
+func (e ComputeSizeTriggered) EventType() event.Type {
+	return ComputeSizeTriggeredType
+}
+
+// ComputeSizeProgress is emitted while the size is being computed, after each listed page of objects.
+type ComputeSizeProgress struct {
+	Directory   *Directory
+	Bytes       uint64
+	ObjectCount int
+}
+
+func (e ComputeSizeProgress) EventType() event.Type {
+	return ComputeSizeProgressType
+}
+
+type ComputeSizeSucceeded struct {
+	Directory *Directory
+	Stats     SizeStats
+}
+
+func (e ComputeSizeSucceeded) EventType() event.Type {
+	return ComputeSizeSucceededType
+}
+
+type ComputeSizeFailed struct {
+	Err       error
+	Directory *Directory
+}
+
+func (e ComputeSizeFailed) EventType() event.Type {
+	return ComputeSizeFailedType
+}
+
+const (
+	SyncPreviewTriggeredType event.Type = "event.directory.sync.preview.triggered"
+	SyncPreviewSucceededType event.Type = "event.directory.sync.preview.succeeded"
+	SyncPreviewFailedType    event.Type = "event.directory.sync.preview.failed"
+	SyncTriggeredType        event.Type = "event.directory.sync.triggered"
+	SyncProgressType         event.Type = "event.directory.sync.progress"
+	SyncSucceededType        event.Type = "event.directory.sync.succeeded"
+	SyncFailedType           event.Type = "event.directory.sync.failed"
+)
+
+type SyncPreviewTriggered struct {
+	Directory *Directory
+	// LocalPath is the local folder compared with the directory
+	LocalPath string
+	Options   SyncOptions
+}
+
+func (e SyncPreviewTriggered) EventType() event.Type {
+	return SyncPreviewTriggeredType
+}
+
+type SyncPreviewSucceeded struct {
+	Plan *SyncPlan
+}
+
+func (e SyncPreviewSucceeded) EventType() event.Type {
+	return SyncPreviewSucceededType
+}
+
+type SyncPreviewFailed struct {
+	Err       error
+	Directory *Directory
+}
+
+func (e SyncPreviewFailed) EventType() event.Type {
+	return SyncPreviewFailedType
+}
+
+type SyncTriggered struct {
+	Plan *SyncPlan
+}
+
+func (e SyncTriggered) EventType() event.Type {
+	return SyncTriggeredType
+}
+
+// SyncProgress is emitted while a sync plan is applied, after each copied or deleted file.
+type SyncProgress struct {
+	Plan        *SyncPlan
+	DoneCount   int
+	FailedCount int
+	Total       int
+}
+
+func (e SyncProgress) EventType() event.Type {
+	return SyncProgressType
+}
+
+type SyncSucceeded struct {
+	Plan         *SyncPlan
+	CopiedCount  int
+	DeletedCount int
+}
+
+func (e SyncSucceeded) EventType() event.Type {
+	return SyncSucceededType
+}
+
+type SyncFailed struct {
+	Err  error
+	Plan *SyncPlan
+}
+
+func (e SyncFailed) EventType() event.Type {
+	return SyncFailedType
+}
@@ -1,6 +1,8 @@
 package directory
 
 import (
+	"time"
+
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 )
@@ -15,6 +17,8 @@ type CreateFileTriggered struct {
 	File         *File
 	ConnectionID connection_deck.ConnectionID
 	Directory    *Directory
+	// Overwrite replaces an existing object, otherwise the creation fails with ErrAlreadyExists
+	Overwrite bool
 	//Recursive    bool
 }
 
@@ -153,6 +157,9 @@ const (
 type UploadFileTriggered struct {
 	Directory *Directory
 	SrcPath   string
+	// Overwrite replaces an existing object, otherwise the upload fails with ErrAlreadyExists.
+	// The directory may be partially loaded, the object is looked up in the bucket.
+	Overwrite bool
 }
 
 func (e UploadFileTriggered) EventType() event.Type {
@@ -208,3 +215,270 @@ type DownloadFileFailed struct {
 func (e DownloadFileFailed) EventType() event.Type {
 	return DownloadFileFailedType
 }
+
+const (
+	LoadFileMetadataTriggeredType event.Type = "event.file.metadata.load.triggered"
+	LoadFileMetadataSucceededType event.Type = "event.file.metadata.load.succeeded"
+	LoadFileMetadataFailedType    event.Type = "event.file.metadata.load.failed"
+)
+
+type LoadFileMetadataTriggered struct {
+	File *File
+}
+
+func (e LoadFileMetadataTriggered) EventType() event.Type {
+	return LoadFileMetadataTriggeredType
+}
+
+type LoadFileMetadataSucceeded struct {
+	File     *File
+	Metadata ObjectMetadata
+}
+
+func (e LoadFileMetadataSucceeded) EventType() event.Type {
+	return LoadFileMetadataSucceededType
+}
+
+type LoadFileMetadataFailed struct {
+	Err  error
+	File *File
+}
+
+func (e LoadFileMetadataFailed) EventType() event.Type {
+	return LoadFileMetadataFailedType
+}
+
+const (
+	UpdateFileMetadataTriggeredType event.Type = "event.file.metadata.update.triggered"
+	UpdateFileMetadataSucceededType event.Type = "event.file.metadata.update.succeeded"
+	UpdateFileMetadataFailedType    event.Type = "event.file.metadata.update.failed"
+)
+
+type UpdateFileMetadataTriggered struct {
+	File     *File
+	Metadata ObjectMetadata
+}
+
+func (e UpdateFileMetadataTriggered) EventType() event.Type {
+	return UpdateFileMetadataTriggeredType
+}
+
+// UpdateFileMetadataSucceeded carries the metadata read back from the storage once updated.
+type UpdateFileMetadataSucceeded struct {
+	File     *File
+	Metadata ObjectMetadata
+}
+
+func (e UpdateFileMetadataSucceeded) EventType() event.Type {
+	return UpdateFileMetadataSucceededType
+}
+
+type UpdateFileMetadataFailed struct {
+	Err  error
+	File *File
+}
+
+func (e UpdateFileMetadataFailed) EventType() event.Type {
+	return UpdateFileMetadataFailedType
+}
+
+const (
+	LoadFileTagsTriggeredType event.Type = "event.file.tags.load.triggered"
+	LoadFileTagsSucceededType event.Type = "event.file.tags.load.succeeded"
+	LoadFileTagsFailedType    event.Type = "event.file.tags.load.failed"
+)
+
+type LoadFileTagsTriggered struct {
+	File *File
+}
+
+func (e LoadFileTagsTriggered) EventType() event.Type {
+	return LoadFileTagsTriggeredType
+}
+
+type LoadFileTagsSucceeded struct {
+	File *File
+	Tags ObjectTags
+}
+
+func (e LoadFileTagsSucceeded) EventType() event.Type {
+	return LoadFileTagsSucceededType
+}
+
+type LoadFileTagsFailed struct {
+	Err  error
+	File *File
+}
+
+func (e LoadFileTagsFailed) EventType() event.Type {
+	return LoadFileTagsFailedType
+}
+
+const (
+	UpdateFileTagsTriggeredType event.Type = "event.file.tags.update.triggered"
+	UpdateFileTagsSucceededType event.Type = "event.file.tags.update.succeeded"
+	UpdateFileTagsFailedType    event.Type = "event.file.tags.update.failed"
+)
+
+type UpdateFileTagsTriggered struct {
+	File *File
+	Tags ObjectTags
+}
+
+func (e UpdateFileTagsTriggered) EventType() event.Type {
+	return UpdateFileTagsTriggeredType
+}
+
+type UpdateFileTagsSucceeded struct {
+	File *File
+	Tags ObjectTags
+}
+
+func (e UpdateFileTagsSucceeded) EventType() event.Type {
+	return UpdateFileTagsSucceededType
+}
+
+type UpdateFileTagsFailed struct {
+	Err  error
+	File *File
+}
+
+func (e UpdateFileTagsFailed) EventType() event.Type {
+	return UpdateFileTagsFailedType
+}
+
+const (
+	CreateFileShareLinkTriggeredType event.Type = "event.file.sharelink.create.triggered"
+	CreateFileShareLinkSucceededType event.Type = "event.file.sharelink.create.succeeded"
+	CreateFileShareLinkFailedType    event.Type = "event.file.sharelink.create.failed"
+)
+
+type CreateFileShareLinkTriggered struct {
+	File   *File
+	Access ShareLinkAccess
+	Expiry time.Duration
+}
+
+func (e CreateFileShareLinkTriggered) EventType() event.Type {
+	return CreateFileShareLinkTriggeredType
+}
+
+type CreateFileShareLinkSucceeded struct {
+	File      *File
+	Access    ShareLinkAccess
+	URL       string
+	ExpiresAt time.Time
+}
+
+func (e CreateFileShareLinkSucceeded) EventType() event.Type {
+	return CreateFileShareLinkSucceededType
+}
+
+type CreateFileShareLinkFailed struct {
+	Err  error
+	File *File
+}
+
+func (e CreateFileShareLinkFailed) EventType() event.Type {
+	return CreateFileShareLinkFailedType
+}
+
+const (
+	LoadFileVersionsTriggeredType event.Type = "event.file.versions.load.triggered"
+	LoadFileVersionsSucceededType event.Type = "event.file.versions.load.succeeded"
+	LoadFileVersionsFailedType    event.Type = "event.file.versions.load.failed"
+)
+
+type LoadFileVersionsTriggered struct {
+	File *File
+}
+
+func (e LoadFileVersionsTriggered) EventType() event.Type {
+	return LoadFileVersionsTriggeredType
+}
+
+type LoadFileVersionsSucceeded struct {
+	File     *File
+	Versions []FileVersion
+}
+
+func (e LoadFileVersionsSucceeded) EventType() event.Type {
+	return LoadFileVersionsSucceededType
+}
+
+type LoadFileVersionsFailed struct {
+	Err  error
+	File *File
+}
+
+func (e LoadFileVersionsFailed) EventType() event.Type {
+	return LoadFileVersionsFailedType
+}
+
+const (
+	RestoreFileVersionTriggeredType event.Type = "event.file.version.restore.triggered"
+	RestoreFileVersionSucceededType event.Type = "event.file.version.restore.succeeded"
+	RestoreFileVersionFailedType    event.Type = "event.file.version.restore.failed"
+)
+
+type RestoreFileVersionTriggered struct {
+	File    *File
+	Version FileVersion
+}
+
+func (e RestoreFileVersionTriggered) EventType() event.Type {
+	return RestoreFileVersionTriggeredType
+}
+
+type RestoreFileVersionSucceeded struct {
+	File         *File
+	Version      FileVersion
+	SizeBytes    uint64
+	LastModified time.Time
+}
+
+func (e RestoreFileVersionSucceeded) EventType() event.Type {
+	return RestoreFileVersionSucceededType
+}
+
+type RestoreFileVersionFailed struct {
+	Err  error
+	File *File
+}
+
+func (e RestoreFileVersionFailed) EventType() event.Type {
+	return RestoreFileVersionFailedType
+}
+
+const (
+	DeleteFileVersionTriggeredType event.Type = "event.file.version.delete.triggered"
+	DeleteFileVersionSucceededType event.Type = "event.file.version.delete.succeeded"
+	DeleteFileVersionFailedType    event.Type = "event.file.version.delete.failed"
+)
+
+type DeleteFileVersionTriggered struct {
+	File    *File
+	Version FileVersion
+}
+
+func (e DeleteFileVersionTriggered) EventType() event.Type {
+	return DeleteFileVersionTriggeredType
+}
+
+type DeleteFileVersionSucceeded struct {
+	File    *File
+	Version FileVersion
+}
+
+func (e DeleteFileVersionSucceeded) EventType() event.Type {
+	return DeleteFileVersionSucceededType
+}
+
+type DeleteFileVersionFailed struct {
+	Err  error
+	File *File
+}
+
+func (e DeleteFileVersionFailed) EventType() event.Type {
+	return DeleteFileVersionFailedType
+}
@@ -0,0 +1,179 @@
+package directory
+
+import (
+	"github.com/thomas-marquis/it-happened/event"
+)
+
+const (
+	DeleteSelectionTriggeredType event.Type = "event.selection.delete.triggered"
+	DeleteSelectionSucceededType event.Type = "event.selection.delete.succeeded"
+	DeleteSelectionFailedType    event.Type = "event.selection.delete.failed"
+)
+
+type DeleteSelectionTriggered struct {
+	Selection *Selection
+}
+
+func (e DeleteSelectionTriggered) EventType() event.Type {
+	return DeleteSelectionTriggeredType
+}
+
+type DeleteSelectionSucceeded struct {
+	Selection    *Selection
+	DeletedCount int
+}
+
+func (e DeleteSelectionSucceeded) EventType() event.Type {
+	return DeleteSelectionSucceededType
+}
+
+type DeleteSelectionFailed struct {
+	Err       error
+	Selection *Selection
+}
+
+func (e DeleteSelectionFailed) EventType() event.Type {
+	return DeleteSelectionFailedType
+}
+
+const (
+	DownloadSelectionTriggeredType event.Type = "event.selection.download.triggered"
+	DownloadSelectionSucceededType event.Type = "event.selection.download.succeeded"
+	DownloadSelectionFailedType    event.Type = "event.selection.download.failed"
+)
+
+type DownloadSelectionTriggered struct {
+	Selection *Selection
+	DstPath   string
+	Strategy  MaterializeStrategy
+}
+
+func (e DownloadSelectionTriggered) EventType() event.Type {
+	return DownloadSelectionTriggeredType
+}
+
+type DownloadSelectionSucceeded struct {
+	Selection       *Selection
+	DstPath         string
+	DownloadedCount int
+	SkippedCount    int
+}
+
+func (e DownloadSelectionSucceeded) EventType() event.Type {
+	return DownloadSelectionSucceededType
+}
+
+type DownloadSelectionFailed struct {
+	Err       error
+	Selection *Selection
+}
+
+func (e DownloadSelectionFailed) EventType() event.Type {
+	return DownloadSelectionFailedType
+}
+
+const (
+	CopySelectionTriggeredType event.Type = "event.selection.copy.triggered"
+	CopySelectionSucceededType event.Type = "event.selection.copy.succeeded"
+	CopySelectionFailedType    event.Type = "event.selection.copy.failed"
+)
+
+type CopySelectionTriggered struct {
+	Selection   *Selection
+	Destination *Directory
+	// Move is true when the source objects must be removed once copied.
+	Move bool
+	// Strategy tells whether the objects already existing in the destination are kept or overwritten.
+	Strategy MaterializeStrategy
+}
+
+func (e CopySelectionTriggered) EventType() event.Type {
+	return CopySelectionTriggeredType
+}
+
+type CopySelectionSucceeded struct {
+	Selection    *Selection
+	Destination  *Directory
+	Move         bool
+	CopiedCount  int
+	SkippedCount int
+}
+
+func (e CopySelectionSucceeded) EventType() event.Type {
+	return CopySelectionSucceededType
+}
+
+type CopySelectionFailed struct {
+	Err         error
+	Selection   *Selection
+	Destination *Directory
+	Move        bool
+}
+
+func (e CopySelectionFailed) EventType() event.Type {
+	return CopySelectionFailedType
+}
+
+const (
+	PastePreviewTriggeredType event.Type = "event.selection.paste.preview.triggered"
+	PastePreviewSucceededType event.Type = "event.selection.paste.preview.succeeded"
+	PastePreviewFailedType    event.Type = "event.selection.paste.preview.failed"
+	PasteReadyType            event.Type = "event.selection.paste.ready"
+)
+
+// PastePreviewTriggered asks for the list of the objects covered by a selection
+// about to be pasted into the destination directory.
+type PastePreviewTriggered struct {
+	Selection   *Selection
+	Destination *Directory
+	Move        bool
+}
+
+func (e PastePreviewTriggered) EventType() event.Type {
+	return PastePreviewTriggeredType
+}
+
+type PastePreviewSucceeded struct {
+	Selection   *Selection
+	Destination *Directory
+	Move        bool
+	Objects     []SelectedObject
+}
+
+func (e PastePreviewSucceeded) EventType() event.Type {
+	return PastePreviewSucceededType
+}
+
+type PastePreviewFailed struct {
+	Err         error
+	Selection   *Selection
+	Destination *Directory
+}
+
+func (e PastePreviewFailed) EventType() event.Type {
+	return PastePreviewFailedType
+}
+
+// PasteReady is published once the destination directories impacted by the paste are loaded,
+// so that the conflicts with the existing files can be previewed.
+type PasteReady PastePreviewSucceeded
+
+func (e PasteReady) EventType() event.Type {
+	return PasteReadyType
+}
+
+const (
+	SelectionProgressType event.Type = "event.selection.progress"
+)
+
+// SelectionProgress is published while a bulk operation on a selection is running.
+type SelectionProgress struct {
+	Selection    *Selection
+	DoneCount    int
+	SkippedCount int
+	FailedCount  int
+}
+
+func (e SelectionProgress) EventType() event.Type {
+	return SelectionProgressType
+}
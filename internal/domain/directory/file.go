@@ -2,6 +2,8 @@ package directory
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -34,6 +36,11 @@ type File struct {
 	parent       *Directory
 	sizeBytes    uint64
 	lastModified time.Time
+	metadata     *ObjectMetadata
+	tags         ObjectTags
+	versions     []FileVersion
+	versionID    string
+	deleted      bool
 }
 
 func NewFile(name string, parent *Directory, opts ...FileOption) (*File, error) {
@@ -94,18 +101,112 @@ func (f *File) LastModified() time.Time {
 	return f.lastModified
 }
 
+// Metadata returns the headers and user metadata of the file, and false when they are not loaded yet.
+func (f *File) Metadata() (ObjectMetadata, bool) {
+	if f.metadata == nil {
+		return ObjectMetadata{}, false
+	}
+	return *f.metadata, true
+}
+
+// SetMetadata stores the loaded metadata of the file, along with its last modification date.
+func (f *File) SetMetadata(metadata ObjectMetadata) {
+	f.metadata = &metadata
+	if !metadata.LastModified.IsZero() {
+		f.lastModified = metadata.LastModified
+	}
+}
+
+// Tags returns the tags of the file, and false when they are not loaded yet.
+func (f *File) Tags() (ObjectTags, bool) {
+	if f.tags == nil {
+		return nil, false
+	}
+	return f.tags.Clone(), true
+}
+
+// SetTags stores the loaded tags of the file.
+func (f *File) SetTags(tags ObjectTags) {
+	f.tags = tags.Clone()
+}
+
+// VersionID returns the version of the file targeted by this instance, or an empty string for the current one.
+func (f *File) VersionID() string {
+	return f.versionID
+}
+
+// IsDeleted tells if the latest version of the file is a delete marker.
+func (f *File) IsDeleted() bool {
+	return f.deleted
+}
+
+// Versions returns the versions of the file, from the most recent one, and false when they are not loaded yet.
+func (f *File) Versions() ([]FileVersion, bool) {
+	if f.versions == nil {
+		return nil, false
+	}
+	return slices.Clone(f.versions), true
+}
+
+// SetVersions stores the loaded versions of the file.
+// The file is flagged as deleted when its latest version is a delete marker,
+// otherwise its size and last modification date are taken from the latest version.
+func (f *File) SetVersions(versions []FileVersion) {
+	f.versions = slices.Clone(versions)
+	if f.versions == nil {
+		f.versions = make([]FileVersion, 0)
+	}
+	for _, v := range f.versions {
+		if !v.IsLatest {
+			continue
+		}
+		f.deleted = v.IsDeleteMarker
+		if !v.IsDeleteMarker {
+			f.sizeBytes = v.SizeBytes
+			f.lastModified = v.LastModified
+		}
+	}
+}
+
+// Restored updates the file once a previous version has been copied over the current one.
+// The loaded metadata, tags and versions are outdated and cleared.
+func (f *File) Restored(sizeBytes uint64, lastModified time.Time) {
+	f.deleted = false
+	f.sizeBytes = sizeBytes
+	f.lastModified = lastModified
+	f.metadata = nil
+	f.tags = nil
+	f.versions = nil
+}
+
+// AtVersion returns a read-only copy of the file targeting the given version,
+// to download or display its content.
+// Returns an error if the version is a delete marker, which has no content.
+func (f *File) AtVersion(version FileVersion) (*File, error) {
+	if version.IsDeleteMarker {
+		return nil, fmt.Errorf("%w: a delete marker has no content", ErrInvalidVersion)
+	}
+	return &File{
+		name:         f.name,
+		parent:       f.parent,
+		sizeBytes:    version.SizeBytes,
+		lastModified: version.LastModified,
+		versionID:    version.ID,
+	}, nil
+}
+
 // FullPath returns the full path of the file in the directory.
 // FullPath is unique within a given bucket.
 func (f *File) FullPath() string {
 	return f.DirectoryPath().String() + f.name.String()
 }
 
-func (f *File) Download(connID connection_deck.ConnectionID, toPath string) event.Event {
+func (f *File) Download(connID connection_deck.ConnectionID, toPath string, opts ...event.Option) event.Event {
 	return event.New(DownloadFileTriggered{
 		ConnectionID: connID,
 		DstPath:      toPath,
 		File:         f,
-	})
+	}, opts...)
 }
 
 func (f *File) Load(connId connection_deck.ConnectionID, opts ...event.Option) event.Event {
@@ -115,6 +216,92 @@ func (f *File) Load(connId connection_deck.ConnectionID, opts ...event.Option) e
 	}, opts...)
 }
 
+// LoadMetadata triggers the loading of the file headers and user metadata.
+func (f *File) LoadMetadata(opts ...event.Option) event.Event {
+	return event.New(LoadFileMetadataTriggered{
+		File: f,
+	}, opts...)
+}
+
+// UpdateMetadata triggers the replacement of the editable metadata of the file.
+// Returns an error if the metadata is invalid.
+func (f *File) UpdateMetadata(metadata ObjectMetadata, opts ...event.Option) (event.Event, error) {
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+	return event.New(UpdateFileMetadataTriggered{
+		File:     f,
+		Metadata: metadata,
+	}, opts...), nil
+}
+
+// LoadTags triggers the loading of the file tags.
+func (f *File) LoadTags(opts ...event.Option) event.Event {
+	return event.New(LoadFileTagsTriggered{
+		File: f,
+	}, opts...)
+}
+
+// UpdateTags triggers the replacement of all the tags of the file.
+// Returns an error if the tags are invalid.
+func (f *File) UpdateTags(tags ObjectTags, opts ...event.Option) (event.Event, error) {
+	if err := tags.Validate(); err != nil {
+		return nil, err
+	}
+	return event.New(UpdateFileTagsTriggered{
+		File: f,
+		Tags: tags.Clone(),
+	}, opts...), nil
+}
+
+// CreateShareLink triggers the generation of a presigned URL granting the given access to the file
+// until the expiry elapses.
+// Returns an error if the expiry is not positive or exceeds ShareLinkMaxExpiry.
+func (f *File) CreateShareLink(access ShareLinkAccess, expiry time.Duration, opts ...event.Option) (event.Event, error) {
+	if err := validateShareLinkExpiry(expiry); err != nil {
+		return nil, err
+	}
+	return event.New(CreateFileShareLinkTriggered{
+		File:   f,
+		Access: access,
+		Expiry: expiry,
+	}, opts...), nil
+}
+
+// LoadVersions triggers the loading of all the versions of the file.
+func (f *File) LoadVersions(opts ...event.Option) event.Event {
+	return event.New(LoadFileVersionsTriggered{
+		File: f,
+	}, opts...)
+}
+
+// RestoreVersion triggers the copy of a previous version over the current one.
+// Restoring a version of a deleted file undeletes it.
+// Returns an error if the version is a delete marker or already the current content.
+func (f *File) RestoreVersion(version FileVersion, opts ...event.Option) (event.Event, error) {
+	if version.IsDeleteMarker {
+		return nil, fmt.Errorf("%w: a delete marker can't be restored", ErrInvalidVersion)
+	}
+	if version.IsLatest {
+		return nil, fmt.Errorf("%w: version %s is already the current one", ErrInvalidVersion, version.ID)
+	}
+	return event.New(RestoreFileVersionTriggered{
+		File:    f,
+		Version: version,
+	}, opts...), nil
+}
+
+// DeleteVersion triggers the permanent deletion of a version of the file.
+func (f *File) DeleteVersion(version FileVersion, opts ...event.Option) (event.Event, error) {
+	if version.ID == "" {
+		return nil, fmt.Errorf("%w: version ID is empty", ErrInvalidVersion)
+	}
+	return event.New(DeleteFileVersionTriggered{
+		File:    f,
+		Version: version,
+	}, opts...), nil
+}
+
 // Rename changes the name of the file.
 // Returns an error if the new name is invalid.
 func (f *File) Rename(newName string) (event.Event, error) {
@@ -129,3 +316,23 @@ func (f *File) Rename(newName string) (event.Event, error) {
 		Directory: f.parent,
 	}), nil
 }
+
+// CopyTo triggers the copy of the file into the destination directory.
+// The destination may belong to another connection.
+func (f *File) CopyTo(dst *Directory, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	sel := NewSelection(f.parent.ConnectionID())
+	if err := sel.AddFile(f); err != nil {
+		return nil, err
+	}
+	return sel.CopyTo(dst, strategy, opts...)
+}
+
+// MoveTo triggers the move of the file into the destination directory.
+// The destination may belong to another connection.
+func (f *File) MoveTo(dst *Directory, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	sel := NewSelection(f.parent.ConnectionID())
+	if err := sel.AddFile(f); err != nil {
+		return nil, err
+	}
+	return sel.MoveTo(dst, strategy, opts...)
+}
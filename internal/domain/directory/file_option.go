@@ -15,3 +15,10 @@ func WithFileLastModified(lastModified time.Time) FileOption {
 		f.lastModified = lastModified
 	}
 }
+
+// WithFileDeleted flags the file as deleted: its latest version is a delete marker.
+func WithFileDeleted(deleted bool) FileOption {
+	return func(f *File) {
+		f.deleted = deleted
+	}
+}
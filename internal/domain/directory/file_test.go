@@ -1,7 +1,9 @@
 package directory_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -78,3 +80,273 @@ func TestFile_Rename(t *testing.T) {
 		assert.Equal(t, directory.FileName("newname.txt"), files[0].Name())
 	})
 }
+
+func TestFile_UpdateMetadata(t *testing.T) {
+	t.Run("should emit event with the new metadata", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		md := directory.ObjectMetadata{ETag: "abc"}.WithEditableFields(
+			"application/json", "no-cache", map[string]string{"owner": "team-data"})
+
+		// When
+		evt, err := file.UpdateMetadata(md)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.UpdateFileMetadataTriggeredType, evt.Type())
+		pl := evt.Payload().(directory.UpdateFileMetadataTriggered)
+		assert.Equal(t, "application/json", pl.Metadata.ContentType)
+		assert.Equal(t, "no-cache", pl.Metadata.CacheControl)
+		assert.Equal(t, "abc", pl.Metadata.ETag)
+		assert.Equal(t, map[string]string{"owner": "team-data"}, pl.Metadata.UserMetadata)
+	})
+
+	t.Run("should return error when a user metadata key is invalid", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		md := directory.ObjectMetadata{UserMetadata: map[string]string{"my key": "value"}}
+
+		// When
+		_, err = file.UpdateMetadata(md)
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrInvalidMetadata)
+	})
+
+	t.Run("should store the metadata and the last modification date", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+		_, loaded := file.Metadata()
+		require.False(t, loaded)
+
+		lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		// When
+		file.SetMetadata(directory.ObjectMetadata{ContentType: "text/plain", LastModified: lastModified})
+
+		// Then
+		md, loaded := file.Metadata()
+		assert.True(t, loaded)
+		assert.Equal(t, "text/plain", md.ContentType)
+		assert.Equal(t, lastModified, file.LastModified())
+	})
+}
+
+func TestFile_UpdateTags(t *testing.T) {
+	t.Run("should emit event with a copy of the new tags", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+		tags := directory.ObjectTags{"cost-center": "42"}
+
+		// When
+		evt, err := file.UpdateTags(tags)
+		tags["cost-center"] = "43"
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.UpdateFileTagsTriggeredType, evt.Type())
+		pl := evt.Payload().(directory.UpdateFileTagsTriggered)
+		assert.Equal(t, directory.ObjectTags{"cost-center": "42"}, pl.Tags)
+	})
+
+	t.Run("should return error when there are too many tags", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+		tags := directory.ObjectTags{}
+		for i := range 11 {
+			tags[fmt.Sprintf("key%d", i)] = "value"
+		}
+
+		// When
+		_, err = file.UpdateTags(tags)
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrInvalidTags)
+	})
+
+	t.Run("should tell when the tags are loaded, even without any tag", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+		_, loaded := file.Tags()
+		require.False(t, loaded)
+
+		// When
+		file.SetTags(nil)
+
+		// Then
+		tags, loaded := file.Tags()
+		assert.True(t, loaded)
+		assert.Empty(t, tags)
+	})
+}
+
+func TestFile_CreateShareLink(t *testing.T) {
+	t.Run("should emit event with the access and the expiry", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		// When
+		evt, err := file.CreateShareLink(directory.ShareLinkUpload, time.Hour)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.CreateFileShareLinkTriggeredType, evt.Type())
+		pl := evt.Payload().(directory.CreateFileShareLinkTriggered)
+		assert.Equal(t, file, pl.File)
+		assert.Equal(t, directory.ShareLinkUpload, pl.Access)
+		assert.Equal(t, time.Hour, pl.Expiry)
+	})
+
+	t.Run("should return error when the expiry is out of bounds", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		for _, expiry := range []time.Duration{0, -time.Minute, directory.ShareLinkMaxExpiry + time.Second} {
+			// When
+			_, err = file.CreateShareLink(directory.ShareLinkDownload, expiry)
+
+			// Then
+			assert.ErrorIs(t, err, directory.ErrInvalidShareLink)
+		}
+	})
+}
+
+func TestFile_Versions(t *testing.T) {
+	lastModified := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	latest := directory.FileVersion{ID: "v3", SizeBytes: 30, LastModified: lastModified, IsLatest: true}
+	previous := directory.FileVersion{ID: "v2", SizeBytes: 20, LastModified: lastModified.Add(-time.Hour)}
+	deleteMarker := directory.FileVersion{ID: "v4", LastModified: lastModified.Add(time.Hour), IsLatest: true, IsDeleteMarker: true}
+
+	t.Run("should flag the file as deleted when its latest version is a delete marker", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+		_, loaded := file.Versions()
+		require.False(t, loaded)
+
+		// When
+		file.SetVersions([]directory.FileVersion{deleteMarker, {ID: "v3", SizeBytes: 30}})
+
+		// Then
+		assert.True(t, file.IsDeleted())
+		versions, loaded := file.Versions()
+		assert.True(t, loaded)
+		assert.Len(t, versions, 2)
+	})
+
+	t.Run("should take the size and the date of the latest version", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir, directory.WithFileSize(20))
+		require.NoError(t, err)
+
+		// When
+		file.SetVersions([]directory.FileVersion{latest, previous})
+
+		// Then
+		assert.False(t, file.IsDeleted())
+		assert.Equal(t, uint64(30), file.SizeBytes())
+		assert.Equal(t, lastModified, file.LastModified())
+	})
+
+	t.Run("should make a read-only copy targeting a version", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir, directory.WithFileSize(30))
+		require.NoError(t, err)
+
+		// When
+		res, err := file.AtVersion(previous)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "v2", res.VersionID())
+		assert.Equal(t, uint64(20), res.SizeBytes())
+		assert.Equal(t, file.FullPath(), res.FullPath())
+		assert.Empty(t, file.VersionID())
+	})
+
+	t.Run("should not make a copy of a delete marker", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		// When
+		_, err = file.AtVersion(deleteMarker)
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrInvalidVersion)
+	})
+
+	t.Run("should emit event to restore a previous version", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		// When
+		evt, err := file.RestoreVersion(previous)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.RestoreFileVersionTriggeredType, evt.Type())
+		pl := evt.Payload().(directory.RestoreFileVersionTriggered)
+		assert.Equal(t, previous, pl.Version)
+	})
+
+	t.Run("should not restore the current version or a delete marker", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir)
+		require.NoError(t, err)
+
+		// When
+		_, errLatest := file.RestoreVersion(latest)
+		_, errMarker := file.RestoreVersion(deleteMarker)
+
+		// Then
+		assert.ErrorIs(t, errLatest, directory.ErrInvalidVersion)
+		assert.ErrorIs(t, errMarker, directory.ErrInvalidVersion)
+	})
+
+	t.Run("should clear the outdated data once restored", func(t *testing.T) {
+		// Given
+		parentDir := tu.NewNotLoadedDirectory(t, "parent", directory.RootPath)
+		file, err := directory.NewFile("data.json", parentDir, directory.WithFileDeleted(true))
+		require.NoError(t, err)
+		file.SetVersions([]directory.FileVersion{deleteMarker, previous})
+		file.SetTags(directory.ObjectTags{"a": "b"})
+
+		// When
+		file.Restored(20, lastModified)
+
+		// Then
+		assert.False(t, file.IsDeleted())
+		assert.Equal(t, uint64(20), file.SizeBytes())
+		assert.Equal(t, lastModified, file.LastModified())
+		_, versionsLoaded := file.Versions()
+		assert.False(t, versionsLoaded)
+		_, tagsLoaded := file.Tags()
+		assert.False(t, tagsLoaded)
+	})
+}
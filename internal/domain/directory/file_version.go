@@ -0,0 +1,12 @@
+package directory
+
+import "time"
+
+// FileVersion is a value object describing one version of a file stored in a versioned bucket.
+type FileVersion struct {
+	ID             string
+	SizeBytes      uint64
+	LastModified   time.Time
+	IsLatest       bool
+	IsDeleteMarker bool
+}
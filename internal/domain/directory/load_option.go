@@ -0,0 +1,28 @@
+package directory
+
+// LoadOption customizes the loading of a directory.
+type LoadOption func(*LoadTriggered)
+
+// WithDeletedFiles also loads the files whose latest version is a delete marker,
+// so they can be restored from a versioned bucket.
+func WithDeletedFiles() LoadOption {
+	return func(e *LoadTriggered) {
+		e.IncludeDeleted = true
+	}
+}
+
+// WithAllPages loads the whole content of the directory at once, instead of its first page,
+// when every existing file must be known, as before materializing a preview.
+func WithAllPages() LoadOption {
+	return func(e *LoadTriggered) {
+		e.AllPages = true
+	}
+}
+
+func newLoadTriggered(d *Directory, opts []LoadOption) LoadTriggered {
+	evt := LoadTriggered{Directory: d}
+	for _, opt := range opts {
+		opt(&evt)
+	}
+	return evt
+}
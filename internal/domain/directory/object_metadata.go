@@ -0,0 +1,53 @@
+package directory
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// ObjectMetadata is a value object holding the headers and the user-defined metadata of a stored file.
+// Only ContentType, CacheControl and UserMetadata can be edited, the other fields are managed by the storage.
+type ObjectMetadata struct {
+	ContentType        string
+	CacheControl       string
+	ContentEncoding    string
+	ContentDisposition string
+	ContentLanguage    string
+	ETag               string
+	StorageClass       string
+	LastModified       time.Time
+	UserMetadata       map[string]string
+}
+
+// WithEditableFields returns a copy of the metadata with the editable fields replaced.
+func (m ObjectMetadata) WithEditableFields(contentType, cacheControl string, userMetadata map[string]string) ObjectMetadata {
+	m.ContentType = contentType
+	m.CacheControl = cacheControl
+	m.UserMetadata = maps.Clone(userMetadata)
+	return m
+}
+
+// Validate returns an error when the user metadata can't be sent as HTTP headers.
+func (m ObjectMetadata) Validate() error {
+	for key, value := range m.UserMetadata {
+		if key == "" {
+			return fmt.Errorf("%w: metadata key is empty", ErrInvalidMetadata)
+		}
+		for _, c := range key {
+			if !isMetadataKeyChar(c) {
+				return fmt.Errorf("%w: metadata key '%s' contains the invalid character '%c'", ErrInvalidMetadata, key, c)
+			}
+		}
+		for _, c := range value {
+			if c < ' ' || c > '~' {
+				return fmt.Errorf("%w: value of metadata '%s' must only contain printable ASCII characters", ErrInvalidMetadata, key)
+			}
+		}
+	}
+	return nil
+}
+
+func isMetadataKeyChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.'
+}
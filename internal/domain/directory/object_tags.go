@@ -0,0 +1,43 @@
+package directory
+
+import (
+	"fmt"
+	"maps"
+	"unicode/utf8"
+)
+
+const (
+	maxObjectTags        = 10
+	maxObjectTagKeyLen   = 128
+	maxObjectTagValueLen = 256
+)
+
+// ObjectTags is a value object holding the tags of a stored file, by key.
+type ObjectTags map[string]string
+
+// Clone returns a copy of the tags that can be modified safely.
+func (t ObjectTags) Clone() ObjectTags {
+	if t == nil {
+		return ObjectTags{}
+	}
+	return maps.Clone(t)
+}
+
+// Validate returns an error when the tags exceed the limits of the storage.
+func (t ObjectTags) Validate() error {
+	if len(t) > maxObjectTags {
+		return fmt.Errorf("%w: a file can't have more than %d tags", ErrInvalidTags, maxObjectTags)
+	}
+	for key, value := range t {
+		if key == "" {
+			return fmt.Errorf("%w: tag key is empty", ErrInvalidTags)
+		}
+		if utf8.RuneCountInString(key) > maxObjectTagKeyLen {
+			return fmt.Errorf("%w: tag key '%s' is longer than %d characters", ErrInvalidTags, key, maxObjectTagKeyLen)
+		}
+		if utf8.RuneCountInString(value) > maxObjectTagValueLen {
+			return fmt.Errorf("%w: value of tag '%s' is longer than %d characters", ErrInvalidTags, key, maxObjectTagValueLen)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/thomas-marquis/it-happened/carrier"
@@ -46,6 +47,9 @@ type Preview struct {
 	files               []*File
 	availableStrategies map[MaterializeStrategy]struct{}
 	counter             *PreviewCounter
+
+	// syncChanges are indexed by relative path when previewing a sync, nil otherwise
+	syncChanges map[string]SyncChange
 }
 
 func newPreview(mount, dir *Directory) *Preview {
@@ -85,12 +89,42 @@ func (p *Preview) AddSubDirectory(name string) (*Preview, error) {
 	}
 
 	newPrev := newPreview(p.mountPoint, subDir)
+	newPrev.syncChanges = p.syncChanges
 	p.children = append(p.children, newPrev)
 	p.incDirCounter()
 	newPrev.parent = p
 	return newPrev, nil
 }
 
+// AddObject adds a file to the preview from its key relative to the previewed directory,
+// along with its parent directories when they are not in the preview yet.
+// A key ending with a slash only adds directories.
+func (p *Preview) AddObject(relativeKey string, sizeBytes uint64, lastModified time.Time) error {
+	names := strings.Split(relativeKey, "/")
+	curr := p
+	for _, name := range names[:len(names)-1] {
+		next, err := curr.getOrAddSubDirectory(name)
+		if err != nil {
+			return err
+		}
+		curr = next
+	}
+
+	if fileName := names[len(names)-1]; fileName != "" {
+		return curr.AddFile(fileName, sizeBytes, lastModified)
+	}
+	return nil
+}
+
+func (p *Preview) getOrAddSubDirectory(name string) (*Preview, error) {
+	for _, sd := range p.children {
+		if sd.dir.Name() == name {
+			return sd, nil
+		}
+	}
+	return p.AddSubDirectory(name)
+}
+
 func (p *Preview) Count() PreviewCounter {
 	return *p.counter
 }
@@ -140,7 +174,16 @@ func (p *Preview) Children() []*Preview {
 	return p.children
 }
 
+// IsSync returns true when the preview is the dry-run of a sync.
+func (p *Preview) IsSync() bool {
+	return p.syncChanges != nil
+}
+
 func (p *Preview) AvailableStrategies() []MaterializeStrategy {
+	if p.syncChanges != nil {
+		// a sync always replaces the changed files
+		return []MaterializeStrategy{MaterializeSkip}
+	}
 	var strats []MaterializeStrategy
 	for s := range p.availableStrategies {
 		strats = append(strats, s)
@@ -159,6 +202,13 @@ func (p *Preview) FileStatus(strategy MaterializeStrategy, fileName string) (str
 	if previewed == nil {
 		return "", "", ErrNotFound
 	}
+	if p.syncChanges != nil {
+		change, ok := p.syncChanges[p.relativePath(fileName)]
+		if !ok {
+			return "", "", ErrNotFound
+		}
+		return change.Action.String(), change.Reason, nil
+	}
 	actual, err := p.dir.GetFileByName(FileName(fileName))
 	if err != nil && !errors.Is(err, ErrNotFound) {
 		return "", "", err
@@ -178,6 +228,9 @@ func (p *Preview) FileStatus(strategy MaterializeStrategy, fileName string) (str
 }
 
 func (p *Preview) DirStatus() string {
+	if p.syncChanges != nil {
+		return ""
+	}
 	if p.dir.Parent().IsSubDirectoryExists(p.dir.Name()) {
 		return ""
 	}
@@ -225,6 +278,10 @@ type Materializer interface {
 	Materialize(strategy MaterializeStrategy) event.Event
 }
 
+// uploadLayerTimeout is long enough for the uploads waiting in the transfer queue,
+// that can be paused or retried before completing.
+const uploadLayerTimeout = 24 * time.Hour
+
 type materializeUpload struct {
 	preview     *Preview
 	srcBasePath string
@@ -259,6 +316,7 @@ func (m *materializeUpload) Materialize(strategy MaterializeStrategy) event.Even
 				evts = append(evts, event.New(UploadFileTriggered{
 					Directory: prev.dir,
 					SrcPath:   uploadPath,
+					Overwrite: strategy == MaterializeReplace,
 				}))
 			}
 
@@ -279,7 +337,7 @@ func (m *materializeUpload) Materialize(strategy MaterializeStrategy) event.Even
 					return evtCarrier.NewFollowup(event.ItHappened{})
 				},
 				event.New(event.ItHappened{}),
-				carrier.WithTimeout(time.Second*120),
+				carrier.WithTimeout(uploadLayerTimeout),
 			))
 		}
 		tmpLay := make([]*Preview, 0)
@@ -326,7 +384,8 @@ func (m *materializeLoad) makeNextEvent(carrierEvt event.Event, dirs []*Director
 		if err != nil {
 			continue
 		}
-		evt, err := dir.Load()
+		// the existing files decide the available strategies and the status of the previewed ones
+		evt, err := dir.Load(WithAllPages())
 		if err != nil {
 			continue
 		}
@@ -1,12 +1,14 @@
 package directory_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thomas-marquis/it-happened/carrier"
+	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/it-happened/eventest"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
@@ -204,3 +206,61 @@ func TestPreview_GetByPath(t *testing.T) {
 	})
 
 }
+
+func TestPreview_AddObject(t *testing.T) {
+	t.Run("should add the file with its parent directories", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "dst",
+			tu.WithRootParent(),
+			tu.IsLoaded(),
+			tu.WithFile("file1.txt"))
+
+		prev, err := dir.Preview()
+		require.NoError(t, err)
+
+		// When
+		require.NoError(t, prev.AddObject("file1.txt", 10, time.Now()))
+		require.NoError(t, prev.AddObject("mydir/", 0, time.Now()))
+		require.NoError(t, prev.AddObject("mydir/sub/file2.txt", 20, time.Now()))
+		require.NoError(t, prev.AddObject("mydir/sub/file3.txt", 30, time.Now()))
+
+		// Then
+		assert.Equal(t, directory.PreviewCounter{Files: 3, Directories: 2}, prev.Count())
+		sub, err := prev.GetByPath("/dst/mydir/sub/")
+		require.NoError(t, err)
+		assert.Len(t, sub.Files(), 2)
+		assert.ElementsMatch(t,
+			[]directory.MaterializeStrategy{directory.MaterializeSkip, directory.MaterializeReplace},
+			prev.AvailableStrategies())
+	})
+}
+
+func TestPreview_LoadMaterializer(t *testing.T) {
+	t.Run("should load every page of the previewed directories", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		_, err := dir.Load()
+		require.NoError(t, err)
+		require.NoError(t, dir.Notify(event.New(directory.LoadSucceeded{
+			Directory:     dir,
+			NextPageToken: "token-1",
+		})))
+
+		prev, err := dir.Preview()
+		require.NoError(t, err)
+		require.NoError(t, prev.AddFile("file1.txt", 0, time.Now()))
+
+		mat := directory.NewLoadMaterializer(prev,
+			directory.UploadReady{Directory: dir},
+			directory.UploadFailed{Err: errors.New("timeout"), Directory: dir})
+
+		// When
+		res := mat.Materialize(directory.MaterializeSkip)
+
+		// Then
+		eventest.AssertIsType(t, res, carrier.AllType)
+		eventest.AssertContainsExactlyAllPayloads(t, res.Payload().(*carrier.All).Carried,
+			directory.LoadTriggered{Directory: dir, AllPages: true},
+		)
+	})
+}
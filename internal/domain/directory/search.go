@@ -0,0 +1,121 @@
+package directory
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SearchPatternKind tells how the pattern of a search query is interpreted.
+type SearchPatternKind int
+
+const (
+	// SearchGlob matches the file name, or the path relative to the searched directory
+	// when the pattern contains a "/", with shell wildcards (*, ?, [...]).
+	SearchGlob SearchPatternKind = iota
+	// SearchRegex matches any part of the path relative to the searched directory.
+	SearchRegex
+)
+
+func (k SearchPatternKind) String() string {
+	switch k {
+	case SearchGlob:
+		return "Glob"
+	case SearchRegex:
+		return "Regex"
+	default:
+		panic("invalid search pattern kind")
+	}
+}
+
+// SearchQuery describes the files to look for under a directory, recursively.
+// Every filter left to its zero value is ignored.
+type SearchQuery struct {
+	Pattern     string
+	PatternKind SearchPatternKind
+
+	MinSizeBytes uint64
+	// MaxSizeBytes is inclusive, 0 meaning no upper bound
+	MaxSizeBytes uint64
+
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+}
+
+// SearchMatcher tells if a file matches a search query.
+// The key is the path of the file relative to the searched directory.
+type SearchMatcher func(relativeKey string, sizeBytes uint64, lastModified time.Time) bool
+
+// Matcher validates the query and returns the function telling if a file matches it.
+func (q SearchQuery) Matcher() (SearchMatcher, error) {
+	if q.MaxSizeBytes > 0 && q.MinSizeBytes > q.MaxSizeBytes {
+		return nil, fmt.Errorf("%w: the minimum size is greater than the maximum one", ErrInvalidSearch)
+	}
+	if !q.ModifiedAfter.IsZero() && !q.ModifiedBefore.IsZero() && q.ModifiedAfter.After(q.ModifiedBefore) {
+		return nil, fmt.Errorf("%w: the modification date range is empty", ErrInvalidSearch)
+	}
+
+	matchKey, err := q.keyMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(relativeKey string, sizeBytes uint64, lastModified time.Time) bool {
+		if sizeBytes < q.MinSizeBytes || (q.MaxSizeBytes > 0 && sizeBytes > q.MaxSizeBytes) {
+			return false
+		}
+		if !q.ModifiedAfter.IsZero() && lastModified.Before(q.ModifiedAfter) {
+			return false
+		}
+		if !q.ModifiedBefore.IsZero() && lastModified.After(q.ModifiedBefore) {
+			return false
+		}
+		return matchKey(relativeKey)
+	}, nil
+}
+
+func (q SearchQuery) keyMatcher() (func(string) bool, error) {
+	if q.Pattern == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	switch q.PatternKind {
+	case SearchRegex:
+		re, err := regexp.Compile(q.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+		}
+		return re.MatchString, nil
+
+	case SearchGlob:
+		if _, err := path.Match(q.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+		}
+		matchWholeKey := strings.Contains(q.Pattern, "/")
+		return func(key string) bool {
+			if !matchWholeKey {
+				key = path.Base(key)
+			}
+			ok, _ := path.Match(q.Pattern, key)
+			return ok
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown pattern kind %d", ErrInvalidSearch, q.PatternKind)
+	}
+}
+
+// SearchHit is a file found by a search.
+type SearchHit struct {
+	DirectoryPath Path
+	Name          FileName
+	SizeBytes     uint64
+	LastModified  time.Time
+}
+
+// FullPath returns the full path of the found file, the same as File.FullPath.
+func (h SearchHit) FullPath() string {
+	return h.DirectoryPath.String() + h.Name.String()
+}
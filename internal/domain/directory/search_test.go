@@ -0,0 +1,117 @@
+package directory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+)
+
+func TestSearchQuery_Matcher(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should match the file name with a glob pattern", func(t *testing.T) {
+		// Given
+		query := directory.SearchQuery{Pattern: "*.csv"}
+
+		// When
+		match, err := query.Matcher()
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, match("reports/2024/sales.csv", 10, now))
+		assert.False(t, match("reports/2024/sales.json", 10, now))
+	})
+
+	t.Run("should match the relative path with a glob pattern containing a slash", func(t *testing.T) {
+		// Given
+		query := directory.SearchQuery{Pattern: "reports/*/sales.csv"}
+
+		// When
+		match, err := query.Matcher()
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, match("reports/2024/sales.csv", 10, now))
+		assert.False(t, match("sales.csv", 10, now))
+	})
+
+	t.Run("should match any part of the relative path with a regex", func(t *testing.T) {
+		// Given
+		query := directory.SearchQuery{Pattern: `20\d\d/sales`, PatternKind: directory.SearchRegex}
+
+		// When
+		match, err := query.Matcher()
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, match("reports/2024/sales.csv", 10, now))
+		assert.False(t, match("reports/latest/sales.csv", 10, now))
+	})
+
+	t.Run("should filter by size and modification date", func(t *testing.T) {
+		// Given
+		query := directory.SearchQuery{
+			MinSizeBytes:   10,
+			MaxSizeBytes:   100,
+			ModifiedAfter:  now.Add(-time.Hour),
+			ModifiedBefore: now.Add(time.Hour),
+		}
+
+		// When
+		match, err := query.Matcher()
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, match("a.txt", 10, now))
+		assert.True(t, match("a.txt", 100, now))
+		assert.False(t, match("a.txt", 9, now))
+		assert.False(t, match("a.txt", 101, now))
+		assert.False(t, match("a.txt", 50, now.Add(-2*time.Hour)))
+		assert.False(t, match("a.txt", 50, now.Add(2*time.Hour)))
+	})
+
+	t.Run("should return an error for an invalid query", func(t *testing.T) {
+		for _, query := range []directory.SearchQuery{
+			{Pattern: "(", PatternKind: directory.SearchRegex},
+			{Pattern: "[", PatternKind: directory.SearchGlob},
+			{MinSizeBytes: 10, MaxSizeBytes: 5},
+			{ModifiedAfter: now, ModifiedBefore: now.Add(-time.Hour)},
+		} {
+			_, err := query.Matcher()
+			assert.ErrorIs(t, err, directory.ErrInvalidSearch)
+		}
+	})
+}
+
+func TestDirectory_Search(t *testing.T) {
+	t.Run("should trigger the search from the directory", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		query := directory.SearchQuery{Pattern: "*.csv"}
+
+		// When
+		evt, err := dir.Search(query)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, directory.SearchTriggeredType, evt.Type())
+		pl := evt.Payload().(directory.SearchTriggered)
+		assert.Equal(t, dir, pl.Directory)
+		assert.Equal(t, query, pl.Query)
+	})
+
+	t.Run("should refuse an invalid query", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+
+		// When
+		_, err := dir.Search(directory.SearchQuery{Pattern: "(", PatternKind: directory.SearchRegex})
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrInvalidSearch)
+	})
+}
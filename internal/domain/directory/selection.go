@@ -0,0 +1,227 @@
+package directory
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+var ErrEmptySelection = errors.New("selection is empty")
+
+// SelectedObject describes an object covered by a selection.
+// Its key is relative to the parent directory of the selected item it belongs to,
+// so that it starts with the name of this item.
+type SelectedObject struct {
+	RelativeKey  string
+	SizeBytes    uint64
+	LastModified time.Time
+}
+
+// Selection is a set of files and directories of the same connection
+// on which bulk operations (delete, download, copy, move) can be run at once.
+type Selection struct {
+	connectionID connection_deck.ConnectionID
+	files        map[string]*File
+	directories  map[Path]*Directory
+}
+
+func NewSelection(connectionID connection_deck.ConnectionID) *Selection {
+	return &Selection{
+		connectionID: connectionID,
+		files:        make(map[string]*File),
+		directories:  make(map[Path]*Directory),
+	}
+}
+
+func (s *Selection) ConnectionID() connection_deck.ConnectionID {
+	return s.connectionID
+}
+
+// AddFile adds a file to the selection.
+// Returns an error if the file doesn't belong to the selection's connection.
+func (s *Selection) AddFile(f *File) error {
+	if f.Parent().ConnectionID() != s.connectionID {
+		return fmt.Errorf("file %s doesn't belong to the selected connection", f.FullPath())
+	}
+	s.files[f.FullPath()] = f
+	return nil
+}
+
+// AddDirectory adds a directory to the selection.
+// Returns an error if the directory doesn't belong to the selection's connection or if it is the root directory.
+func (s *Selection) AddDirectory(d *Directory) error {
+	if d.IsRoot() {
+		return errors.New("root directory cannot be selected")
+	}
+	if d.ConnectionID() != s.connectionID {
+		return fmt.Errorf("directory %s doesn't belong to the selected connection", d.Path())
+	}
+	s.directories[d.Path()] = d
+	return nil
+}
+
+func (s *Selection) RemoveFile(f *File) {
+	delete(s.files, f.FullPath())
+}
+
+func (s *Selection) RemoveDirectory(d *Directory) {
+	delete(s.directories, d.Path())
+}
+
+func (s *Selection) ContainsFile(f *File) bool {
+	_, found := s.files[f.FullPath()]
+	return found
+}
+
+func (s *Selection) ContainsDirectory(d *Directory) bool {
+	_, found := s.directories[d.Path()]
+	return found
+}
+
+// Files returns the selected files sorted by their full path.
+func (s *Selection) Files() []*File {
+	files := make([]*File, 0, len(s.files))
+	for _, key := range slices.Sorted(maps.Keys(s.files)) {
+		files = append(files, s.files[key])
+	}
+	return files
+}
+
+// Directories returns the selected directories sorted by their path.
+func (s *Selection) Directories() []*Directory {
+	dirs := make([]*Directory, 0, len(s.directories))
+	for _, key := range slices.Sorted(maps.Keys(s.directories)) {
+		dirs = append(dirs, s.directories[key])
+	}
+	return dirs
+}
+
+func (s *Selection) Len() int {
+	return len(s.files) + len(s.directories)
+}
+
+func (s *Selection) IsEmpty() bool {
+	return s.Len() == 0
+}
+
+func (s *Selection) Clear() {
+	clear(s.files)
+	clear(s.directories)
+}
+
+// Delete triggers the deletion of every selected file and of the selected directories with all their content.
+func (s *Selection) Delete(opts ...event.Option) (event.Event, error) {
+	if s.IsEmpty() {
+		return nil, ErrEmptySelection
+	}
+	return event.New(DeleteSelectionTriggered{Selection: s.topLevel()}, opts...), nil
+}
+
+// Download triggers the download of the selection into the local directory dstPath.
+// Local files that already exist are kept or overwritten according to the strategy.
+func (s *Selection) Download(dstPath string, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	if s.IsEmpty() {
+		return nil, ErrEmptySelection
+	}
+	return event.New(DownloadSelectionTriggered{
+		Selection: s.topLevel(),
+		DstPath:   dstPath,
+		Strategy:  strategy,
+	}, opts...), nil
+}
+
+// CopyTo triggers the copy of the selection into the destination directory.
+// The destination may belong to another connection, in which case the objects are streamed from one to the other.
+// Objects that already exist in the destination are kept or overwritten according to the strategy.
+func (s *Selection) CopyTo(dst *Directory, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	return s.transferTo(dst, false, strategy, opts...)
+}
+
+// MoveTo triggers the move of the selection into the destination directory.
+// Objects that already exist in the destination are kept or overwritten according to the strategy.
+// Source objects that are kept in the destination are not removed.
+func (s *Selection) MoveTo(dst *Directory, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	return s.transferTo(dst, true, strategy, opts...)
+}
+
+// PreparePaste triggers the listing of the objects covered by the selection,
+// so that their copy or move into the destination directory can be previewed before anything is written.
+func (s *Selection) PreparePaste(dst *Directory, move bool, opts ...event.Option) (event.Event, error) {
+	if err := s.checkDestination(dst); err != nil {
+		return nil, err
+	}
+	return event.New(PastePreviewTriggered{
+		Selection:   s.topLevel(),
+		Destination: dst,
+		Move:        move,
+	}, opts...), nil
+}
+
+func (s *Selection) transferTo(dst *Directory, move bool, strategy MaterializeStrategy, opts ...event.Option) (event.Event, error) {
+	if err := s.checkDestination(dst); err != nil {
+		return nil, err
+	}
+	return event.New(CopySelectionTriggered{
+		Selection:   s.topLevel(),
+		Destination: dst,
+		Move:        move,
+		Strategy:    strategy,
+	}, opts...), nil
+}
+
+// checkDestination returns an error if the selected items can't be copied into dst.
+// Within another connection (hence another bucket), items can't overlap with the destination.
+func (s *Selection) checkDestination(dst *Directory) error {
+	if s.IsEmpty() {
+		return ErrEmptySelection
+	}
+	if dst.ConnectionID() != s.connectionID {
+		return nil
+	}
+	for _, f := range s.files {
+		if f.DirectoryPath() == dst.Path() {
+			return fmt.Errorf("file %s is already in %s", f.Name(), dst.Path())
+		}
+	}
+	for _, d := range s.directories {
+		if d.Parent().Is(dst) {
+			return fmt.Errorf("directory %s is already in %s", d.Name(), dst.Path())
+		}
+		if strings.HasPrefix(dst.Path().String(), d.Path().String()) {
+			return fmt.Errorf("directory %s cannot be copied or moved into itself", d.Path())
+		}
+	}
+	return nil
+}
+
+// topLevel returns a copy of the selection without the items already covered by a selected parent directory.
+// Events carry this copy so that later changes in the selection don't affect running operations.
+func (s *Selection) topLevel() *Selection {
+	res := NewSelection(s.connectionID)
+	isCovered := func(parentPath Path) bool {
+		for dirPath := range s.directories {
+			if strings.HasPrefix(parentPath.String(), dirPath.String()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for p, d := range s.directories {
+		if !isCovered(p.ParentPath()) {
+			res.directories[p] = d
+		}
+	}
+	for key, f := range s.files {
+		if !isCovered(f.DirectoryPath()) {
+			res.files[key] = f
+		}
+	}
+	return res
+}
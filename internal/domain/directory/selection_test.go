@@ -0,0 +1,265 @@
+package directory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+)
+
+func TestSelection(t *testing.T) {
+	t.Run("should add and remove files and directories", func(t *testing.T) {
+		// Given
+		var (
+			subdir *directory.Directory
+			file   *directory.File
+		)
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithFileTo("file.txt", &file),
+			tu.WithSubDirectory("subdir", tu.To(&subdir)))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+
+		// When
+		require.NoError(t, sel.AddFile(file))
+		require.NoError(t, sel.AddDirectory(subdir))
+
+		// Then
+		assert.Equal(t, 2, sel.Len())
+		assert.True(t, sel.ContainsFile(file))
+		assert.True(t, sel.ContainsDirectory(subdir))
+		assert.Equal(t, []*directory.File{file}, sel.Files())
+		assert.Equal(t, []*directory.Directory{subdir}, sel.Directories())
+
+		// When
+		sel.RemoveFile(file)
+
+		// Then
+		assert.False(t, sel.ContainsFile(file))
+		assert.Equal(t, 1, sel.Len())
+
+		// When
+		sel.Clear()
+
+		// Then
+		assert.True(t, sel.IsEmpty())
+	})
+
+	t.Run("should refuse the root directory", func(t *testing.T) {
+		// Given
+		root := tu.MakeDirectory(t, "", tu.AsRoot())
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+
+		// When
+		err := sel.AddDirectory(root)
+
+		// Then
+		assert.Error(t, err)
+		assert.True(t, sel.IsEmpty())
+	})
+
+	t.Run("should refuse a directory from another connection", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "mydir", tu.WithRootParent())
+		sel := directory.NewSelection(connection_deck.NewConnectionID())
+
+		// When
+		err := sel.AddDirectory(dir)
+
+		// Then
+		assert.Error(t, err)
+	})
+
+	t.Run("should return an error when deleting an empty selection", func(t *testing.T) {
+		// Given
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+
+		// When
+		_, err := sel.Delete()
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrEmptySelection)
+	})
+
+	t.Run("should not carry items already covered by a selected directory", func(t *testing.T) {
+		// Given
+		var (
+			mydir, subdir, other *directory.Directory
+			nested, top          *directory.File
+		)
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithFileTo("top.txt", &top),
+			tu.WithSubDirectory("other", tu.To(&other)),
+			tu.WithSubDirectory("mydir",
+				tu.To(&mydir),
+				tu.WithSubDirectory("subdir",
+					tu.To(&subdir),
+					tu.WithFileTo("nested.txt", &nested))))
+
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddDirectory(mydir))
+		require.NoError(t, sel.AddDirectory(subdir))
+		require.NoError(t, sel.AddDirectory(other))
+		require.NoError(t, sel.AddFile(nested))
+		require.NoError(t, sel.AddFile(top))
+
+		// When
+		evt, err := sel.Delete()
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.DeleteSelectionTriggered)
+		assert.Equal(t, []*directory.Directory{mydir, other}, pl.Selection.Directories())
+		assert.Equal(t, []*directory.File{top}, pl.Selection.Files())
+		assert.Equal(t, 5, sel.Len())
+	})
+
+	t.Run("should trigger the download of the selection", func(t *testing.T) {
+		// Given
+		var file *directory.File
+		tu.MakeDirectory(t, "", tu.AsRoot(), tu.WithFileTo("file.txt", &file))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddFile(file))
+
+		// When
+		evt, err := sel.Download("/tmp/local", directory.MaterializeSkip)
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.DownloadSelectionTriggered)
+		assert.Equal(t, "/tmp/local", pl.DstPath)
+		assert.Equal(t, directory.MaterializeStrategy(directory.MaterializeSkip), pl.Strategy)
+		assert.Equal(t, []*directory.File{file}, pl.Selection.Files())
+	})
+
+	t.Run("should trigger the move of the selection to another directory", func(t *testing.T) {
+		// Given
+		var (
+			dst  *directory.Directory
+			file *directory.File
+		)
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithFileTo("file.txt", &file),
+			tu.WithSubDirectory("dst", tu.To(&dst)))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddFile(file))
+
+		// When
+		evt, err := sel.MoveTo(dst, directory.MaterializeReplace)
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.CopySelectionTriggered)
+		assert.True(t, pl.Move)
+		assert.Equal(t, dst, pl.Destination)
+	})
+
+	t.Run("should trigger the copy of the selection into another connection", func(t *testing.T) {
+		// Given
+		var file *directory.File
+		tu.MakeDirectory(t, "", tu.AsRoot(), tu.WithFileTo("file.txt", &file))
+		otherRoot := tu.MakeDirectory(t, "", tu.AsRoot(), tu.WithConnectionId(connection_deck.NewConnectionID()))
+
+		// When
+		evt, err := file.CopyTo(otherRoot, directory.MaterializeSkip)
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.CopySelectionTriggered)
+		assert.False(t, pl.Move)
+		assert.Equal(t, otherRoot, pl.Destination)
+		assert.Equal(t, []*directory.File{file}, pl.Selection.Files())
+	})
+
+	t.Run("should prepare the paste of the selection into another directory", func(t *testing.T) {
+		// Given
+		var (
+			dst, mydir *directory.Directory
+			nested     *directory.File
+		)
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithSubDirectory("dst", tu.To(&dst)),
+			tu.WithSubDirectory("mydir",
+				tu.To(&mydir),
+				tu.WithFileTo("nested.txt", &nested)))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddDirectory(mydir))
+		require.NoError(t, sel.AddFile(nested))
+
+		// When
+		evt, err := sel.PreparePaste(dst, true)
+
+		// Then
+		require.NoError(t, err)
+		pl := evt.Payload().(directory.PastePreviewTriggered)
+		assert.True(t, pl.Move)
+		assert.Equal(t, dst, pl.Destination)
+		assert.Equal(t, []*directory.Directory{mydir}, pl.Selection.Directories())
+		assert.Empty(t, pl.Selection.Files())
+	})
+
+	t.Run("should refuse to copy a directory into itself", func(t *testing.T) {
+		// Given
+		var mydir, subdir *directory.Directory
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithSubDirectory("mydir",
+				tu.To(&mydir),
+				tu.WithSubDirectory("subdir", tu.To(&subdir))))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddDirectory(mydir))
+
+		// When
+		_, err := sel.CopyTo(subdir, directory.MaterializeReplace)
+
+		// Then
+		assert.Error(t, err)
+	})
+
+	t.Run("should refuse to copy a file into its own directory", func(t *testing.T) {
+		// Given
+		var file *directory.File
+		root := tu.MakeDirectory(t, "", tu.AsRoot(), tu.WithFileTo("file.txt", &file))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddFile(file))
+
+		// When
+		_, err := sel.CopyTo(root, directory.MaterializeReplace)
+
+		// Then
+		assert.Error(t, err)
+	})
+
+	t.Run("should remove the deleted items from their parent directory", func(t *testing.T) {
+		// Given
+		var (
+			subdir *directory.Directory
+			file   *directory.File
+		)
+		root := tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded(),
+			tu.WithFileTo("file.txt", &file),
+			tu.WithFile("kept.txt"),
+			tu.WithSubDirectory("subdir", tu.To(&subdir)))
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddFile(file))
+		require.NoError(t, sel.AddDirectory(subdir))
+
+		// When
+		err := root.Notify(event.New(directory.DeleteSelectionSucceeded{Selection: sel, DeletedCount: 2}))
+
+		// Then
+		require.NoError(t, err)
+		assert.Empty(t, root.SubDirectories())
+		require.Len(t, root.Files(), 1)
+		assert.Equal(t, directory.FileName("kept.txt"), root.Files()[0].Name())
+	})
+}
@@ -0,0 +1,37 @@
+package directory
+
+import (
+	"fmt"
+	"time"
+)
+
+// ShareLinkMaxExpiry is the longest validity accepted for a presigned URL.
+const ShareLinkMaxExpiry = 7 * 24 * time.Hour
+
+// ShareLinkAccess is the operation granted by a share link.
+type ShareLinkAccess int
+
+const (
+	// ShareLinkDownload grants the download of the file (GET).
+	ShareLinkDownload ShareLinkAccess = iota
+	// ShareLinkUpload grants the upload of a new content for the file (PUT).
+	ShareLinkUpload
+)
+
+func (a ShareLinkAccess) String() string {
+	switch a {
+	case ShareLinkDownload:
+		return "Download"
+	case ShareLinkUpload:
+		return "Upload"
+	default:
+		panic("invalid share link access")
+	}
+}
+
+func validateShareLinkExpiry(expiry time.Duration) error {
+	if expiry <= 0 || expiry > ShareLinkMaxExpiry {
+		return fmt.Errorf("%w: expiry must be between 1s and %s, got %s", ErrInvalidShareLink, ShareLinkMaxExpiry, expiry)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package directory
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// SizeStatsLargestFilesCount is the number of largest files kept by the size statistics.
+const SizeStatsLargestFilesCount = 10
+
+// SizeStatsEntry sums the size of a group of objects.
+type SizeStatsEntry struct {
+	Bytes       uint64
+	ObjectCount int
+}
+
+func (e *SizeStatsEntry) add(sizeBytes uint64) {
+	e.Bytes += sizeBytes
+	e.ObjectCount++
+}
+
+// SizeStatsFile is one of the largest files found under a directory.
+type SizeStatsFile struct {
+	FullPath  string
+	SizeBytes uint64
+}
+
+// SizeStats is a value object holding the size of the objects stored under a directory, recursively.
+type SizeStats struct {
+	Total SizeStatsEntry
+	// ByStorageClass groups the objects by storage class, like STANDARD or GLACIER
+	ByStorageClass map[string]SizeStatsEntry
+	// BySubDirectory groups the objects by immediate subdirectory name,
+	// the files stored directly in the directory being grouped under an empty name
+	BySubDirectory map[string]SizeStatsEntry
+	// LargestFiles holds the largest files, from the largest one
+	LargestFiles []SizeStatsFile
+	ComputedAt   time.Time
+}
+
+// NewSizeStats creates empty statistics, to be filled with Add.
+func NewSizeStats() SizeStats {
+	return SizeStats{
+		ByStorageClass: make(map[string]SizeStatsEntry),
+		BySubDirectory: make(map[string]SizeStatsEntry),
+		LargestFiles:   make([]SizeStatsFile, 0, SizeStatsLargestFilesCount),
+	}
+}
+
+// Add counts an object stored under the directory.
+// The key is the path of the object relative to the directory.
+func (s *SizeStats) Add(dirPath Path, relativeKey string, sizeBytes uint64, storageClass string) {
+	s.Total.add(sizeBytes)
+
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+	byClass := s.ByStorageClass[storageClass]
+	byClass.add(sizeBytes)
+	s.ByStorageClass[storageClass] = byClass
+
+	subDir, _, isNested := strings.Cut(relativeKey, "/")
+	if !isNested {
+		subDir = ""
+	}
+	bySubDir := s.BySubDirectory[subDir]
+	bySubDir.add(sizeBytes)
+	s.BySubDirectory[subDir] = bySubDir
+
+	if strings.HasSuffix(relativeKey, "/") {
+		return
+	}
+	idx, _ := slices.BinarySearchFunc(s.LargestFiles, sizeBytes, func(f SizeStatsFile, size uint64) int {
+		// sorted from the largest file
+		switch {
+		case f.SizeBytes > size:
+			return -1
+		case f.SizeBytes < size:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if idx >= SizeStatsLargestFilesCount {
+		return
+	}
+	s.LargestFiles = slices.Insert(s.LargestFiles, idx, SizeStatsFile{
+		FullPath:  dirPath.String() + relativeKey,
+		SizeBytes: sizeBytes,
+	})
+	if len(s.LargestFiles) > SizeStatsLargestFilesCount {
+		s.LargestFiles = s.LargestFiles[:SizeStatsLargestFilesCount]
+	}
+}
+
+// SubDirectoryNames returns the names of the immediate subdirectories, sorted from the largest one.
+func (s SizeStats) SubDirectoryNames() []string {
+	return sortedBySize(s.BySubDirectory)
+}
+
+// StorageClasses returns the storage classes of the objects, sorted from the largest one.
+func (s SizeStats) StorageClasses() []string {
+	return sortedBySize(s.ByStorageClass)
+}
+
+func sortedBySize(entries map[string]SizeStatsEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if entries[a].Bytes != entries[b].Bytes {
+			if entries[a].Bytes > entries[b].Bytes {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	return keys
+}
@@ -0,0 +1,80 @@
+package directory_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+)
+
+func TestSizeStats_Add(t *testing.T) {
+	t.Run("should sum the objects by storage class and by subdirectory", func(t *testing.T) {
+		// Given
+		stats := directory.NewSizeStats()
+
+		// When
+		stats.Add("/data/", "readme.md", 10, "")
+		stats.Add("/data/", "2024/q1.csv", 100, "STANDARD")
+		stats.Add("/data/", "2024/q2.csv", 200, "GLACIER")
+		stats.Add("/data/", "archive/old/2019.csv", 1000, "GLACIER")
+
+		// Then
+		assert.Equal(t, directory.SizeStatsEntry{Bytes: 1310, ObjectCount: 4}, stats.Total)
+		assert.Equal(t, map[string]directory.SizeStatsEntry{
+			"STANDARD": {Bytes: 110, ObjectCount: 2},
+			"GLACIER":  {Bytes: 1200, ObjectCount: 2},
+		}, stats.ByStorageClass)
+		assert.Equal(t, map[string]directory.SizeStatsEntry{
+			"":        {Bytes: 10, ObjectCount: 1},
+			"2024":    {Bytes: 300, ObjectCount: 2},
+			"archive": {Bytes: 1000, ObjectCount: 1},
+		}, stats.BySubDirectory)
+		assert.Equal(t, []string{"archive", "2024", ""}, stats.SubDirectoryNames())
+		assert.Equal(t, []string{"GLACIER", "STANDARD"}, stats.StorageClasses())
+	})
+
+	t.Run("should keep the largest files only, from the largest one", func(t *testing.T) {
+		// Given
+		stats := directory.NewSizeStats()
+
+		// When
+		for i := range 2 * directory.SizeStatsLargestFilesCount {
+			stats.Add("/", fmt.Sprintf("file-%d", i), uint64(i), "")
+		}
+
+		// Then
+		require.Len(t, stats.LargestFiles, directory.SizeStatsLargestFilesCount)
+		assert.Equal(t, directory.SizeStatsFile{FullPath: "/file-19", SizeBytes: 19}, stats.LargestFiles[0])
+		assert.Equal(t, directory.SizeStatsFile{FullPath: "/file-10", SizeBytes: 10}, stats.LargestFiles[9])
+	})
+}
+
+func TestDirectory_SizeStats(t *testing.T) {
+	t.Run("should cache the size statistics until the next load", func(t *testing.T) {
+		// Given
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		_, err := dir.Load()
+		require.NoError(t, err)
+		require.NoError(t, dir.Notify(event.New(directory.LoadSucceeded{Directory: dir})))
+
+		stats := directory.NewSizeStats()
+		stats.Add(dir.Path(), "a.txt", 10, "")
+
+		// When
+		dir.SetSizeStats(stats)
+
+		// Then
+		res, ok := dir.SizeStats()
+		assert.True(t, ok)
+		assert.Equal(t, uint64(10), res.Total.Bytes)
+
+		_, err = dir.Load()
+		require.NoError(t, err)
+		_, ok = dir.SizeStats()
+		assert.False(t, ok)
+	})
+}
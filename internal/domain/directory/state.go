@@ -1,6 +1,11 @@
 package directory
 
-import "github.com/thomas-marquis/it-happened/event"
+import (
+	"maps"
+	"slices"
+
+	"github.com/thomas-marquis/it-happened/event"
+)
 
 type StateType int
 
@@ -13,7 +18,9 @@ const (
 
 type state interface {
 	Type() StateType
-	Load() (event.Event, error)
+	Load(opts ...LoadOption) (event.Event, error)
+	LoadNextPage() (event.Event, error)
+	HasMorePages() bool
 	Status() Status
 	Recover(choice RecoveryChoice) (event.Event, error)
 	Files() []*File
@@ -49,6 +56,32 @@ func (s *baseState) Files() []*File {
 	return make([]*File, 0)
 }
 
+func (s *baseState) LoadNextPage() (event.Event, error) {
+	return nil, ErrNotLoaded
+}
+
+func (s *baseState) HasMorePages() bool {
+	return false
+}
+
+func sortedSubDirectories(subDirs map[Path]*Directory) (dirs []*Directory) {
+	keys := slices.Collect(maps.Keys(subDirs))
+	slices.Sort(keys)
+	for _, path := range keys {
+		dirs = append(dirs, subDirs[path])
+	}
+	return
+}
+
+func sortedFiles(files map[FileName]*File) (res []*File) {
+	keys := slices.Collect(maps.Keys(files))
+	slices.Sort(keys)
+	for _, name := range keys {
+		res = append(res, files[name])
+	}
+	return
+}
+
 func (s *baseState) UploadFile(string, bool) (event.Event, error) {
 	return nil, ErrNotLoaded
 }
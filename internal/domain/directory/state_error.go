@@ -21,10 +21,10 @@ func (s *errorState) Type() StateType {
 	return stateTypeError
 }
 
-func (s *errorState) Load() (event.Event, error) {
+func (s *errorState) Load(opts ...LoadOption) (event.Event, error) {
 	// reload
 	s.d.setState(newLoadingState(s.baseState))
-	return event.New(LoadTriggered{Directory: s.d}), nil
+	return event.New(newLoadTriggered(s.d, opts)), nil
 }
 
 func (s *errorState) UploadFile(localPtah string, overwrite bool) (event.Event, error) {
@@ -71,6 +71,16 @@ func (s *errorState) Recover(choice RecoveryChoice) (event.Event, error) {
 			dstDir = s.d
 		}
 
+		if status.IsMove() {
+			s.d.setState(newLoadingState(s.baseState))
+			return event.New(RenameRecoveryTriggered{
+				Directory:    srcDir,
+				DstDir:       dstDir,
+				Choice:       choice,
+				OtherDirPath: status.OtherDirPath,
+			}), nil
+		}
+
 		parent := s.d.parent // no need to check parent nullity: renaming root dir is forbidden
 		otherDir, err := parent.GetSubDirectoryByName(status.OtherDirPath.DirectoryName())
 		if err != nil {
@@ -3,15 +3,15 @@ package directory
 import (
 	"errors"
 	"fmt"
-	"maps"
 	"path/filepath"
-	"slices"
 
 	"github.com/thomas-marquis/it-happened/event"
 )
 
 type loadedState struct {
 	baseState
+	// nextPageToken is set while the directory is partially loaded
+	nextPageToken string
 }
 
 var _ state = (*loadedState)(nil)
@@ -26,35 +26,37 @@ func newLoadedState(previous baseState, subDirs map[Path]*Directory, files map[F
 	}
 	bs.subDirs = subDirs
 	bs.files = files
-	return &loadedState{bs}
+	return &loadedState{baseState: bs}
 }
 
 func (s *loadedState) Type() StateType {
 	return stateTypeLoaded
 }
 
-func (s *loadedState) SubDirectories() (dirs []*Directory) {
-	keys := slices.Collect(maps.Keys(s.subDirs))
-	slices.Sort(keys)
-	for _, path := range keys {
-		dirs = append(dirs, s.subDirs[path])
-	}
-	return
+func (s *loadedState) SubDirectories() []*Directory {
+	return sortedSubDirectories(s.subDirs)
+}
+
+func (s *loadedState) Files() []*File {
+	return sortedFiles(s.files)
 }
 
-func (s *loadedState) Files() (files []*File) {
-	keys := slices.Collect(maps.Keys(s.files))
-	slices.Sort(keys)
-	for _, name := range keys {
-		files = append(files, s.files[name])
+func (s *loadedState) HasMorePages() bool {
+	return s.nextPageToken != ""
+}
+
+func (s *loadedState) LoadNextPage() (event.Event, error) {
+	if s.nextPageToken == "" {
+		return nil, ErrNoMorePages
 	}
-	return
+	s.d.setState(newLoadingPageState(s.baseState, s.nextPageToken))
+	return event.New(LoadPageTriggered{Directory: s.d, PageToken: s.nextPageToken}), nil
 }
 
-func (s *loadedState) Load() (event.Event, error) {
+func (s *loadedState) Load(opts ...LoadOption) (event.Event, error) {
 	// reload
 	s.d.setState(newLoadingState(s.baseState))
-	return event.New(LoadTriggered{Directory: s.d}), nil
+	return event.New(newLoadTriggered(s.d, opts)), nil
 }
 
 func (s *loadedState) UploadFile(localPath string, overwrite bool) (event.Event, error) {
@@ -69,6 +71,7 @@ func (s *loadedState) UploadFile(localPath string, overwrite bool) (event.Event,
 	uploadedEvt := event.New(UploadFileTriggered{
 		Directory: s.d,
 		SrcPath:   localPath,
+		Overwrite: overwrite,
 	})
 
 	return uploadedEvt, nil
@@ -137,10 +140,32 @@ func (s *loadedState) Notify(evt event.Event) error {
 		if !s.updateFile(f) {
 			s.files[f.Name()] = f
 		}
+
+	case DeleteSelectionSucceeded:
+		s.removeSelected(pl.Selection)
+
+	case CopySelectionSucceeded:
+		if pl.Move {
+			s.removeSelected(pl.Selection)
+		}
 	}
 	return nil
 }
 
+// removeSelected forgets the selected files and subdirectories that are direct children of the directory.
+func (s *loadedState) removeSelected(sel *Selection) {
+	for _, f := range sel.Files() {
+		if f.DirectoryPath() == s.d.path {
+			delete(s.files, f.Name())
+		}
+	}
+	for _, d := range sel.Directories() {
+		if d.Parent().Is(s.d) {
+			delete(s.subDirs, d.Path())
+		}
+	}
+}
+
 func (s *loadedState) updateFile(f *File) bool {
 	if _, found := s.files[f.Name()]; found {
 		s.files[f.Name()] = f
@@ -2,25 +2,47 @@ package directory
 
 import (
 	"errors"
+	"maps"
 
 	"github.com/thomas-marquis/it-happened/event"
 )
 
 type loadingState struct {
 	baseState
+	// pageToken is set when loading the next page of a partially loaded directory,
+	// the already loaded content being kept meanwhile
+	pageToken string
 }
 
 var _ state = (*loadingState)(nil)
 
 func newLoadingState(previous baseState) *loadingState {
-	return &loadingState{previous.Clone()}
+	return &loadingState{baseState: previous.Clone()}
+}
+
+func newLoadingPageState(previous baseState, pageToken string) *loadingState {
+	return &loadingState{baseState: previous.Clone(), pageToken: pageToken}
+}
+
+func (s *loadingState) SubDirectories() []*Directory {
+	if s.pageToken == "" {
+		return s.baseState.SubDirectories()
+	}
+	return sortedSubDirectories(s.subDirs)
+}
+
+func (s *loadingState) Files() []*File {
+	if s.pageToken == "" {
+		return s.baseState.Files()
+	}
+	return sortedFiles(s.files)
 }
 
 func (s *loadingState) Type() StateType {
 	return stateTypeLoading
 }
 
-func (s *loadingState) Load() (event.Event, error) {
+func (s *loadingState) Load(...LoadOption) (event.Event, error) {
 	return nil, NewError(s.d, "loading is still in progress")
 }
 
@@ -35,7 +57,28 @@ func (s *loadingState) Notify(evt event.Event) error {
 		for _, subDir := range pl.SubDirectories {
 			subDirs[subDir.Path()] = subDir
 		}
-		s.d.setState(newLoadedState(s.baseState, subDirs, files))
+		loaded := newLoadedState(s.baseState, subDirs, files)
+		loaded.nextPageToken = pl.NextPageToken
+		s.d.setState(loaded)
+
+	case LoadPageSucceeded:
+		files := maps.Clone(s.files)
+		for _, file := range pl.Files {
+			files[file.Name()] = file
+		}
+		subDirs := maps.Clone(s.subDirs)
+		for _, subDir := range pl.SubDirectories {
+			subDirs[subDir.Path()] = subDir
+		}
+		loaded := newLoadedState(s.baseState, subDirs, files)
+		loaded.nextPageToken = pl.NextPageToken
+		s.d.setState(loaded)
+
+	case LoadPageFailed:
+		// keep the already loaded content, the page can be loaded again
+		loaded := newLoadedState(s.baseState, s.subDirs, s.files)
+		loaded.nextPageToken = s.pageToken
+		s.d.setState(loaded)
 
 	case LoadFailed:
 		var urErr UncompletedRename
@@ -48,7 +91,12 @@ func (s *loadingState) Notify(evt event.Event) error {
 				otherPath = urErr.SourceDirPath
 			}
 
-			otherDir, err := s.d.parent.GetSubDirectoryByName(otherPath.DirectoryName())
+			// the other directory is a sibling, unless a move between directories was interrupted
+			var otherDir *Directory
+			err := ErrNotFound
+			if otherPath.ParentPath() == s.d.parent.Path() {
+				otherDir, err = s.d.parent.GetSubDirectoryByName(otherPath.DirectoryName())
+			}
 			if err == nil && !otherDir.HasError() {
 				otherDir.setState(newErrorState(baseState{d: otherDir},
 					RenameFailedStatus{
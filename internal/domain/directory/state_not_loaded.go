@@ -15,9 +15,9 @@ func newNotLoadedState(d *Directory, status Status) *notLoadedState {
 
 func (s *notLoadedState) Type() StateType { return stateTypeNotLoaded }
 
-func (s *notLoadedState) Load() (event.Event, error) {
+func (s *notLoadedState) Load(opts ...LoadOption) (event.Event, error) {
 	s.d.setState(newLoadingState(s.baseState))
-	return event.New(LoadTriggered{Directory: s.d}), nil
+	return event.New(newLoadTriggered(s.d, opts)), nil
 }
 
 func (s *notLoadedState) Notify(event.Event) error {
@@ -17,22 +17,36 @@ type RenameFailedStatus struct {
 }
 
 func (s RenameFailedStatus) Title() string {
+	if s.IsMove() {
+		return "Move pending"
+	}
 	return "Rename pending"
 }
 
 func (s RenameFailedStatus) Message() string {
 	msg := strings.Builder{}
-	fmt.Fprintf(&msg, "A rename operation is pending for this directory ") // nolint:errcheck
 	srcPath := s.CurrentDirectory.Path()
 	dstPath := s.OtherDirPath
 	if !s.IsSourceDir {
 		srcPath, dstPath = dstPath, srcPath
 	}
+	if s.IsMove() {
+		fmt.Fprintf(&msg, "A move operation is pending for this directory ")       // nolint:errcheck
+		fmt.Fprintf(&msg, "from '%s' to '%s'", srcPath.String(), dstPath.String()) // nolint:errcheck
+		return msg.String()
+	}
+	fmt.Fprintf(&msg, "A rename operation is pending for this directory ")                   // nolint:errcheck
 	fmt.Fprintf(&msg, "from '%s' to '%s'", srcPath.DirectoryName(), dstPath.DirectoryName()) // nolint:errcheck
 	return msg.String()
 
 }
 
+// IsMove tells whether the pending operation moves the directory under another parent
+// instead of renaming it in place.
+func (s RenameFailedStatus) IsMove() bool {
+	return s.OtherDirPath.ParentPath() != s.CurrentDirectory.Path().ParentPath()
+}
+
 type ErrorStatus struct {
 	Err error
 }
@@ -0,0 +1,249 @@
+package directory
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thomas-marquis/it-happened/event"
+)
+
+// SyncDirection tells which side of a sync is the source, the other one being made identical to it.
+type SyncDirection int
+
+const (
+	SyncToBucket SyncDirection = iota
+	SyncToLocal
+)
+
+func (d SyncDirection) String() string {
+	switch d {
+	case SyncToBucket:
+		return "Local folder to bucket"
+	case SyncToLocal:
+		return "Bucket to local folder"
+	default:
+		return "unknown"
+	}
+}
+
+type SyncOptions struct {
+	Direction SyncDirection
+	// DeleteExtraneous deletes from the destination the files that are not in the source
+	DeleteExtraneous bool
+	// CompareChecksums compares the MD5 of the local files with the ETag of the objects, when it is one.
+	// Two files with the same checksum are considered identical, whatever their modification dates.
+	CompareChecksums bool
+}
+
+// SyncEntry is a file on one side of a sync.
+type SyncEntry struct {
+	// RelativePath is the slash separated path of the file from the synced directory
+	RelativePath string
+	SizeBytes    uint64
+	LastModified time.Time
+	// Checksum is the hexadecimal MD5 of the content, empty when unknown
+	Checksum string
+}
+
+type SyncAction int
+
+const (
+	// SyncCreate copies a file missing from the destination
+	SyncCreate SyncAction = iota
+	// SyncUpdate replaces a file of the destination that differs from the source
+	SyncUpdate
+	// SyncDelete removes a file of the destination that is not in the source
+	SyncDelete
+	// SyncKeep leaves untouched a file of the destination that is not in the source
+	SyncKeep
+)
+
+func (a SyncAction) String() string {
+	switch a {
+	case SyncCreate:
+		return "New"
+	case SyncUpdate:
+		return "Changed"
+	case SyncDelete:
+		return "Deleted"
+	case SyncKeep:
+		return "Kept"
+	default:
+		return "unknown"
+	}
+}
+
+// SyncChange is a difference between the source and the destination, and what the sync will do about it.
+type SyncChange struct {
+	RelativePath string
+	Action       SyncAction
+	// Source is the zero value when the file is only in the destination
+	Source SyncEntry
+	// Destination is the zero value when the file is only in the source
+	Destination SyncEntry
+	Reason      string
+}
+
+// SyncPlan is the result of the comparison between a local folder and a directory of the bucket.
+// Applying it makes the destination identical to the source.
+type SyncPlan struct {
+	Directory *Directory
+	LocalPath string
+	Options   SyncOptions
+	// Changes are sorted by path, the unchanged files being left out
+	Changes        []SyncChange
+	UnchangedCount int
+}
+
+// NewSyncPlan compares the files of the local folder with the ones of the directory.
+// A file is considered changed when its size differs, when its checksum differs if both are known,
+// or else when the source one was modified after the destination one.
+func NewSyncPlan(dir *Directory, localPath string, opts SyncOptions, localEntries, bucketEntries []SyncEntry) *SyncPlan {
+	src, dst := localEntries, bucketEntries
+	if opts.Direction == SyncToLocal {
+		src, dst = bucketEntries, localEntries
+	}
+
+	dstByPath := make(map[string]SyncEntry, len(dst))
+	for _, e := range dst {
+		dstByPath[e.RelativePath] = e
+	}
+
+	plan := &SyncPlan{Directory: dir, LocalPath: localPath, Options: opts}
+	for _, s := range src {
+		d, found := dstByPath[s.RelativePath]
+		if !found {
+			plan.Changes = append(plan.Changes, SyncChange{
+				RelativePath: s.RelativePath,
+				Action:       SyncCreate,
+				Source:       s,
+				Reason:       "The file doesn't exist in the destination yet",
+			})
+			continue
+		}
+		delete(dstByPath, s.RelativePath)
+
+		if changed, reason := compareSyncEntries(s, d, opts); changed {
+			plan.Changes = append(plan.Changes, SyncChange{
+				RelativePath: s.RelativePath,
+				Action:       SyncUpdate,
+				Source:       s,
+				Destination:  d,
+				Reason:       reason,
+			})
+		} else {
+			plan.UnchangedCount++
+		}
+	}
+
+	for _, d := range dstByPath {
+		change := SyncChange{
+			RelativePath: d.RelativePath,
+			Action:       SyncKeep,
+			Destination:  d,
+			Reason:       "The file is not in the source and will be kept",
+		}
+		if opts.DeleteExtraneous {
+			change.Action = SyncDelete
+			change.Reason = "The file is not in the source and will be deleted"
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	slices.SortFunc(plan.Changes, func(a, b SyncChange) int {
+		return strings.Compare(a.RelativePath, b.RelativePath)
+	})
+	return plan
+}
+
+func compareSyncEntries(src, dst SyncEntry, opts SyncOptions) (bool, string) {
+	if src.SizeBytes != dst.SizeBytes {
+		return true, fmt.Sprintf("The size differs (source: %dKB; destination: %dKB)",
+			src.SizeBytes/1024, dst.SizeBytes/1024)
+	}
+	if opts.CompareChecksums && src.Checksum != "" && dst.Checksum != "" {
+		if src.Checksum != dst.Checksum {
+			return true, "The content differs"
+		}
+		return false, ""
+	}
+	if src.LastModified.After(dst.LastModified) {
+		return true, fmt.Sprintf("The source was modified more recently (source: %s; destination: %s)",
+			src.LastModified.Format(time.DateTime), dst.LastModified.Format(time.DateTime))
+	}
+	return false, ""
+}
+
+// Copies returns the changes copying a file from the source to the destination.
+func (p *SyncPlan) Copies() []SyncChange {
+	return p.filter(SyncCreate, SyncUpdate)
+}
+
+// Deletions returns the changes deleting a file from the destination.
+func (p *SyncPlan) Deletions() []SyncChange {
+	return p.filter(SyncDelete)
+}
+
+// IsEmpty returns true when applying the plan wouldn't change anything.
+func (p *SyncPlan) IsEmpty() bool {
+	return len(p.Copies()) == 0 && len(p.Deletions()) == 0
+}
+
+func (p *SyncPlan) filter(actions ...SyncAction) []SyncChange {
+	var res []SyncChange
+	for _, c := range p.Changes {
+		if slices.Contains(actions, c.Action) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Preview returns the tree of the changes, displayed as a dry-run before applying the plan.
+func (p *SyncPlan) Preview() (*Preview, error) {
+	prev, err := p.Directory.Preview()
+	if err != nil {
+		return nil, err
+	}
+	prev.syncChanges = make(map[string]SyncChange, len(p.Changes))
+	for _, c := range p.Changes {
+		prev.syncChanges[c.RelativePath] = c
+		size, lastModified := c.Source.SizeBytes, c.Source.LastModified
+		if c.Action == SyncDelete || c.Action == SyncKeep {
+			size, lastModified = c.Destination.SizeBytes, c.Destination.LastModified
+		}
+		if err := prev.AddObject(c.RelativePath, size, lastModified); err != nil {
+			return nil, err
+		}
+	}
+	return prev, nil
+}
+
+// Apply triggers the copies and deletions of the plan.
+// The sync can be canceled through the event context.
+func (p *SyncPlan) Apply(opts ...event.Option) event.Event {
+	return event.New(SyncTriggered{Plan: p}, opts...)
+}
+
+// PrepareSync triggers the comparison of the directory with a local folder, without changing anything.
+// The resulting plan can then be applied.
+func (d *Directory) PrepareSync(localPath string, opts SyncOptions, evtOpts ...event.Option) event.Event {
+	return event.New(SyncPreviewTriggered{
+		Directory: d,
+		LocalPath: localPath,
+		Options:   opts,
+	}, evtOpts...)
+}
+
+// relativePath returns the slash separated path of a previewed file from the mount point.
+func (p *Preview) relativePath(fileName string) string {
+	names := []string{fileName}
+	for curr := p; curr.parent != nil; curr = curr.parent {
+		names = append(names, curr.dir.Name())
+	}
+	slices.Reverse(names)
+	return path.Join(names...)
+}
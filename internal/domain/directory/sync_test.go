@@ -0,0 +1,146 @@
+package directory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+)
+
+func TestNewSyncPlan(t *testing.T) {
+	older := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	t.Run("should create the missing files and update the changed ones in the bucket", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "data", tu.WithRootParent(), tu.IsLoaded())
+		local := []directory.SyncEntry{
+			{RelativePath: "new.txt", SizeBytes: 10, LastModified: older},
+			{RelativePath: "resized.txt", SizeBytes: 20, LastModified: older},
+			{RelativePath: "sub/touched.txt", SizeBytes: 30, LastModified: newer},
+			{RelativePath: "same.txt", SizeBytes: 40, LastModified: older},
+		}
+		bucket := []directory.SyncEntry{
+			{RelativePath: "resized.txt", SizeBytes: 21, LastModified: newer},
+			{RelativePath: "sub/touched.txt", SizeBytes: 30, LastModified: older},
+			{RelativePath: "same.txt", SizeBytes: 40, LastModified: newer},
+		}
+
+		// When
+		plan := directory.NewSyncPlan(dir, "/home/user/data", directory.SyncOptions{}, local, bucket)
+
+		// Then
+		require.Len(t, plan.Changes, 3)
+		assert.Equal(t, "new.txt", plan.Changes[0].RelativePath)
+		assert.Equal(t, directory.SyncCreate, plan.Changes[0].Action)
+		assert.Equal(t, "resized.txt", plan.Changes[1].RelativePath)
+		assert.Equal(t, directory.SyncUpdate, plan.Changes[1].Action)
+		assert.Equal(t, "sub/touched.txt", plan.Changes[2].RelativePath)
+		assert.Equal(t, directory.SyncUpdate, plan.Changes[2].Action)
+		assert.Equal(t, 1, plan.UnchangedCount)
+		assert.Len(t, plan.Copies(), 3)
+		assert.Empty(t, plan.Deletions())
+	})
+
+	t.Run("should keep the extraneous files of the destination by default", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "data", tu.WithRootParent(), tu.IsLoaded())
+		bucket := []directory.SyncEntry{{RelativePath: "old.txt", SizeBytes: 10, LastModified: older}}
+
+		// When
+		plan := directory.NewSyncPlan(dir, "/home/user/data", directory.SyncOptions{}, nil, bucket)
+
+		// Then
+		require.Len(t, plan.Changes, 1)
+		assert.Equal(t, directory.SyncKeep, plan.Changes[0].Action)
+		assert.True(t, plan.IsEmpty())
+	})
+
+	t.Run("should delete the extraneous files of the destination when asked to", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "data", tu.WithRootParent(), tu.IsLoaded())
+		bucket := []directory.SyncEntry{{RelativePath: "old.txt", SizeBytes: 10, LastModified: older}}
+		opts := directory.SyncOptions{DeleteExtraneous: true}
+
+		// When
+		plan := directory.NewSyncPlan(dir, "/home/user/data", opts, nil, bucket)
+
+		// Then
+		require.Len(t, plan.Deletions(), 1)
+		assert.Equal(t, "old.txt", plan.Deletions()[0].RelativePath)
+		assert.False(t, plan.IsEmpty())
+	})
+
+	t.Run("should consider files with the same checksum as unchanged whatever their dates", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "data", tu.WithRootParent(), tu.IsLoaded())
+		local := []directory.SyncEntry{
+			{RelativePath: "same.txt", SizeBytes: 10, LastModified: newer, Checksum: "aaa"},
+			{RelativePath: "edited.txt", SizeBytes: 10, LastModified: older, Checksum: "bbb"},
+		}
+		bucket := []directory.SyncEntry{
+			{RelativePath: "same.txt", SizeBytes: 10, LastModified: older, Checksum: "aaa"},
+			{RelativePath: "edited.txt", SizeBytes: 10, LastModified: newer, Checksum: "ccc"},
+		}
+		opts := directory.SyncOptions{CompareChecksums: true}
+
+		// When
+		plan := directory.NewSyncPlan(dir, "/home/user/data", opts, local, bucket)
+
+		// Then
+		require.Len(t, plan.Changes, 1)
+		assert.Equal(t, "edited.txt", plan.Changes[0].RelativePath)
+		assert.Equal(t, directory.SyncUpdate, plan.Changes[0].Action)
+		assert.Equal(t, 1, plan.UnchangedCount)
+	})
+
+	t.Run("should take the bucket as the source when syncing to the local folder", func(t *testing.T) {
+		// Given
+		dir := tu.MakeDirectory(t, "data", tu.WithRootParent(), tu.IsLoaded())
+		local := []directory.SyncEntry{{RelativePath: "local-only.txt", SizeBytes: 10, LastModified: older}}
+		bucket := []directory.SyncEntry{{RelativePath: "remote-only.txt", SizeBytes: 10, LastModified: older}}
+		opts := directory.SyncOptions{Direction: directory.SyncToLocal, DeleteExtraneous: true}
+
+		// When
+		plan := directory.NewSyncPlan(dir, "/home/user/data", opts, local, bucket)
+
+		// Then
+		require.Len(t, plan.Changes, 2)
+		assert.Equal(t, "local-only.txt", plan.Changes[0].RelativePath)
+		assert.Equal(t, directory.SyncDelete, plan.Changes[0].Action)
+		assert.Equal(t, "remote-only.txt", plan.Changes[1].RelativePath)
+		assert.Equal(t, directory.SyncCreate, plan.Changes[1].Action)
+	})
+}
+
+func TestSyncPlan_Preview(t *testing.T) {
+	t.Run("should describe each change as the status of its file", func(t *testing.T) {
+		// Given
+		now := time.Now()
+		dir := tu.MakeDirectory(t, "data", tu.WithRootParent(), tu.IsLoaded())
+		local := []directory.SyncEntry{{RelativePath: "sub/new.txt", SizeBytes: 10, LastModified: now}}
+		bucket := []directory.SyncEntry{{RelativePath: "old.txt", SizeBytes: 10, LastModified: now}}
+		plan := directory.NewSyncPlan(dir, "/home/user/data",
+			directory.SyncOptions{DeleteExtraneous: true}, local, bucket)
+
+		// When
+		prev, err := plan.Preview()
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, prev.IsSync())
+		assert.Equal(t, []directory.MaterializeStrategy{directory.MaterializeSkip}, prev.AvailableStrategies())
+
+		status, _, err := prev.FileStatus(directory.MaterializeSkip, "old.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "Deleted", status)
+
+		require.Len(t, prev.Children(), 1)
+		status, _, err = prev.Children()[0].FileStatus(directory.MaterializeSkip, "new.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "New", status)
+	})
+}
@@ -0,0 +1,11 @@
+package transfer
+
+import "errors"
+
+var (
+	ErrNotFound          = errors.New("transfer not found")
+	ErrAlreadyExists     = errors.New("transfer already exists")
+	ErrInvalidTransition = errors.New("invalid transfer status transition")
+	ErrTechnical         = errors.New("technical error occurred")
+	ErrTargetChanged     = errors.New("the bucket or the root prefix of the connection changed since the transfer was queued")
+)
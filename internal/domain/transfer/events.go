@@ -0,0 +1,153 @@
+package transfer
+
+import (
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+const (
+	JobUpdatedType event.Type = "transfer.job.updated"
+)
+
+// JobUpdated is published each time a job is added, progresses or changes status.
+type JobUpdated struct {
+	Job Job
+}
+
+func (e JobUpdated) EventType() event.Type {
+	return JobUpdatedType
+}
+
+const (
+	PauseTriggeredType  event.Type = "transfer.job.pause.triggered"
+	ResumeTriggeredType event.Type = "transfer.job.resume.triggered"
+	CancelTriggeredType event.Type = "transfer.job.cancel.triggered"
+)
+
+type PauseTriggered struct {
+	JobID JobID
+}
+
+func (e PauseTriggered) EventType() event.Type {
+	return PauseTriggeredType
+}
+
+type ResumeTriggered struct {
+	JobID JobID
+}
+
+func (e ResumeTriggered) EventType() event.Type {
+	return ResumeTriggeredType
+}
+
+type CancelTriggered struct {
+	JobID JobID
+}
+
+func (e CancelTriggered) EventType() event.Type {
+	return CancelTriggeredType
+}
+
+const (
+	ClearFinishedTriggeredType event.Type = "transfer.clear.triggered"
+	ClearFinishedSucceededType event.Type = "transfer.clear.succeeded"
+)
+
+type ClearFinishedTriggered struct{}
+
+func (e ClearFinishedTriggered) EventType() event.Type {
+	return ClearFinishedTriggeredType
+}
+
+type ClearFinishedSucceeded struct {
+	JobIDs []JobID
+}
+
+func (e ClearFinishedSucceeded) EventType() event.Type {
+	return ClearFinishedSucceededType
+}
+
+const (
+	RestoreTriggeredType        event.Type = "transfer.restore.triggered"
+	SetConcurrencyTriggeredType event.Type = "transfer.concurrency.set.triggered"
+)
+
+// RestoreTriggered asks to put back in the queue the jobs saved before the application was closed.
+type RestoreTriggered struct {
+	Jobs []Job
+}
+
+func (e RestoreTriggered) EventType() event.Type {
+	return RestoreTriggeredType
+}
+
+type SetConcurrencyTriggered struct {
+	MaxConcurrency int
+}
+
+func (e SetConcurrencyTriggered) EventType() event.Type {
+	return SetConcurrencyTriggeredType
+}
+
+const (
+	ListIncompleteUploadsTriggeredType  event.Type = "transfer.incomplete.list.triggered"
+	ListIncompleteUploadsSucceededType  event.Type = "transfer.incomplete.list.succeeded"
+	ListIncompleteUploadsFailedType     event.Type = "transfer.incomplete.list.failed"
+	AbortIncompleteUploadsTriggeredType event.Type = "transfer.incomplete.abort.triggered"
+	AbortIncompleteUploadsSucceededType event.Type = "transfer.incomplete.abort.succeeded"
+	AbortIncompleteUploadsFailedType    event.Type = "transfer.incomplete.abort.failed"
+)
+
+// ListIncompleteUploadsTriggered asks for the multipart uploads left in the bucket of a connection.
+type ListIncompleteUploadsTriggered struct {
+	ConnectionID connection_deck.ConnectionID
+}
+
+func (e ListIncompleteUploadsTriggered) EventType() event.Type {
+	return ListIncompleteUploadsTriggeredType
+}
+
+type ListIncompleteUploadsSucceeded struct {
+	Uploads []IncompleteUpload
+}
+
+func (e ListIncompleteUploadsSucceeded) EventType() event.Type {
+	return ListIncompleteUploadsSucceededType
+}
+
+type ListIncompleteUploadsFailed struct {
+	Err error
+}
+
+func (e ListIncompleteUploadsFailed) EventType() event.Type {
+	return ListIncompleteUploadsFailedType
+}
+
+// AbortIncompleteUploadsTriggered asks to abort multipart uploads, deleting their parts from the bucket.
+type AbortIncompleteUploadsTriggered struct {
+	ConnectionID connection_deck.ConnectionID
+	Uploads      []IncompleteUpload
+}
+
+func (e AbortIncompleteUploadsTriggered) EventType() event.Type {
+	return AbortIncompleteUploadsTriggeredType
+}
+
+type AbortIncompleteUploadsSucceeded struct {
+	Uploads []IncompleteUpload
+}
+
+func (e AbortIncompleteUploadsSucceeded) EventType() event.Type {
+	return AbortIncompleteUploadsSucceededType
+}
+
+// AbortIncompleteUploadsFailed is published when at least one upload couldn't be aborted,
+// Aborted listing the ones that could.
+type AbortIncompleteUploadsFailed struct {
+	Err     error
+	Aborted []IncompleteUpload
+}
+
+func (e AbortIncompleteUploadsFailed) EventType() event.Type {
+	return AbortIncompleteUploadsFailedType
+}
@@ -0,0 +1,160 @@
+package transfer
+
+import (
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+type JobID string
+
+func NewJobID() JobID {
+	return JobID(uuid.NewString())
+}
+
+func (id JobID) String() string {
+	return string(id)
+}
+
+// Kind is the direction of a transfer, from or to the bucket.
+type Kind int
+
+const (
+	KindUpload Kind = iota
+	KindDownload
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindUpload:
+		return "upload"
+	case KindDownload:
+		return "download"
+	default:
+		return "unknown"
+	}
+}
+
+type Status int
+
+const (
+	StatusQueued Status = iota
+	StatusRunning
+	StatusPaused
+	StatusSucceeded
+	StatusFailed
+	StatusCanceled
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusQueued:
+		return "queued"
+	case StatusRunning:
+		return "running"
+	case StatusPaused:
+		return "paused"
+	case StatusSucceeded:
+		return "succeeded"
+	case StatusFailed:
+		return "failed"
+	case StatusCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// IsFinished returns true when the job won't be run anymore, unless it is resumed after a failure.
+func (s Status) IsFinished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Job is a snapshot of a single file transfer between the local file system and a bucket.
+type Job struct {
+	ID           JobID
+	Kind         Kind
+	ConnectionID connection_deck.ConnectionID
+	// Bucket and RootPrefix are the ones of the connection when the job was queued,
+	// empty for the jobs saved before they were recorded
+	Bucket     string
+	RootPrefix string
+	// Key is the object key in the bucket, relative to the root prefix
+	Key       string
+	VersionID string
+	LocalPath string
+
+	TotalBytes       uint64
+	TransferredBytes uint64
+
+	Status   Status
+	Attempts int
+	// LastError is the message of the last failure, kept when the job is retried
+	LastError string
+	// RetryAt is the time from which a queued job, after a failure, can be run again
+	RetryAt time.Time
+
+	// Multipart is the state of a resumable upload, nil until its first part is sent
+	Multipart *MultipartUpload
+
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
+// NewUploadJob creates a queued job uploading the local file to the given key.
+func NewUploadJob(connID connection_deck.ConnectionID, localPath, key string, sizeBytes uint64) Job {
+	return Job{
+		ID:           NewJobID(),
+		Kind:         KindUpload,
+		ConnectionID: connID,
+		Key:          key,
+		LocalPath:    localPath,
+		TotalBytes:   sizeBytes,
+		Status:       StatusQueued,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// NewDownloadJob creates a queued job downloading the object (or one of its versions) to the local path.
+func NewDownloadJob(connID connection_deck.ConnectionID, key, versionID, localPath string, sizeBytes uint64) Job {
+	return Job{
+		ID:           NewJobID(),
+		Kind:         KindDownload,
+		ConnectionID: connID,
+		Key:          key,
+		VersionID:    versionID,
+		LocalPath:    localPath,
+		TotalBytes:   sizeBytes,
+		Status:       StatusQueued,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// IsInterruptedUpload returns true for an upload that is not finished and has already sent some parts.
+func (j Job) IsInterruptedUpload() bool {
+	return j.Kind == KindUpload && !j.Status.IsFinished() && j.Multipart != nil
+}
+
+// Targets returns true when the job was queued for the given bucket and root prefix.
+// A job without a recorded bucket targets any of them.
+func (j Job) Targets(bucket, rootPrefix string) bool {
+	return j.Bucket == "" || j.Bucket == bucket && j.RootPrefix == rootPrefix
+}
+
+// Name returns the name of the transferred file.
+func (j Job) Name() string {
+	return path.Base(j.Key)
+}
+
+// Progress returns the transferred ratio, between 0 and 1.
+func (j Job) Progress() float64 {
+	if j.Status == StatusSucceeded {
+		return 1
+	}
+	if j.TotalBytes == 0 {
+		return 0
+	}
+	return min(float64(j.TransferredBytes)/float64(j.TotalBytes), 1)
+}
@@ -0,0 +1,29 @@
+package transfer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+)
+
+func TestJob_Targets(t *testing.T) {
+	t.Run("should target the bucket and the root prefix it was queued for", func(t *testing.T) {
+		// Given
+		job := newFakeJob(t, "file.txt")
+		job.Bucket, job.RootPrefix = "bucket-a", "team-x/"
+
+		// When & Then
+		assert.True(t, job.Targets("bucket-a", "team-x/"))
+		assert.False(t, job.Targets("bucket-b", "team-x/"))
+		assert.False(t, job.Targets("bucket-a", ""))
+	})
+
+	t.Run("should target any bucket when none was recorded", func(t *testing.T) {
+		// Given
+		job := transfer.Job{Key: "data/file.txt"}
+
+		// When & Then
+		assert.True(t, job.Targets("bucket-b", "team-x/"))
+	})
+}
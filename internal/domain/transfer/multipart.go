@@ -0,0 +1,78 @@
+package transfer
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// MultipartThreshold is the file size from which an upload is sent in several parts and can be resumed
+	MultipartThreshold = 16 * 1024 * 1024
+
+	minPartSizeBytes = 8 * 1024 * 1024
+	maxPartCount     = 10_000
+)
+
+// PartSize returns the size of the parts used to upload a file of the given size,
+// large enough to stay under the limit of 10,000 parts per upload.
+func PartSize(totalBytes uint64) int64 {
+	size := int64((totalBytes + maxPartCount - 1) / maxPartCount)
+	return max(size, minPartSizeBytes)
+}
+
+// Fingerprint identifies a version of a local file,
+// to detect that it was modified after its upload started.
+type Fingerprint struct {
+	SizeBytes int64
+	ModTime   time.Time
+}
+
+func (f Fingerprint) Equal(other Fingerprint) bool {
+	return f.SizeBytes == other.SizeBytes && f.ModTime.Equal(other.ModTime)
+}
+
+// Part is a chunk of a multipart upload already stored in the bucket.
+type Part struct {
+	Number    int32
+	ETag      string
+	Checksum  string
+	SizeBytes int64
+}
+
+// MultipartUpload is the state of an upload sent in several parts,
+// from which it can be resumed after a failure or a restart of the application.
+type MultipartUpload struct {
+	UploadID      string
+	PartSizeBytes int64
+	Parts         []Part
+	Fingerprint   Fingerprint
+}
+
+// NextPartNumber returns the number of the first part not uploaded yet, starting from 1.
+func (u MultipartUpload) NextPartNumber() int32 {
+	return int32(len(u.Parts)) + 1
+}
+
+func (u MultipartUpload) UploadedBytes() uint64 {
+	var total uint64
+	for _, p := range u.Parts {
+		total += uint64(p.SizeBytes)
+	}
+	return total
+}
+
+// Clone returns a copy that doesn't share its parts with the original one.
+func (u MultipartUpload) Clone() MultipartUpload {
+	u.Parts = slices.Clone(u.Parts)
+	return u
+}
+
+// IncompleteUpload is a multipart upload started in a bucket and neither completed nor aborted.
+// Its parts are billed until it is aborted.
+type IncompleteUpload struct {
+	Key         string
+	UploadID    string
+	InitiatedAt time.Time
+	// Resumable is true when a local transfer job can still complete the upload
+	Resumable bool
+}
@@ -0,0 +1,311 @@
+package transfer
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	DefaultMaxConcurrency = 3
+	DefaultMaxAttempts    = 5
+
+	retryBaseDelay = time.Second
+	retryMaxDelay  = time.Minute
+)
+
+type Option func(*Queue)
+
+// WithMaxAttempts sets how many times a job is run before being considered as failed.
+func WithMaxAttempts(n int) Option {
+	return func(q *Queue) {
+		q.maxAttempts = max(n, 1)
+	}
+}
+
+// WithClock replaces the clock used to compute the retry times.
+func WithClock(now func() time.Time) Option {
+	return func(q *Queue) {
+		q.now = now
+	}
+}
+
+// Queue orders the transfer jobs and decides which ones can run,
+// according to the concurrency limit and the retry delays.
+// It is not safe for concurrent use.
+type Queue struct {
+	jobs           []*Job
+	maxConcurrency int
+	maxAttempts    int
+	now            func() time.Time
+}
+
+func NewQueue(maxConcurrency int, opts ...Option) *Queue {
+	q := &Queue{
+		maxConcurrency: max(maxConcurrency, 1),
+		maxAttempts:    DefaultMaxAttempts,
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Backoff returns the delay to wait before running a job again after its nth failed attempt.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	delay := retryBaseDelay << min(attempt-1, 16)
+	return min(delay, retryMaxDelay)
+}
+
+func (q *Queue) SetMaxConcurrency(n int) {
+	q.maxConcurrency = max(n, 1)
+}
+
+func (q *Queue) MaxConcurrency() int {
+	return q.maxConcurrency
+}
+
+// Add appends a new job at the end of the queue.
+func (q *Queue) Add(job Job) error {
+	if _, err := q.find(job.ID); err == nil {
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, job.ID)
+	}
+	q.jobs = append(q.jobs, &job)
+	return nil
+}
+
+// Restore appends a job saved before the application was closed.
+// The jobs that were queued or running are paused, so that the user decides whether to run them again.
+func (q *Queue) Restore(job Job) error {
+	if job.Status == StatusQueued || job.Status == StatusRunning {
+		job.Status = StatusPaused
+		job.TransferredBytes = job.resumedBytes()
+		job.RetryAt = time.Time{}
+	}
+	return q.Add(job)
+}
+
+func (q *Queue) Get(id JobID) (Job, error) {
+	j, err := q.find(id)
+	if err != nil {
+		return Job{}, err
+	}
+	return *j, nil
+}
+
+// Jobs returns a snapshot of all the jobs, in the order they were added.
+func (q *Queue) Jobs() []Job {
+	res := make([]Job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		res = append(res, *j)
+	}
+	return res
+}
+
+func (q *Queue) RunningCount() int {
+	count := 0
+	for _, j := range q.jobs {
+		if j.Status == StatusRunning {
+			count++
+		}
+	}
+	return count
+}
+
+// Next marks the first runnable job as running and returns it.
+// It returns false when the concurrency limit is reached or when no queued job can run yet.
+func (q *Queue) Next() (Job, bool) {
+	if q.RunningCount() >= q.maxConcurrency {
+		return Job{}, false
+	}
+	now := q.now()
+	for _, j := range q.jobs {
+		if j.Status != StatusQueued || j.RetryAt.After(now) {
+			continue
+		}
+		j.Status = StatusRunning
+		j.Attempts++
+		j.TransferredBytes = j.resumedBytes()
+		j.RetryAt = time.Time{}
+		return *j, true
+	}
+	return Job{}, false
+}
+
+// NextRetryDelay returns the time to wait before a queued job waiting for a retry can run.
+func (q *Queue) NextRetryDelay() (time.Duration, bool) {
+	var (
+		now   = q.now()
+		next  time.Time
+		found bool
+	)
+	for _, j := range q.jobs {
+		if j.Status != StatusQueued || !j.RetryAt.After(now) {
+			continue
+		}
+		if !found || j.RetryAt.Before(next) {
+			next = j.RetryAt
+			found = true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	return next.Sub(now), true
+}
+
+func (q *Queue) UpdateProgress(id JobID, transferredBytes uint64) (Job, error) {
+	j, err := q.findWithStatus(id, StatusRunning)
+	if err != nil {
+		return Job{}, err
+	}
+	j.TransferredBytes = transferredBytes
+	return *j, nil
+}
+
+func (q *Queue) Succeed(id JobID) (Job, error) {
+	j, err := q.findWithStatus(id, StatusRunning)
+	if err != nil {
+		return Job{}, err
+	}
+	j.Status = StatusSucceeded
+	j.TransferredBytes = j.TotalBytes
+	j.Multipart = nil
+	j.LastError = ""
+	j.FinishedAt = q.now()
+	return *j, nil
+}
+
+// Fail records a failed attempt: the job is queued again after a backoff delay
+// until the maximum number of attempts is reached.
+func (q *Queue) Fail(id JobID, cause error) (Job, error) {
+	j, err := q.findWithStatus(id, StatusRunning)
+	if err != nil {
+		return Job{}, err
+	}
+	j.LastError = cause.Error()
+	if j.Attempts < q.maxAttempts {
+		j.Status = StatusQueued
+		j.RetryAt = q.now().Add(Backoff(j.Attempts))
+		return *j, nil
+	}
+	j.Status = StatusFailed
+	j.FinishedAt = q.now()
+	return *j, nil
+}
+
+// FailPermanently records a failure that won't be fixed by retrying, like a missing object.
+func (q *Queue) FailPermanently(id JobID, cause error) (Job, error) {
+	j, err := q.findWithStatus(id, StatusRunning)
+	if err != nil {
+		return Job{}, err
+	}
+	j.LastError = cause.Error()
+	j.Status = StatusFailed
+	j.FinishedAt = q.now()
+	return *j, nil
+}
+
+// Checkpoint records the state of the multipart upload of a job, or forgets it when nil.
+// The job restarts from this state the next time it runs.
+func (q *Queue) Checkpoint(id JobID, upload *MultipartUpload) (Job, error) {
+	j, err := q.findWithStatus(id, StatusQueued, StatusRunning, StatusPaused, StatusFailed)
+	if err != nil {
+		return Job{}, err
+	}
+	if j.Kind != KindUpload {
+		return Job{}, fmt.Errorf("%w: job %s is not an upload", ErrInvalidTransition, id)
+	}
+	if upload == nil {
+		j.Multipart = nil
+		return *j, nil
+	}
+	cp := upload.Clone()
+	j.Multipart = &cp
+	if j.Status == StatusRunning {
+		j.TransferredBytes = cp.UploadedBytes()
+	}
+	return *j, nil
+}
+
+// Pause stops a queued or running job until it is resumed.
+func (q *Queue) Pause(id JobID) (Job, error) {
+	j, err := q.findWithStatus(id, StatusQueued, StatusRunning)
+	if err != nil {
+		return Job{}, err
+	}
+	j.Status = StatusPaused
+	j.TransferredBytes = j.resumedBytes()
+	j.RetryAt = time.Time{}
+	return *j, nil
+}
+
+// Resume queues again a paused job, or a failed one with a fresh number of attempts.
+func (q *Queue) Resume(id JobID) (Job, error) {
+	j, err := q.findWithStatus(id, StatusPaused, StatusFailed)
+	if err != nil {
+		return Job{}, err
+	}
+	if j.Status == StatusFailed {
+		j.Attempts = 0
+		j.FinishedAt = time.Time{}
+	}
+	j.Status = StatusQueued
+	return *j, nil
+}
+
+// Cancel definitively stops a job that is not finished yet.
+func (q *Queue) Cancel(id JobID) (Job, error) {
+	j, err := q.findWithStatus(id, StatusQueued, StatusRunning, StatusPaused)
+	if err != nil {
+		return Job{}, err
+	}
+	j.Status = StatusCanceled
+	j.FinishedAt = q.now()
+	return *j, nil
+}
+
+// ClearFinished removes the finished jobs from the queue and returns their IDs.
+func (q *Queue) ClearFinished() []JobID {
+	var removed []JobID
+	q.jobs = slices.DeleteFunc(q.jobs, func(j *Job) bool {
+		if j.Status.IsFinished() {
+			removed = append(removed, j.ID)
+			return true
+		}
+		return false
+	})
+	return removed
+}
+
+// resumedBytes returns the number of bytes a job doesn't need to transfer again.
+func (j Job) resumedBytes() uint64 {
+	if j.Multipart == nil {
+		return 0
+	}
+	return j.Multipart.UploadedBytes()
+}
+
+func (q *Queue) find(id JobID) (*Job, error) {
+	for _, j := range q.jobs {
+		if j.ID == id {
+			return j, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+func (q *Queue) findWithStatus(id JobID, allowed ...Status) (*Job, error) {
+	j, err := q.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(allowed, j.Status) {
+		return nil, fmt.Errorf("%w: job %s is %s", ErrInvalidTransition, id, j.Status)
+	}
+	return j, nil
+}
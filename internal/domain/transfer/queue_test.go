@@ -0,0 +1,342 @@
+package transfer_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+)
+
+func newFakeJob(t *testing.T, name string) transfer.Job {
+	t.Helper()
+	return transfer.NewUploadJob(connection_deck.NewConnectionID(), "/tmp/"+name, "data/"+name, 100)
+}
+
+func TestQueue_Next(t *testing.T) {
+	t.Run("should run the jobs in order up to the concurrency limit", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(2)
+		j1, j2, j3 := newFakeJob(t, "a"), newFakeJob(t, "b"), newFakeJob(t, "c")
+		require.NoError(t, q.Add(j1))
+		require.NoError(t, q.Add(j2))
+		require.NoError(t, q.Add(j3))
+
+		// When
+		first, ok1 := q.Next()
+		second, ok2 := q.Next()
+		_, ok3 := q.Next()
+
+		// Then
+		assert.True(t, ok1)
+		assert.True(t, ok2)
+		assert.False(t, ok3)
+		assert.Equal(t, j1.ID, first.ID)
+		assert.Equal(t, j2.ID, second.ID)
+		assert.Equal(t, transfer.StatusRunning, first.Status)
+		assert.Equal(t, 1, first.Attempts)
+		assert.Equal(t, 2, q.RunningCount())
+	})
+
+	t.Run("should run more jobs when the concurrency limit is raised", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		require.NoError(t, q.Add(newFakeJob(t, "a")))
+		require.NoError(t, q.Add(newFakeJob(t, "b")))
+		_, _ = q.Next()
+
+		// When
+		q.SetMaxConcurrency(2)
+		_, ok := q.Next()
+
+		// Then
+		assert.True(t, ok)
+	})
+}
+
+func TestQueue_Fail(t *testing.T) {
+	t.Run("should retry the job after a growing delay", func(t *testing.T) {
+		// Given
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		q := transfer.NewQueue(1, transfer.WithClock(func() time.Time { return now }))
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+
+		// When
+		res, err := q.Fail(job.ID, errors.New("connection reset"))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusQueued, res.Status)
+		assert.Equal(t, "connection reset", res.LastError)
+		assert.Equal(t, now.Add(time.Second), res.RetryAt)
+
+		_, ok := q.Next()
+		assert.False(t, ok)
+		delay, ok := q.NextRetryDelay()
+		assert.True(t, ok)
+		assert.Equal(t, time.Second, delay)
+
+		now = now.Add(time.Second)
+		retried, ok := q.Next()
+		assert.True(t, ok)
+		assert.Equal(t, 2, retried.Attempts)
+	})
+
+	t.Run("should fail the job after the last attempt", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1, transfer.WithMaxAttempts(1))
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+
+		// When
+		res, err := q.Fail(job.ID, errors.New("access denied"))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusFailed, res.Status)
+		assert.False(t, res.FinishedAt.IsZero())
+	})
+}
+
+func TestQueue_FailPermanently(t *testing.T) {
+	t.Run("should fail the job without retrying it", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+
+		// When
+		res, err := q.FailPermanently(job.ID, errors.New("not found"))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusFailed, res.Status)
+		assert.Equal(t, 1, res.Attempts)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("should double the delay up to a maximum", func(t *testing.T) {
+		assert.Equal(t, time.Second, transfer.Backoff(1))
+		assert.Equal(t, 2*time.Second, transfer.Backoff(2))
+		assert.Equal(t, 8*time.Second, transfer.Backoff(4))
+		assert.Equal(t, time.Minute, transfer.Backoff(30))
+	})
+}
+
+func TestQueue_PauseResumeCancel(t *testing.T) {
+	t.Run("should pause a running job and queue it again when resumed", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+		_, err := q.UpdateProgress(job.ID, 50)
+		require.NoError(t, err)
+
+		// When
+		paused, err := q.Pause(job.ID)
+		require.NoError(t, err)
+		resumed, err := q.Resume(job.ID)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, transfer.StatusPaused, paused.Status)
+		assert.Equal(t, uint64(0), paused.TransferredBytes)
+		assert.Equal(t, transfer.StatusQueued, resumed.Status)
+		assert.Equal(t, 0, q.RunningCount())
+	})
+
+	t.Run("should resume a failed job with fresh attempts", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1, transfer.WithMaxAttempts(1))
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+		_, err := q.Fail(job.ID, errors.New("boom"))
+		require.NoError(t, err)
+
+		// When
+		res, err := q.Resume(job.ID)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusQueued, res.Status)
+		assert.Equal(t, 0, res.Attempts)
+	})
+
+	t.Run("should not cancel a finished job", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+		_, err := q.Succeed(job.ID)
+		require.NoError(t, err)
+
+		// When
+		_, err = q.Cancel(job.ID)
+
+		// Then
+		assert.ErrorIs(t, err, transfer.ErrInvalidTransition)
+	})
+
+	t.Run("should return an error for an unknown job", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+
+		// When
+		_, err := q.Pause(transfer.NewJobID())
+
+		// Then
+		assert.ErrorIs(t, err, transfer.ErrNotFound)
+	})
+}
+
+func TestQueue_Restore(t *testing.T) {
+	t.Run("should pause the interrupted jobs and keep the finished ones", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		running := newFakeJob(t, "a")
+		running.Status = transfer.StatusRunning
+		running.TransferredBytes = 42
+		done := newFakeJob(t, "b")
+		done.Status = transfer.StatusSucceeded
+
+		// When
+		require.NoError(t, q.Restore(running))
+		require.NoError(t, q.Restore(done))
+
+		// Then
+		jobs := q.Jobs()
+		require.Len(t, jobs, 2)
+		assert.Equal(t, transfer.StatusPaused, jobs[0].Status)
+		assert.Equal(t, uint64(0), jobs[0].TransferredBytes)
+		assert.Equal(t, transfer.StatusSucceeded, jobs[1].Status)
+		_, ok := q.Next()
+		assert.False(t, ok)
+	})
+}
+
+func TestQueue_Checkpoint(t *testing.T) {
+	upload := transfer.MultipartUpload{
+		UploadID:      "upload-1",
+		PartSizeBytes: 40,
+		Parts: []transfer.Part{
+			{Number: 1, ETag: "etag-1", SizeBytes: 40},
+		},
+	}
+
+	t.Run("should resume the job from the uploaded parts", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+
+		// When
+		res, err := q.Checkpoint(job.ID, &upload)
+		require.NoError(t, err)
+		_, err = q.Pause(job.ID)
+		require.NoError(t, err)
+		_, err = q.Resume(job.ID)
+		require.NoError(t, err)
+		next, ok := q.Next()
+
+		// Then
+		assert.Equal(t, uint64(40), res.TransferredBytes)
+		require.True(t, ok)
+		require.NotNil(t, next.Multipart)
+		assert.Equal(t, "upload-1", next.Multipart.UploadID)
+		assert.Equal(t, uint64(40), next.TransferredBytes)
+		assert.True(t, next.IsInterruptedUpload())
+	})
+
+	t.Run("should keep the uploaded parts of a restored job", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := newFakeJob(t, "a")
+		job.Status = transfer.StatusRunning
+		job.Multipart = &upload
+
+		// When
+		require.NoError(t, q.Restore(job))
+
+		// Then
+		res, err := q.Get(job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusPaused, res.Status)
+		assert.Equal(t, uint64(40), res.TransferredBytes)
+	})
+
+	t.Run("should forget the upload state once succeeded", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := newFakeJob(t, "a")
+		require.NoError(t, q.Add(job))
+		_, _ = q.Next()
+		_, err := q.Checkpoint(job.ID, &upload)
+		require.NoError(t, err)
+
+		// When
+		res, err := q.Succeed(job.ID)
+
+		// Then
+		require.NoError(t, err)
+		assert.Nil(t, res.Multipart)
+	})
+
+	t.Run("should refuse a download job", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(1)
+		job := transfer.NewDownloadJob(connection_deck.NewConnectionID(), "data/a", "", "/tmp/a", 100)
+		require.NoError(t, q.Add(job))
+
+		// When
+		_, err := q.Checkpoint(job.ID, &upload)
+
+		// Then
+		assert.ErrorIs(t, err, transfer.ErrInvalidTransition)
+	})
+}
+
+func TestPartSize(t *testing.T) {
+	t.Run("should use the minimal part size for small files", func(t *testing.T) {
+		assert.Equal(t, int64(8*1024*1024), transfer.PartSize(100*1024*1024))
+	})
+
+	t.Run("should stay under 10,000 parts for huge files", func(t *testing.T) {
+		size := uint64(200 * 1024 * 1024 * 1024)
+		res := transfer.PartSize(size)
+		assert.LessOrEqual(t, (size+uint64(res)-1)/uint64(res), uint64(10_000))
+	})
+}
+
+func TestQueue_ClearFinished(t *testing.T) {
+	t.Run("should remove the finished jobs only", func(t *testing.T) {
+		// Given
+		q := transfer.NewQueue(2)
+		done, pending := newFakeJob(t, "a"), newFakeJob(t, "b")
+		require.NoError(t, q.Add(done))
+		require.NoError(t, q.Add(pending))
+		_, _ = q.Next()
+		_, err := q.Succeed(done.ID)
+		require.NoError(t, err)
+
+		// When
+		removed := q.ClearFinished()
+
+		// Then
+		assert.Equal(t, []transfer.JobID{done.ID}, removed)
+		jobs := q.Jobs()
+		require.Len(t, jobs, 1)
+		assert.Equal(t, pending.ID, jobs[0].ID)
+	})
+}
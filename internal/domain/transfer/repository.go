@@ -0,0 +1,6 @@
+package transfer
+
+type Repository interface {
+	// Load returns the jobs saved before the application was closed, in the order they were added.
+	Load() ([]Job, error)
+}
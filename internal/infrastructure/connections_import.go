@@ -0,0 +1,279 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/dto"
+)
+
+const defaultAWSRegion = "us-east-1"
+
+var errNoImportFile = errors.New("no file to import found at the default location")
+
+func (r *FyneConnectionsRepository) Import(
+	_ context.Context,
+	file io.Reader,
+	options connection_deck.ImportOptions,
+) ([]*connection_deck.Connection, error) {
+	var (
+		source  = options.Source
+		content []byte
+		err     error
+	)
+	if file == nil {
+		content, err = readDefaultImportFiles(source)
+	} else {
+		content, err = io.ReadAll(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s connections: %w", source, errors.Join(err, connection_deck.ErrTechnical))
+	}
+
+	var conns []*connection_deck.Connection
+	switch source {
+	case connection_deck.ImportFromJSON:
+		conns, err = parseJSONExport(content, options.Passphrase)
+	case connection_deck.ImportFromAWSProfiles:
+		conns, err = parseAWSProfiles(content)
+	case connection_deck.ImportFromRclone:
+		conns, err = parseRcloneRemotes(content)
+	default:
+		err = fmt.Errorf("unknown import source %q", source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s connections: %w", source, err)
+	}
+	return conns, nil
+}
+
+// parseJSONExport reads the connections of an export, decrypted with the passphrase when it is encrypted.
+// The connections of an export without secrets have no credentials.
+func parseJSONExport(content []byte, passphrase string) ([]*connection_deck.Connection, error) {
+	// a clear export is a JSON array, an encrypted one a sealed box object
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		var box sealedBox
+		if err := json.Unmarshal(trimmed, &box); err != nil {
+			return nil, err
+		}
+		if passphrase == "" {
+			return nil, errors.New("the export is encrypted, a passphrase is required")
+		}
+		var err error
+		if content, err = box.openWithPassphrase(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	dtos, err := dto.NewConnectionsDTOFromJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	return dtos.ToConnections().Get(), nil
+}
+
+// readDefaultImportFiles reads the files where the tools write their configuration by default.
+// The AWS credentials and config files are read together.
+func readDefaultImportFiles(source connection_deck.ImportSource) ([]byte, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	switch source {
+	case connection_deck.ImportFromAWSProfiles:
+		paths = []string{
+			envOrDefault("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, ".aws", "credentials")),
+			envOrDefault("AWS_CONFIG_FILE", filepath.Join(home, ".aws", "config")),
+		}
+	case connection_deck.ImportFromRclone:
+		if p := os.Getenv("RCLONE_CONFIG"); p != "" {
+			paths = []string{p}
+			break
+		}
+		if configDir, err := os.UserConfigDir(); err == nil {
+			paths = append(paths, filepath.Join(configDir, "rclone", "rclone.conf"))
+		}
+		paths = append(paths, filepath.Join(home, ".config", "rclone", "rclone.conf"), filepath.Join(home, ".rclone.conf"))
+	default:
+		return nil, fmt.Errorf("%w for the %s format", errNoImportFile, source)
+	}
+
+	var content bytes.Buffer
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		content.Write(b)
+		content.WriteByte('\n')
+		if source == connection_deck.ImportFromRclone {
+			break
+		}
+	}
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoImportFile, strings.Join(paths, ", "))
+	}
+	return content.Bytes(), nil
+}
+
+// parseAWSProfiles reads the profiles with an access key from the content of the AWS credentials and config files.
+// The profiles without an endpoint_url are connections to AWS, the other ones to an S3-like server.
+// The profiles with a session token keep it with their temporary credentials.
+func parseAWSProfiles(content []byte) ([]*connection_deck.Connection, error) {
+	sections, err := parseINI(content)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]iniSection, 0, len(sections))
+	indexes := make(map[string]int, len(sections))
+	for _, s := range sections {
+		// the config file prefixes its sections with "profile", except the default one
+		name := strings.TrimPrefix(s.name, "profile ")
+		if strings.Contains(name, " ") {
+			// sso-session, services...
+			continue
+		}
+		i, ok := indexes[name]
+		if !ok {
+			i = len(profiles)
+			indexes[name] = i
+			profiles = append(profiles, iniSection{name: name, values: make(map[string]string)})
+		}
+		for k, v := range s.values {
+			profiles[i].values[k] = v
+		}
+	}
+
+	deck := connection_deck.New()
+	for _, p := range profiles {
+		accessKey := p.values["aws_access_key_id"]
+		if accessKey == "" {
+			continue
+		}
+		opt := connection_deck.AsAWS(valueOrDefault(p.values["region"], defaultAWSRegion))
+		if endpoint := p.values["endpoint_url"]; endpoint != "" {
+			opt = connection_deck.AsS3Like(parseEndpoint(endpoint))
+		}
+		opts := []connection_deck.ConnectionOption{opt}
+		if token := p.values["aws_session_token"]; token != "" {
+			opts = append(opts, connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+				Type:         connection_deck.SessionCredentials,
+				SessionToken: token,
+			}))
+		}
+		deck.New(p.name, accessKey, p.values["aws_secret_access_key"], "", opts...)
+	}
+	return deck.Get(), nil
+}
+
+// rcloneProviders are the providers of the rclone s3 remotes with a preset, by lower cased rclone name.
+var rcloneProviders = map[string]connection_deck.Provider{
+	"minio":      connection_deck.MinIOProvider,
+	"cloudflare": connection_deck.R2Provider,
+	"wasabi":     connection_deck.WasabiProvider,
+	"ovhcloud":   connection_deck.OVHProvider,
+	"scaleway":   connection_deck.ScalewayProvider,
+	"gcs":        connection_deck.GCSProvider,
+	"ceph":       connection_deck.CephProvider,
+}
+
+// parseRcloneRemotes reads the s3 remotes with an access key from the content of the rclone config file.
+// The remotes of a provider with a preset get it, the other ones are generic S3-like connections.
+func parseRcloneRemotes(content []byte) ([]*connection_deck.Connection, error) {
+	sections, err := parseINI(content)
+	if err != nil {
+		return nil, err
+	}
+
+	deck := connection_deck.New()
+	for _, s := range sections {
+		accessKey := s.values["access_key_id"]
+		if s.values["type"] != "s3" || accessKey == "" {
+			continue
+		}
+		endpoint := s.values["endpoint"]
+		opt := connection_deck.AsAWS(valueOrDefault(s.values["region"], defaultAWSRegion))
+		if endpoint != "" && !strings.EqualFold(s.values["provider"], "AWS") {
+			server, useTLS := parseEndpoint(endpoint)
+			provider, ok := rcloneProviders[strings.ToLower(s.values["provider"])]
+			if !ok {
+				provider = connection_deck.S3LikeProvider
+			}
+			opt = connection_deck.AsProvider(provider, server, s.values["region"], useTLS)
+		}
+		deck.New(s.name, accessKey, s.values["secret_access_key"], "", opt)
+	}
+	return deck.Get(), nil
+}
+
+// parseEndpoint returns the server of an endpoint URL, with TLS unless the scheme is http.
+func parseEndpoint(endpoint string) (string, bool) {
+	if !strings.Contains(endpoint, "://") {
+		return strings.TrimSuffix(endpoint, "/"), true
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint, true
+	}
+	return u.Host, u.Scheme != "http"
+}
+
+type iniSection struct {
+	name   string
+	values map[string]string
+}
+
+// parseINI reads the sections of an INI file, in their order of appearance.
+// The keys are lower cased and the lines out of a section are ignored.
+func parseINI(content []byte) ([]iniSection, error) {
+	var sections []iniSection
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unclosed section", lineNum)
+			}
+			sections = append(sections, iniSection{
+				name:   strings.TrimSpace(line[1 : len(line)-1]),
+				values: make(map[string]string),
+			})
+		case len(sections) > 0:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: missing '='", lineNum)
+			}
+			sections[len(sections)-1].values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return sections, scanner.Err()
+}
+
+func envOrDefault(key, defaultValue string) string {
+	return valueOrDefault(os.Getenv(key), defaultValue)
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
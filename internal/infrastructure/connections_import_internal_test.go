@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func TestParseAWSProfiles(t *testing.T) {
+	t.Run("should read the profiles of the credentials and config files", func(t *testing.T) {
+		// Given
+		content := []byte(`
+[default]
+aws_access_key_id = AKIADEFAULT
+aws_secret_access_key = secretdefault
+
+[minio]
+aws_access_key_id=minioadmin
+aws_secret_access_key=minioadmin
+aws_session_token=miniotoken
+
+[sso]
+sso_session = my-sso
+
+# config file
+[default]
+region = eu-west-3
+
+[profile minio]
+endpoint_url = http://localhost:9000
+
+[sso-session my-sso]
+sso_region = eu-west-1
+`)
+
+		// When
+		res, err := parseAWSProfiles(content)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		assert.Equal(t, "default", res[0].Name())
+		assert.Equal(t, "AKIADEFAULT", res[0].AccessKey())
+		assert.Equal(t, "secretdefault", res[0].SecretKey())
+		assert.Equal(t, connection_deck.AWSProvider, res[0].Provider())
+		assert.Equal(t, "eu-west-3", res[0].Region())
+		assert.Equal(t, connection_deck.StaticCredentials, res[0].CredentialSource().Type)
+
+		assert.Equal(t, "minio", res[1].Name())
+		assert.Equal(t, "minioadmin", res[1].AccessKey())
+		assert.Equal(t, connection_deck.SessionCredentials, res[1].CredentialSource().Type)
+		assert.Equal(t, "miniotoken", res[1].CredentialSource().SessionToken)
+		assert.Equal(t, connection_deck.S3LikeProvider, res[1].Provider())
+		assert.Equal(t, "localhost:9000", res[1].Server())
+		assert.False(t, res[1].IsTLSActivated())
+	})
+}
+
+func TestParseRcloneRemotes(t *testing.T) {
+	t.Run("should read the s3 remotes with an access key", func(t *testing.T) {
+		// Given
+		content := []byte(`
+[r2]
+type = s3
+provider = Cloudflare
+access_key_id = r2key
+secret_access_key = r2secret
+endpoint = https://account.r2.cloudflarestorage.com
+
+[aws]
+type = s3
+provider = AWS
+access_key_id = AKIA
+secret_access_key = awssecret
+region = eu-central-1
+
+[env]
+type = s3
+env_auth = true
+
+[drive]
+type = drive
+`)
+
+		// When
+		res, err := parseRcloneRemotes(content)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+
+		assert.Equal(t, "r2", res[0].Name())
+		assert.Equal(t, "r2key", res[0].AccessKey())
+		assert.Equal(t, "r2secret", res[0].SecretKey())
+		assert.Equal(t, connection_deck.R2Provider, res[0].Provider())
+		assert.Equal(t, "account.r2.cloudflarestorage.com", res[0].Server())
+		assert.True(t, res[0].IsTLSActivated())
+
+		assert.Equal(t, "aws", res[1].Name())
+		assert.Equal(t, connection_deck.AWSProvider, res[1].Provider())
+		assert.Equal(t, "eu-central-1", res[1].Region())
+	})
+
+	t.Run("should return an error when a section is not closed", func(t *testing.T) {
+		// Given
+		content := []byte("[r2\ntype = s3\n")
+
+		// When
+		_, err := parseRcloneRemotes(content)
+
+		// Then
+		assert.ErrorContains(t, err, "line 1: unclosed section")
+	})
+}
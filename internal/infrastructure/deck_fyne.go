@@ -11,6 +11,7 @@ import (
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/infrastructure/dto"
+	"github.com/thomas-marquis/s3-box/internal/u"
 )
 
 const (
@@ -18,17 +19,32 @@ const (
 )
 
 type FyneConnectionsRepository struct {
-	prefs fyne.Preferences
-	bus   event.Bus
+	prefs   fyne.Preferences
+	bus     event.Bus
+	secrets connection_deck.SecretStore
 }
 
 var _ connection_deck.Repository = &FyneConnectionsRepository{}
 
+type FyneConnectionsRepositoryOption func(*FyneConnectionsRepository)
+
+// WithSecretStore keeps the credentials in the secret store once it is enabled,
+// the preferences then only hold references to them.
+func WithSecretStore(store connection_deck.SecretStore) FyneConnectionsRepositoryOption {
+	return func(r *FyneConnectionsRepository) {
+		r.secrets = store
+	}
+}
+
 func NewFyneConnectionsRepository(
 	prefs fyne.Preferences,
 	bus event.Bus,
+	opts ...FyneConnectionsRepositoryOption,
 ) *FyneConnectionsRepository {
 	r := &FyneConnectionsRepository{prefs: prefs, bus: bus}
+	for _, opt := range opts {
+		opt(r)
+	}
 
 	bus.Subscribe().
 		On(event.Is(connection_deck.SelectConnectionTriggeredType), r.handleSelect).
@@ -40,35 +56,88 @@ func NewFyneConnectionsRepository(
 	return r
 }
 
-func (r *FyneConnectionsRepository) Get(_ context.Context) (*connection_deck.Deck, error) {
+func (r *FyneConnectionsRepository) Get(ctx context.Context) (*connection_deck.Deck, error) {
 	dtos, err := r.loadConnectionsDTO()
 	if err != nil {
 		return nil, fmt.Errorf("load connections: %w", errors.Join(err, connection_deck.ErrTechnical))
 	}
 
+	if dtos.HasSecretRefs() {
+		if r.secrets == nil {
+			return nil, fmt.Errorf("load connections: %w", errors.Join(connection_deck.ErrSecretStoreLocked, connection_deck.ErrTechnical))
+		}
+		if err := dtos.ResolveSecrets(func(ref string) (string, error) {
+			return r.secrets.Get(ctx, ref)
+		}); err != nil {
+			return nil, fmt.Errorf("load connections secrets: %w", errors.Join(err, connection_deck.ErrTechnical))
+		}
+	}
+
 	return dtos.ToConnections(), nil
 }
 
-func (r *FyneConnectionsRepository) Export(_ context.Context, file io.Writer) error {
-	deck, err := r.Get(context.Background())
+func (r *FyneConnectionsRepository) Export(ctx context.Context, file io.Writer, options connection_deck.ExportOptions) error {
+	deck, err := r.Get(ctx)
 	if err != nil {
 		return fmt.Errorf("get connections: %w", errors.Join(err, connection_deck.ErrTechnical))
 	}
 
 	dtos := dto.NewConnectionsDTO(deck)
+	if options.Mode == connection_deck.ExportWithoutSecrets {
+		dtos.RemoveSecrets()
+	}
 	jsonContent, err := json.Marshal(dtos)
 	if err != nil {
 		return fmt.Errorf("serialize connections: %w", errors.Join(err, connection_deck.ErrTechnical))
 	}
 
+	if options.Mode == connection_deck.ExportEncrypted {
+		box, err := sealWithPassphrase(options.Passphrase, jsonContent)
+		if err != nil {
+			return fmt.Errorf("encrypt connections: %w", err)
+		}
+		if jsonContent, err = json.Marshal(box); err != nil {
+			return fmt.Errorf("serialize connections: %w", errors.Join(err, connection_deck.ErrTechnical))
+		}
+	}
+
 	if _, err = file.Write(jsonContent); err != nil {
 		return fmt.Errorf("write connections: %w", errors.Join(err, connection_deck.ErrTechnical))
 	}
 	return nil
 }
 
-func (r *FyneConnectionsRepository) saveDeck(_ context.Context, deck *connection_deck.Deck) error {
+func (r *FyneConnectionsRepository) SecretsProtected() bool {
+	return r.secrets != nil && r.secrets.IsEnabled()
+}
+
+func (r *FyneConnectionsRepository) ProtectSecrets(ctx context.Context, passphrase string) error {
+	if r.secrets == nil {
+		return errors.New("no secret store is available")
+	}
+	if r.secrets.IsEnabled() {
+		return errors.New("the credentials are already protected")
+	}
+
+	deck, err := r.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.secrets.Unlock(passphrase); err != nil {
+		return fmt.Errorf("create the secret store: %w", err)
+	}
+	return r.saveDeck(ctx, deck)
+}
+
+func (r *FyneConnectionsRepository) saveDeck(ctx context.Context, deck *connection_deck.Deck) error {
 	dtos := dto.NewConnectionsDTO(deck)
+	if r.SecretsProtected() {
+		if err := dtos.StoreSecrets(func(ref, secret string) error {
+			return r.secrets.Set(ctx, ref, secret)
+		}); err != nil {
+			return fmt.Errorf("save connections secrets: %w", err)
+		}
+	}
 	jsonContent, err := json.Marshal(dtos)
 	if err != nil {
 		return fmt.Errorf("serialize connections: %w", errors.Join(err, connection_deck.ErrTechnical))
@@ -124,6 +193,11 @@ func (r *FyneConnectionsRepository) handleRemove(evt event.Event) {
 			WasSelected:       pl.WasSelected,
 		}))
 	}
+	if r.SecretsProtected() {
+		for _, ref := range dto.SecretRefs(pl.Connection().ID()) {
+			u.Skip(r.secrets.Delete(ctx, ref))
+		}
+	}
 	r.bus.Publish(evt.NewFollowup(connection_deck.RemoveConnectionSucceeded{
 		ConnectionPayload: pl.ConnectionPayload,
 		Deck:              pl.Deck,
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,7 @@ import (
 	"github.com/thomas-marquis/it-happened/eventest"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/infrastructure"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/dto"
 	"github.com/thomas-marquis/s3-box/internal/tu"
 	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
 	mocks_fyne "github.com/thomas-marquis/s3-box/mocks/fyne"
@@ -310,13 +312,87 @@ func TestFyneConnectionsRepository_Export(t *testing.T) {
 		buf := &bytes.Buffer{}
 
 		// When
-		err := repo.Export(context.TODO(), buf)
+		err := repo.Export(context.TODO(), buf, connection_deck.ExportOptions{})
 
 		// Then
 		assert.NoError(t, err)
 		assert.JSONEq(t, connJson, buf.String())
 	})
 
+	t.Run("should export connections without the credentials", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockPrefs := mocks_fyne.NewMockPreferences(ctrl)
+		mockBus := mocks_event.NewMockBus(ctrl)
+
+		connJson := fmt.Sprintf(
+			`[{"id":"%s","name":"conn 1","accessKey":"ak","secretKey":"sk","bucket":"b","type":"aws","region":"us-east-1","useTls":true}]`,
+			connection_deck.NewConnectionID())
+
+		mockPrefs.EXPECT().
+			String("allConnections").
+			Return(connJson).
+			Times(1)
+		mockBus.EXPECT().
+			Subscribe().
+			Return(event.NewSubscriber(make(chan event.Event))).
+			Times(1)
+
+		repo := infrastructure.NewFyneConnectionsRepository(mockPrefs, mockBus)
+		buf := &bytes.Buffer{}
+
+		// When
+		err := repo.Export(context.TODO(), buf, connection_deck.ExportOptions{Mode: connection_deck.ExportWithoutSecrets})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotContains(t, buf.String(), `"ak"`)
+		assert.NotContains(t, buf.String(), `"sk"`)
+		assert.Contains(t, buf.String(), `"conn 1"`)
+	})
+
+	t.Run("should export connections encrypted with a passphrase readable by the import", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockPrefs := mocks_fyne.NewMockPreferences(ctrl)
+		mockBus := mocks_event.NewMockBus(ctrl)
+
+		connJson := fmt.Sprintf(
+			`[{"id":"%s","name":"conn 1","accessKey":"ak","secretKey":"sk","bucket":"b","type":"aws","region":"us-east-1","useTls":true}]`,
+			connection_deck.NewConnectionID())
+
+		mockPrefs.EXPECT().
+			String("allConnections").
+			Return(connJson).
+			Times(1)
+		mockBus.EXPECT().
+			Subscribe().
+			Return(event.NewSubscriber(make(chan event.Event))).
+			Times(1)
+
+		repo := infrastructure.NewFyneConnectionsRepository(mockPrefs, mockBus)
+		buf := &bytes.Buffer{}
+
+		// When
+		err := repo.Export(context.TODO(), buf, connection_deck.ExportOptions{
+			Mode:       connection_deck.ExportEncrypted,
+			Passphrase: "my passphrase",
+		})
+
+		// Then
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "conn 1")
+
+		res, err := repo.Import(context.TODO(), bytes.NewReader(buf.Bytes()), connection_deck.ImportOptions{
+			Source:     connection_deck.ImportFromJSON,
+			Passphrase: "my passphrase",
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, "conn 1", res[0].Name())
+		assert.Equal(t, "sk", res[0].SecretKey())
+	})
+
 	t.Run("should return error when Get fails", func(t *testing.T) {
 		// Given
 		ctrl := gomock.NewController(t)
@@ -335,10 +411,56 @@ func TestFyneConnectionsRepository_Export(t *testing.T) {
 		repo := infrastructure.NewFyneConnectionsRepository(mockPrefs, mockBus)
 
 		// When
-		err := repo.Export(context.TODO(), io.Discard)
+		err := repo.Export(context.TODO(), io.Discard, connection_deck.ExportOptions{})
 
 		// Then
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, connection_deck.ErrTechnical)
 	})
 }
+
+func TestFyneConnectionsRepository_ProtectSecrets(t *testing.T) {
+	t.Run("should move the credentials to the secret store and keep only references in the preferences", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockPrefs := mocks_fyne.NewMockPreferences(ctrl)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		store := infrastructure.NewEncryptedFileSecretStore(filepath.Join(t.TempDir(), "secrets.enc"))
+
+		id := connection_deck.NewConnectionID()
+		connJson := fmt.Sprintf(
+			`[{"id":"%s","name":"conn 1","accessKey":"ak","secretKey":"sk","bucket":"b","type":"aws","region":"us-east-1","useTls":true}]`,
+			id)
+
+		var saved string
+		mockPrefs.EXPECT().
+			String("allConnections").
+			Return(connJson).
+			Times(1)
+		mockPrefs.EXPECT().
+			SetString("allConnections", gomock.Any()).
+			Do(func(_ string, value string) { saved = value }).
+			Times(1)
+		mockBus.EXPECT().
+			Subscribe().
+			Return(event.NewSubscriber(make(chan event.Event))).
+			Times(1)
+
+		repo := infrastructure.NewFyneConnectionsRepository(mockPrefs, mockBus, infrastructure.WithSecretStore(store))
+
+		// When
+		err := repo.ProtectSecrets(context.TODO(), "my passphrase")
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, repo.SecretsProtected())
+		assert.NotContains(t, saved, `"sk"`)
+		refs := dto.SecretRefs(id)
+		assert.Contains(t, saved, refs[0])
+		assert.Contains(t, saved, refs[1])
+
+		secret, err := store.Get(context.TODO(), refs[1])
+		require.NoError(t, err)
+		assert.Equal(t, "sk", secret)
+	})
+}
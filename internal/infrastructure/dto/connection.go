@@ -2,6 +2,8 @@ package dto
 
 import (
 	"encoding/json"
+	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
@@ -21,6 +23,29 @@ type connectionDTO struct {
 	Type      string    `json:"type,omitempty"`
 	UseTls    bool      `json:"useTls,omitempty"`
 	ReadOnly  bool      `json:"readOnly,omitempty"`
+	// AccountLevel connections browse any bucket of the account, Bucket being the one browsed
+	AccountLevel bool `json:"accountLevel,omitempty"`
+	// RootPrefix is the key prefix the connection is mounted on
+	RootPrefix string `json:"rootPrefix,omitempty"`
+	// AccessKeyRef and SecretKeyRef replace the credentials when they are kept in a secret store
+	AccessKeyRef string `json:"accessKeyRef,omitempty"`
+	SecretKeyRef string `json:"secretKeyRef,omitempty"`
+
+	CredentialSource    string `json:"credentialSource,omitempty"`
+	SessionToken        string `json:"sessionToken,omitempty"`
+	SessionTokenRef     string `json:"sessionTokenRef,omitempty"`
+	Profile             string `json:"profile,omitempty"`
+	RoleARN             string `json:"roleArn,omitempty"`
+	ExternalID          string `json:"externalId,omitempty"`
+	RoleSessionDuration int    `json:"roleSessionDuration,omitempty"` // in seconds
+
+	CABundle           string `json:"caBundle,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+	ProxyURL           string `json:"proxyUrl,omitempty"`
+	AddressingStyle    string `json:"addressingStyle,omitempty"`
+	Timeout            int    `json:"timeout,omitempty"` // in seconds
+	// ProxyUserRef replaces the user and password of the proxy URL when they are kept in a secret store
+	ProxyUserRef string `json:"proxyUserRef,omitempty"`
 }
 
 type ConnectionsDTO struct {
@@ -45,7 +70,12 @@ func NewConnectionsDTO(c *connection_deck.Deck) *ConnectionsDTO {
 			Type:      conn.Provider().String(),
 			UseTls:    conn.IsTLSActivated(),
 			ReadOnly:  conn.ReadOnly(),
+
+			AccountLevel: conn.IsAccountLevel(),
+			RootPrefix:   conn.RootPrefix(),
 		}
+		dto.setCredentialSource(conn.CredentialSource())
+		dto.setTransport(conn.Transport())
 		if selectedID != nil && selectedID.Is(conn) {
 			dto.Selected = true
 		}
@@ -80,13 +110,15 @@ func (c *ConnectionsDTO) ToConnections() *connection_deck.Deck {
 			connection_deck.WithUseTLS(dto.UseTls),
 			connection_deck.WithID(connID),
 			connection_deck.WithReadOnlyOption(dto.ReadOnly),
+			connection_deck.WithAccountLevel(dto.AccountLevel),
+			connection_deck.WithRootPrefix(dto.RootPrefix),
+			connection_deck.WithCredentialSource(dto.credentialSource()),
+			connection_deck.WithTransport(dto.transport()),
 		)
 		newConn := evt.Payload().(connection_deck.CreateConnectionTriggered).Connection()
-		switch dto.Type {
-		case "aws":
-			connection_deck.AsAWS(dto.Region)(newConn)
-		case "s3-like":
-			connection_deck.AsS3Like(dto.Server, dto.UseTls)(newConn)
+		if dto.Type != "" {
+			provider := connection_deck.NewProviderFromString(dto.Type)
+			connection_deck.AsProvider(provider, dto.Server, dto.Region, dto.UseTls)(newConn)
 		}
 		if dto.Selected {
 			selectedID = connID
@@ -98,6 +130,177 @@ func (c *ConnectionsDTO) ToConnections() *connection_deck.Deck {
 	return conns
 }
 
+func (c *connectionDTO) setCredentialSource(source connection_deck.CredentialSource) {
+	if source.Type != connection_deck.StaticCredentials {
+		c.CredentialSource = source.Type.String()
+	}
+	c.SessionToken = source.SessionToken
+	c.Profile = source.Profile
+	c.RoleARN = source.RoleARN
+	c.ExternalID = source.ExternalID
+	c.RoleSessionDuration = int(source.SessionDuration / time.Second)
+}
+
+func (c *connectionDTO) credentialSource() connection_deck.CredentialSource {
+	return connection_deck.CredentialSource{
+		Type:            connection_deck.NewCredentialSourceTypeFromString(c.CredentialSource),
+		SessionToken:    c.SessionToken,
+		Profile:         c.Profile,
+		RoleARN:         c.RoleARN,
+		ExternalID:      c.ExternalID,
+		SessionDuration: time.Duration(c.RoleSessionDuration) * time.Second,
+	}
+}
+
+// HasSecretRefs returns true when some credentials are references to a secret store.
+func (c *ConnectionsDTO) HasSecretRefs() bool {
+	for _, dto := range c.connections {
+		if dto.AccessKeyRef != "" || dto.SecretKeyRef != "" || dto.SessionTokenRef != "" || dto.ProxyUserRef != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreSecrets saves the credentials with the save function and replaces them by their references.
+func (c *ConnectionsDTO) StoreSecrets(save func(ref, secret string) error) error {
+	for _, dto := range c.connections {
+		accessKeyRef, secretKeyRef := secretRefs(dto.ID)
+		if err := save(accessKeyRef, dto.AccessKey); err != nil {
+			return err
+		}
+		if err := save(secretKeyRef, dto.SecretKey); err != nil {
+			return err
+		}
+		dto.AccessKey, dto.SecretKey = "", ""
+		dto.AccessKeyRef, dto.SecretKeyRef = accessKeyRef, secretKeyRef
+		if dto.SessionToken != "" {
+			sessionTokenRef := sessionTokenRef(dto.ID)
+			if err := save(sessionTokenRef, dto.SessionToken); err != nil {
+				return err
+			}
+			dto.SessionToken, dto.SessionTokenRef = "", sessionTokenRef
+		}
+		if proxyURL, userinfo := splitProxyUser(dto.ProxyURL); userinfo != "" {
+			proxyUserRef := proxyUserRef(dto.ID)
+			if err := save(proxyUserRef, userinfo); err != nil {
+				return err
+			}
+			dto.ProxyURL, dto.ProxyUserRef = proxyURL, proxyUserRef
+		}
+	}
+	return nil
+}
+
+// ResolveSecrets replaces the references of the credentials by the secrets returned by the get function.
+func (c *ConnectionsDTO) ResolveSecrets(get func(ref string) (string, error)) error {
+	for _, dto := range c.connections {
+		if dto.AccessKeyRef != "" {
+			accessKey, err := get(dto.AccessKeyRef)
+			if err != nil {
+				return err
+			}
+			dto.AccessKey, dto.AccessKeyRef = accessKey, ""
+		}
+		if dto.SecretKeyRef != "" {
+			secretKey, err := get(dto.SecretKeyRef)
+			if err != nil {
+				return err
+			}
+			dto.SecretKey, dto.SecretKeyRef = secretKey, ""
+		}
+		if dto.SessionTokenRef != "" {
+			sessionToken, err := get(dto.SessionTokenRef)
+			if err != nil {
+				return err
+			}
+			dto.SessionToken, dto.SessionTokenRef = sessionToken, ""
+		}
+		if dto.ProxyUserRef != "" {
+			userinfo, err := get(dto.ProxyUserRef)
+			if err != nil {
+				return err
+			}
+			dto.ProxyURL, dto.ProxyUserRef = joinProxyUser(dto.ProxyURL, userinfo), ""
+		}
+	}
+	return nil
+}
+
+// RemoveSecrets removes the credentials, the user and password of the proxy, and their references.
+func (c *ConnectionsDTO) RemoveSecrets() {
+	for _, dto := range c.connections {
+		dto.AccessKey, dto.SecretKey = "", ""
+		dto.AccessKeyRef, dto.SecretKeyRef = "", ""
+		dto.SessionToken, dto.SessionTokenRef = "", ""
+		dto.ProxyURL, _ = splitProxyUser(dto.ProxyURL)
+		dto.ProxyUserRef = ""
+	}
+}
+
+// SecretRefs returns the references of the access key, secret key, session token and proxy user
+// of a connection in a secret store.
+func SecretRefs(id connection_deck.ConnectionID) []string {
+	accessKeyRef, secretKeyRef := secretRefs(uuid.UUID(id))
+	return []string{accessKeyRef, secretKeyRef, sessionTokenRef(uuid.UUID(id)), proxyUserRef(uuid.UUID(id))}
+}
+
+func secretRefs(id uuid.UUID) (string, string) {
+	return "connection/" + id.String() + "/accessKey", "connection/" + id.String() + "/secretKey"
+}
+
+func sessionTokenRef(id uuid.UUID) string {
+	return "connection/" + id.String() + "/sessionToken"
+}
+
+func proxyUserRef(id uuid.UUID) string {
+	return "connection/" + id.String() + "/proxyUser"
+}
+
+// splitProxyUser returns the proxy URL without its user and password, and them as "user:password".
+// An URL that can't be parsed is returned as is.
+func splitProxyUser(proxyURL string) (string, string) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || parsed.User == nil {
+		return proxyURL, ""
+	}
+	userinfo := parsed.User.String()
+	parsed.User = nil
+	return parsed.String(), userinfo
+}
+
+// joinProxyUser puts the "user:password" returned by splitProxyUser back into the proxy URL.
+func joinProxyUser(proxyURL, userinfo string) string {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil || userinfo == "" {
+		return proxyURL
+	}
+	withUser, err := url.Parse(parsed.Scheme + "://" + userinfo + "@" + parsed.Host)
+	if err != nil {
+		return proxyURL
+	}
+	parsed.User = withUser.User
+	return parsed.String()
+}
+
 func (c *ConnectionsDTO) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.connections)
 }
+
+func (c *connectionDTO) setTransport(transport connection_deck.TransportOptions) {
+	c.CABundle = transport.CABundle
+	c.InsecureSkipVerify = transport.InsecureSkipVerify
+	c.ProxyURL = transport.ProxyURL
+	c.AddressingStyle = transport.AddressingStyle.String()
+	c.Timeout = int(transport.Timeout / time.Second)
+}
+
+func (c *connectionDTO) transport() connection_deck.TransportOptions {
+	return connection_deck.TransportOptions{
+		CABundle:           c.CABundle,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		ProxyURL:           c.ProxyURL,
+		AddressingStyle:    connection_deck.NewAddressingStyleFromString(c.AddressingStyle),
+		Timeout:            time.Duration(c.Timeout) * time.Second,
+	}
+}
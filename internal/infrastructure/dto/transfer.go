@@ -0,0 +1,181 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+)
+
+type transferJobDTO struct {
+	ID           string    `json:"id"`
+	Kind         string    `json:"kind"`
+	ConnectionID uuid.UUID `json:"connectionId"`
+	Bucket       string    `json:"bucket,omitempty"`
+	RootPrefix   string    `json:"rootPrefix,omitempty"`
+	Key          string    `json:"key"`
+	VersionID    string    `json:"versionId,omitempty"`
+	LocalPath    string    `json:"localPath"`
+	TotalBytes   uint64    `json:"totalBytes"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts,omitempty"`
+	LastError    string    `json:"lastError,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	FinishedAt   time.Time `json:"finishedAt,omitzero"`
+
+	Multipart *multipartUploadDTO `json:"multipart,omitempty"`
+}
+
+type multipartUploadDTO struct {
+	UploadID       string             `json:"uploadId"`
+	PartSizeBytes  int64              `json:"partSizeBytes"`
+	Parts          []multipartPartDTO `json:"parts"`
+	LocalSizeBytes int64              `json:"localSizeBytes"`
+	LocalModTime   time.Time          `json:"localModTime"`
+}
+
+type multipartPartDTO struct {
+	Number    int32  `json:"number"`
+	ETag      string `json:"etag"`
+	Checksum  string `json:"checksum,omitempty"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+func newMultipartUploadDTO(upload *transfer.MultipartUpload) *multipartUploadDTO {
+	if upload == nil {
+		return nil
+	}
+	parts := make([]multipartPartDTO, len(upload.Parts))
+	for i, p := range upload.Parts {
+		parts[i] = multipartPartDTO{
+			Number:    p.Number,
+			ETag:      p.ETag,
+			Checksum:  p.Checksum,
+			SizeBytes: p.SizeBytes,
+		}
+	}
+	return &multipartUploadDTO{
+		UploadID:       upload.UploadID,
+		PartSizeBytes:  upload.PartSizeBytes,
+		Parts:          parts,
+		LocalSizeBytes: upload.Fingerprint.SizeBytes,
+		LocalModTime:   upload.Fingerprint.ModTime,
+	}
+}
+
+func (d *multipartUploadDTO) toMultipartUpload() *transfer.MultipartUpload {
+	if d == nil {
+		return nil
+	}
+	parts := make([]transfer.Part, len(d.Parts))
+	for i, p := range d.Parts {
+		parts[i] = transfer.Part{
+			Number:    p.Number,
+			ETag:      p.ETag,
+			Checksum:  p.Checksum,
+			SizeBytes: p.SizeBytes,
+		}
+	}
+	return &transfer.MultipartUpload{
+		UploadID:      d.UploadID,
+		PartSizeBytes: d.PartSizeBytes,
+		Parts:         parts,
+		Fingerprint: transfer.Fingerprint{
+			SizeBytes: d.LocalSizeBytes,
+			ModTime:   d.LocalModTime,
+		},
+	}
+}
+
+type TransferJobsDTO struct {
+	jobs []*transferJobDTO
+}
+
+func NewTransferJobsDTO(jobs []transfer.Job) *TransferJobsDTO {
+	dtos := make([]*transferJobDTO, 0, len(jobs))
+	for _, job := range jobs {
+		dtos = append(dtos, &transferJobDTO{
+			ID:           job.ID.String(),
+			Kind:         job.Kind.String(),
+			ConnectionID: uuid.UUID(job.ConnectionID),
+			Bucket:       job.Bucket,
+			RootPrefix:   job.RootPrefix,
+			Key:          job.Key,
+			VersionID:    job.VersionID,
+			LocalPath:    job.LocalPath,
+			TotalBytes:   job.TotalBytes,
+			Status:       job.Status.String(),
+			Attempts:     job.Attempts,
+			LastError:    job.LastError,
+			CreatedAt:    job.CreatedAt,
+			FinishedAt:   job.FinishedAt,
+			Multipart:    newMultipartUploadDTO(job.Multipart),
+		})
+	}
+	return &TransferJobsDTO{jobs: dtos}
+}
+
+func NewTransferJobsDTOFromJSON(content []byte) (*TransferJobsDTO, error) {
+	var dtos []*transferJobDTO
+	if err := json.Unmarshal(content, &dtos); err != nil {
+		return nil, err
+	}
+	return &TransferJobsDTO{jobs: dtos}, nil
+}
+
+// ToJobs returns the saved jobs, ignoring the ones with an unknown kind.
+func (d *TransferJobsDTO) ToJobs() []transfer.Job {
+	jobs := make([]transfer.Job, 0, len(d.jobs))
+	for _, dto := range d.jobs {
+		job := transfer.Job{
+			ID:           transfer.JobID(dto.ID),
+			ConnectionID: connection_deck.ConnectionID(dto.ConnectionID),
+			Bucket:       dto.Bucket,
+			RootPrefix:   dto.RootPrefix,
+			Key:          dto.Key,
+			VersionID:    dto.VersionID,
+			LocalPath:    dto.LocalPath,
+			TotalBytes:   dto.TotalBytes,
+			Status:       parseTransferStatus(dto.Status),
+			Attempts:     dto.Attempts,
+			LastError:    dto.LastError,
+			CreatedAt:    dto.CreatedAt,
+			FinishedAt:   dto.FinishedAt,
+			Multipart:    dto.Multipart.toMultipartUpload(),
+		}
+		switch dto.Kind {
+		case transfer.KindUpload.String():
+			job.Kind = transfer.KindUpload
+		case transfer.KindDownload.String():
+			job.Kind = transfer.KindDownload
+		default:
+			continue
+		}
+		if job.Status == transfer.StatusSucceeded {
+			job.TransferredBytes = job.TotalBytes
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func (d *TransferJobsDTO) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.jobs)
+}
+
+func parseTransferStatus(s string) transfer.Status {
+	for _, status := range []transfer.Status{
+		transfer.StatusRunning,
+		transfer.StatusPaused,
+		transfer.StatusSucceeded,
+		transfer.StatusFailed,
+		transfer.StatusCanceled,
+	} {
+		if status.String() == s {
+			return status
+		}
+	}
+	return transfer.StatusQueued
+}
@@ -0,0 +1,31 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+// handleListBuckets lists the buckets with a client built from the connection of the event,
+// which may not be saved yet.
+func (h *EventHandler) handleListBuckets(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(connection_deck.ListBucketsTriggered)
+
+	buckets, err := h.clientFactory.New(pl.Connection()).ListBuckets(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed listing buckets: %w", err)
+		h.notifier.NotifyError(err)
+		h.bus.Publish(e.NewFollowup(connection_deck.ListBucketsFailed{
+			ConnectionPayload: pl.ConnectionPayload,
+			Err:               err,
+		}))
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(connection_deck.ListBucketsSucceeded{
+		ConnectionPayload: pl.ConnectionPayload,
+		Buckets:           buckets,
+	}))
+}
@@ -21,7 +21,24 @@ func (h *EventHandler) handleCreateFile(evt event.Event) {
 			evt.NewFollowup(directory.CreateFileFailed{Err: err, Directory: pl.Directory}))
 	}
 
-	obj, err := h.loadFile(ctx, pl.File, pl.ConnectionID)
+	if err := h.checkWritable(ctx, pl.ConnectionID); err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.ConnectionID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	if !pl.Overwrite {
+		if err := checkNotExists(ctx, client, mapFileToKey(pl.File)); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	obj, err := NewObject(ctx, client, pl.File)
 	if err != nil {
 		handleError(err)
 		return
@@ -45,6 +62,11 @@ func (h *EventHandler) handleCreateDirectory(e event.Event) {
 			e.NewFollowup(directory.CreateFailed{Err: err, ParentDirectory: pl.ParentDirectory}))
 	}
 
+	if err := h.checkWritable(ctx, pl.ParentDirectory.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, pl.ParentDirectory.ConnectionID())
 	if err != nil {
 		handleError(err)
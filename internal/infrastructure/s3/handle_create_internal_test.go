@@ -0,0 +1,89 @@
+package s3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+)
+
+// fakeExistingObjectClient finds every object in the bucket.
+type fakeExistingObjectClient struct {
+	fakeBucketClient
+}
+
+func (c *fakeExistingObjectClient) HeadObject(context.Context, string, ...s3client.Option) (*s3.HeadObjectOutput, error) {
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func TestEventHandler_handleCreate(t *testing.T) {
+	newRootDir := func(t *testing.T) *directory.Directory {
+		return tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded(),
+			tu.WithConnectionId(tu.FakeAwsConnectionId))
+	}
+
+	t.Run("should refuse to create a directory in a read-only connection", func(t *testing.T) {
+		// Given
+		// the embedded client is nil, any request to the bucket would panic
+		h, published := newReadOnlyEventHandler(t, &fakeBucketClient{})
+		evt, err := newRootDir(t).NewSubDirectory("mydir")
+		require.NoError(t, err)
+
+		// When
+		h.handleCreateDirectory(evt)
+
+		// Then
+		require.NotNil(t, *published)
+		pl, ok := (*published).Payload().(directory.CreateFailed)
+		require.True(t, ok)
+		assert.ErrorIs(t, pl.Err, directory.ErrReadOnly)
+	})
+
+	t.Run("should refuse to create a file in a read-only connection", func(t *testing.T) {
+		// Given
+		h, published := newReadOnlyEventHandler(t, &fakeBucketClient{})
+		evt, err := newRootDir(t).NewFile("file.txt", false)
+		require.NoError(t, err)
+
+		// When
+		h.handleCreateFile(evt)
+
+		// Then
+		require.NotNil(t, *published)
+		pl, ok := (*published).Payload().(directory.CreateFileFailed)
+		require.True(t, ok)
+		assert.ErrorIs(t, pl.Err, directory.ErrReadOnly)
+	})
+
+	t.Run("should refuse to create a file existing beyond the loaded page", func(t *testing.T) {
+		// Given
+		// the embedded client is nil, any upload to the bucket would panic
+		h, published := newFailingEventHandler(t, &fakeExistingObjectClient{}, false)
+		dir := tu.NewNotLoadedDirectoryWithConn(t, tu.FakeAwsConnectionId, "data", directory.RootPath)
+		_, err := dir.Load()
+		require.NoError(t, err)
+		require.NoError(t, dir.Notify(event.New(directory.LoadSucceeded{
+			Directory:     dir,
+			NextPageToken: "token-1",
+		})))
+		evt, err := dir.NewFile("file.txt", false)
+		require.NoError(t, err)
+
+		// When
+		h.handleCreateFile(evt)
+
+		// Then
+		require.NotNil(t, *published)
+		pl, ok := (*published).Payload().(directory.CreateFileFailed)
+		require.True(t, ok)
+		assert.ErrorIs(t, pl.Err, directory.ErrAlreadyExists)
+	})
+}
@@ -1,10 +1,14 @@
 package s3
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
 )
 
 func (h *EventHandler) handleDeleteFile(evt event.Event) {
@@ -17,6 +21,11 @@ func (h *EventHandler) handleDeleteFile(evt event.Event) {
 			directory.DeleteFileFailed{Err: err, ParentDirectory: pl.ParentDirectory}))
 	}
 
+	if err := h.checkWritable(ctx, pl.ConnectionID); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, pl.ConnectionID)
 	if err != nil {
 		handleError(err)
@@ -59,12 +68,22 @@ func (h *EventHandler) handleDeleteDirectory(evt event.Event) {
 			directory.DeleteFailed{Err: err, Parent: pl.Directory, Directory: child}))
 	}
 
+	if err := h.checkWritable(ctx, pl.Directory.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, pl.Directory.ConnectionID())
 	if err != nil {
 		handleError(err)
 		return
 	}
 
+	if pl.Recursive {
+		h.deleteDirectoryRecursively(evt, client, pl.Directory, child)
+		return
+	}
+
 	key := mapPathToObjectKey(pl.DeletedDirPath)
 	if err := client.DeleteObject(ctx, key); err != nil {
 		handleError(err)
@@ -74,3 +93,63 @@ func (h *EventHandler) handleDeleteDirectory(evt event.Event) {
 	h.bus.Publish(
 		evt.NewFollowup(directory.DeleteSucceeded{Directory: child, Parent: pl.Directory}))
 }
+
+// deleteDirectoryRecursively removes every object under the directory prefix, page by page.
+// A progress event is published after each page. When some objects can't be removed, or when
+// the event's context is canceled, a failure event is published with an UncompletedDelete error.
+func (h *EventHandler) deleteDirectoryRecursively(evt event.Event, client s3client.Client, parent, child *directory.Directory) {
+	ctx := evt.Context()
+
+	var (
+		deletedCount int
+		failedKeys   = make(map[string]error)
+	)
+
+	listErr := client.ListObjectsWithCallback(ctx, mapPathToSearchKey(child.Path()), true, func(page *s3.ListObjectsV2Output) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+
+		failures := client.DeleteObjects(ctx, keys)
+		deletedCount += len(keys) - len(failures)
+		for key, err := range failures {
+			failedKeys[key] = err
+		}
+
+		h.bus.Publish(evt.NewFollowup(directory.DeleteProgress{
+			Parent:       parent,
+			Directory:    child,
+			DeletedCount: deletedCount,
+			FailedCount:  len(failedKeys),
+		}))
+		return nil
+	})
+
+	var wrapped error
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		wrapped = errors.Join(directory.ErrCanceled, ctxErr)
+	} else if listErr != nil {
+		wrapped = listErr
+	}
+
+	if wrapped != nil || len(failedKeys) > 0 {
+		err := directory.UncompletedDelete{
+			DirPath:      child.Path(),
+			DeletedCount: deletedCount,
+			FailedKeys:   failedKeys,
+			Wrapped:      wrapped,
+		}
+		h.notifier.NotifyError(fmt.Errorf("failed deleting directory: %w", err))
+		h.bus.Publish(evt.NewFollowup(
+			directory.DeleteFailed{Err: err, Parent: parent, Directory: child}))
+		return
+	}
+
+	h.bus.Publish(evt.NewFollowup(
+		directory.DeleteSucceeded{Directory: child, Parent: parent, DeletedCount: deletedCount}))
+}
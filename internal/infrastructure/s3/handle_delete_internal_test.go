@@ -0,0 +1,91 @@
+package s3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	mocks_connection_deck "github.com/thomas-marquis/s3-box/mocks/connection_deck"
+	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
+	mocks_notification "github.com/thomas-marquis/s3-box/mocks/notification"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeDeletingClient struct {
+	s3client.Client
+	deletedKeys []string
+}
+
+func (c *fakeDeletingClient) DeleteObject(_ context.Context, key string, _ ...s3client.Option) error {
+	c.deletedKeys = append(c.deletedKeys, key)
+	return nil
+}
+
+func (c *fakeDeletingClient) DeleteObjects(_ context.Context, keys []string, _ ...s3client.Option) map[string]error {
+	c.deletedKeys = append(c.deletedKeys, keys...)
+	return nil
+}
+
+// newReadOnlyEventHandler returns a handler whose only connection is read-only.
+// The failure of the refused operation is expected to be notified then published once.
+func newReadOnlyEventHandler(t *testing.T, client s3client.Client) (*EventHandler, *event.Event) {
+	t.Helper()
+	return newFailingEventHandler(t, client, true)
+}
+
+// newFailingEventHandler returns a handler whose only connection is read-only or not.
+// The failure of the operation is expected to be notified then published once.
+func newFailingEventHandler(t *testing.T, client s3client.Client, readOnly bool) (*EventHandler, *event.Event) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockBus := mocks_event.NewMockBus(ctrl)
+	mockNotifier := mocks_notification.NewMockRepository(ctrl)
+	mockConnRepo := mocks_connection_deck.NewMockRepository(ctrl)
+
+	conn := tu.FakeAwsConnection(t, tu.FakeAwsBucketName)
+	conn.SetReadOnly(readOnly)
+	mockConnRepo.EXPECT().Get(gomock.Any()).Return(tu.FakeDeckWithConnections(t, conn), nil)
+
+	published := new(event.Event)
+	mockNotifier.EXPECT().NotifyError(gomock.Any())
+	mockBus.EXPECT().Publish(gomock.Any()).Do(func(e event.Event) { *published = e })
+
+	return &EventHandler{
+		connectionRepository: mockConnRepo,
+		bus:                  mockBus,
+		notifier:             mockNotifier,
+		clientFactory:        &fakeClientFactory{client: client},
+	}, published
+}
+
+func TestEventHandler_handleDeleteDirectory(t *testing.T) {
+	t.Run("should refuse to delete a directory of a read-only connection", func(t *testing.T) {
+		// Given
+		client := &fakeDeletingClient{}
+		h, published := newReadOnlyEventHandler(t, client)
+
+		rootDir := tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded(),
+			tu.WithConnectionId(tu.FakeAwsConnectionId),
+			tu.WithSubDirectory("mydir"))
+		evt, err := rootDir.RemoveSubDirectoryRecursively("mydir")
+		require.NoError(t, err)
+
+		// When
+		h.handleDeleteDirectory(evt)
+
+		// Then
+		require.NotNil(t, *published)
+		pl, ok := (*published).Payload().(directory.DeleteFailed)
+		require.True(t, ok)
+		assert.ErrorIs(t, pl.Err, directory.ErrReadOnly)
+		assert.Empty(t, client.deletedKeys)
+	})
+}
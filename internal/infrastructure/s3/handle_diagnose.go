@@ -0,0 +1,21 @@
+package s3
+
+import (
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+)
+
+// handleDiagnose checks the connection of the event step by step, with a client built from it
+// as the connection may not be saved yet.
+func (h *EventHandler) handleDiagnose(e event.Event) {
+	pl := e.Payload().(connection_deck.DiagnoseTriggered)
+	conn := pl.Connection()
+
+	steps := s3client.Diagnose(e.Context(), conn, h.clientFactory.New(conn))
+
+	h.bus.Publish(e.NewFollowup(connection_deck.DiagnoseSucceeded{
+		ConnectionPayload: pl.ConnectionPayload,
+		Steps:             steps,
+	}))
+}
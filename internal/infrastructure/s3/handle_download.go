@@ -1,40 +1,259 @@
 package s3
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
 	"github.com/thomas-marquis/s3-box/internal/u"
 )
 
+const (
+	maxDownloadingWorkers = 5
+)
+
+type downloadOutcome int
+
+const (
+	downloadOutcomeDone downloadOutcome = iota
+	downloadOutcomeSkipped
+	downloadOutcomeIgnored
+)
+
 func (h *EventHandler) handleDownloadFile(e event.Event) {
-	ctx := e.Context()
 	pl := e.Payload().(directory.DownloadFileTriggered)
 
-	handleError := func(err error) {
+	client, err := h.clientFactory.Get(e.Context(), pl.ConnectionID)
+	if err != nil {
 		h.notifier.NotifyError(fmt.Errorf("failed downloading file: %w", err))
 		h.bus.Publish(e.NewFollowup(directory.DownloadFileFailed{Err: err}))
+		return
+	}
+
+	job := transfer.NewDownloadJob(pl.ConnectionID, mapFileToKey(pl.File), pl.File.VersionID(), pl.DstPath, pl.File.SizeBytes())
+	job.Bucket, job.RootPrefix = client.Bucket(), client.RootPrefix()
+	// the job runs in the background, it is canceled when the download request is,
+	// e.g. when the command line is interrupted
+	stopCancel := context.AfterFunc(e.Context(), func() {
+		h.bus.Publish(event.New(transfer.CancelTriggered{JobID: job.ID}))
+	})
+	h.transfers.enqueue(job, func(_ transfer.Job, err error) {
+		stopCancel()
+		if err != nil {
+			// the failure is already notified by the transfer manager
+			h.bus.Publish(e.NewFollowup(directory.DownloadFileFailed{Err: err}))
+			return
+		}
+		h.bus.Publish(e.NewFollowup(directory.DownloadFileSucceeded{File: pl.File}))
+	})
+}
+
+func (h *EventHandler) handleDownloadDirectory(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.DownloadTriggered)
+	dir := pl.Directory
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed downloading directory: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.DownloadFailed{Err: err, Directory: dir}))
 	}
 
-	client, err := h.clientFactory.Get(ctx, pl.ConnectionID)
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
 	if err != nil {
 		handleError(err)
 		return
 	}
 
-	localFile, err := os.OpenFile(pl.DstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
-	if err != nil {
-		handleError(fmt.Errorf("failed opening the file to download: %w", err))
+	dstRoot := filepath.Join(pl.DstPath, dir.Name())
+	if err := os.MkdirAll(dstRoot, 0o755); err != nil {
+		handleError(fmt.Errorf("failed creating the local directory: %w", err))
 		return
 	}
-	defer u.SkipD(localFile.Close)
 
-	if err := client.Download(ctx, mapFileToKey(pl.File), localFile); err != nil {
-		handleError(fmt.Errorf("failed downloading file: %w", err))
+	var (
+		prefix                        = mapPathToSearchKey(dir.Path())
+		downloadedCount, skippedCount int
+		failedKeys                    = make(map[string]error)
+	)
+
+	listErr := client.ListObjectsWithCallback(ctx, prefix, true, func(page *s3.ListObjectsV2Output) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+
+		downloaded, skipped, failures := downloadObjects(ctx, client, keys, prefix, dstRoot, pl.Strategy)
+		downloadedCount += downloaded
+		skippedCount += skipped
+		for key, err := range failures {
+			failedKeys[key] = err
+		}
+
+		h.bus.Publish(e.NewFollowup(directory.DownloadProgress{
+			Directory:       dir,
+			DownloadedCount: downloadedCount,
+			SkippedCount:    skippedCount,
+			FailedCount:     len(failedKeys),
+		}))
+		return nil
+	})
+
+	var wrapped error
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		wrapped = errors.Join(directory.ErrCanceled, ctxErr)
+	} else if listErr != nil {
+		wrapped = listErr
+	}
+
+	if wrapped != nil || len(failedKeys) > 0 {
+		handleError(directory.UncompletedDownload{
+			DirPath:         dir.Path(),
+			DownloadedCount: downloadedCount,
+			FailedKeys:      failedKeys,
+			Wrapped:         wrapped,
+		})
 		return
 	}
 
-	h.bus.Publish(e.NewFollowup(directory.DownloadFileSucceeded{File: pl.File}))
+	h.bus.Publish(e.NewFollowup(directory.DownloadSucceeded{
+		Directory:       dir,
+		DstPath:         dstRoot,
+		DownloadedCount: downloadedCount,
+		SkippedCount:    skippedCount,
+	}))
+}
+
+// downloadObjects downloads the given keys concurrently under dstRoot, keeping their path relative to prefix.
+// It returns the number of downloaded and skipped objects, and the errors indexed by key for the ones that failed.
+func downloadObjects(
+	ctx context.Context,
+	client s3client.Client,
+	keys []string,
+	prefix, dstRoot string,
+	strategy directory.MaterializeStrategy,
+) (int, int, map[string]error) {
+	var (
+		workload = make(chan string)
+		failures = make(map[string]error)
+
+		downloaded, skipped int
+
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for range min(len(keys), maxDownloadingWorkers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range workload {
+				if ctx.Err() != nil {
+					continue
+				}
+				outcome, err := downloadObject(ctx, client, key, prefix, dstRoot, strategy)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					failures[key] = err
+				case outcome == downloadOutcomeDone:
+					downloaded++
+				case outcome == downloadOutcomeSkipped:
+					skipped++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		workload <- key
+	}
+	close(workload)
+	wg.Wait()
+
+	return downloaded, skipped, failures
+}
+
+// downloadObject writes a single object to its local location.
+// Directory markers only create the local directory, rename markers are ignored and,
+// with the skip strategy, existing local files are kept untouched.
+func downloadObject(
+	ctx context.Context,
+	client s3client.Client,
+	key, prefix, dstRoot string,
+	strategy directory.MaterializeStrategy,
+) (downloadOutcome, error) {
+	relPath := filepath.FromSlash(strings.TrimPrefix(key, prefix))
+	if relPath == "" || isRenameMarkerFile(key) {
+		return downloadOutcomeIgnored, nil
+	}
+	if !filepath.IsLocal(relPath) {
+		return downloadOutcomeIgnored, fmt.Errorf("invalid object key %s for a local path", key)
+	}
+
+	localPath := filepath.Join(dstRoot, relPath)
+	if strings.HasSuffix(key, "/") {
+		return downloadOutcomeIgnored, os.MkdirAll(localPath, 0o755)
+	}
+
+	if strategy == directory.MaterializeSkip {
+		if _, err := os.Stat(localPath); err == nil {
+			return downloadOutcomeSkipped, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return downloadOutcomeIgnored, err
+	}
+
+	if err := downloadToFile(localPath, func(writer io.WriterAt) error {
+		return client.Download(ctx, key, writer)
+	}); err != nil {
+		return downloadOutcomeIgnored, err
+	}
+	return downloadOutcomeDone, nil
+}
+
+// downloadToFile writes the download in a temporary file next to the local path,
+// then moves it over the local path once complete.
+// A failed or canceled download removes the temporary file only, an existing local file is left untouched.
+func downloadToFile(localPath string, download func(writer io.WriterAt) error) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".*.part")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	err = download(tmpFile)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// the temporary files are only readable by their owner
+		err = os.Chmod(tmpPath, 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, localPath)
+	}
+	if err != nil {
+		u.Skip(os.Remove(tmpPath))
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,198 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
+	mocks_notification "github.com/thomas-marquis/s3-box/mocks/notification"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeDownloadClient struct {
+	fakeBucketClient
+	contents map[string]string
+	// broken keys fail once their content is partially written
+	broken map[string]bool
+}
+
+func (c *fakeDownloadClient) Download(_ context.Context, key string, writer io.WriterAt, _ ...s3client.Option) error {
+	content, ok := c.contents[key]
+	if !ok {
+		return directory.ErrNotFound
+	}
+	if c.broken[key] {
+		_, _ = writer.WriteAt([]byte(content[:len(content)/2]), 0)
+		return errors.New("connection reset by peer")
+	}
+	_, err := writer.WriteAt([]byte(content), 0)
+	return err
+}
+
+func TestDownloadObject(t *testing.T) {
+	client := &fakeDownloadClient{contents: map[string]string{
+		"mydir/file.txt":       "new content",
+		"mydir/sub/nested.txt": "nested",
+		"mydir/broken.txt":     "content never fully received",
+	}, broken: map[string]bool{"mydir/broken.txt": true}}
+
+	t.Run("should recreate the sub-tree locally", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+
+		// When
+		res, err := downloadObject(t.Context(), client, "mydir/sub/nested.txt", "mydir/", dstRoot, directory.MaterializeSkip)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, downloadOutcomeDone, res)
+		content, err := os.ReadFile(filepath.Join(dstRoot, "sub", "nested.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "nested", string(content))
+	})
+
+	t.Run("should keep the existing local file with the skip strategy", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+		localPath := filepath.Join(dstRoot, "file.txt")
+		assert.NoError(t, os.WriteFile(localPath, []byte("old content"), 0o644))
+
+		// When
+		res, err := downloadObject(t.Context(), client, "mydir/file.txt", "mydir/", dstRoot, directory.MaterializeSkip)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, downloadOutcomeSkipped, res)
+		content, _ := os.ReadFile(localPath)
+		assert.Equal(t, "old content", string(content))
+	})
+
+	t.Run("should overwrite the existing local file with the replace strategy", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+		localPath := filepath.Join(dstRoot, "file.txt")
+		assert.NoError(t, os.WriteFile(localPath, []byte("old content but longer"), 0o644))
+
+		// When
+		res, err := downloadObject(t.Context(), client, "mydir/file.txt", "mydir/", dstRoot, directory.MaterializeReplace)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, downloadOutcomeDone, res)
+		content, _ := os.ReadFile(localPath)
+		assert.Equal(t, "new content", string(content))
+	})
+
+	t.Run("should only create the local directory for a directory marker", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+
+		// When
+		res, err := downloadObject(t.Context(), client, "mydir/empty/", "mydir/", dstRoot, directory.MaterializeSkip)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, downloadOutcomeIgnored, res)
+		assert.DirExists(t, filepath.Join(dstRoot, "empty"))
+	})
+
+	t.Run("should refuse keys escaping the local directory", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+
+		// When
+		_, err := downloadObject(t.Context(), client, "mydir/../../evil.txt", "mydir/", dstRoot, directory.MaterializeSkip)
+
+		// Then
+		assert.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(dstRoot, "..", "..", "evil.txt"))
+	})
+
+	t.Run("should leave the existing local file untouched when the download fails", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+		localPath := filepath.Join(dstRoot, "broken.txt")
+		assert.NoError(t, os.WriteFile(localPath, []byte("local work"), 0o644))
+
+		// When
+		_, err := downloadObject(t.Context(), client, "mydir/broken.txt", "mydir/", dstRoot, directory.MaterializeReplace)
+
+		// Then
+		assert.Error(t, err)
+		content, _ := os.ReadFile(localPath)
+		assert.Equal(t, "local work", string(content))
+		entries, _ := os.ReadDir(dstRoot)
+		assert.Len(t, entries, 1, "the temporary file should be removed")
+	})
+
+	t.Run("should not create the local file when the download fails", func(t *testing.T) {
+		// Given
+		dstRoot := t.TempDir()
+
+		// When
+		_, err := downloadObject(t.Context(), client, "mydir/missing.txt", "mydir/", dstRoot, directory.MaterializeReplace)
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrNotFound)
+		assert.NoFileExists(t, filepath.Join(dstRoot, "missing.txt"))
+		entries, _ := os.ReadDir(dstRoot)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestEventHandler_handleDownloadFile(t *testing.T) {
+	t.Run("should cancel the queued job when the download request is canceled", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+		mockNotifier.EXPECT().NotifyError(gomock.Any()).AnyTimes()
+		client := &fakeBlockingDownloadClient{started: make(chan struct{})}
+		factory := &fakeClientFactory{client: client}
+		h := &EventHandler{
+			bus:           mockBus,
+			notifier:      mockNotifier,
+			clientFactory: factory,
+			transfers:     newTransferManager(mockBus, mockNotifier, factory),
+		}
+
+		done := make(chan struct{})
+		var resErr error
+		mockBus.EXPECT().Publish(gomock.Any()).AnyTimes().Do(func(e event.Event) {
+			switch pl := e.Payload().(type) {
+			case transfer.CancelTriggered:
+				go h.transfers.handleCancel(e)
+			case directory.DownloadFileFailed:
+				resErr = pl.Err
+				close(done)
+			}
+		})
+
+		dir := tu.NewNotLoadedDirectoryWithConn(t, tu.FakeAwsConnectionId, "mydir", directory.RootPath)
+		file, err := directory.NewFile("file.txt", dir)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(t.Context())
+		evt := file.Download(tu.FakeAwsConnectionId, filepath.Join(t.TempDir(), "file.txt"), event.WithContext(ctx))
+
+		h.handleDownloadFile(evt)
+		tu.AssertEventually(t, client.started)
+
+		// When
+		cancel()
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.ErrorIs(t, resErr, directory.ErrCanceled)
+	})
+}
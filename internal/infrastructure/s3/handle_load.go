@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
@@ -19,7 +20,7 @@ func (h *EventHandler) handleLoadDirectory(e event.Event) {
 
 	handleError := func(err error) {
 		h.notifier.NotifyError(fmt.Errorf("failed loading directory: %w", err))
-		h.bus.Publish(event.New(directory.LoadFailed{
+		h.bus.Publish(e.NewFollowup(directory.LoadFailed{
 			Err:       err,
 			Directory: pl.Directory,
 		}))
@@ -31,64 +32,170 @@ func (h *EventHandler) handleLoadDirectory(e event.Event) {
 		return
 	}
 
-	if err := h.loadDirectory(ctx, client, dir, e); err != nil {
+	if err := h.loadDirectory(ctx, client, dir, e, pl.IncludeDeleted, pl.AllPages); err != nil {
 		handleError(err)
 	}
 }
 
-func (h *EventHandler) loadDirectory(ctx context.Context, client s3client.Client, dir *directory.Directory, prevEvent event.Event) error {
+func (h *EventHandler) handleLoadDirectoryPage(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.LoadPageTriggered)
+	dir := pl.Directory
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed loading the next page of the directory: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.LoadPageFailed{
+			Err:       err,
+			Directory: dir,
+		}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
 	searchKey := mapPathToSearchKey(dir.Path())
+	page, err := client.ListObjectsPage(ctx, searchKey, false, pl.PageToken)
+	if err != nil {
+		handleError(err)
+		return
+	}
 
-	files := make([]*directory.File, 0)
-	subDirectories := make([]*directory.Directory, 0)
+	files, subDirectories, err := h.mapListObjectsPage(ctx, client, dir, searchKey, page)
+	if err != nil {
+		handleError(err)
+		return
+	}
 
-	if err := client.ListObjectsWithCallback(ctx, searchKey, false, func(page *s3.ListObjectsV2Output) error {
-		for _, obj := range page.Contents {
-			key := *obj.Key
+	h.bus.Publish(e.NewFollowup(directory.LoadPageSucceeded{
+		Directory:      dir,
+		Files:          files,
+		SubDirectories: subDirectories,
+		NextPageToken:  aws.ToString(page.NextContinuationToken),
+	}))
+}
 
-			if isRenameMarkerFile(key) {
-				return h.getPendingRenameErr(ctx, client, dir, key)
-			}
+// loadDirectory loads the first page of the directory content, or all of them with allPages.
+// The following pages are loaded on demand, from the returned continuation token.
+func (h *EventHandler) loadDirectory(ctx context.Context, client s3client.Client, dir *directory.Directory, prevEvent event.Event, includeDeleted, allPages bool) error {
+	searchKey := mapPathToSearchKey(dir.Path())
 
-			if key == searchKey {
-				continue
-			}
-			f, err := directory.NewFile(mapKeyToObjectName(key), dir,
-				directory.WithFileSize(uint64(*obj.Size)),
-				directory.WithFileLastModified(*obj.LastModified))
-			if err != nil {
-				return fmt.Errorf("error while creating a file: %w", err)
-			}
-			files = append(files, f)
+	page, err := client.ListObjectsPage(ctx, searchKey, false, "")
+	if err != nil {
+		return err
+	}
+
+	files, subDirectories, err := h.mapListObjectsPage(ctx, client, dir, searchKey, page)
+	if err != nil {
+		return err
+	}
+
+	for allPages && aws.ToString(page.NextContinuationToken) != "" {
+		page, err = client.ListObjectsPage(ctx, searchKey, false, aws.ToString(page.NextContinuationToken))
+		if err != nil {
+			return err
 		}
+		pageFiles, pageSubDirectories, err := h.mapListObjectsPage(ctx, client, dir, searchKey, page)
+		if err != nil {
+			return err
+		}
+		files = append(files, pageFiles...)
+		subDirectories = append(subDirectories, pageSubDirectories...)
+	}
 
-		for _, obj := range page.CommonPrefixes {
-			if *obj.Prefix == searchKey {
-				continue
-			}
-			s3Prefix := *obj.Prefix
-			isDir := strings.HasSuffix(s3Prefix, "/")
-			if isDir {
-				d, err := directory.New(dir.ConnectionID(), directory.NewPath(s3Prefix).DirectoryName(), dir)
-				if err != nil {
-					return fmt.Errorf("error while loading a directory: %w", err)
-				}
-				subDirectories = append(subDirectories, d)
-			}
+	if includeDeleted {
+		deletedFiles, err := listDeletedFiles(ctx, client, dir, searchKey, files)
+		if err != nil {
+			return err
 		}
-		return nil
-	}); err != nil {
-		return err
+		files = append(files, deletedFiles...)
 	}
 
 	h.bus.Publish(prevEvent.NewFollowup(directory.LoadSucceeded{
 		Directory:      dir,
 		Files:          files,
 		SubDirectories: subDirectories,
+		NextPageToken:  aws.ToString(page.NextContinuationToken),
 	}))
 	return nil
 }
 
+// mapListObjectsPage converts a page of listed objects into the files and the subdirectories of the directory.
+func (h *EventHandler) mapListObjectsPage(ctx context.Context, client s3client.Client, dir *directory.Directory, searchKey string, page *s3.ListObjectsV2Output) ([]*directory.File, []*directory.Directory, error) {
+	files := make([]*directory.File, 0)
+	subDirectories := make([]*directory.Directory, 0)
+
+	for _, obj := range page.Contents {
+		key := *obj.Key
+
+		if isRenameMarkerFile(key) {
+			return nil, nil, h.getPendingRenameErr(ctx, client, dir, key)
+		}
+
+		if key == searchKey {
+			continue
+		}
+		f, err := directory.NewFile(mapKeyToObjectName(key), dir,
+			directory.WithFileSize(uint64(*obj.Size)),
+			directory.WithFileLastModified(*obj.LastModified))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error while creating a file: %w", err)
+		}
+		files = append(files, f)
+	}
+
+	for _, obj := range page.CommonPrefixes {
+		if *obj.Prefix == searchKey {
+			continue
+		}
+		s3Prefix := *obj.Prefix
+		isDir := strings.HasSuffix(s3Prefix, "/")
+		if isDir {
+			d, err := directory.New(dir.ConnectionID(), directory.NewPath(s3Prefix).DirectoryName(), dir)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error while loading a directory: %w", err)
+			}
+			subDirectories = append(subDirectories, d)
+		}
+	}
+
+	return files, subDirectories, nil
+}
+
+// listDeletedFiles returns the files of the directory whose latest version is a delete marker.
+func listDeletedFiles(ctx context.Context, client s3client.Client, dir *directory.Directory, searchKey string, existing []*directory.File) ([]*directory.File, error) {
+	versions, err := client.ListObjectVersions(ctx, searchKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing the deleted files: %w", err)
+	}
+
+	existingNames := make(map[directory.FileName]struct{}, len(existing))
+	for _, f := range existing {
+		existingNames[f.Name()] = struct{}{}
+	}
+
+	files := make([]*directory.File, 0)
+	for _, v := range versions {
+		if !v.IsLatest || !v.IsDeleteMarker || v.Key == searchKey || strings.HasSuffix(v.Key, "/") || isRenameMarkerFile(v.Key) {
+			continue
+		}
+		name := mapKeyToObjectName(v.Key)
+		if _, ok := existingNames[directory.FileName(name)]; ok {
+			continue
+		}
+		f, err := directory.NewFile(name, dir,
+			directory.WithFileLastModified(v.LastModified),
+			directory.WithFileDeleted(true))
+		if err != nil {
+			return nil, fmt.Errorf("error while creating a deleted file: %w", err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
 func (h *EventHandler) handleLoadFile(e event.Event) {
 	ctx := e.Context()
 	pl := e.Payload().(directory.LoadFileTriggered)
@@ -0,0 +1,133 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
+	mocks_notification "github.com/thomas-marquis/s3-box/mocks/notification"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEventHandler_mapListObjectsPage(t *testing.T) {
+	t.Run("should map the objects of the page to files and subdirectories", func(t *testing.T) {
+		// Given
+		h := &EventHandler{}
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		page := &s3.ListObjectsV2Output{
+			Contents: []types.Object{
+				{Key: aws.String("data/"), Size: aws.Int64(0), LastModified: aws.Time(time.Now())},
+				{Key: aws.String("data/a.txt"), Size: aws.Int64(12), LastModified: aws.Time(time.Now())},
+			},
+			CommonPrefixes: []types.CommonPrefix{
+				{Prefix: aws.String("data/sub/")},
+			},
+			NextContinuationToken: aws.String("token"),
+		}
+
+		// When
+		files, subDirs, err := h.mapListObjectsPage(t.Context(), nil, dir, "data/", page)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, directory.FileName("a.txt"), files[0].Name())
+		assert.Equal(t, uint64(12), files[0].SizeBytes())
+		require.Len(t, subDirs, 1)
+		assert.Equal(t, "sub", subDirs[0].Name())
+	})
+}
+
+type fakeListingClient struct {
+	s3client.Client
+	err error
+	// pages are listed by continuation token, the first one under ""
+	pages map[string]*s3.ListObjectsV2Output
+}
+
+func (c *fakeListingClient) ListObjectsPage(_ context.Context, _ string, _ bool, pageToken string, _ ...s3client.Option) (*s3.ListObjectsV2Output, error) {
+	return c.pages[pageToken], c.err
+}
+
+func TestEventHandler_handleLoadDirectory(t *testing.T) {
+	t.Run("should publish the failure as a follow-up of the loading", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+		h := &EventHandler{
+			bus:           mockBus,
+			notifier:      mockNotifier,
+			clientFactory: &fakeClientFactory{client: &fakeListingClient{err: errors.New("access denied")}},
+		}
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		evt, err := dir.Load()
+		require.NoError(t, err)
+
+		mockNotifier.EXPECT().NotifyError(gomock.Any())
+		var published event.Event
+		mockBus.EXPECT().Publish(gomock.Any()).Do(func(e event.Event) { published = e })
+
+		// When
+		h.handleLoadDirectory(evt)
+
+		// Then
+		require.NotNil(t, published)
+		assert.Equal(t, directory.LoadFailedType, published.Type())
+		assert.Equal(t, evt.ID(), published.ParentID())
+	})
+
+	t.Run("should list every page when asked to load all of them", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		client := &fakeListingClient{pages: map[string]*s3.ListObjectsV2Output{
+			"": {
+				Contents: []types.Object{
+					{Key: aws.String("data/a.txt"), Size: aws.Int64(1), LastModified: aws.Time(time.Now())},
+				},
+				NextContinuationToken: aws.String("token-1"),
+			},
+			"token-1": {
+				Contents: []types.Object{
+					{Key: aws.String("data/b.txt"), Size: aws.Int64(1), LastModified: aws.Time(time.Now())},
+				},
+				CommonPrefixes: []types.CommonPrefix{{Prefix: aws.String("data/sub/")}},
+			},
+		}}
+		h := &EventHandler{
+			bus:           mockBus,
+			clientFactory: &fakeClientFactory{client: client},
+		}
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		evt, err := dir.Load(directory.WithAllPages())
+		require.NoError(t, err)
+
+		var published event.Event
+		mockBus.EXPECT().Publish(gomock.Any()).Do(func(e event.Event) { published = e })
+
+		// When
+		h.handleLoadDirectory(evt)
+
+		// Then
+		require.NotNil(t, published)
+		pl, ok := published.Payload().(directory.LoadSucceeded)
+		require.True(t, ok)
+		require.Len(t, pl.Files, 2)
+		assert.Equal(t, directory.FileName("a.txt"), pl.Files[0].Name())
+		assert.Equal(t, directory.FileName("b.txt"), pl.Files[1].Name())
+		assert.Len(t, pl.SubDirectories, 1)
+		assert.Empty(t, pl.NextPageToken)
+	})
+}
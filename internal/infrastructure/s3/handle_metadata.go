@@ -0,0 +1,106 @@
+package s3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+const (
+	defaultStorageClass = "STANDARD"
+)
+
+func (h *EventHandler) handleLoadFileMetadata(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.LoadFileMetadataTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed loading file metadata: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.LoadFileMetadataFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.File.Parent().ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	head, err := client.HeadObject(ctx, mapFileToKey(pl.File))
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.LoadFileMetadataSucceeded{
+		File:     pl.File,
+		Metadata: mapHeadObjectToMetadata(head),
+	}))
+}
+
+func (h *EventHandler) handleUpdateFileMetadata(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.UpdateFileMetadataTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed updating file metadata: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.UpdateFileMetadataFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	connID := pl.File.Parent().ConnectionID()
+	if err := h.checkWritable(ctx, connID); err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, connID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	key := mapFileToKey(pl.File)
+	if err := client.ReplaceObjectMetadata(ctx, key, pl.Metadata); err != nil {
+		handleError(err)
+		return
+	}
+
+	// The copy in place changes the ETag and the last modification date.
+	head, err := client.HeadObject(ctx, key)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.UpdateFileMetadataSucceeded{
+		File:     pl.File,
+		Metadata: mapHeadObjectToMetadata(head),
+	}))
+}
+
+func mapHeadObjectToMetadata(head *s3.HeadObjectOutput) directory.ObjectMetadata {
+	storageClass := string(head.StorageClass)
+	if storageClass == "" {
+		storageClass = defaultStorageClass
+	}
+	return directory.ObjectMetadata{
+		ContentType:        aws.ToString(head.ContentType),
+		CacheControl:       aws.ToString(head.CacheControl),
+		ContentEncoding:    aws.ToString(head.ContentEncoding),
+		ContentDisposition: aws.ToString(head.ContentDisposition),
+		ContentLanguage:    aws.ToString(head.ContentLanguage),
+		ETag:               strings.Trim(aws.ToString(head.ETag), `"`),
+		StorageClass:       storageClass,
+		LastModified:       aws.ToTime(head.LastModified),
+		UserMetadata:       head.Metadata,
+	}
+}
@@ -0,0 +1,48 @@
+package s3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func TestMapHeadObjectToMetadata(t *testing.T) {
+	t.Run("should map the headers and the user metadata", func(t *testing.T) {
+		// Given
+		lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		head := &s3.HeadObjectOutput{
+			ContentType:  aws.String("application/json"),
+			CacheControl: aws.String("max-age=3600"),
+			ETag:         aws.String(`"d41d8cd98f00b204e9800998ecf8427e"`),
+			StorageClass: types.StorageClassGlacier,
+			LastModified: aws.Time(lastModified),
+			Metadata:     map[string]string{"owner": "team-data"},
+		}
+
+		// When
+		res := mapHeadObjectToMetadata(head)
+
+		// Then
+		assert.Equal(t, directory.ObjectMetadata{
+			ContentType:  "application/json",
+			CacheControl: "max-age=3600",
+			ETag:         "d41d8cd98f00b204e9800998ecf8427e",
+			StorageClass: "GLACIER",
+			LastModified: lastModified,
+			UserMetadata: map[string]string{"owner": "team-data"},
+		}, res)
+	})
+
+	t.Run("should default to the standard storage class", func(t *testing.T) {
+		// When
+		res := mapHeadObjectToMetadata(&s3.HeadObjectOutput{})
+
+		// Then
+		assert.Equal(t, "STANDARD", res.StorageClass)
+	})
+}
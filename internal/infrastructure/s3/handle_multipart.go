@@ -0,0 +1,85 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+)
+
+func (h *EventHandler) handleListIncompleteUploads(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(transfer.ListIncompleteUploadsTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed listing incomplete uploads: %w", err))
+		h.bus.Publish(e.NewFollowup(transfer.ListIncompleteUploadsFailed{Err: err}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.ConnectionID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	multipartUploads, err := client.ListMultipartUploads(ctx, "")
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	resumable := h.transfers.resumableUploadIDs()
+	uploads := make([]transfer.IncompleteUpload, 0, len(multipartUploads))
+	for _, mu := range multipartUploads {
+		uploads = append(uploads, transfer.IncompleteUpload{
+			Key:         mu.Key,
+			UploadID:    mu.UploadID,
+			InitiatedAt: mu.Initiated,
+			Resumable:   resumable[mu.UploadID],
+		})
+	}
+
+	h.bus.Publish(e.NewFollowup(transfer.ListIncompleteUploadsSucceeded{Uploads: uploads}))
+}
+
+// handleAbortIncompleteUploads aborts each upload independently,
+// a failure not preventing the next ones from being aborted.
+func (h *EventHandler) handleAbortIncompleteUploads(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(transfer.AbortIncompleteUploadsTriggered)
+
+	handleError := func(err error, aborted []transfer.IncompleteUpload) {
+		h.notifier.NotifyError(fmt.Errorf("failed aborting incomplete uploads: %w", err))
+		h.bus.Publish(e.NewFollowup(transfer.AbortIncompleteUploadsFailed{Err: err, Aborted: aborted}))
+	}
+
+	if err := h.checkWritable(ctx, pl.ConnectionID); err != nil {
+		handleError(err, nil)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.ConnectionID)
+	if err != nil {
+		handleError(err, nil)
+		return
+	}
+
+	var (
+		aborted []transfer.IncompleteUpload
+		errs    []error
+	)
+	for _, upload := range pl.Uploads {
+		if err := client.AbortMultipartUpload(ctx, upload.Key, upload.UploadID); err != nil && !isNotFoundError(err) {
+			errs = append(errs, err)
+			continue
+		}
+		aborted = append(aborted, upload)
+	}
+
+	if len(errs) > 0 {
+		handleError(errors.Join(errs...), aborted)
+		return
+	}
+	h.bus.Publish(e.NewFollowup(transfer.AbortIncompleteUploadsSucceeded{Uploads: aborted}))
+}
@@ -10,7 +10,6 @@ import (
 	"sync"
 	"sync/atomic"
 
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
@@ -72,6 +71,11 @@ func (h *EventHandler) handleRenameFile(e event.Event) {
 		}))
 	}
 
+	if err := h.checkWritable(ctx, pl.Directory.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, pl.Directory.ConnectionID())
 	if err != nil {
 		handleError(err)
@@ -108,6 +112,11 @@ func (h *EventHandler) handleRenameRequest(e event.Event) {
 		}))
 	}
 
+	if err := h.checkWritable(ctx, dir.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
 	if err != nil {
 		handleError(err)
@@ -134,7 +143,7 @@ func (h *EventHandler) handleRenameRequest(e event.Event) {
 	}
 
 	if lsSrc.IsEmpty() {
-		if err := h.renameObjects(ctx, client, dir.Path(), pl.NewName, lsSrc.Keys, true, false); err != nil {
+		if err := h.renameObjects(ctx, client, dir.Path(), directory.NewPath(dstDirKey), lsSrc.Keys, true, false); err != nil {
 			handleError(err)
 			return
 		}
@@ -179,6 +188,11 @@ func (h *EventHandler) handleRenameDirectory(e event.Event) {
 		}))
 	}
 
+	if err := h.checkWritable(ctx, dir.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
 	if err != nil {
 		handleError(err)
@@ -199,7 +213,7 @@ func (h *EventHandler) handleRenameDirectory(e event.Event) {
 		return
 	}
 
-	if err := h.renameObjects(ctx, client, dir.Path(), newName, lsRes.Keys, true, false); err != nil {
+	if err := h.renameObjects(ctx, client, dir.Path(), directory.NewPath(dstDirKey), lsRes.Keys, true, false); err != nil {
 		handleError(err)
 		return
 	}
@@ -211,6 +225,11 @@ func (h *EventHandler) handleRenameDirectory(e event.Event) {
 func (h *EventHandler) handleRenameRecovery(evt event.Event) {
 	pl := evt.Payload().(directory.RenameRecoveryTriggered)
 
+	if pl.OtherDirPath != directory.NilParentPath {
+		h.handleMoveRecovery(evt, pl)
+		return
+	}
+
 	switch pl.Choice {
 	case directory.RecoveryChoiceRenameResume:
 		h.handleRenameResuming(evt, pl.Directory, pl.DstDir, false)
@@ -226,10 +245,7 @@ func (h *EventHandler) handleRenameRecovery(evt event.Event) {
 func (h *EventHandler) handleRenameResuming(evt event.Event, srcDir, dstDir *directory.Directory, isRollback bool) {
 	ctx := evt.Context()
 
-	srcPath := srcDir.Path()
-	dstPath := dstDir.Path()
-
-	newName := dstPath.DirectoryName()
+	newName := dstDir.Path().DirectoryName()
 
 	handleError := func(err error) {
 		h.notifier.NotifyError(fmt.Errorf("failed handling rename: %w", err))
@@ -240,12 +256,75 @@ func (h *EventHandler) handleRenameResuming(evt event.Event, srcDir, dstDir *dir
 		}))
 	}
 
+	if err := h.checkWritable(ctx, srcDir.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, srcDir.ConnectionID())
 	if err != nil {
 		handleError(err)
 		return
 	}
 
+	if err := h.resumeRenaming(ctx, client, srcDir.Path(), dstDir.Path(), isRollback); err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(evt.NewFollowup(directory.RenameSucceeded{
+		Directory: srcDir,
+		NewName:   newName,
+	}))
+}
+
+// handleMoveRecovery recovers a move between two directories of the same bucket that has been interrupted.
+// Only one of the two directories is known by the explorer: it is reloaded once done,
+// which shows the pending operation again if the recovery failed.
+func (h *EventHandler) handleMoveRecovery(evt event.Event, pl directory.RenameRecoveryTriggered) {
+	ctx := evt.Context()
+
+	dir, srcPath, dstPath := pl.Directory, pl.OtherDirPath, pl.OtherDirPath
+	if dir != nil {
+		srcPath = dir.Path()
+	} else {
+		dir = pl.DstDir
+		dstPath = dir.Path()
+	}
+
+	if err := h.checkWritable(ctx, dir.ConnectionID()); err != nil {
+		h.notifier.NotifyError(fmt.Errorf("failed recovering move: %w", err))
+		h.bus.Publish(evt.NewFollowup(directory.LoadFailed{Err: err, Directory: dir}))
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
+	if err != nil {
+		h.notifier.NotifyError(fmt.Errorf("failed recovering move: %w", err))
+		h.bus.Publish(evt.NewFollowup(directory.LoadFailed{Err: err, Directory: dir}))
+		return
+	}
+
+	switch pl.Choice {
+	case directory.RecoveryChoiceRenameResume:
+		err = h.resumeRenaming(ctx, client, srcPath, dstPath, false)
+	case directory.RecoveryChoiceRenameRollback:
+		err = h.resumeRenaming(ctx, client, dstPath, srcPath, true)
+	case directory.RecoveryChoiceRenameAbort:
+		err = deleteRenameMarkers(ctx, client, mapPathToSearchKey(srcPath), mapPathToSearchKey(dstPath), false)
+	}
+	if err != nil {
+		h.notifier.NotifyError(fmt.Errorf("failed recovering move: %w", err))
+	}
+
+	if err := h.loadDirectory(ctx, client, dir, evt, false, false); err != nil {
+		h.bus.Publish(evt.NewFollowup(directory.LoadFailed{Err: err, Directory: dir}))
+	}
+}
+
+// resumeRenaming checks the rename markers left by an interrupted rename or move and relocates
+// the remaining objects from srcPath to dstPath. On rollback, the markers are read the other way around.
+func (h *EventHandler) resumeRenaming(ctx context.Context, client s3client.Client, srcPath, dstPath directory.Path, isRollback bool) error {
 	srcDirKey := mapPathToSearchKey(srcPath)
 	dstDirKey := mapPathToSearchKey(dstPath)
 
@@ -260,36 +339,24 @@ func (h *EventHandler) handleRenameResuming(evt event.Event, srcDir, dstDir *dir
 
 	srcMrk, err := readRenameMarker(ctx, client, srcMarkerKey)
 	if err != nil {
-		handleError(fmt.Errorf("failed reading rename marker at %s: %w", srcMarkerKey, err))
-		return
+		return fmt.Errorf("failed reading rename marker at %s: %w", srcMarkerKey, err)
 	}
 	dstMrk, err := readRenameMarker(ctx, client, dstMarkerKey)
 	if err != nil {
-		handleError(fmt.Errorf("failed reading rename marker at %s: %w", dstMarkerKey, err))
-		return
+		return fmt.Errorf("failed reading rename marker at %s: %w", dstMarkerKey, err)
 	}
 
 	if (!isRollback && (dstMrk.SrcDirPath != srcPath || srcMrk.DstDirPath != dstPath)) ||
 		(isRollback && (srcMrk.DstDirPath != srcPath || dstMrk.SrcDirPath != dstPath)) {
-		handleError(errors.New("invalid rename marker(s) content"))
-		return
+		return errors.New("invalid rename marker(s) content")
 	}
 
-	lsRes, err := client.ListObjects(ctx, mapPathToSearchKey(srcPath), true)
+	lsRes, err := client.ListObjects(ctx, srcDirKey, true)
 	if err != nil {
-		handleError(err)
-		return
-	}
-
-	if err := h.renameObjects(ctx, client, srcPath, newName, lsRes.Keys, false, isRollback); err != nil {
-		handleError(err)
-		return
+		return err
 	}
 
-	h.bus.Publish(evt.NewFollowup(directory.RenameSucceeded{
-		Directory: srcDir,
-		NewName:   newName,
-	}))
+	return h.renameObjects(ctx, client, srcPath, dstPath, lsRes.Keys, false, isRollback)
 }
 
 func (h *EventHandler) handleRenameAbort(evt event.Event, srcDir, dstDir *directory.Directory) {
@@ -312,6 +379,11 @@ func (h *EventHandler) handleRenameAbort(evt event.Event, srcDir, dstDir *direct
 		connID = dstDir.ConnectionID()
 	}
 
+	if err := h.checkWritable(ctx, connID); err != nil {
+		handleError(err)
+		return
+	}
+
 	client, err := h.clientFactory.Get(ctx, connID)
 	if err != nil {
 		handleError(err)
@@ -338,14 +410,14 @@ func (h *EventHandler) handleRenameAbort(evt event.Event, srcDir, dstDir *direct
 
 	if srcDir != nil {
 		go func() {
-			if err := h.loadDirectory(ctx, client, srcDir, evt); err != nil {
+			if err := h.loadDirectory(ctx, client, srcDir, evt, false, false); err != nil {
 				handleError(err)
 			}
 		}()
 	}
 	if dstDir != nil {
 		go func() {
-			if err := h.loadDirectory(ctx, client, dstDir, evt); err != nil {
+			if err := h.loadDirectory(ctx, client, dstDir, evt, false, false); err != nil {
 				handleError(err)
 			}
 		}()
@@ -355,14 +427,13 @@ func (h *EventHandler) handleRenameAbort(evt event.Event, srcDir, dstDir *direct
 func (h *EventHandler) renameObjects(
 	ctx context.Context,
 	client s3client.Client,
-	srcPath directory.Path,
-	newName string,
+	srcPath, dstPath directory.Path,
 	keys []string,
 	createMarkers bool,
 	isRollback bool,
 ) error {
 	srcDirKey := mapPathToSearchKey(srcPath)
-	dstDirKey := getDstDirKey(srcDirKey, newName)
+	dstDirKey := mapPathToSearchKey(dstPath)
 
 	if len(keys) == 0 {
 		return deleteRenameMarkers(ctx, client, srcDirKey, dstDirKey, isRollback)
@@ -377,7 +448,7 @@ func (h *EventHandler) renameObjects(
 	if len(keys) == 1 {
 		key := keys[0]
 		if isRenameMarkerFile(key) {
-			return nil
+			return deleteRenameMarkers(ctx, client, srcDirKey, dstDirKey, isRollback)
 		}
 		if err := client.RenameObject(ctx, key, getObjectDstKey(srcDirKey, dstDirKey, key)); err != nil {
 			return err
@@ -390,9 +461,11 @@ func (h *EventHandler) renameObjects(
 		workload  = make(chan string)
 		done      = make(chan struct{})
 
-		errCnt int64
-		wg     sync.WaitGroup
-		once   sync.Once
+		errCnt     int64
+		copiedKeys []string
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		once       sync.Once
 	)
 	defer close(workload)
 
@@ -407,8 +480,12 @@ func (h *EventHandler) renameObjects(
 						wg.Done()
 						continue
 					}
-					if err := client.RenameObject(ctx, key, getObjectDstKey(srcDirKey, dstDirKey, key)); err != nil {
+					if err := client.CopyObject(ctx, key, getObjectDstKey(srcDirKey, dstDirKey, key)); err != nil {
 						atomic.AddInt64(&errCnt, 1)
+					} else {
+						mu.Lock()
+						copiedKeys = append(copiedKeys, key)
+						mu.Unlock()
 					}
 					wg.Done()
 				}
@@ -429,10 +506,12 @@ func (h *EventHandler) renameObjects(
 	wg.Wait()
 	once.Do(func() { close(done) })
 
+	errCnt += int64(len(client.DeleteObjects(ctx, copiedKeys)))
+
 	if errCnt > 0 {
 		return directory.UncompletedRename{
 			SourceDirPath:      srcPath,
-			DestinationDirPath: directory.NewPath(dstDirKey),
+			DestinationDirPath: dstPath,
 			Wrapped:            fmt.Errorf("%d error(s) occurred while renaming objects", errCnt),
 		}
 	}
@@ -536,43 +615,18 @@ func deleteRenameMarkers(ctx context.Context, client s3client.Client, srcDirPref
 	var (
 		srcKey = srcDirPrefix + markerSrcFileName
 		dstKey = dstDirPrefix + markerDstFileName
-
-		wg      sync.WaitGroup
-		errChan = make(chan error)
 	)
 	if markerInversed {
 		srcKey = dstDirPrefix + markerSrcFileName
 		dstKey = srcDirPrefix + markerDstFileName
 	}
 
-	wg.Add(2)
-
-	deleteObject := func(key string) {
-		defer wg.Done()
-		if err := client.DeleteObject(ctx, key); err != nil {
-			var nskErr *types.NoSuchKey
-			if errors.As(err, &nskErr) {
-				return
-			}
-			select {
-			case errChan <- err:
-			default:
-			}
+	for _, err := range client.DeleteObjects(ctx, []string{srcKey, dstKey}) {
+		if !isNotFoundError(err) {
+			return err
 		}
 	}
-
-	go deleteObject(srcKey)
-	go deleteObject(dstKey)
-
-	wg.Wait()
-
-	select {
-	case err := <-errChan:
-		close(errChan)
-		return err
-	default:
-		return nil
-	}
+	return nil
 }
 
 func getObjectDstKey(srcDirPrefix, dstDirPrefix, oldKey string) string {
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
 )
 
 func TestGetObjectDstKey(t *testing.T) {
@@ -76,3 +79,50 @@ func TestGetDstKey(t *testing.T) {
 		})
 	}
 }
+
+func TestEventHandler_handleRename(t *testing.T) {
+	t.Run("should refuse to rename a file of a read-only connection", func(t *testing.T) {
+		// Given
+		// the embedded client is nil, any request to the bucket would panic
+		h, published := newReadOnlyEventHandler(t, &fakeBucketClient{})
+		var file *directory.File
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded(),
+			tu.WithConnectionId(tu.FakeAwsConnectionId),
+			tu.WithFileTo("file.txt", &file))
+		evt, err := file.Rename("renamed.txt")
+		require.NoError(t, err)
+
+		// When
+		h.handleRenameFile(evt)
+
+		// Then
+		require.NotNil(t, *published)
+		pl, ok := (*published).Payload().(directory.RenameFileFailed)
+		require.True(t, ok)
+		assert.ErrorIs(t, pl.Err, directory.ErrReadOnly)
+	})
+
+	t.Run("should refuse to rename a directory of a read-only connection", func(t *testing.T) {
+		// Given
+		h, published := newReadOnlyEventHandler(t, &fakeBucketClient{})
+		var mydir *directory.Directory
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded(),
+			tu.WithConnectionId(tu.FakeAwsConnectionId),
+			tu.WithSubDirectory("mydir", tu.To(&mydir)))
+		evt, err := mydir.Rename("renamed")
+		require.NoError(t, err)
+
+		// When
+		h.handleRenameRequest(evt)
+
+		// Then
+		require.NotNil(t, *published)
+		pl, ok := (*published).Payload().(directory.RenameFailed)
+		require.True(t, ok)
+		assert.ErrorIs(t, pl.Err, directory.ErrReadOnly)
+	})
+}
@@ -0,0 +1,104 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func (h *EventHandler) handleSearch(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.SearchTriggered)
+	dir := pl.Directory
+
+	handleError := func(err error) {
+		if !errors.Is(err, directory.ErrCanceled) {
+			h.notifier.NotifyError(fmt.Errorf("failed searching in directory: %w", err))
+		}
+		h.bus.Publish(e.NewFollowup(directory.SearchFailed{Err: err, Directory: dir}))
+	}
+
+	match, err := pl.Query.Matcher()
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var (
+		prefix                 = mapPathToSearchKey(dir.Path())
+		hitCount, scannedCount int
+	)
+
+	listErr := client.ListObjectsWithCallback(ctx, prefix, true, func(page *s3.ListObjectsV2Output) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		hits := searchObjects(page, prefix, match)
+		scannedCount += len(page.Contents)
+		hitCount += len(hits)
+
+		h.bus.Publish(e.NewFollowup(directory.SearchProgress{
+			Directory:    dir,
+			Hits:         hits,
+			ScannedCount: scannedCount,
+		}))
+		return nil
+	})
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		handleError(errors.Join(directory.ErrCanceled, ctxErr))
+		return
+	}
+	if listErr != nil {
+		handleError(listErr)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.SearchSucceeded{
+		Directory:    dir,
+		HitCount:     hitCount,
+		ScannedCount: scannedCount,
+	}))
+}
+
+// searchObjects returns the files of the page matching the search, ignoring the directory markers.
+func searchObjects(page *s3.ListObjectsV2Output, prefix string, match directory.SearchMatcher) []directory.SearchHit {
+	hits := make([]directory.SearchHit, 0)
+	for _, obj := range page.Contents {
+		key := aws.ToString(obj.Key)
+		if strings.HasSuffix(key, "/") || isRenameMarkerFile(key) {
+			continue
+		}
+
+		relativeKey := strings.TrimPrefix(key, prefix)
+		size := uint64(aws.ToInt64(obj.Size))
+		lastModified := aws.ToTime(obj.LastModified)
+		if !match(relativeKey, size, lastModified) {
+			continue
+		}
+
+		dirKey := "/"
+		if i := strings.LastIndex(key, "/"); i >= 0 {
+			dirKey = key[:i+1]
+		}
+		hits = append(hits, directory.SearchHit{
+			DirectoryPath: directory.NewPath(dirKey),
+			Name:          directory.FileName(mapKeyToObjectName(key)),
+			SizeBytes:     size,
+			LastModified:  lastModified,
+		})
+	}
+	return hits
+}
@@ -0,0 +1,58 @@
+package s3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func TestSearchObjects(t *testing.T) {
+	t.Run("should return the matching files with their directory path", func(t *testing.T) {
+		// Given
+		lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		page := &s3.ListObjectsV2Output{
+			Contents: []types.Object{
+				{Key: aws.String("data/"), Size: aws.Int64(0), LastModified: aws.Time(lastModified)},
+				{Key: aws.String("data/sales.csv"), Size: aws.Int64(12), LastModified: aws.Time(lastModified)},
+				{Key: aws.String("data/2024/q1.csv"), Size: aws.Int64(30), LastModified: aws.Time(lastModified)},
+				{Key: aws.String("data/readme.md"), Size: aws.Int64(5), LastModified: aws.Time(lastModified)},
+			},
+		}
+		match, err := directory.SearchQuery{Pattern: "*.csv"}.Matcher()
+		require.NoError(t, err)
+
+		// When
+		res := searchObjects(page, "data/", match)
+
+		// Then
+		assert.Equal(t, []directory.SearchHit{
+			{DirectoryPath: "/data/", Name: "sales.csv", SizeBytes: 12, LastModified: lastModified},
+			{DirectoryPath: "/data/2024/", Name: "q1.csv", SizeBytes: 30, LastModified: lastModified},
+		}, res)
+	})
+
+	t.Run("should place the files at the bucket root in the root directory", func(t *testing.T) {
+		// Given
+		page := &s3.ListObjectsV2Output{
+			Contents: []types.Object{
+				{Key: aws.String("sales.csv"), Size: aws.Int64(12), LastModified: aws.Time(time.Now())},
+			},
+		}
+		match, err := directory.SearchQuery{}.Matcher()
+		require.NoError(t, err)
+
+		// When
+		res := searchObjects(page, "", match)
+
+		// Then
+		require.Len(t, res, 1)
+		assert.Equal(t, directory.RootPath, res[0].DirectoryPath)
+		assert.Equal(t, "/sales.csv", res[0].FullPath())
+	})
+}
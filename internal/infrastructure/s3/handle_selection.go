@@ -0,0 +1,499 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+// selectionReport accumulates the outcome of a bulk operation on a selection.
+type selectionReport struct {
+	doneCount    int
+	skippedCount int
+	failedKeys   map[string]error
+}
+
+func newSelectionReport() *selectionReport {
+	return &selectionReport{failedKeys: make(map[string]error)}
+}
+
+func (r *selectionReport) addFailures(failures map[string]error) {
+	for key, err := range failures {
+		r.failedKeys[key] = err
+	}
+}
+
+// hasFailureUnder tells whether an object under the given prefix failed.
+func (r *selectionReport) hasFailureUnder(prefix string) bool {
+	for key := range r.failedKeys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// uncompletedErr returns an UncompletedSelection error when the operation didn't process every object, nil otherwise.
+func (r *selectionReport) uncompletedErr(ctx context.Context, operation string, walkErr error) error {
+	var wrapped error
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		wrapped = errors.Join(directory.ErrCanceled, ctxErr)
+	} else if walkErr != nil {
+		wrapped = walkErr
+	}
+
+	if wrapped == nil && len(r.failedKeys) == 0 {
+		return nil
+	}
+	return directory.UncompletedSelection{
+		Operation:  operation,
+		DoneCount:  r.doneCount,
+		FailedKeys: r.failedKeys,
+		Wrapped:    wrapped,
+	}
+}
+
+func (h *EventHandler) handleDeleteSelection(evt event.Event) {
+	ctx := evt.Context()
+	pl := evt.Payload().(directory.DeleteSelectionTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed deleting selection: %w", err))
+		h.bus.Publish(evt.NewFollowup(directory.DeleteSelectionFailed{Err: err, Selection: pl.Selection}))
+	}
+
+	if err := h.checkWritable(ctx, pl.Selection.ConnectionID()); err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.Selection.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	report := newSelectionReport()
+	walkErr := walkSelection(ctx, client, pl.Selection, func(_ string, objects []types.Object) {
+		keys := objectKeys(objects)
+		failures := client.DeleteObjects(ctx, keys)
+		report.doneCount += len(keys) - len(failures)
+		report.addFailures(failures)
+		h.publishSelectionProgress(evt, pl.Selection, report)
+	})
+
+	if err := report.uncompletedErr(ctx, "deletion", walkErr); err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(evt.NewFollowup(directory.DeleteSelectionSucceeded{
+		Selection:    pl.Selection,
+		DeletedCount: report.doneCount,
+	}))
+}
+
+func (h *EventHandler) handleDownloadSelection(evt event.Event) {
+	ctx := evt.Context()
+	pl := evt.Payload().(directory.DownloadSelectionTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed downloading selection: %w", err))
+		h.bus.Publish(evt.NewFollowup(directory.DownloadSelectionFailed{Err: err, Selection: pl.Selection}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.Selection.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	report := newSelectionReport()
+	walkErr := walkSelection(ctx, client, pl.Selection, func(prefix string, objects []types.Object) {
+		downloaded, skipped, failures := downloadObjects(ctx, client, objectKeys(objects), prefix, pl.DstPath, pl.Strategy)
+		report.doneCount += downloaded
+		report.skippedCount += skipped
+		report.addFailures(failures)
+		h.publishSelectionProgress(evt, pl.Selection, report)
+	})
+
+	if err := report.uncompletedErr(ctx, "download", walkErr); err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(evt.NewFollowup(directory.DownloadSelectionSucceeded{
+		Selection:       pl.Selection,
+		DstPath:         pl.DstPath,
+		DownloadedCount: report.doneCount,
+		SkippedCount:    report.skippedCount,
+	}))
+}
+
+func (h *EventHandler) handleCopySelection(evt event.Event) {
+	ctx := evt.Context()
+	pl := evt.Payload().(directory.CopySelectionTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed copying selection: %w", err))
+		h.bus.Publish(evt.NewFollowup(directory.CopySelectionFailed{
+			Err:         err,
+			Selection:   pl.Selection,
+			Destination: pl.Destination,
+			Move:        pl.Move,
+		}))
+	}
+
+	srcConnID := pl.Selection.ConnectionID()
+	dstConnID := pl.Destination.ConnectionID()
+	sameBucket := srcConnID == dstConnID
+
+	if err := h.checkWritable(ctx, dstConnID); err != nil {
+		handleError(err)
+		return
+	}
+	if pl.Move && !sameBucket {
+		if err := h.checkWritable(ctx, srcConnID); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	client, err := h.clientFactory.Get(ctx, srcConnID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	// Within the same bucket, objects are copied server side.
+	// Otherwise, they are streamed from one connection to the other as they may use different endpoints or credentials.
+	dstClient, copier := client, serverSideCopier(client)
+	if !sameBucket {
+		dstClient, err = h.clientFactory.Get(ctx, dstConnID)
+		if err != nil {
+			handleError(err)
+			return
+		}
+		copier = streamingCopier(client, dstClient)
+	}
+
+	dstPrefix := mapPathToSearchKey(pl.Destination.Path())
+
+	var existingKeys map[string]struct{}
+	if pl.Strategy == directory.MaterializeSkip {
+		existingKeys, err = listExistingKeys(ctx, dstClient, pl.Selection, dstPrefix)
+		if err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	// Moved directories are marked the same way as renamed ones,
+	// so that an interrupted move can be resumed, rolled back or aborted from the explorer.
+	var movedDirs []*directory.Directory
+	if pl.Move && sameBucket {
+		movedDirs = pl.Selection.Directories()
+		if err := markMovedDirectories(ctx, client, movedDirs, dstPrefix); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	report := newSelectionReport()
+	walkErr := walkSelection(ctx, client, pl.Selection, func(prefix string, objects []types.Object) {
+		keys := slices.DeleteFunc(objectKeys(objects), func(key string) bool {
+			_, exists := existingKeys[dstPrefix+strings.TrimPrefix(key, prefix)]
+			if exists {
+				report.skippedCount++
+			}
+			return exists
+		})
+		copiedKeys, failures := copyObjects(ctx, copier, keys, prefix, dstPrefix)
+		if pl.Move {
+			moveFailures := client.DeleteObjects(ctx, copiedKeys)
+			maps.Copy(failures, moveFailures)
+			copiedKeys = slices.DeleteFunc(copiedKeys, func(key string) bool {
+				_, failed := moveFailures[key]
+				return failed
+			})
+		}
+		report.doneCount += len(copiedKeys)
+		report.addFailures(failures)
+		h.publishSelectionProgress(evt, pl.Selection, report)
+	})
+
+	operation := "copy"
+	if pl.Move {
+		operation = "move"
+	}
+	uncompletedErr := report.uncompletedErr(ctx, operation, walkErr)
+
+	for _, dir := range movedDirs {
+		srcDirKey := mapPathToSearchKey(dir.Path())
+		if uncompletedErr != nil && (walkErr != nil || ctx.Err() != nil || report.hasFailureUnder(srcDirKey)) {
+			continue // keep the markers for the recovery
+		}
+		if err := deleteRenameMarkers(ctx, client, srcDirKey, dstPrefix+dir.Name()+"/", false); err != nil {
+			uncompletedErr = errors.Join(uncompletedErr, err)
+		}
+	}
+
+	if uncompletedErr != nil {
+		handleError(uncompletedErr)
+		return
+	}
+
+	h.bus.Publish(evt.NewFollowup(directory.CopySelectionSucceeded{
+		Selection:    pl.Selection,
+		Destination:  pl.Destination,
+		Move:         pl.Move,
+		CopiedCount:  report.doneCount,
+		SkippedCount: report.skippedCount,
+	}))
+}
+
+func (h *EventHandler) handlePastePreview(evt event.Event) {
+	ctx := evt.Context()
+	pl := evt.Payload().(directory.PastePreviewTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed previewing paste: %w", err))
+		h.bus.Publish(evt.NewFollowup(directory.PastePreviewFailed{
+			Err:         err,
+			Selection:   pl.Selection,
+			Destination: pl.Destination,
+		}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.Selection.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var objects []directory.SelectedObject
+	err = walkSelection(ctx, client, pl.Selection, func(prefix string, page []types.Object) {
+		for _, obj := range page {
+			key := aws.ToString(obj.Key)
+			if isRenameMarkerFile(key) {
+				continue
+			}
+			objects = append(objects, directory.SelectedObject{
+				RelativeKey:  strings.TrimPrefix(key, prefix),
+				SizeBytes:    uint64(aws.ToInt64(obj.Size)),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	})
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(evt.NewFollowup(directory.PastePreviewSucceeded{
+		Selection:   pl.Selection,
+		Destination: pl.Destination,
+		Move:        pl.Move,
+		Objects:     objects,
+	}))
+}
+
+// checkWritable returns directory.ErrReadOnly if the connection is read-only.
+func (h *EventHandler) checkWritable(ctx context.Context, connID connection_deck.ConnectionID) error {
+	deck, err := h.connectionRepository.Get(ctx)
+	if err != nil {
+		return err
+	}
+	conn, err := deck.GetByID(connID)
+	if err != nil {
+		return err
+	}
+	if conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	return nil
+}
+
+func (h *EventHandler) publishSelectionProgress(evt event.Event, sel *directory.Selection, report *selectionReport) {
+	h.bus.Publish(evt.NewFollowup(directory.SelectionProgress{
+		Selection:    sel,
+		DoneCount:    report.doneCount,
+		SkippedCount: report.skippedCount,
+		FailedCount:  len(report.failedKeys),
+	}))
+}
+
+// walkSelection calls fn with batches of objects covered by the selection.
+// Files are batched by parent directory and directories are listed recursively, page by page.
+// The prefix given to fn is the key of the parent directory of the selected items, so that
+// the key relative to this prefix starts with the selected item name.
+func walkSelection(
+	ctx context.Context,
+	client s3client.Client,
+	sel *directory.Selection,
+	fn func(prefix string, objects []types.Object),
+) error {
+	objectsByPrefix := make(map[string][]types.Object)
+	for _, f := range sel.Files() {
+		prefix := mapPathToSearchKey(f.DirectoryPath())
+		objectsByPrefix[prefix] = append(objectsByPrefix[prefix], types.Object{
+			Key:          aws.String(mapFileToKey(f)),
+			Size:         aws.Int64(int64(f.SizeBytes())),
+			LastModified: aws.Time(f.LastModified()),
+		})
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(objectsByPrefix)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fn(prefix, objectsByPrefix[prefix])
+	}
+
+	for _, dir := range sel.Directories() {
+		prefix := mapPathToSearchKey(dir.Parent().Path())
+		err := client.ListObjectsWithCallback(ctx, mapPathToSearchKey(dir.Path()), true, func(page *s3.ListObjectsV2Output) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fn(prefix, page.Contents)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func objectKeys(objects []types.Object) []string {
+	keys := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		keys = append(keys, aws.ToString(obj.Key))
+	}
+	return keys
+}
+
+// listExistingKeys returns the keys already existing in the dstPrefix directory
+// for the items of the selection about to be copied there.
+func listExistingKeys(ctx context.Context, client s3client.Client, sel *directory.Selection, dstPrefix string) (map[string]struct{}, error) {
+	prefixes := make([]string, 0, sel.Len())
+	for _, f := range sel.Files() {
+		prefixes = append(prefixes, dstPrefix+f.Name().String())
+	}
+	for _, d := range sel.Directories() {
+		prefixes = append(prefixes, dstPrefix+d.Name()+"/")
+	}
+
+	existing := make(map[string]struct{})
+	for _, prefix := range prefixes {
+		err := client.ListObjectsWithCallback(ctx, prefix, true, func(page *s3.ListObjectsV2Output) error {
+			for _, obj := range page.Contents {
+				existing[aws.ToString(obj.Key)] = struct{}{}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// markMovedDirectories creates the rename markers of the directories moved into the dstPrefix directory.
+// It fails if one of them already has a pending rename or move.
+func markMovedDirectories(ctx context.Context, client s3client.Client, dirs []*directory.Directory, dstPrefix string) error {
+	for _, dir := range dirs {
+		srcDirKey := mapPathToSearchKey(dir.Path())
+		if _, err := readRenameMarker(ctx, client, srcDirKey+markerSrcFileName); err == nil {
+			return fmt.Errorf("a rename or move operation is still pending for directory %s", dir.Path())
+		} else if !isNotFoundError(err) {
+			return err
+		}
+		if err := createRenameMarkers(ctx, client, srcDirKey, dstPrefix+dir.Name()+"/"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectCopier copies the object srcKey to dstKey.
+type objectCopier func(ctx context.Context, srcKey, dstKey string) error
+
+func serverSideCopier(client s3client.Client) objectCopier {
+	return func(ctx context.Context, srcKey, dstKey string) error {
+		return client.CopyObject(ctx, srcKey, dstKey)
+	}
+}
+
+// streamingCopier downloads the objects with srcClient and uploads them with dstClient at the same time,
+// without buffering the whole object. The content headers and the user metadata are kept.
+func streamingCopier(srcClient, dstClient s3client.Client) objectCopier {
+	return func(ctx context.Context, srcKey, dstKey string) error {
+		res, err := srcClient.GetObject(ctx, srcKey)
+		if err != nil {
+			return err
+		}
+		defer u.SkipD(res.Body.Close)
+		return dstClient.Upload(ctx, dstKey, res.Body, s3client.WithHeadersOf(res))
+	}
+}
+
+// copyObjects concurrently copies the given keys from srcPrefix to dstPrefix, keeping their relative path.
+// Rename markers are not copied. It returns the copied keys and the errors indexed by key for the ones that failed.
+func copyObjects(
+	ctx context.Context,
+	copier objectCopier,
+	keys []string,
+	srcPrefix, dstPrefix string,
+) ([]string, map[string]error) {
+	var (
+		workload   = make(chan string)
+		failures   = make(map[string]error)
+		copiedKeys = make([]string, 0, len(keys))
+
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for range min(len(keys), maxRenamingWorkers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range workload {
+				if ctx.Err() != nil || isRenameMarkerFile(key) {
+					continue
+				}
+				err := copier(ctx, key, dstPrefix+strings.TrimPrefix(key, srcPrefix))
+
+				mu.Lock()
+				if err != nil {
+					failures[key] = err
+				} else {
+					copiedKeys = append(copiedKeys, key)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		workload <- key
+	}
+	close(workload)
+	wg.Wait()
+
+	return copiedKeys, failures
+}
@@ -0,0 +1,184 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/transfermanager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+)
+
+type fakeCopyClient struct {
+	s3client.Client
+	mu     sync.Mutex
+	copies map[string]string
+	failOn string
+}
+
+func (c *fakeCopyClient) CopyObject(_ context.Context, srcKey, dstKey string, _ ...s3client.Option) error {
+	if srcKey == c.failOn {
+		return errors.New("ckc")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.copies[srcKey] = dstKey
+	return nil
+}
+
+func TestCopyObjects(t *testing.T) {
+	t.Run("should copy the objects under the destination prefix", func(t *testing.T) {
+		// Given
+		client := &fakeCopyClient{copies: make(map[string]string), failOn: "src/mydir/broken.txt"}
+		keys := []string{
+			"src/mydir/",
+			"src/mydir/file.txt",
+			"src/mydir/sub/nested.txt",
+			"src/mydir/broken.txt",
+			"src/mydir/" + markerSrcFileName,
+		}
+
+		// When
+		copied, failures := copyObjects(t.Context(), serverSideCopier(client), keys, "src/", "dst/")
+
+		// Then
+		assert.ElementsMatch(t, []string{"src/mydir/", "src/mydir/file.txt", "src/mydir/sub/nested.txt"}, copied)
+		assert.Equal(t, map[string]string{
+			"src/mydir/":               "dst/mydir/",
+			"src/mydir/file.txt":       "dst/mydir/file.txt",
+			"src/mydir/sub/nested.txt": "dst/mydir/sub/nested.txt",
+		}, client.copies)
+		require.Len(t, failures, 1)
+		assert.Contains(t, failures, "src/mydir/broken.txt")
+	})
+}
+
+type fakeStreamClient struct {
+	s3client.Client
+	mu      sync.Mutex
+	objects map[string]string
+	// contentTypes and metadata are the headers of the objects, by key
+	contentTypes map[string]string
+	metadata     map[string]map[string]string
+}
+
+func (c *fakeStreamClient) GetObject(_ context.Context, key string, _ ...s3client.Option) (*s3.GetObjectOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	content, ok := c.objects[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &s3.GetObjectOutput{
+		Body:        io.NopCloser(strings.NewReader(content)),
+		ContentType: aws.String(c.contentTypes[key]),
+		Metadata:    c.metadata[key],
+	}, nil
+}
+
+func (c *fakeStreamClient) Upload(_ context.Context, key string, body io.Reader, opts ...s3client.Option) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	in := &transfermanager.UploadObjectInput{}
+	for _, opt := range opts {
+		opt(in)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.objects[key] = string(content)
+	if c.contentTypes != nil {
+		c.contentTypes[key] = aws.ToString(in.ContentType)
+		c.metadata[key] = in.Metadata
+	}
+	return nil
+}
+
+func TestStreamingCopier(t *testing.T) {
+	t.Run("should stream the objects from one connection to the other", func(t *testing.T) {
+		// Given
+		src := &fakeStreamClient{objects: map[string]string{
+			"mydir/file.txt":  "hello",
+			"mydir/other.txt": "world",
+		}}
+		dst := &fakeStreamClient{objects: make(map[string]string)}
+		keys := []string{"mydir/file.txt", "mydir/other.txt", "mydir/missing.txt"}
+
+		// When
+		copied, failures := copyObjects(t.Context(), streamingCopier(src, dst), keys, "", "backup/")
+
+		// Then
+		assert.ElementsMatch(t, []string{"mydir/file.txt", "mydir/other.txt"}, copied)
+		assert.Equal(t, map[string]string{
+			"backup/mydir/file.txt":  "hello",
+			"backup/mydir/other.txt": "world",
+		}, dst.objects)
+		require.Len(t, failures, 1)
+		assert.Contains(t, failures, "mydir/missing.txt")
+	})
+
+	t.Run("should keep the content type and the user metadata of the objects", func(t *testing.T) {
+		// Given
+		src := &fakeStreamClient{
+			objects:      map[string]string{"mydir/file.txt": "hello"},
+			contentTypes: map[string]string{"mydir/file.txt": "text/plain"},
+			metadata:     map[string]map[string]string{"mydir/file.txt": {"owner": "team-x"}},
+		}
+		dst := &fakeStreamClient{
+			objects:      make(map[string]string),
+			contentTypes: make(map[string]string),
+			metadata:     make(map[string]map[string]string),
+		}
+
+		// When
+		_, failures := copyObjects(t.Context(), streamingCopier(src, dst), []string{"mydir/file.txt"}, "", "backup/")
+
+		// Then
+		require.Empty(t, failures)
+		assert.Equal(t, "text/plain", dst.contentTypes["backup/mydir/file.txt"])
+		assert.Equal(t, map[string]string{"owner": "team-x"}, dst.metadata["backup/mydir/file.txt"])
+	})
+}
+
+func TestWalkSelection(t *testing.T) {
+	t.Run("should batch the selected files by parent directory", func(t *testing.T) {
+		// Given
+		var (
+			top, nested *directory.File
+		)
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithFileTo("top.txt", &top),
+			tu.WithSubDirectory("mydir",
+				tu.WithFileTo("nested.txt", &nested)))
+
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddFile(top))
+		require.NoError(t, sel.AddFile(nested))
+
+		batches := make(map[string][]string)
+
+		// When
+		err := walkSelection(t.Context(), nil, sel, func(prefix string, objects []types.Object) {
+			batches[prefix] = objectKeys(objects)
+		})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"":       {"top.txt"},
+			"mydir/": {"mydir/nested.txt"},
+		}, batches)
+	})
+}
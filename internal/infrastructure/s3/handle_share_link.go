@@ -0,0 +1,57 @@
+package s3
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func (h *EventHandler) handleCreateFileShareLink(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.CreateFileShareLinkTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed creating share link: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.CreateFileShareLinkFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	connID := pl.File.Parent().ConnectionID()
+	if pl.Access == directory.ShareLinkUpload {
+		if err := h.checkWritable(ctx, connID); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	client, err := h.clientFactory.Get(ctx, connID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	expiresAt := time.Now().Add(pl.Expiry)
+	key := mapFileToKey(pl.File)
+	var url string
+	switch pl.Access {
+	case directory.ShareLinkUpload:
+		url, err = client.PresignPutObject(ctx, key, pl.Expiry)
+	default:
+		url, err = client.PresignGetObject(ctx, key, pl.Expiry)
+	}
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.CreateFileShareLinkSucceeded{
+		File:      pl.File,
+		Access:    pl.Access,
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}))
+}
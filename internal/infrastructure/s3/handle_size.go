@@ -0,0 +1,76 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func (h *EventHandler) handleComputeSize(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.ComputeSizeTriggered)
+	dir := pl.Directory
+
+	handleError := func(err error) {
+		if !errors.Is(err, directory.ErrCanceled) {
+			h.notifier.NotifyError(fmt.Errorf("failed computing the directory size: %w", err))
+		}
+		h.bus.Publish(e.NewFollowup(directory.ComputeSizeFailed{Err: err, Directory: dir}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	prefix := mapPathToSearchKey(dir.Path())
+	stats := directory.NewSizeStats()
+
+	listErr := client.ListObjectsWithCallback(ctx, prefix, true, func(page *s3.ListObjectsV2Output) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		addToSizeStats(&stats, dir.Path(), prefix, page)
+
+		h.bus.Publish(e.NewFollowup(directory.ComputeSizeProgress{
+			Directory:   dir,
+			Bytes:       stats.Total.Bytes,
+			ObjectCount: stats.Total.ObjectCount,
+		}))
+		return nil
+	})
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		handleError(errors.Join(directory.ErrCanceled, ctxErr))
+		return
+	}
+	if listErr != nil {
+		handleError(listErr)
+		return
+	}
+
+	stats.ComputedAt = time.Now()
+	h.bus.Publish(e.NewFollowup(directory.ComputeSizeSucceeded{
+		Directory: dir,
+		Stats:     stats,
+	}))
+}
+
+// addToSizeStats counts the objects of the page, ignoring the directory markers.
+func addToSizeStats(stats *directory.SizeStats, dirPath directory.Path, prefix string, page *s3.ListObjectsV2Output) {
+	for _, obj := range page.Contents {
+		key := aws.ToString(obj.Key)
+		if strings.HasSuffix(key, "/") || isRenameMarkerFile(key) {
+			continue
+		}
+		stats.Add(dirPath, strings.TrimPrefix(key, prefix), uint64(aws.ToInt64(obj.Size)), string(obj.StorageClass))
+	}
+}
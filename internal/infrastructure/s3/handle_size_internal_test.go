@@ -0,0 +1,37 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func TestAddToSizeStats(t *testing.T) {
+	t.Run("should count the files of the page by storage class and subdirectory", func(t *testing.T) {
+		// Given
+		stats := directory.NewSizeStats()
+		page := &s3.ListObjectsV2Output{
+			Contents: []types.Object{
+				{Key: aws.String("data/"), Size: aws.Int64(0)},
+				{Key: aws.String("data/a.txt"), Size: aws.Int64(10)},
+				{Key: aws.String("data/sub/b.txt"), Size: aws.Int64(20), StorageClass: types.ObjectStorageClassGlacier},
+			},
+		}
+
+		// When
+		addToSizeStats(&stats, "/data/", "data/", page)
+
+		// Then
+		assert.Equal(t, directory.SizeStatsEntry{Bytes: 30, ObjectCount: 2}, stats.Total)
+		assert.Equal(t, directory.SizeStatsEntry{Bytes: 20, ObjectCount: 1}, stats.ByStorageClass["GLACIER"])
+		assert.Equal(t, directory.SizeStatsEntry{Bytes: 20, ObjectCount: 1}, stats.BySubDirectory["sub"])
+		assert.Equal(t, []directory.SizeStatsFile{
+			{FullPath: "/data/sub/b.txt", SizeBytes: 20},
+			{FullPath: "/data/a.txt", SizeBytes: 10},
+		}, stats.LargestFiles)
+	})
+}
@@ -0,0 +1,338 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+func (h *EventHandler) handleSyncPreview(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.SyncPreviewTriggered)
+	dir := pl.Directory
+
+	handleError := func(err error) {
+		if !errors.Is(err, directory.ErrCanceled) {
+			h.notifier.NotifyError(fmt.Errorf("failed comparing the directory with %s: %w", pl.LocalPath, err))
+		}
+		h.bus.Publish(e.NewFollowup(directory.SyncPreviewFailed{Err: err, Directory: dir}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	localEntries, err := listLocalSyncEntries(ctx, pl.LocalPath, pl.Options.CompareChecksums)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	prefix := mapPathToSearchKey(dir.Path())
+	var bucketEntries []directory.SyncEntry
+	listErr := client.ListObjectsWithCallback(ctx, prefix, true, func(page *s3.ListObjectsV2Output) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		bucketEntries = append(bucketEntries, mapPageToSyncEntries(page, prefix)...)
+		return nil
+	})
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		handleError(errors.Join(directory.ErrCanceled, ctxErr))
+		return
+	}
+	if listErr != nil {
+		handleError(listErr)
+		return
+	}
+
+	plan := directory.NewSyncPlan(dir, pl.LocalPath, pl.Options, localEntries, bucketEntries)
+	h.bus.Publish(e.NewFollowup(directory.SyncPreviewSucceeded{Plan: plan}))
+}
+
+// handleSync applies the copies of the plan concurrently, then its deletions.
+// A failure on a file doesn't prevent the other ones from being synced.
+func (h *EventHandler) handleSync(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.SyncTriggered)
+	plan := pl.Plan
+	dir := plan.Directory
+
+	handleError := func(err error) {
+		if !errors.Is(err, directory.ErrCanceled) {
+			h.notifier.NotifyError(fmt.Errorf("failed syncing the directory with %s: %w", plan.LocalPath, err))
+		}
+		h.bus.Publish(e.NewFollowup(directory.SyncFailed{Err: err, Plan: plan}))
+	}
+
+	if plan.Options.Direction == directory.SyncToBucket {
+		if err := h.checkWritable(ctx, dir.ConnectionID()); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	client, err := h.clientFactory.Get(ctx, dir.ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	var (
+		copies    = plan.Copies()
+		deletions = plan.Deletions()
+		total     = len(copies) + len(deletions)
+		prefix    = mapPathToSearchKey(dir.Path())
+		failures  = make(map[string]error)
+		doneCount int
+	)
+
+	publishProgress := func(done, failed int) {
+		h.bus.Publish(e.NewFollowup(directory.SyncProgress{
+			Plan:        plan,
+			DoneCount:   done,
+			FailedCount: failed,
+			Total:       total,
+		}))
+	}
+
+	copied, copyFailures := syncCopies(ctx, client, plan, prefix, copies, publishProgress)
+	doneCount += copied
+	for p, err := range copyFailures {
+		failures[p] = err
+	}
+
+	var deleted int
+	if ctx.Err() == nil && len(deletions) > 0 {
+		var deleteFailures map[string]error
+		deleted, deleteFailures = syncDeletions(ctx, client, plan, prefix, deletions)
+		doneCount += deleted
+		for p, err := range deleteFailures {
+			failures[p] = err
+		}
+		publishProgress(doneCount, len(failures))
+	}
+
+	var wrapped error
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		wrapped = errors.Join(directory.ErrCanceled, ctxErr)
+	}
+
+	if wrapped != nil || len(failures) > 0 {
+		handleError(directory.UncompletedSync{
+			LocalPath:   plan.LocalPath,
+			DoneCount:   doneCount,
+			FailedPaths: failures,
+			Wrapped:     wrapped,
+		})
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.SyncSucceeded{
+		Plan:         plan,
+		CopiedCount:  copied,
+		DeletedCount: deleted,
+	}))
+}
+
+// syncCopies copies the files of the changes concurrently from the source to the destination of the plan.
+// It returns the number of copied files, and the errors indexed by relative path for the ones that failed.
+func syncCopies(
+	ctx context.Context,
+	client s3client.Client,
+	plan *directory.SyncPlan,
+	prefix string,
+	changes []directory.SyncChange,
+	onProgress func(done, failed int),
+) (int, map[string]error) {
+	var (
+		workload = make(chan directory.SyncChange)
+		failures = make(map[string]error)
+
+		copied int
+
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for range min(len(changes), maxDownloadingWorkers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for change := range workload {
+				if ctx.Err() != nil {
+					continue
+				}
+				err := syncCopy(ctx, client, plan, prefix, change)
+
+				mu.Lock()
+				if err != nil {
+					failures[change.RelativePath] = err
+				} else {
+					copied++
+				}
+				onProgress(copied, len(failures))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, change := range changes {
+		workload <- change
+	}
+	close(workload)
+	wg.Wait()
+
+	return copied, failures
+}
+
+func syncCopy(ctx context.Context, client s3client.Client, plan *directory.SyncPlan, prefix string, change directory.SyncChange) error {
+	relPath := filepath.FromSlash(change.RelativePath)
+	if !filepath.IsLocal(relPath) {
+		return fmt.Errorf("invalid relative path %s for a local path", change.RelativePath)
+	}
+
+	if plan.Options.Direction == directory.SyncToLocal {
+		_, err := downloadObject(ctx, client, prefix+change.RelativePath, prefix, plan.LocalPath, directory.MaterializeReplace)
+		return err
+	}
+
+	localFile, err := os.Open(filepath.Join(plan.LocalPath, relPath))
+	if err != nil {
+		return err
+	}
+	defer u.SkipD(localFile.Close)
+
+	return client.Upload(ctx, prefix+change.RelativePath, localFile)
+}
+
+// syncDeletions removes the files of the changes from the destination of the plan.
+// It returns the number of deleted files, and the errors indexed by relative path for the ones that failed.
+func syncDeletions(
+	ctx context.Context,
+	client s3client.Client,
+	plan *directory.SyncPlan,
+	prefix string,
+	changes []directory.SyncChange,
+) (int, map[string]error) {
+	failures := make(map[string]error)
+
+	if plan.Options.Direction == directory.SyncToBucket {
+		keys := make([]string, 0, len(changes))
+		for _, change := range changes {
+			keys = append(keys, prefix+change.RelativePath)
+		}
+		for key, err := range client.DeleteObjects(ctx, keys) {
+			failures[strings.TrimPrefix(key, prefix)] = err
+		}
+		return len(changes) - len(failures), failures
+	}
+
+	for _, change := range changes {
+		relPath := filepath.FromSlash(change.RelativePath)
+		if !filepath.IsLocal(relPath) {
+			failures[change.RelativePath] = fmt.Errorf("invalid relative path %s for a local path", change.RelativePath)
+			continue
+		}
+		if err := os.Remove(filepath.Join(plan.LocalPath, relPath)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			failures[change.RelativePath] = err
+		}
+	}
+	return len(changes) - len(failures), failures
+}
+
+// listLocalSyncEntries returns the regular files under root, with their MD5 when withChecksum is true.
+func listLocalSyncEntries(ctx context.Context, root string, withChecksum bool) ([]directory.SyncEntry, error) {
+	var entries []directory.SyncEntry
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		entry := directory.SyncEntry{
+			RelativePath: filepath.ToSlash(rel),
+			SizeBytes:    uint64(info.Size()),
+			LastModified: info.ModTime(),
+		}
+		if withChecksum {
+			if entry.Checksum, err = fileMD5(p); err != nil {
+				return err
+			}
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing the local files: %w", err)
+	}
+	return entries, nil
+}
+
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer u.SkipD(f.Close)
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// mapPageToSyncEntries ignores the directory and rename markers.
+// The ETag is only used as checksum when it is the MD5 of the content, which is not the case for multipart uploads.
+func mapPageToSyncEntries(page *s3.ListObjectsV2Output, prefix string) []directory.SyncEntry {
+	entries := make([]directory.SyncEntry, 0, len(page.Contents))
+	for _, obj := range page.Contents {
+		key := aws.ToString(obj.Key)
+		if strings.HasSuffix(key, "/") || isRenameMarkerFile(key) {
+			continue
+		}
+		entry := directory.SyncEntry{
+			RelativePath: strings.TrimPrefix(key, prefix),
+			SizeBytes:    uint64(aws.ToInt64(obj.Size)),
+			LastModified: aws.ToTime(obj.LastModified),
+		}
+		if etag := strings.Trim(aws.ToString(obj.ETag), `"`); !strings.Contains(etag, "-") {
+			entry.Checksum = etag
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,108 @@
+package s3
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func TestListLocalSyncEntries(t *testing.T) {
+	t.Run("should list the nested files with their slash separated path and checksum", func(t *testing.T) {
+		// Given
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "sub", "empty"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "file.txt"), []byte("hello"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "sub", "nested.txt"), []byte("nested"), 0o644))
+
+		// When
+		res, err := listLocalSyncEntries(t.Context(), root, true)
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, "file.txt", res[0].RelativePath)
+		assert.Equal(t, uint64(5), res[0].SizeBytes)
+		assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", res[0].Checksum)
+		assert.Equal(t, "sub/nested.txt", res[1].RelativePath)
+	})
+}
+
+func TestMapPageToSyncEntries(t *testing.T) {
+	t.Run("should ignore the markers and the ETag of multipart uploads", func(t *testing.T) {
+		// Given
+		page := &s3.ListObjectsV2Output{Contents: []types.Object{
+			{Key: aws.String("mydir/sub/"), Size: aws.Int64(0)},
+			{Key: aws.String("mydir/file.txt"), Size: aws.Int64(5), ETag: aws.String(`"5d41402abc4b2a76b9719d911017c592"`)},
+			{Key: aws.String("mydir/big.bin"), Size: aws.Int64(50), ETag: aws.String(`"d41d8cd98f00b204e9800998ecf8427e-3"`)},
+		}}
+
+		// When
+		res := mapPageToSyncEntries(page, "mydir/")
+
+		// Then
+		require.Len(t, res, 2)
+		assert.Equal(t, "file.txt", res[0].RelativePath)
+		assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", res[0].Checksum)
+		assert.Equal(t, "big.bin", res[1].RelativePath)
+		assert.Empty(t, res[1].Checksum)
+	})
+}
+
+func TestSyncCopy(t *testing.T) {
+	t.Run("should keep the local working copy when the download of an updated file fails", func(t *testing.T) {
+		// Given
+		root := t.TempDir()
+		localPath := filepath.Join(root, "report.txt")
+		require.NoError(t, os.WriteFile(localPath, []byte("local work"), 0o644))
+		client := &fakeDownloadClient{
+			contents: map[string]string{"mydir/report.txt": "remote content"},
+			broken:   map[string]bool{"mydir/report.txt": true},
+		}
+		plan := &directory.SyncPlan{
+			LocalPath: root,
+			Options:   directory.SyncOptions{Direction: directory.SyncToLocal},
+		}
+		change := directory.SyncChange{RelativePath: "report.txt", Action: directory.SyncUpdate}
+
+		// When
+		err := syncCopy(t.Context(), client, plan, "mydir/", change)
+
+		// Then
+		assert.Error(t, err)
+		content, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, "local work", string(content))
+	})
+}
+
+func TestSyncDeletions(t *testing.T) {
+	t.Run("should remove the extraneous local files when syncing to the local folder", func(t *testing.T) {
+		// Given
+		root := t.TempDir()
+		localPath := filepath.Join(root, "old.txt")
+		require.NoError(t, os.WriteFile(localPath, []byte("old"), 0o644))
+		plan := &directory.SyncPlan{
+			LocalPath: root,
+			Options:   directory.SyncOptions{Direction: directory.SyncToLocal, DeleteExtraneous: true},
+		}
+		changes := []directory.SyncChange{
+			{RelativePath: "old.txt", Action: directory.SyncDelete},
+			{RelativePath: "../outside.txt", Action: directory.SyncDelete},
+		}
+
+		// When
+		deleted, failures := syncDeletions(t.Context(), nil, plan, "mydir/", changes)
+
+		// Then
+		assert.Equal(t, 1, deleted)
+		assert.Contains(t, failures, "../outside.txt")
+		assert.NoFileExists(t, localPath)
+	})
+}
@@ -0,0 +1,79 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+func (h *EventHandler) handleLoadFileTags(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.LoadFileTagsTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed loading file tags: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.LoadFileTagsFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.File.Parent().ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	tags, err := client.GetObjectTagging(ctx, mapFileToKey(pl.File))
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.LoadFileTagsSucceeded{
+		File: pl.File,
+		Tags: tags,
+	}))
+}
+
+func (h *EventHandler) handleUpdateFileTags(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.UpdateFileTagsTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed updating file tags: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.UpdateFileTagsFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	connID := pl.File.Parent().ConnectionID()
+	if err := h.checkWritable(ctx, connID); err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, connID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	key := mapFileToKey(pl.File)
+	if len(pl.Tags) == 0 {
+		err = client.DeleteObjectTagging(ctx, key)
+	} else {
+		err = client.PutObjectTagging(ctx, key, pl.Tags)
+	}
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.UpdateFileTagsSucceeded{
+		File: pl.File,
+		Tags: pl.Tags,
+	}))
+}
@@ -7,11 +7,10 @@ import (
 
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
-	"github.com/thomas-marquis/s3-box/internal/u"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
 )
 
 func (h *EventHandler) handleUploadFile(e event.Event) {
-	ctx := e.Context()
 	pl := e.Payload().(directory.UploadFileTriggered)
 
 	handleError := func(err error) {
@@ -19,20 +18,12 @@ func (h *EventHandler) handleUploadFile(e event.Event) {
 		h.bus.Publish(e.NewFollowup(directory.UploadFileFailed{Err: err, Directory: pl.Directory}))
 	}
 
-	client, err := h.clientFactory.Get(ctx, pl.Directory.ConnectionID())
-	if err != nil {
-		handleError(err)
-		return
-	}
-
-	localFile, err := os.Open(pl.SrcPath)
-	if err != nil {
+	if err := h.checkWritable(e.Context(), pl.Directory.ConnectionID()); err != nil {
 		handleError(err)
 		return
 	}
-	defer u.SkipD(localFile.Close)
 
-	info, err := localFile.Stat()
+	info, err := os.Stat(pl.SrcPath)
 	if err != nil {
 		handleError(fmt.Errorf("failed reading the file info: %w", err))
 		return
@@ -51,10 +42,27 @@ func (h *EventHandler) handleUploadFile(e event.Event) {
 		return
 	}
 
-	if err := client.Upload(ctx, mapFileToKey(newFile), localFile); err != nil {
-		handleError(fmt.Errorf("failed uploading file: %w", err))
+	client, err := h.clientFactory.Get(e.Context(), pl.Directory.ConnectionID())
+	if err != nil {
+		handleError(err)
 		return
 	}
 
-	h.bus.Publish(e.NewFollowup(directory.UploadFileSucceeded{File: newFile, Directory: pl.Directory}))
+	if !pl.Overwrite {
+		if err := checkNotExists(e.Context(), client, mapFileToKey(newFile)); err != nil {
+			handleError(err)
+			return
+		}
+	}
+
+	job := transfer.NewUploadJob(pl.Directory.ConnectionID(), pl.SrcPath, mapFileToKey(newFile), uint64(info.Size()))
+	job.Bucket, job.RootPrefix = client.Bucket(), client.RootPrefix()
+	h.transfers.enqueue(job, func(_ transfer.Job, err error) {
+		if err != nil {
+			// the failure is already notified by the transfer manager
+			h.bus.Publish(e.NewFollowup(directory.UploadFileFailed{Err: err, Directory: pl.Directory}))
+			return
+		}
+		h.bus.Publish(e.NewFollowup(directory.UploadFileSucceeded{File: newFile, Directory: pl.Directory}))
+	})
 }
@@ -0,0 +1,144 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+)
+
+func (h *EventHandler) handleLoadFileVersions(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.LoadFileVersionsTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed loading file versions: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.LoadFileVersionsFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	client, err := h.clientFactory.Get(ctx, pl.File.Parent().ConnectionID())
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	key := mapFileToKey(pl.File)
+	objVersions, err := client.ListObjectVersions(ctx, key, false)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.LoadFileVersionsSucceeded{
+		File:     pl.File,
+		Versions: mapObjectVersionsToFileVersions(key, objVersions),
+	}))
+}
+
+func (h *EventHandler) handleRestoreFileVersion(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.RestoreFileVersionTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed restoring file version: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.RestoreFileVersionFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	connID := pl.File.Parent().ConnectionID()
+	if err := h.checkWritable(ctx, connID); err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, connID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	key := mapFileToKey(pl.File)
+	if err := client.CopyObject(ctx, key, key, s3client.WithVersionID(pl.Version.ID)); err != nil {
+		handleError(err)
+		return
+	}
+
+	head, err := client.HeadObject(ctx, key)
+	if err != nil {
+		handleError(err)
+		return
+	}
+	md := mapHeadObjectToMetadata(head)
+
+	var sizeBytes uint64
+	if head.ContentLength != nil {
+		sizeBytes = uint64(*head.ContentLength)
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.RestoreFileVersionSucceeded{
+		File:         pl.File,
+		Version:      pl.Version,
+		SizeBytes:    sizeBytes,
+		LastModified: md.LastModified,
+	}))
+}
+
+func (h *EventHandler) handleDeleteFileVersion(e event.Event) {
+	ctx := e.Context()
+	pl := e.Payload().(directory.DeleteFileVersionTriggered)
+
+	handleError := func(err error) {
+		h.notifier.NotifyError(fmt.Errorf("failed deleting file version: %w", err))
+		h.bus.Publish(e.NewFollowup(directory.DeleteFileVersionFailed{
+			Err:  err,
+			File: pl.File,
+		}))
+	}
+
+	connID := pl.File.Parent().ConnectionID()
+	if err := h.checkWritable(ctx, connID); err != nil {
+		handleError(err)
+		return
+	}
+
+	client, err := h.clientFactory.Get(ctx, connID)
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	if err := client.DeleteObject(ctx, mapFileToKey(pl.File), s3client.WithVersionID(pl.Version.ID)); err != nil {
+		handleError(err)
+		return
+	}
+
+	h.bus.Publish(e.NewFollowup(directory.DeleteFileVersionSucceeded{
+		File:    pl.File,
+		Version: pl.Version,
+	}))
+}
+
+// mapObjectVersionsToFileVersions keeps the versions of the given key only,
+// as the listing also returns the other keys sharing the same prefix.
+func mapObjectVersionsToFileVersions(key string, objVersions []s3client.ObjectVersion) []directory.FileVersion {
+	versions := make([]directory.FileVersion, 0, len(objVersions))
+	for _, v := range objVersions {
+		if v.Key != key {
+			continue
+		}
+		versions = append(versions, directory.FileVersion{
+			ID:             v.VersionID,
+			SizeBytes:      uint64(max(v.SizeBytes, 0)),
+			LastModified:   v.LastModified,
+			IsLatest:       v.IsLatest,
+			IsDeleteMarker: v.IsDeleteMarker,
+		})
+	}
+	return versions
+}
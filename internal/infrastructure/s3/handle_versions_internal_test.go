@@ -0,0 +1,31 @@
+package s3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+)
+
+func TestMapObjectVersionsToFileVersions(t *testing.T) {
+	t.Run("should keep the versions of the file key only", func(t *testing.T) {
+		// Given
+		lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		objVersions := []s3client.ObjectVersion{
+			{Key: "dir/data.json", VersionID: "v2", LastModified: lastModified, IsLatest: true, IsDeleteMarker: true},
+			{Key: "dir/data.json", VersionID: "v1", SizeBytes: 12, LastModified: lastModified.Add(-time.Hour)},
+			{Key: "dir/data.json.bak", VersionID: "v3", SizeBytes: 8, IsLatest: true},
+		}
+
+		// When
+		res := mapObjectVersionsToFileVersions("dir/data.json", objVersions)
+
+		// Then
+		assert.Equal(t, []directory.FileVersion{
+			{ID: "v2", LastModified: lastModified, IsLatest: true, IsDeleteMarker: true},
+			{ID: "v1", SizeBytes: 12, LastModified: lastModified.Add(-time.Hour)},
+		}, res)
+	})
+}
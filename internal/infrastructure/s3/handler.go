@@ -7,6 +7,7 @@ import (
 
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/notification"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
 	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -23,6 +24,7 @@ type EventHandler struct {
 	s3ClientOptions      []func(*s3.Options)
 
 	clientFactory s3client.Factory
+	transfers     *transferManager
 	subscriber    *event.Subscriber
 }
 
@@ -32,13 +34,15 @@ func NewS3EventHandler(
 	notifier notification.Repository,
 	s3ClientOptions ...func(*s3.Options),
 ) *EventHandler {
+	clientFactory := s3client.NewFactory(connectionsRepository, notifier, s3ClientOptions...)
 	return &EventHandler{
 		connectionRepository: connectionsRepository,
 		logger:               log.New(os.Stdout, "S3Repository: ", log.LstdFlags),
 		bus:                  bus,
 		notifier:             notifier,
 		s3ClientOptions:      s3ClientOptions,
-		clientFactory:        s3client.NewFactory(connectionsRepository, notifier, s3ClientOptions...),
+		clientFactory:        clientFactory,
+		transfers:            newTransferManager(bus, notifier, clientFactory),
 	}
 }
 
@@ -50,12 +54,40 @@ func (h *EventHandler) Listen() {
 		On(event.Is(directory.DeleteFileTriggeredType), h.handleDeleteFile).
 		On(event.Is(directory.UploadFileTriggeredType), h.handleUploadFile).
 		On(event.Is(directory.DownloadFileTriggeredType), h.handleDownloadFile).
+		On(event.Is(directory.DownloadTriggeredType), h.handleDownloadDirectory).
+		On(event.Is(transfer.PauseTriggeredType), h.transfers.handlePause).
+		On(event.Is(transfer.ResumeTriggeredType), h.transfers.handleResume).
+		On(event.Is(transfer.CancelTriggeredType), h.transfers.handleCancel).
+		On(event.Is(transfer.ClearFinishedTriggeredType), h.transfers.handleClearFinished).
+		On(event.Is(transfer.RestoreTriggeredType), h.transfers.handleRestore).
+		On(event.Is(transfer.SetConcurrencyTriggeredType), h.transfers.handleSetConcurrency).
+		On(event.Is(transfer.ListIncompleteUploadsTriggeredType), h.handleListIncompleteUploads).
+		On(event.Is(transfer.AbortIncompleteUploadsTriggeredType), h.handleAbortIncompleteUploads).
+		On(event.Is(directory.SearchTriggeredType), h.handleSearch).
+		On(event.Is(directory.ComputeSizeTriggeredType), h.handleComputeSize).
+		On(event.Is(directory.SyncPreviewTriggeredType), h.handleSyncPreview).
+		On(event.Is(directory.SyncTriggeredType), h.handleSync).
 		On(event.Is(directory.LoadTriggeredType), h.handleLoadDirectory).
+		On(event.Is(directory.LoadPageTriggeredType), h.handleLoadDirectoryPage).
 		On(event.Is(directory.LoadFileTriggeredType), h.handleLoadFile).
+		On(event.Is(directory.LoadFileMetadataTriggeredType), h.handleLoadFileMetadata).
+		On(event.Is(directory.UpdateFileMetadataTriggeredType), h.handleUpdateFileMetadata).
+		On(event.Is(directory.LoadFileTagsTriggeredType), h.handleLoadFileTags).
+		On(event.Is(directory.UpdateFileTagsTriggeredType), h.handleUpdateFileTags).
+		On(event.Is(directory.CreateFileShareLinkTriggeredType), h.handleCreateFileShareLink).
+		On(event.Is(directory.LoadFileVersionsTriggeredType), h.handleLoadFileVersions).
+		On(event.Is(directory.RestoreFileVersionTriggeredType), h.handleRestoreFileVersion).
+		On(event.Is(directory.DeleteFileVersionTriggeredType), h.handleDeleteFileVersion).
 		On(event.Is(directory.UserValidationAcceptedType), h.handleRenameDirectory).
 		On(event.Is(directory.RenameFileTriggeredType), h.handleRenameFile).
 		On(event.Is(directory.RenameTriggeredType), h.handleRenameRequest).
 		On(event.Is(directory.RenameRecoveryTriggeredType), h.handleRenameRecovery).
+		On(event.Is(directory.DeleteSelectionTriggeredType), h.handleDeleteSelection).
+		On(event.Is(directory.DownloadSelectionTriggeredType), h.handleDownloadSelection).
+		On(event.Is(directory.CopySelectionTriggeredType), h.handleCopySelection).
+		On(event.Is(directory.PastePreviewTriggeredType), h.handlePastePreview).
+		On(event.Is(connection_deck.ListBucketsTriggeredType), h.handleListBuckets).
+		On(event.Is(connection_deck.DiagnoseTriggeredType), h.handleDiagnose).
 		On(event.IsOneOf(
 			connection_deck.RemoveConnectionSucceededType,
 			connection_deck.UpdateConnectionSucceededType,
@@ -1,9 +1,12 @@
 package s3
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
 
 	"github.com/aws/smithy-go"
 )
@@ -25,3 +28,17 @@ func isNotFoundError(err error) bool {
 
 	return false
 }
+
+// checkNotExists returns directory.ErrAlreadyExists when the object is in the bucket,
+// which a partially loaded directory can't tell.
+func checkNotExists(ctx context.Context, client s3client.Client, key string) error {
+	_, err := client.HeadObject(ctx, key)
+	switch {
+	case err == nil:
+		return errors.Join(directory.ErrAlreadyExists, fmt.Errorf("object %s already exists", key))
+	case isNotFoundError(err):
+		return nil
+	default:
+		return err
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 )
 
+// The keys mapped from the paths are relative to the root of the browsed tree.
+// When the connection is mounted on a root prefix, the client adds it to reach the objects of the server.
+
 func mapDirToObjectKey(dir *directory.Directory) string {
 	if dir.Path().String() == "" || dir.IsRoot() {
 		return ""
@@ -38,7 +38,7 @@ func NewObject(ctx context.Context, client s3client.Client, file *directory.File
 	// Check if an object exists to determine the initial state
 	buff := types.NewWriteAtBuffer([]byte{})
 	key := buildS3Key(file)
-	if err := client.Download(ctx, key, buff); err != nil {
+	if err := client.Download(ctx, key, buff, s3client.WithVersionID(file.VersionID())); err != nil {
 		if isNotFoundError(err) {
 			obj.setState(&s3ObjectNotExists{obj: obj})
 		} else {
@@ -57,8 +57,13 @@ func (o *Object) Read(p []byte) (n int, err error) {
 	return o.currentState.Read(p)
 }
 
-// Write delegates to the current state's Write implementation
+// Write delegates to the current state's Write implementation.
+// A previous version of the object can't be modified.
 func (o *Object) Write(p []byte) (n int, err error) {
+	if o.file.VersionID() != "" {
+		return 0, fmt.Errorf("%w: version %s of %s is read-only, restore it first",
+			directory.ErrInvalidVersion, o.file.VersionID(), o.file.Name())
+	}
 	return o.currentState.Write(p)
 }
 
@@ -5,14 +5,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/transfermanager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 )
 
+const (
+	maxDeleteObjectsBatchSize = 1000
+)
+
 type baseApiImpl struct {
 	client   *s3.Client
 	bucket   string
@@ -54,6 +62,49 @@ func (c *baseApiImpl) DeleteObject(ctx context.Context, key string, opts ...Opti
 	return c.handleS3SdkError(err, key)
 }
 
+// DeleteObjects removes the given keys with batched requests of at most 1000 keys each.
+// It returns the errors indexed by key for the objects that couldn't be deleted.
+// When a whole request fails, all the keys of the batch are reported with the same error.
+func (c *baseApiImpl) DeleteObjects(ctx context.Context, keys []string, opts ...Option) map[string]error {
+	failures := make(map[string]error)
+
+	for batch := range slices.Chunk(keys, maxDeleteObjectsBatchSize) {
+		objects := make([]s3types.ObjectIdentifier, len(batch))
+		for i, key := range batch {
+			objects[i] = s3types.ObjectIdentifier{Key: aws.String(key)}
+		}
+
+		in := &s3.DeleteObjectsInput{
+			Bucket: aws.String(c.bucket),
+			Delete: &s3types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		}
+		for _, opt := range opts {
+			opt(in)
+		}
+
+		res, err := c.client.DeleteObjects(ctx, in)
+		if err != nil {
+			for _, key := range batch {
+				failures[key] = c.handleS3SdkError(err, key)
+			}
+			continue
+		}
+
+		for _, e := range res.Errors {
+			key := aws.ToString(e.Key)
+			failures[key] = c.handleS3SdkError(&smithy.GenericAPIError{
+				Code:    aws.ToString(e.Code),
+				Message: aws.ToString(e.Message),
+			}, key)
+		}
+	}
+
+	return failures
+}
+
 func (c *baseApiImpl) GetObject(ctx context.Context, key string, opts ...Option) (*s3.GetObjectOutput, error) {
 	in := &s3.GetObjectInput{
 		Bucket: aws.String(c.bucket),
@@ -66,6 +117,68 @@ func (c *baseApiImpl) GetObject(ctx context.Context, key string, opts ...Option)
 	return res, c.handleS3SdkError(err, key)
 }
 
+func (c *baseApiImpl) HeadObject(ctx context.Context, key string, opts ...Option) (*s3.HeadObjectOutput, error) {
+	in := &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	res, err := c.client.HeadObject(ctx, in)
+	return res, c.handleS3SdkError(err, key)
+}
+
+func (c *baseApiImpl) GetObjectTagging(ctx context.Context, key string, opts ...Option) (map[string]string, error) {
+	in := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	res, err := c.client.GetObjectTagging(ctx, in)
+	if err != nil {
+		return nil, c.handleS3SdkError(err, key)
+	}
+
+	tags := make(map[string]string, len(res.TagSet))
+	for _, tag := range res.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+func (c *baseApiImpl) PutObjectTagging(ctx context.Context, key string, tags map[string]string, opts ...Option) error {
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		tagSet = append(tagSet, s3types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+
+	in := &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(c.bucket),
+		Key:     aws.String(key),
+		Tagging: &s3types.Tagging{TagSet: tagSet},
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	_, err := c.client.PutObjectTagging(ctx, in)
+	return c.handleS3SdkError(err, key)
+}
+
+func (c *baseApiImpl) DeleteObjectTagging(ctx context.Context, key string, opts ...Option) error {
+	in := &s3.DeleteObjectTaggingInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	_, err := c.client.DeleteObjectTagging(ctx, in)
+	return c.handleS3SdkError(err, key)
+}
+
 func (c *baseApiImpl) ListObjects(ctx context.Context, prefix string, recursive bool, opts ...Option) (ListObjectsResult, error) {
 	var keys []string
 	var sizeBytesTot int64
@@ -116,6 +229,93 @@ func (c *baseApiImpl) ListObjectsWithCallback(ctx context.Context, prefix string
 	return nil
 }
 
+// ListObjectsPage returns a single page of objects under the prefix,
+// starting from the continuation token of the previous page (empty for the first one).
+// The NextContinuationToken of the output is set when more pages are available.
+func (c *baseApiImpl) ListObjectsPage(ctx context.Context, prefix string, recursive bool, continuationToken string, opts ...Option) (*s3.ListObjectsV2Output, error) {
+	var delimiter *string
+	if !recursive {
+		delimiter = aws.String("/")
+	}
+
+	inputs := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(c.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: delimiter,
+		MaxKeys:   aws.Int32(1000),
+	}
+	if continuationToken != "" {
+		inputs.ContinuationToken = aws.String(continuationToken)
+	}
+	for _, opt := range opts {
+		opt(inputs)
+	}
+
+	page, err := c.client.ListObjectsV2(ctx, inputs)
+	if err != nil {
+		return nil, c.handleS3SdkError(err, prefix)
+	}
+	return page, nil
+}
+
+// ListObjectVersions returns the versions and the delete markers of the objects under the prefix,
+// sorted by key and from the most recent version.
+func (c *baseApiImpl) ListObjectVersions(ctx context.Context, prefix string, recursive bool, opts ...Option) ([]ObjectVersion, error) {
+	var delimiter *string
+	if !recursive {
+		delimiter = aws.String("/")
+	}
+
+	in := &s3.ListObjectVersionsInput{
+		Bucket:    aws.String(c.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: delimiter,
+		MaxKeys:   aws.Int32(1000),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	versions := make([]ObjectVersion, 0)
+	for {
+		page, err := c.client.ListObjectVersions(ctx, in)
+		if err != nil {
+			return nil, c.handleS3SdkError(err, prefix)
+		}
+		for _, v := range page.Versions {
+			versions = append(versions, ObjectVersion{
+				Key:          aws.ToString(v.Key),
+				VersionID:    aws.ToString(v.VersionId),
+				SizeBytes:    aws.ToInt64(v.Size),
+				LastModified: aws.ToTime(v.LastModified),
+				IsLatest:     aws.ToBool(v.IsLatest),
+			})
+		}
+		for _, m := range page.DeleteMarkers {
+			versions = append(versions, ObjectVersion{
+				Key:            aws.ToString(m.Key),
+				VersionID:      aws.ToString(m.VersionId),
+				LastModified:   aws.ToTime(m.LastModified),
+				IsLatest:       aws.ToBool(m.IsLatest),
+				IsDeleteMarker: true,
+			})
+		}
+		if !aws.ToBool(page.IsTruncated) {
+			break
+		}
+		in.KeyMarker = page.NextKeyMarker
+		in.VersionIdMarker = page.NextVersionIdMarker
+	}
+
+	slices.SortStableFunc(versions, func(a, b ObjectVersion) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return b.LastModified.Compare(a.LastModified)
+	})
+	return versions, nil
+}
+
 func (c *baseApiImpl) GetObjectGrants(ctx context.Context, key string, opts ...Option) (Grants, error) {
 	return Grants{}, nil
 }
@@ -146,13 +346,146 @@ func (c *baseApiImpl) Upload(ctx context.Context, key string, body io.Reader, op
 	return c.handleS3SdkError(err, key)
 }
 
+// CreateMultipartUpload starts an upload sent in several parts and returns its ID.
+// The parts are checked with a CRC32 checksum, the same way the transfer manager does.
+func (c *baseApiImpl) CreateMultipartUpload(ctx context.Context, key string, opts ...Option) (string, error) {
+	in := &s3.CreateMultipartUploadInput{
+		Bucket:            aws.String(c.bucket),
+		Key:               aws.String(key),
+		ChecksumAlgorithm: s3types.ChecksumAlgorithmCrc32,
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	res, err := c.client.CreateMultipartUpload(ctx, in)
+	if err != nil {
+		return "", c.handleS3SdkError(err, key)
+	}
+	return aws.ToString(res.UploadId), nil
+}
+
+// UploadPart sends one part of a multipart upload, the part numbers starting from 1.
+// The body is seekable so that the request can be signed and retried.
+func (c *baseApiImpl) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.ReadSeeker, opts ...Option) (CompletedPart, error) {
+	in := &s3.UploadPartInput{
+		Bucket:            aws.String(c.bucket),
+		Key:               aws.String(key),
+		UploadId:          aws.String(uploadID),
+		PartNumber:        aws.Int32(partNumber),
+		Body:              body,
+		ChecksumAlgorithm: s3types.ChecksumAlgorithmCrc32,
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	res, err := c.client.UploadPart(ctx, in)
+	if err != nil {
+		return CompletedPart{}, c.handleS3SdkError(err, key)
+	}
+	return CompletedPart{
+		Number:   partNumber,
+		ETag:     aws.ToString(res.ETag),
+		Checksum: aws.ToString(res.ChecksumCRC32),
+	}, nil
+}
+
+func (c *baseApiImpl) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart, opts ...Option) error {
+	completed := make([]s3types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completed[i] = s3types.CompletedPart{
+			PartNumber:    aws.Int32(p.Number),
+			ETag:          aws.String(p.ETag),
+			ChecksumCRC32: nilIfEmpty(p.Checksum),
+		}
+	}
+
+	in := &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(c.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3types.CompletedMultipartUpload{Parts: completed},
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	_, err := c.client.CompleteMultipartUpload(ctx, in)
+	return c.handleS3SdkError(err, key)
+}
+
+// AbortMultipartUpload stops a multipart upload and deletes its parts from the bucket.
+func (c *baseApiImpl) AbortMultipartUpload(ctx context.Context, key, uploadID string, opts ...Option) error {
+	in := &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(c.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	_, err := c.client.AbortMultipartUpload(ctx, in)
+	return c.handleS3SdkError(err, key)
+}
+
+// ListMultipartUploads returns the multipart uploads in progress under the prefix, sorted by key.
+func (c *baseApiImpl) ListMultipartUploads(ctx context.Context, prefix string, opts ...Option) ([]MultipartUpload, error) {
+	in := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	uploads := make([]MultipartUpload, 0)
+	for {
+		page, err := c.client.ListMultipartUploads(ctx, in)
+		if err != nil {
+			return nil, c.handleS3SdkError(err, prefix)
+		}
+		for _, u := range page.Uploads {
+			uploads = append(uploads, MultipartUpload{
+				Key:       aws.ToString(u.Key),
+				UploadID:  aws.ToString(u.UploadId),
+				Initiated: aws.ToTime(u.Initiated),
+			})
+		}
+		if !aws.ToBool(page.IsTruncated) {
+			break
+		}
+		in.KeyMarker = page.NextKeyMarker
+		in.UploadIdMarker = page.NextUploadIdMarker
+	}
+	return uploads, nil
+}
+
 func (c *baseApiImpl) handleS3SdkError(err error, objName string) error {
 	if err == nil {
 		return nil
 	}
 
+	// Operations without a modeled NoSuchKey error, like the tagging ones, return a generic API error.
 	var nsk *s3types.NoSuchKey
-	if errors.As(err, &nsk) {
+	var apiErr smithy.APIError
+	if errors.As(err, &nsk) || (errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey") {
+		return errors.Join(
+			directory.ErrNotFound,
+			fmt.Errorf("object %s not found in bucket %s: %w",
+				objName, c.bucket, err),
+		)
+	}
+
+	var nsu *s3types.NoSuchUpload
+	if errors.As(err, &nsu) || (errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchUpload") {
+		return errors.Join(
+			directory.ErrNotFound,
+			fmt.Errorf("multipart upload of %s not found in bucket %s: %w",
+				objName, c.bucket, err),
+		)
+	}
+
+	// HeadObject doesn't return a body, hence a generic not found error.
+	var nf *s3types.NotFound
+	if errors.As(err, &nf) {
 		return errors.Join(
 			directory.ErrNotFound,
 			fmt.Errorf("object %s not found in bucket %s: %w",
@@ -168,5 +501,5 @@ func (c *baseApiImpl) handleS3SdkError(err error, objName string) error {
 		)
 	}
 
-	return fmt.Errorf("another kind of s3 error occurred: %w", err)
+	return explainError(err)
 }
@@ -1,11 +1,19 @@
 package s3client
 
 import (
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 )
 
@@ -24,6 +32,13 @@ func TestBaseApiImpl_handleS3SdkError(t *testing.T) {
 		assert.Contains(t, err.Error(), "object key not found")
 	})
 
+	t.Run("should wrap NotFound with directory.ErrNotFound", func(t *testing.T) {
+		s3Err := &types.NotFound{}
+		err := c.handleS3SdkError(s3Err, "key")
+		assert.ErrorIs(t, err, directory.ErrNotFound)
+		assert.Contains(t, err.Error(), "object key not found")
+	})
+
 	t.Run("should wrap NoSuchBucket with directory.ErrNotFound", func(t *testing.T) {
 		s3Err := &types.NoSuchBucket{}
 		err := c.handleS3SdkError(s3Err, "key")
@@ -39,3 +54,281 @@ func TestBaseApiImpl_handleS3SdkError(t *testing.T) {
 		assert.Contains(t, err.Error(), "something else")
 	})
 }
+
+func newFakeS3Server(t *testing.T, handler http.HandlerFunc) *baseApiImpl {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client := s3.New(s3.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(srv.URL),
+		UsePathStyle:     true,
+		Credentials:      credentials.NewStaticCredentialsProvider("access", "secret", ""),
+		RetryMaxAttempts: 1,
+	})
+	return newBaseApiImpl(client, "test-bucket")
+}
+
+type fakeDeleteRequest struct {
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+func TestBaseApiImpl_DeleteObjects(t *testing.T) {
+	t.Run("should split the keys into batches of 1000", func(t *testing.T) {
+		// Given
+		// the requests are decoded by the server, then checked by the test
+		received := make(chan fakeDeleteRequest, 10)
+		decodeErrs := make(chan error, 10)
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			var req fakeDeleteRequest
+			if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+				decodeErrs <- err
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			received <- req
+			_, _ = w.Write([]byte(`<DeleteResult></DeleteResult>`))
+		})
+
+		keys := make([]string, 2500)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("dir/file-%d.txt", i)
+		}
+
+		// When
+		res := c.DeleteObjects(t.Context(), keys)
+
+		// Then
+		close(received)
+		close(decodeErrs)
+		for err := range decodeErrs {
+			require.NoError(t, err)
+		}
+		var batchSizes []int
+		for req := range received {
+			batchSizes = append(batchSizes, len(req.Objects))
+		}
+		assert.Empty(t, res)
+		assert.Equal(t, []int{1000, 1000, 500}, batchSizes)
+	})
+
+	t.Run("should return the errors indexed by key", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<DeleteResult>` +
+				`<Error><Key>dir/locked.txt</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>` +
+				`</DeleteResult>`))
+		})
+
+		// When
+		res := c.DeleteObjects(t.Context(), []string{"dir/file.txt", "dir/locked.txt"})
+
+		// Then
+		assert.Len(t, res, 1)
+		assert.ErrorContains(t, res["dir/locked.txt"], "AccessDenied")
+	})
+
+	t.Run("should report all the keys of a batch when the request fails", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>not found</Message></Error>`))
+		})
+
+		// When
+		res := c.DeleteObjects(t.Context(), []string{"a.txt", "b.txt"})
+
+		// Then
+		assert.Len(t, res, 2)
+		assert.ErrorContains(t, res["a.txt"], "NoSuchBucket")
+		assert.ErrorContains(t, res["b.txt"], "NoSuchBucket")
+	})
+}
+
+func TestBaseApiImpl_ObjectTagging(t *testing.T) {
+	t.Run("should return the tags by key", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, r.URL.Query().Has("tagging"))
+			_, _ = w.Write([]byte(`<Tagging><TagSet>` +
+				`<Tag><Key>cost-center</Key><Value>42</Value></Tag>` +
+				`<Tag><Key>env</Key><Value>prod</Value></Tag>` +
+				`</TagSet></Tagging>`))
+		})
+
+		// When
+		res, err := c.GetObjectTagging(t.Context(), "dir/file.txt")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"cost-center": "42", "env": "prod"}, res)
+	})
+
+	t.Run("should send every tag sorted by key", func(t *testing.T) {
+		// Given
+		var req struct {
+			Tags []struct {
+				Key   string `xml:"Key"`
+				Value string `xml:"Value"`
+			} `xml:"TagSet>Tag"`
+		}
+		// the request is decoded by the server, then checked by the test
+		decodeErr := make(chan error, 1)
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			err := xml.NewDecoder(r.Body).Decode(&req)
+			decodeErr <- err
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		})
+
+		// When
+		err := c.PutObjectTagging(t.Context(), "dir/file.txt", map[string]string{"env": "prod", "cost-center": "42"})
+
+		// Then
+		require.NoError(t, <-decodeErr)
+		assert.NoError(t, err)
+		require.Len(t, req.Tags, 2)
+		assert.Equal(t, "cost-center", req.Tags[0].Key)
+		assert.Equal(t, "42", req.Tags[0].Value)
+		assert.Equal(t, "env", req.Tags[1].Key)
+	})
+
+	t.Run("should wrap a missing object with directory.ErrNotFound", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`))
+		})
+
+		// When
+		err := c.DeleteObjectTagging(t.Context(), "dir/missing.txt")
+
+		// Then
+		assert.ErrorIs(t, err, directory.ErrNotFound)
+	})
+}
+
+func TestBaseApiImpl_ListObjectsPage(t *testing.T) {
+	t.Run("should fetch a single page from the continuation token", func(t *testing.T) {
+		// Given
+		var calls int
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			assert.Equal(t, "token-1", r.URL.Query().Get("continuation-token"))
+			assert.Equal(t, "/", r.URL.Query().Get("delimiter"))
+			_, _ = w.Write([]byte(`<ListBucketResult><IsTruncated>true</IsTruncated>` +
+				`<NextContinuationToken>token-2</NextContinuationToken>` +
+				`<Contents><Key>dir/b.txt</Key><Size>10</Size></Contents>` +
+				`</ListBucketResult>`))
+		})
+
+		// When
+		res, err := c.ListObjectsPage(t.Context(), "dir/", false, "token-1")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		require.Len(t, res.Contents, 1)
+		assert.Equal(t, "dir/b.txt", aws.ToString(res.Contents[0].Key))
+		assert.Equal(t, "token-2", aws.ToString(res.NextContinuationToken))
+	})
+
+	t.Run("should start from the first page without token", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.False(t, r.URL.Query().Has("continuation-token"))
+			_, _ = w.Write([]byte(`<ListBucketResult><IsTruncated>false</IsTruncated></ListBucketResult>`))
+		})
+
+		// When
+		res, err := c.ListObjectsPage(t.Context(), "dir/", false, "")
+
+		// Then
+		require.NoError(t, err)
+		assert.Nil(t, res.NextContinuationToken)
+	})
+}
+
+func TestBaseApiImpl_ListObjectVersions(t *testing.T) {
+	t.Run("should merge versions and delete markers across pages", func(t *testing.T) {
+		// Given
+		var calls int
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, r.URL.Query().Has("versions"))
+			calls++
+			if calls == 1 {
+				assert.Empty(t, r.URL.Query().Get("key-marker"))
+				_, _ = w.Write([]byte(`<ListVersionsResult><IsTruncated>true</IsTruncated>` +
+					`<NextKeyMarker>dir/a.txt</NextKeyMarker><NextVersionIdMarker>v1</NextVersionIdMarker>` +
+					`<Version><Key>dir/a.txt</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest>` +
+					`<LastModified>2024-01-01T10:00:00.000Z</LastModified><Size>10</Size></Version>` +
+					`</ListVersionsResult>`))
+				return
+			}
+			assert.Equal(t, "dir/a.txt", r.URL.Query().Get("key-marker"))
+			assert.Equal(t, "v1", r.URL.Query().Get("version-id-marker"))
+			_, _ = w.Write([]byte(`<ListVersionsResult><IsTruncated>false</IsTruncated>` +
+				`<Version><Key>dir/b.txt</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest>` +
+				`<LastModified>2024-01-01T09:00:00.000Z</LastModified><Size>30</Size></Version>` +
+				`<DeleteMarker><Key>dir/a.txt</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest>` +
+				`<LastModified>2024-01-02T10:00:00.000Z</LastModified></DeleteMarker>` +
+				`</ListVersionsResult>`))
+		})
+
+		// When
+		res, err := c.ListObjectVersions(t.Context(), "dir/", false)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		require.Len(t, res, 3)
+		assert.Equal(t, "v2", res[0].VersionID)
+		assert.True(t, res[0].IsDeleteMarker)
+		assert.True(t, res[0].IsLatest)
+		assert.Equal(t, "v1", res[1].VersionID)
+		assert.Equal(t, int64(10), res[1].SizeBytes)
+		assert.False(t, res[1].IsDeleteMarker)
+		assert.Equal(t, "dir/b.txt", res[2].Key)
+	})
+}
+
+func TestWithVersionID(t *testing.T) {
+	t.Run("should target the version of the object to get", func(t *testing.T) {
+		// Given
+		in := &s3.GetObjectInput{}
+
+		// When
+		WithVersionID("v1")(in)
+
+		// Then
+		assert.Equal(t, "v1", aws.ToString(in.VersionId))
+	})
+
+	t.Run("should copy from the version of the source object", func(t *testing.T) {
+		// Given
+		in := &s3.CopyObjectInput{CopySource: aws.String("bucket%2Fkey")}
+
+		// When
+		WithVersionID("a+b")(in)
+
+		// Then
+		assert.Equal(t, "bucket%2Fkey?versionId=a%2Bb", aws.ToString(in.CopySource))
+	})
+
+	t.Run("should target the current version when empty", func(t *testing.T) {
+		// Given
+		in := &s3.DeleteObjectInput{}
+
+		// When
+		WithVersionID("")(in)
+
+		// Then
+		assert.Nil(t, in.VersionId)
+	})
+}
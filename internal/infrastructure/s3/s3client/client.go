@@ -4,45 +4,84 @@ import (
 	"context"
 	"io"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/transfermanager"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 )
 
 type BaseAPI interface {
 	PutObject(ctx context.Context, key string, body io.Reader, opts ...Option) error
 	GetObjectGrants(ctx context.Context, key string, opts ...Option) (Grants, error)
 	DeleteObject(ctx context.Context, key string, opts ...Option) error
+	DeleteObjects(ctx context.Context, keys []string, opts ...Option) map[string]error
 	GetObject(ctx context.Context, key string, opts ...Option) (*s3.GetObjectOutput, error)
+	HeadObject(ctx context.Context, key string, opts ...Option) (*s3.HeadObjectOutput, error)
+	GetObjectTagging(ctx context.Context, key string, opts ...Option) (map[string]string, error)
+	PutObjectTagging(ctx context.Context, key string, tags map[string]string, opts ...Option) error
+	DeleteObjectTagging(ctx context.Context, key string, opts ...Option) error
 	ListObjects(ctx context.Context, prefix string, recursive bool, opts ...Option) (ListObjectsResult, error)
 	ListObjectsWithCallback(ctx context.Context, prefix string, recursive bool, callback func(page *s3.ListObjectsV2Output) error, opts ...Option) error
+	ListObjectsPage(ctx context.Context, prefix string, recursive bool, continuationToken string, opts ...Option) (*s3.ListObjectsV2Output, error)
+	ListObjectVersions(ctx context.Context, prefix string, recursive bool, opts ...Option) ([]ObjectVersion, error)
 	Download(ctx context.Context, key string, writer io.WriterAt, opts ...Option) error
 	Upload(ctx context.Context, key string, body io.Reader, opts ...Option) error
+	CreateMultipartUpload(ctx context.Context, key string, opts ...Option) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.ReadSeeker, opts ...Option) (CompletedPart, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart, opts ...Option) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string, opts ...Option) error
+	ListMultipartUploads(ctx context.Context, prefix string, opts ...Option) ([]MultipartUpload, error)
 }
 
 type Client interface {
 	BaseAPI
 
+	CopyObject(ctx context.Context, srcKey, dstKey string, opts ...Option) error
 	RenameObject(ctx context.Context, oldKey, newKey string, opts ...Option) error
+	ReplaceObjectMetadata(ctx context.Context, key string, metadata directory.ObjectMetadata, opts ...Option) error
+	PresignGetObject(ctx context.Context, key string, expiry time.Duration, opts ...Option) (string, error)
+	PresignPutObject(ctx context.Context, key string, expiry time.Duration, opts ...Option) (string, error)
+
+	// ListBuckets returns the sorted names of the buckets the credentials have access to, whatever the client bucket.
+	ListBuckets(ctx context.Context) ([]string, error)
+
+	// HeadBucket checks the client bucket exists and is reachable.
+	// It returns the region of the bucket, empty when the server doesn't tell it.
+	HeadBucket(ctx context.Context) (string, error)
+
+	// Bucket returns the name of the bucket the client works on.
+	Bucket() string
+	// RootPrefix returns the prefix the keys given to the client are relative to, empty for the whole bucket.
+	RootPrefix() string
 }
 
 type clientImpl struct {
 	api BaseAPI
 
-	client   *s3.Client
-	bucket   string
-	tmClient *transfermanager.Client
+	client             *s3.Client
+	bucket             string
+	tmClient           *transfermanager.Client
+	copySourceEscaping connection_deck.CopySourceEscaping
 }
 
-func newClientImpl(client *s3.Client, bucket string, api BaseAPI) *clientImpl {
+func newClientImpl(
+	client *s3.Client,
+	bucket string,
+	copySourceEscaping connection_deck.CopySourceEscaping,
+	api BaseAPI,
+) *clientImpl {
 	return &clientImpl{
-		api:      api,
-		client:   client,
-		bucket:   bucket,
-		tmClient: transfermanager.New(client),
+		api:                api,
+		client:             client,
+		bucket:             bucket,
+		tmClient:           transfermanager.New(client),
+		copySourceEscaping: copySourceEscaping,
 	}
 }
 
@@ -51,28 +90,53 @@ func WeiredEscape(bucket, key string) string {
 	return strings.ReplaceAll(url.QueryEscape(bucket+"/"+key), "+", " ")
 }
 
-func (c *clientImpl) RenameObject(ctx context.Context, oldKey, newKey string, opts ...Option) error {
-	hIn := &s3.HeadObjectInput{
-		Bucket: aws.String(c.bucket),
-		Key:    aws.String(oldKey),
+// EscapeCopySource returns the copy source header of the object, escaped as the provider expects.
+func EscapeCopySource(escaping connection_deck.CopySourceEscaping, bucket, key string) string {
+	if escaping == connection_deck.EscapeCopySourceRawSpaces {
+		return WeiredEscape(bucket, key)
 	}
-	for _, opt := range opts {
-		opt(hIn)
-	}
-	headRes, err := c.client.HeadObject(ctx, hIn)
+	return strings.ReplaceAll(url.QueryEscape(bucket+"/"+key), "+", "%20")
+}
+
+// CopyObject makes a server-side copy of an object, keeping its metadata, grants and tags.
+func (c *clientImpl) CopyObject(ctx context.Context, srcKey, dstKey string, opts ...Option) error {
+	cpyInput, err := c.makeCopyObjectInput(ctx, srcKey, dstKey, opts...)
 	if err != nil {
 		return err
 	}
+	_, err = c.client.CopyObject(ctx, cpyInput)
+	return err
+}
 
-	grants, err := c.api.GetObjectGrants(ctx, oldKey, opts...)
+// ReplaceObjectMetadata copies the object in place to replace its content type, cache control and user metadata.
+// Other headers, grants and tags are kept.
+func (c *clientImpl) ReplaceObjectMetadata(ctx context.Context, key string, metadata directory.ObjectMetadata, opts ...Option) error {
+	cpyInput, err := c.makeCopyObjectInput(ctx, key, key, opts...)
 	if err != nil {
 		return err
 	}
+	cpyInput.ContentType = nilIfEmpty(metadata.ContentType)
+	cpyInput.CacheControl = nilIfEmpty(metadata.CacheControl)
+	cpyInput.Metadata = metadata.UserMetadata
+	_, err = c.client.CopyObject(ctx, cpyInput)
+	return err
+}
+
+func (c *clientImpl) makeCopyObjectInput(ctx context.Context, srcKey, dstKey string, opts ...Option) (*s3.CopyObjectInput, error) {
+	headRes, err := c.api.HeadObject(ctx, srcKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := c.api.GetObjectGrants(ctx, srcKey, opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	cpyInput := &s3.CopyObjectInput{
 		Bucket:                         aws.String(c.bucket),
-		CopySource:                     aws.String(WeiredEscape(c.bucket, oldKey)),
-		Key:                            aws.String(newKey),
+		CopySource:                     aws.String(EscapeCopySource(c.copySourceEscaping, c.bucket, srcKey)),
+		Key:                            aws.String(dstKey),
 		CacheControl:                   headRes.CacheControl,
 		ContentDisposition:             headRes.ContentDisposition,
 		ContentEncoding:                headRes.ContentEncoding,
@@ -100,13 +164,83 @@ func (c *clientImpl) RenameObject(ctx context.Context, oldKey, newKey string, op
 	for _, opt := range opts {
 		opt(cpyInput)
 	}
-	if _, err := c.client.CopyObject(ctx, cpyInput); err != nil {
+	return cpyInput, nil
+}
+
+func (c *clientImpl) RenameObject(ctx context.Context, oldKey, newKey string, opts ...Option) error {
+	if err := c.CopyObject(ctx, oldKey, newKey, opts...); err != nil {
 		return err
 	}
 
 	return c.api.DeleteObject(ctx, oldKey, opts...)
 }
 
+// PresignGetObject returns a URL allowing anyone to download the object until the expiry elapses.
+// The URL is signed locally with the connection credentials, no request is sent to the server.
+func (c *clientImpl) PresignGetObject(ctx context.Context, key string, expiry time.Duration, opts ...Option) (string, error) {
+	in := &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	req, err := s3.NewPresignClient(c.client).PresignGetObject(ctx, in, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+// PresignPutObject returns a URL allowing anyone to upload a new content for the object until the expiry elapses.
+// The URL is signed locally with the connection credentials, no request is sent to the server.
+func (c *clientImpl) PresignPutObject(ctx context.Context, key string, expiry time.Duration, opts ...Option) (string, error) {
+	in := &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	for _, opt := range opts {
+		opt(in)
+	}
+	req, err := s3.NewPresignClient(c.client).PresignPutObject(ctx, in, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (c *clientImpl) ListBuckets(ctx context.Context) ([]string, error) {
+	var buckets []string
+	paginator := s3.NewListBucketsPaginator(c.client, &s3.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, explainError(err)
+		}
+		for _, b := range page.Buckets {
+			buckets = append(buckets, aws.ToString(b.Name))
+		}
+	}
+	slices.Sort(buckets)
+	return buckets, nil
+}
+
+func (c *clientImpl) Bucket() string {
+	return c.bucket
+}
+
+func (c *clientImpl) RootPrefix() string {
+	return ""
+}
+
+func (c *clientImpl) HeadBucket(ctx context.Context) (string, error) {
+	res, err := c.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(c.bucket)})
+	if err != nil {
+		return BucketRegionFromError(err), err
+	}
+	return aws.ToString(res.BucketRegion), nil
+}
+
 func (c *clientImpl) PutObject(ctx context.Context, key string, body io.Reader, opts ...Option) error {
 	return c.api.PutObject(ctx, key, body, opts...)
 }
@@ -119,10 +253,30 @@ func (c *clientImpl) DeleteObject(ctx context.Context, key string, opts ...Optio
 	return c.api.DeleteObject(ctx, key, opts...)
 }
 
+func (c *clientImpl) DeleteObjects(ctx context.Context, keys []string, opts ...Option) map[string]error {
+	return c.api.DeleteObjects(ctx, keys, opts...)
+}
+
 func (c *clientImpl) GetObject(ctx context.Context, key string, opts ...Option) (*s3.GetObjectOutput, error) {
 	return c.api.GetObject(ctx, key, opts...)
 }
 
+func (c *clientImpl) HeadObject(ctx context.Context, key string, opts ...Option) (*s3.HeadObjectOutput, error) {
+	return c.api.HeadObject(ctx, key, opts...)
+}
+
+func (c *clientImpl) GetObjectTagging(ctx context.Context, key string, opts ...Option) (map[string]string, error) {
+	return c.api.GetObjectTagging(ctx, key, opts...)
+}
+
+func (c *clientImpl) PutObjectTagging(ctx context.Context, key string, tags map[string]string, opts ...Option) error {
+	return c.api.PutObjectTagging(ctx, key, tags, opts...)
+}
+
+func (c *clientImpl) DeleteObjectTagging(ctx context.Context, key string, opts ...Option) error {
+	return c.api.DeleteObjectTagging(ctx, key, opts...)
+}
+
 func (c *clientImpl) ListObjects(ctx context.Context, prefix string, recursive bool, opts ...Option) (ListObjectsResult, error) {
 	return c.api.ListObjects(ctx, prefix, recursive, opts...)
 }
@@ -131,6 +285,14 @@ func (c *clientImpl) ListObjectsWithCallback(ctx context.Context, prefix string,
 	return c.api.ListObjectsWithCallback(ctx, prefix, recursive, callback, opts...)
 }
 
+func (c *clientImpl) ListObjectsPage(ctx context.Context, prefix string, recursive bool, continuationToken string, opts ...Option) (*s3.ListObjectsV2Output, error) {
+	return c.api.ListObjectsPage(ctx, prefix, recursive, continuationToken, opts...)
+}
+
+func (c *clientImpl) ListObjectVersions(ctx context.Context, prefix string, recursive bool, opts ...Option) ([]ObjectVersion, error) {
+	return c.api.ListObjectVersions(ctx, prefix, recursive, opts...)
+}
+
 func (c *clientImpl) Download(ctx context.Context, key string, writer io.WriterAt, opts ...Option) error {
 	return c.api.Download(ctx, key, writer, opts...)
 }
@@ -139,4 +301,81 @@ func (c *clientImpl) Upload(ctx context.Context, key string, body io.Reader, opt
 	return c.api.Upload(ctx, key, body, opts...)
 }
 
+func (c *clientImpl) CreateMultipartUpload(ctx context.Context, key string, opts ...Option) (string, error) {
+	return c.api.CreateMultipartUpload(ctx, key, opts...)
+}
+
+func (c *clientImpl) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.ReadSeeker, opts ...Option) (CompletedPart, error) {
+	return c.api.UploadPart(ctx, key, uploadID, partNumber, body, opts...)
+}
+
+func (c *clientImpl) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart, opts ...Option) error {
+	return c.api.CompleteMultipartUpload(ctx, key, uploadID, parts, opts...)
+}
+
+func (c *clientImpl) AbortMultipartUpload(ctx context.Context, key, uploadID string, opts ...Option) error {
+	return c.api.AbortMultipartUpload(ctx, key, uploadID, opts...)
+}
+
+func (c *clientImpl) ListMultipartUploads(ctx context.Context, prefix string, opts ...Option) ([]MultipartUpload, error) {
+	return c.api.ListMultipartUploads(ctx, prefix, opts...)
+}
+
 type Option func(any)
+
+// WithVersionID targets a specific version of the object instead of the current one.
+// For a copy, the version applies to the source object.
+func WithVersionID(versionID string) Option {
+	return func(in any) {
+		if versionID == "" {
+			return
+		}
+		switch in := in.(type) {
+		case *s3.GetObjectInput:
+			in.VersionId = aws.String(versionID)
+		case *s3.HeadObjectInput:
+			in.VersionId = aws.String(versionID)
+		case *s3.GetObjectAclInput:
+			in.VersionId = aws.String(versionID)
+		case *s3.GetObjectTaggingInput:
+			in.VersionId = aws.String(versionID)
+		case *s3.DeleteObjectInput:
+			in.VersionId = aws.String(versionID)
+		case *transfermanager.DownloadObjectInput:
+			in.VersionID = aws.String(versionID)
+		case *s3.CopyObjectInput:
+			in.CopySource = aws.String(aws.ToString(in.CopySource) + "?versionId=" + url.QueryEscape(versionID))
+		}
+	}
+}
+
+// WithMaxKeys limits the number of keys of a listed page.
+func WithMaxKeys(maxKeys int32) Option {
+	return func(in any) {
+		if in, ok := in.(*s3.ListObjectsV2Input); ok {
+			in.MaxKeys = aws.Int32(maxKeys)
+		}
+	}
+}
+
+// WithHeadersOf uploads the object with the content headers and the user metadata of the downloaded one,
+// as a copy between two buckets would keep them.
+func WithHeadersOf(src *s3.GetObjectOutput) Option {
+	return func(in any) {
+		if in, ok := in.(*transfermanager.UploadObjectInput); ok {
+			in.CacheControl = src.CacheControl
+			in.ContentDisposition = src.ContentDisposition
+			in.ContentEncoding = src.ContentEncoding
+			in.ContentLanguage = src.ContentLanguage
+			in.ContentType = src.ContentType
+			in.Metadata = src.Metadata
+		}
+	}
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
@@ -0,0 +1,103 @@
+package s3client_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+)
+
+func newTestConnection(opt connection_deck.ConnectionOption) *connection_deck.Connection {
+	deck := connection_deck.New()
+	return deck.New("conn", "AKIAEXAMPLE", "secret", "my-bucket", opt).
+		Payload().(connection_deck.CreateConnectionTriggered).Connection()
+}
+
+func TestClient_Presign(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should sign a GET URL with a path-style S3-like endpoint", func(t *testing.T) {
+		// Given
+		conn := newTestConnection(connection_deck.AsS3Like("localhost:9000", false))
+		client := s3client.NewS3LikeClient(conn)
+
+		// When
+		res, err := client.PresignGetObject(ctx, "reports/2024/data.csv", 15*time.Minute)
+
+		// Then
+		require.NoError(t, err)
+		u, err := url.Parse(res)
+		require.NoError(t, err)
+		assert.Equal(t, "http", u.Scheme)
+		assert.Equal(t, "localhost:9000", u.Host)
+		assert.Equal(t, "/my-bucket/reports/2024/data.csv", u.Path)
+		q := u.Query()
+		assert.Equal(t, "900", q.Get("X-Amz-Expires"))
+		assert.True(t, strings.HasPrefix(q.Get("X-Amz-Credential"), "AKIAEXAMPLE/"))
+		assert.Contains(t, q.Get("X-Amz-Credential"), "/us-east-1/s3/")
+		assert.NotEmpty(t, q.Get("X-Amz-Signature"))
+	})
+
+	t.Run("should sign a PUT URL with the AWS regional endpoint", func(t *testing.T) {
+		// Given
+		conn := newTestConnection(connection_deck.AsAWS("eu-west-3"))
+		client := s3client.NewAwsClient(conn)
+
+		// When
+		res, err := client.PresignPutObject(ctx, "upload.bin", 24*time.Hour)
+
+		// Then
+		require.NoError(t, err)
+		u, err := url.Parse(res)
+		require.NoError(t, err)
+		assert.Equal(t, "https", u.Scheme)
+		assert.Equal(t, "my-bucket.s3.eu-west-3.amazonaws.com", u.Host)
+		assert.Equal(t, "/upload.bin", u.Path)
+		q := u.Query()
+		assert.Equal(t, "86400", q.Get("X-Amz-Expires"))
+		assert.Contains(t, q.Get("X-Amz-Credential"), "/eu-west-3/s3/")
+		assert.NotEmpty(t, q.Get("X-Amz-Signature"))
+	})
+
+	t.Run("should produce different signatures for GET and PUT", func(t *testing.T) {
+		// Given
+		conn := newTestConnection(connection_deck.AsS3Like("https://s3.example.com", true))
+		client := s3client.NewS3LikeClient(conn)
+
+		// When
+		getURL, errGet := client.PresignGetObject(ctx, "file.txt", time.Hour)
+		putURL, errPut := client.PresignPutObject(ctx, "file.txt", time.Hour)
+
+		// Then
+		require.NoError(t, errGet)
+		require.NoError(t, errPut)
+		getU, _ := url.Parse(getURL)
+		putU, _ := url.Parse(putURL)
+		assert.Equal(t, "s3.example.com", getU.Host)
+		assert.NotEqual(t, getU.Query().Get("X-Amz-Signature"), putU.Query().Get("X-Amz-Signature"))
+	})
+}
+
+func TestEscapeCopySource(t *testing.T) {
+	t.Run("should URL-encode the spaces of the key", func(t *testing.T) {
+		// When
+		res := s3client.EscapeCopySource(connection_deck.EscapeCopySourceURL, "bucket", "dir/my file+1.txt")
+
+		// Then
+		assert.Equal(t, "bucket%2Fdir%2Fmy%20file%2B1.txt", res)
+	})
+
+	t.Run("should leave the spaces as is for the providers expecting it", func(t *testing.T) {
+		// When
+		res := s3client.EscapeCopySource(connection_deck.EscapeCopySourceRawSpaces, "bucket", "dir/my file+1.txt")
+
+		// Then
+		assert.Equal(t, "bucket%2Fdir%2Fmy file%2B1.txt", res)
+	})
+}
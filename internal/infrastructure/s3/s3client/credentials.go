@@ -0,0 +1,149 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+const (
+	defaultRegion = "us-east-1"
+	// credentialsExpiryWindow is how long before their expiration the temporary credentials are refreshed
+	credentialsExpiryWindow = 5 * time.Minute
+	roleSessionName         = "s3-box"
+)
+
+var errNoEnvCredentials = errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables are not set")
+
+// newCredentialsProvider returns the provider of the credentials of the connection, from its credential source.
+// The temporary credentials are cached and refreshed shortly before they expire.
+func newCredentialsProvider(conn *connection_deck.Connection) aws.CredentialsProvider {
+	source := conn.CredentialSource()
+	switch source.Type {
+	case connection_deck.SessionCredentials:
+		return credentials.NewStaticCredentialsProvider(conn.AccessKey(), conn.SecretKey(), source.SessionToken)
+	case connection_deck.ProfileCredentials:
+		return newCredentialsCache(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return retrieveFromConfig(ctx, config.WithSharedConfigProfile(source.Profile))
+		}))
+	case connection_deck.EnvCredentials:
+		// not cached, as the environment credentials have no expiration to refresh them
+		return aws.CredentialsProviderFunc(retrieveFromEnv)
+	case connection_deck.AssumeRoleCredentials:
+		return newCredentialsCache(newAssumeRoleProvider(conn))
+	default:
+		return credentials.NewStaticCredentialsProvider(conn.AccessKey(), conn.SecretKey(), "")
+	}
+}
+
+func newCredentialsCache(provider aws.CredentialsProvider) *aws.CredentialsCache {
+	return aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = credentialsExpiryWindow
+	})
+}
+
+// newAssumeRoleProvider assumes the role of the connection with its keys, or with the default credentials chain
+// when it has none. The role of an S3-like connection is asked to its server, as MinIO does.
+func newAssumeRoleProvider(conn *connection_deck.Connection) aws.CredentialsProvider {
+	source := conn.CredentialSource()
+
+	var baseCredentials aws.CredentialsProvider = newCredentialsCache(aws.CredentialsProviderFunc(
+		func(ctx context.Context) (aws.Credentials, error) {
+			return retrieveFromConfig(ctx)
+		}))
+	if conn.AccessKey() != "" {
+		baseCredentials = credentials.NewStaticCredentialsProvider(conn.AccessKey(), conn.SecretKey(), "")
+	}
+
+	stsClient := sts.New(sts.Options{
+		Credentials:  baseCredentials,
+		Region:       connectionRegion(conn),
+		BaseEndpoint: connectionEndpoint(conn),
+		HTTPClient:   newHTTPClient(conn),
+	})
+	return stscreds.NewAssumeRoleProvider(stsClient, source.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = roleSessionName
+		if source.ExternalID != "" {
+			o.ExternalID = aws.String(source.ExternalID)
+		}
+		if source.SessionDuration > 0 {
+			o.Duration = source.SessionDuration
+		}
+	})
+}
+
+// retrieveFromConfig retrieves the credentials of the AWS shared config, environment and instance metadata.
+func retrieveFromConfig(ctx context.Context, opts ...func(*config.LoadOptions) error) (aws.Credentials, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	if cfg.Credentials == nil {
+		return aws.Credentials{}, errors.New("no credentials found in the AWS configuration")
+	}
+	return cfg.Credentials.Retrieve(ctx)
+}
+
+// retrieveFromEnv reads the credentials from the environment at each retrieval,
+// so that the credentials renewed by an external tool are used.
+func retrieveFromEnv(context.Context) (aws.Credentials, error) {
+	env, err := config.NewEnvConfig()
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	if !env.Credentials.HasKeys() {
+		return aws.Credentials{}, errNoEnvCredentials
+	}
+	return env.Credentials, nil
+}
+
+// connectionRegion returns the region the requests are signed for, the default one of the provider when not set.
+func connectionRegion(conn *connection_deck.Connection) string {
+	if conn.Region() != "" {
+		return conn.Region()
+	}
+	if region := conn.Provider().Preset().DefaultRegion; region != "" {
+		return region
+	}
+	return defaultRegion
+}
+
+// usePathStyle returns true when the bucket is addressed in the path rather than in the host name.
+// Unless the connection sets the style, the virtual-hosted one is avoided for the bucket names with dots,
+// which don't match the TLS certificates, and for the AWS connections to a custom server, like a local test one.
+func usePathStyle(conn *connection_deck.Connection) bool {
+	switch conn.Transport().AddressingStyle {
+	case connection_deck.AddressingPath:
+		return true
+	case connection_deck.AddressingVirtualHosted:
+		return false
+	}
+	if !conn.Provider().Preset().VirtualHostedStyle {
+		return true
+	}
+	return strings.Contains(conn.Bucket(), ".") || conn.Server() != ""
+}
+
+// connectionEndpoint returns the URL of the server of the connection, nil for AWS.
+func connectionEndpoint(conn *connection_deck.Connection) *string {
+	if conn.Server() == "" {
+		return nil
+	}
+	server := conn.Server()
+	if !strings.HasPrefix(server, "http://") && !strings.HasPrefix(server, "https://") {
+		protocol := "http://"
+		if conn.IsTLSActivated() {
+			protocol = "https://"
+		}
+		server = protocol + server
+	}
+	return aws.String(server)
+}
@@ -0,0 +1,247 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func newTestConnection(options ...connection_deck.ConnectionOption) *connection_deck.Connection {
+	return connection_deck.New().New("conn", "ak", "sk", "bucket", options...).
+		Payload().(connection_deck.CreateConnectionTriggered).Connection()
+}
+
+func TestNewCredentialsProvider(t *testing.T) {
+	t.Run("should sign with the keys of the connection by default", func(t *testing.T) {
+		// Given
+		conn := newTestConnection()
+
+		// When
+		res, err := newCredentialsProvider(conn).Retrieve(context.TODO())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "ak", res.AccessKeyID)
+		assert.Equal(t, "sk", res.SecretAccessKey)
+		assert.Empty(t, res.SessionToken)
+	})
+
+	t.Run("should add the session token of the connection", func(t *testing.T) {
+		// Given
+		conn := newTestConnection(connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+			Type:         connection_deck.SessionCredentials,
+			SessionToken: "token",
+		}))
+
+		// When
+		res, err := newCredentialsProvider(conn).Retrieve(context.TODO())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "ak", res.AccessKeyID)
+		assert.Equal(t, "token", res.SessionToken)
+	})
+
+	t.Run("should read the credentials from the environment", func(t *testing.T) {
+		// Given
+		t.Setenv("AWS_ACCESS_KEY_ID", "env-ak")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "env-sk")
+		t.Setenv("AWS_SESSION_TOKEN", "env-token")
+		conn := newTestConnection(connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+			Type: connection_deck.EnvCredentials,
+		}))
+
+		// When
+		res, err := newCredentialsProvider(conn).Retrieve(context.TODO())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "env-ak", res.AccessKeyID)
+		assert.Equal(t, "env-sk", res.SecretAccessKey)
+		assert.Equal(t, "env-token", res.SessionToken)
+	})
+
+	t.Run("should read the credentials renewed in the environment", func(t *testing.T) {
+		// Given
+		t.Setenv("AWS_ACCESS_KEY_ID", "env-ak")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "env-sk")
+		conn := newTestConnection(connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+			Type: connection_deck.EnvCredentials,
+		}))
+		provider := newCredentialsProvider(conn)
+		_, err := provider.Retrieve(context.TODO())
+		require.NoError(t, err)
+		t.Setenv("AWS_ACCESS_KEY_ID", "rotated-ak")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "rotated-sk")
+
+		// When
+		res, err := provider.Retrieve(context.TODO())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "rotated-ak", res.AccessKeyID)
+		assert.Equal(t, "rotated-sk", res.SecretAccessKey)
+	})
+
+	t.Run("should return an error when the environment has no credentials", func(t *testing.T) {
+		// Given
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+		conn := newTestConnection(connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+			Type: connection_deck.EnvCredentials,
+		}))
+
+		// When
+		_, err := newCredentialsProvider(conn).Retrieve(context.TODO())
+
+		// Then
+		assert.ErrorIs(t, err, errNoEnvCredentials)
+	})
+
+	t.Run("should read the credentials of the profile", func(t *testing.T) {
+		// Given
+		credentialsFile := t.TempDir() + "/credentials"
+		require.NoError(t, os.WriteFile(credentialsFile,
+			[]byte("[other]\naws_access_key_id = other-ak\naws_secret_access_key = other-sk\n"), 0o600))
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+		t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+		conn := newTestConnection(connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+			Type:    connection_deck.ProfileCredentials,
+			Profile: "other",
+		}))
+
+		// When
+		res, err := newCredentialsProvider(conn).Retrieve(context.TODO())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "other-ak", res.AccessKeyID)
+		assert.Equal(t, "other-sk", res.SecretAccessKey)
+	})
+
+	t.Run("should assume the role and refresh the credentials only once expired", func(t *testing.T) {
+		// Given
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !assert.NoError(t, r.ParseForm()) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			assert.Equal(t, "AssumeRole", r.Form.Get("Action"))
+			assert.Equal(t, "arn:aws:iam::123456789012:role/reader", r.Form.Get("RoleArn"))
+			assert.Equal(t, "my-external-id", r.Form.Get("ExternalId"))
+			assert.Equal(t, "1800", r.Form.Get("DurationSeconds"))
+			calls++
+			w.Header().Set("Content-Type", "text/xml")
+			_, _ = fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>role-ak</AccessKeyId>
+      <SecretAccessKey>role-sk</SecretAccessKey>
+      <SessionToken>role-token</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+		}))
+		defer server.Close()
+
+		conn := newTestConnection(
+			connection_deck.AsS3Like(server.URL, false),
+			connection_deck.WithCredentialSource(connection_deck.CredentialSource{
+				Type:            connection_deck.AssumeRoleCredentials,
+				RoleARN:         "arn:aws:iam::123456789012:role/reader",
+				ExternalID:      "my-external-id",
+				SessionDuration: 30 * time.Minute,
+			}))
+		provider := newCredentialsProvider(conn)
+
+		// When
+		res, err := provider.Retrieve(context.TODO())
+		require.NoError(t, err)
+		_, err = provider.Retrieve(context.TODO())
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, "role-ak", res.AccessKeyID)
+		assert.Equal(t, "role-token", res.SessionToken)
+		assert.True(t, res.CanExpire)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestUsePathStyle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  []connection_deck.ConnectionOption
+		bucket   string
+		expected bool
+	}{
+		{
+			name:     "should use the virtual-hosted style with AWS",
+			options:  []connection_deck.ConnectionOption{connection_deck.AsAWS("eu-west-3")},
+			bucket:   "my-bucket",
+			expected: false,
+		},
+		{
+			name:     "should use the path style with AWS for a bucket name with dots",
+			options:  []connection_deck.ConnectionOption{connection_deck.AsAWS("eu-west-3")},
+			bucket:   "my.bucket",
+			expected: true,
+		},
+		{
+			name: "should use the path style with the providers without virtual-hosted style",
+			options: []connection_deck.ConnectionOption{
+				connection_deck.AsProvider(connection_deck.MinIOProvider, "localhost:9000", "", false),
+			},
+			bucket:   "my-bucket",
+			expected: true,
+		},
+		{
+			name: "should use the addressing style set for the connection",
+			options: []connection_deck.ConnectionOption{
+				connection_deck.AsProvider(connection_deck.MinIOProvider, "minio.example.com", "", true),
+				connection_deck.WithTransport(connection_deck.TransportOptions{
+					AddressingStyle: connection_deck.AddressingVirtualHosted,
+				}),
+			},
+			bucket:   "my-bucket",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Given
+			conn := connection_deck.New().New("conn", "ak", "sk", tc.bucket, tc.options...).
+				Payload().(connection_deck.CreateConnectionTriggered).Connection()
+
+			// When
+			res := usePathStyle(conn)
+
+			// Then
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestConnectionRegion(t *testing.T) {
+	t.Run("should use the default region of the provider when not set", func(t *testing.T) {
+		// Given
+		conn := newTestConnection(connection_deck.AsProvider(connection_deck.ScalewayProvider, "s3.fr-par.scw.cloud", "", true))
+
+		// When
+		res := connectionRegion(conn)
+
+		// Then
+		assert.Equal(t, "fr-par", res)
+	})
+}
@@ -0,0 +1,220 @@
+package s3client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+const diagnosticDialTimeout = 10 * time.Second
+
+// diagnostic runs the steps of a connection check in order.
+// Once a step failed, the following ones are skipped as they would fail for the same reason.
+type diagnostic struct {
+	steps  []connection_deck.DiagnosticStep
+	failed bool
+}
+
+func (d *diagnostic) run(name string, check func() (connection_deck.DiagnosticStatus, string)) {
+	if d.failed {
+		d.add(name, connection_deck.DiagnosticSkipped, "skipped as a previous step failed")
+		return
+	}
+	status, details := check()
+	d.add(name, status, details)
+}
+
+func (d *diagnostic) add(name string, status connection_deck.DiagnosticStatus, details string) {
+	d.steps = append(d.steps, connection_deck.DiagnosticStep{Name: name, Status: status, Details: details})
+	if status == connection_deck.DiagnosticFailed {
+		d.failed = true
+	}
+}
+
+// Diagnose checks the connection step by step: the endpoint URL, its DNS resolution, the TCP and TLS handshake,
+// the bucket access, its region and a listing probe. The client must be built from the connection.
+func Diagnose(ctx context.Context, conn *connection_deck.Connection, client Client) []connection_deck.DiagnosticStep {
+	d := &diagnostic{}
+
+	var endpoint *url.URL
+	d.run("Endpoint", func() (connection_deck.DiagnosticStatus, string) {
+		var err error
+		endpoint, err = diagnosticEndpoint(conn)
+		if err != nil {
+			return connection_deck.DiagnosticFailed, fmt.Sprintf("the endpoint isn't a valid URL: %s", err)
+		}
+		return connection_deck.DiagnosticPassed, fmt.Sprintf("the server is reached at %s", endpoint)
+	})
+
+	d.run("DNS", func() (connection_deck.DiagnosticStatus, string) {
+		return checkDNS(ctx, endpoint.Hostname())
+	})
+
+	d.run("Connection", func() (connection_deck.DiagnosticStatus, string) {
+		return checkHandshake(ctx, conn.Transport(), endpoint)
+	})
+
+	var bucketRegion string
+	d.run("Bucket", func() (connection_deck.DiagnosticStatus, string) {
+		if conn.Bucket() == "" {
+			return connection_deck.DiagnosticSkipped, "no bucket to check, the connection is account-level"
+		}
+		var err error
+		bucketRegion, err = client.HeadBucket(ctx)
+		if err != nil {
+			return connection_deck.DiagnosticFailed, describeError(err)
+		}
+		return connection_deck.DiagnosticPassed, fmt.Sprintf("the bucket %s exists and the credentials can reach it", conn.Bucket())
+	})
+
+	// the region explains a failure of the bucket access, so it is checked whatever the previous steps
+	status, details := checkRegion(conn, bucketRegion)
+	d.add("Region", status, details)
+
+	// the client already explains the errors of the listings
+	d.run("Listing", func() (connection_deck.DiagnosticStatus, string) {
+		if conn.Bucket() == "" {
+			buckets, err := client.ListBuckets(ctx)
+			if err != nil {
+				return connection_deck.DiagnosticFailed, err.Error()
+			}
+			return connection_deck.DiagnosticPassed, fmt.Sprintf("the credentials can see %d bucket(s)", len(buckets))
+		}
+		if _, err := client.ListObjectsPage(ctx, "", false, "", WithMaxKeys(1)); err != nil {
+			return connection_deck.DiagnosticFailed, err.Error()
+		}
+		if conn.RootPrefix() != "" {
+			return connection_deck.DiagnosticPassed, fmt.Sprintf("the credentials can list the content of %s in the bucket", conn.RootPrefix())
+		}
+		return connection_deck.DiagnosticPassed, "the credentials can list the content of the bucket"
+	})
+
+	return d.steps
+}
+
+// diagnosticEndpoint returns the URL of the server of the connection, the regional S3 one for AWS.
+func diagnosticEndpoint(conn *connection_deck.Connection) (*url.URL, error) {
+	endpoint := fmt.Sprintf("https://s3.%s.amazonaws.com", connectionRegion(conn))
+	if e := connectionEndpoint(conn); e != nil {
+		endpoint = *e
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("no host in %q", endpoint)
+	}
+	return u, nil
+}
+
+func checkDNS(ctx context.Context, host string) (connection_deck.DiagnosticStatus, string) {
+	if net.ParseIP(host) != nil {
+		return connection_deck.DiagnosticPassed, "the endpoint is an IP address, nothing to resolve"
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return connection_deck.DiagnosticFailed, describeError(err)
+	}
+	return connection_deck.DiagnosticPassed, fmt.Sprintf("%s resolves to %s", host, strings.Join(addrs, ", "))
+}
+
+// checkHandshake opens a TCP connection to the server, then makes the TLS handshake when the endpoint is https,
+// with the transport options of the connection.
+// When a proxy is set for the connection or in the environment, only the connection to the proxy is checked.
+func checkHandshake(
+	ctx context.Context,
+	transport connection_deck.TransportOptions,
+	endpoint *url.URL,
+) (connection_deck.DiagnosticStatus, string) {
+	address := hostPort(endpoint)
+	proxyFunc, err := newProxyFunc(transport)
+	if err != nil {
+		return connection_deck.DiagnosticFailed, err.Error()
+	}
+	proxy, err := proxyFunc(&http.Request{URL: endpoint})
+	if err != nil {
+		return connection_deck.DiagnosticFailed, fmt.Sprintf("the proxy is invalid: %s", err)
+	}
+	if proxy != nil {
+		address = hostPort(proxy)
+	}
+
+	dialer := &net.Dialer{Timeout: diagnosticDialTimeout}
+	tcpConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return connection_deck.DiagnosticFailed, describeError(err)
+	}
+	defer tcpConn.Close()
+
+	if proxy != nil {
+		return connection_deck.DiagnosticPassed, fmt.Sprintf("connected to the proxy %s, the server is reached through it", proxy.Host)
+	}
+	if endpoint.Scheme != "https" {
+		return connection_deck.DiagnosticPassed, fmt.Sprintf("connected to %s, without TLS", address)
+	}
+
+	tlsConfig, err := newTLSConfig(transport)
+	if err != nil {
+		return connection_deck.DiagnosticFailed, err.Error()
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.ServerName = endpoint.Hostname()
+	tlsConn := tls.Client(tcpConn, tlsConfig)
+	handshakeCtx, cancel := context.WithTimeout(ctx, diagnosticDialTimeout)
+	defer cancel()
+	if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+		return connection_deck.DiagnosticFailed, describeError(err)
+	}
+	state := tlsConn.ConnectionState()
+	cert := state.PeerCertificates[0]
+	details := fmt.Sprintf(
+		"connected to %s with %s, certificate of %s issued by %s, valid until %s",
+		address, tls.VersionName(state.Version), cert.Subject.CommonName, cert.Issuer.CommonName,
+		cert.NotAfter.Format(time.DateOnly))
+	if transport.InsecureSkipVerify {
+		details += ", NOT VERIFIED as the TLS verification is off"
+	}
+	return connection_deck.DiagnosticPassed, details
+}
+
+func checkRegion(conn *connection_deck.Connection, bucketRegion string) (connection_deck.DiagnosticStatus, string) {
+	switch {
+	case bucketRegion == "":
+		return connection_deck.DiagnosticSkipped, "the server didn't tell the region of the bucket"
+	case conn.Region() == "":
+		return connection_deck.DiagnosticPassed, fmt.Sprintf("the server reports the region %s", bucketRegion)
+	case bucketRegion != conn.Region():
+		return connection_deck.DiagnosticFailed, fmt.Sprintf(
+			"the bucket is in the region %s but the connection is set to %s", bucketRegion, conn.Region())
+	default:
+		return connection_deck.DiagnosticPassed, fmt.Sprintf("the bucket is in the region of the connection, %s", bucketRegion)
+	}
+}
+
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "http" {
+		return net.JoinHostPort(u.Hostname(), "80")
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+// describeError returns the explanation of the error followed by the error itself.
+func describeError(err error) string {
+	if explanation := explainS3Error(err); explanation != "" {
+		return fmt.Sprintf("%s (%s)", explanation, err)
+	}
+	return err.Error()
+}
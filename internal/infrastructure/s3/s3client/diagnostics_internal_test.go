@@ -0,0 +1,106 @@
+package s3client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func newDiagnosedConnection(t *testing.T, handler http.HandlerFunc, options ...connection_deck.ConnectionOption) (*connection_deck.Connection, Client) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	options = append(options, connection_deck.AsS3Like(strings.TrimPrefix(srv.URL, "http://"), false))
+	conn := newTestConnection(options...)
+	return conn, NewS3LikeClient(conn, func(o *s3.Options) {
+		o.RetryMaxAttempts = 1
+	})
+}
+
+func stepsByName(steps []connection_deck.DiagnosticStep) map[string]connection_deck.DiagnosticStep {
+	res := make(map[string]connection_deck.DiagnosticStep, len(steps))
+	for _, s := range steps {
+		res[s.Name] = s
+	}
+	return res
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Run("should pass all the steps of a reachable bucket", func(t *testing.T) {
+		// Given
+		conn, client := newDiagnosedConnection(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(bucketRegionHeader, "us-east-1")
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`<ListBucketResult><Name>bucket</Name><KeyCount>0</KeyCount></ListBucketResult>`))
+			}
+		})
+
+		// When
+		res := Diagnose(t.Context(), conn, client)
+
+		// Then
+		require.Len(t, res, 6)
+		steps := stepsByName(res)
+		assert.Equal(t, connection_deck.DiagnosticPassed, steps["Endpoint"].Status)
+		assert.Equal(t, connection_deck.DiagnosticPassed, steps["DNS"].Status)
+		assert.Equal(t, connection_deck.DiagnosticPassed, steps["Connection"].Status)
+		assert.Contains(t, steps["Connection"].Details, "without TLS")
+		assert.Equal(t, connection_deck.DiagnosticPassed, steps["Bucket"].Status)
+		assert.Equal(t, connection_deck.DiagnosticPassed, steps["Region"].Status)
+		assert.Contains(t, steps["Region"].Details, "us-east-1")
+		assert.Equal(t, connection_deck.DiagnosticPassed, steps["Listing"].Status)
+	})
+
+	t.Run("should explain a denied access and skip the following steps", func(t *testing.T) {
+		// Given
+		conn, client := newDiagnosedConnection(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+
+		// When
+		res := Diagnose(t.Context(), conn, client)
+
+		// Then
+		steps := stepsByName(res)
+		assert.Equal(t, connection_deck.DiagnosticFailed, steps["Bucket"].Status)
+		assert.Contains(t, steps["Bucket"].Details, "access denied")
+		assert.Equal(t, connection_deck.DiagnosticSkipped, steps["Listing"].Status)
+	})
+
+	t.Run("should detect a bucket in another region than the connection one", func(t *testing.T) {
+		// Given
+		conn := newTestConnection(connection_deck.AsAWS("us-east-1"))
+
+		// When
+		status, details := checkRegion(conn, "eu-west-3")
+
+		// Then
+		assert.Equal(t, connection_deck.DiagnosticFailed, status)
+		assert.Equal(t, "the bucket is in the region eu-west-3 but the connection is set to us-east-1", details)
+	})
+
+	t.Run("should report an unreachable server", func(t *testing.T) {
+		// Given
+		srv := httptest.NewServer(http.NotFoundHandler())
+		address := strings.TrimPrefix(srv.URL, "http://")
+		srv.Close()
+		conn := newTestConnection(connection_deck.AsS3Like(address, false))
+
+		// When
+		res := Diagnose(t.Context(), conn, NewS3LikeClient(conn))
+
+		// Then
+		steps := stepsByName(res)
+		assert.Equal(t, connection_deck.DiagnosticFailed, steps["Connection"].Status)
+		assert.Contains(t, steps["Connection"].Details, "the server refused the connection")
+		assert.Equal(t, connection_deck.DiagnosticSkipped, steps["Bucket"].Status)
+	})
+}
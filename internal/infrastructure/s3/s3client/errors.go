@@ -0,0 +1,123 @@
+package s3client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+const bucketRegionHeader = "X-Amz-Bucket-Region"
+
+// explainError wraps the error with a human explanation of its cause, when it is a known one.
+func explainError(err error) error {
+	if explanation := explainS3Error(err); explanation != "" {
+		return fmt.Errorf("%s: %w", explanation, err)
+	}
+	return fmt.Errorf("another kind of s3 error occurred: %w", err)
+}
+
+// explainS3Error returns what went wrong in plain words, from the network errors to the S3 error codes.
+// It returns an empty string when the cause of the error is unknown.
+func explainS3Error(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return fmt.Sprintf("the server name %s can't be resolved, check the endpoint of the connection", dnsErr.Name)
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return "the server refused the connection, check the host and the port of the endpoint"
+	}
+	if explanation := explainTLSError(err); explanation != "" {
+		return explanation
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "the server didn't answer in time, check the endpoint and your network"
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "InvalidAccessKeyId":
+			return "the access key is unknown to the server"
+		case "SignatureDoesNotMatch":
+			return "the secret key doesn't match the access key"
+		case "ExpiredToken", "TokenRefreshRequired":
+			return "the temporary credentials have expired"
+		case "InvalidToken":
+			return "the session token is invalid"
+		case "PermanentRedirect", "AuthorizationHeaderMalformed", "IllegalLocationConstraintException":
+			return explainRegionMismatch(err)
+		case "AccessDenied":
+			return "access denied, the credentials aren't allowed to do this operation"
+		case "NoSuchBucket", "NotFound":
+			return "the bucket doesn't exist"
+		}
+	}
+
+	// the responses of the HEAD requests have no body, hence no error code
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.HTTPStatusCode() {
+		case http.StatusMovedPermanently:
+			return explainRegionMismatch(err)
+		case http.StatusBadRequest:
+			if BucketRegionFromError(err) != "" {
+				return explainRegionMismatch(err)
+			}
+		case http.StatusForbidden:
+			return "access denied, the credentials aren't allowed to do this operation"
+		case http.StatusNotFound:
+			return "the bucket doesn't exist"
+		}
+	}
+	return ""
+}
+
+func explainTLSError(err error) string {
+	var (
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		invalidErr     x509.CertificateInvalidError
+		recordErr      tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &unknownAuthErr):
+		return "the TLS certificate of the server is signed by an unknown authority"
+	case errors.As(err, &hostnameErr):
+		return fmt.Sprintf("the TLS certificate of the server isn't valid for %s", hostnameErr.Host)
+	case errors.As(err, &invalidErr):
+		return "the TLS certificate of the server is invalid or expired"
+	case errors.As(err, &recordErr):
+		return "the server doesn't speak TLS, disable TLS for this connection"
+	}
+	return ""
+}
+
+func explainRegionMismatch(err error) string {
+	if region := BucketRegionFromError(err); region != "" {
+		return fmt.Sprintf("the bucket is in the region %s, change the region of the connection", region)
+	}
+	return "the bucket is in another region than the connection one"
+}
+
+// BucketRegionFromError returns the region of the bucket given by the server with an error response,
+// or an empty string.
+func BucketRegionFromError(err error) string {
+	var respErr *awshttp.ResponseError
+	if !errors.As(err, &respErr) || respErr.Response == nil {
+		return ""
+	}
+	return respErr.Response.Header.Get(bucketRegionHeader)
+}
@@ -0,0 +1,105 @@
+package s3client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainS3Error(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		region   string
+		expected string
+	}{
+		{
+			name:     "should explain an unknown access key",
+			status:   http.StatusForbidden,
+			body:     `<Error><Code>InvalidAccessKeyId</Code></Error>`,
+			expected: "the access key is unknown to the server",
+		},
+		{
+			name:     "should explain a wrong secret key",
+			status:   http.StatusForbidden,
+			body:     `<Error><Code>SignatureDoesNotMatch</Code></Error>`,
+			expected: "the secret key doesn't match the access key",
+		},
+		{
+			name:     "should explain expired temporary credentials",
+			status:   http.StatusBadRequest,
+			body:     `<Error><Code>ExpiredToken</Code></Error>`,
+			expected: "the temporary credentials have expired",
+		},
+		{
+			name:     "should give the bucket region of a redirection",
+			status:   http.StatusMovedPermanently,
+			body:     `<Error><Code>PermanentRedirect</Code></Error>`,
+			region:   "eu-west-3",
+			expected: "the bucket is in the region eu-west-3, change the region of the connection",
+		},
+		{
+			name:     "should explain a denied access",
+			status:   http.StatusForbidden,
+			body:     `<Error><Code>AccessDenied</Code></Error>`,
+			expected: "access denied, the credentials aren't allowed to do this operation",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Given
+			c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+				if tc.region != "" {
+					w.Header().Set(bucketRegionHeader, tc.region)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			})
+			_, sdkErr := c.ListObjectsPage(t.Context(), "", false, "")
+			require.Error(t, sdkErr)
+
+			// When
+			res := explainS3Error(sdkErr)
+
+			// Then
+			assert.Equal(t, tc.expected, res)
+			assert.ErrorContains(t, sdkErr, tc.expected)
+		})
+	}
+
+	t.Run("should explain a bucket in another region from the status of a HEAD request", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(bucketRegionHeader, "eu-west-3")
+			w.WriteHeader(http.StatusMovedPermanently)
+		})
+		_, sdkErr := c.HeadObject(t.Context(), "key")
+		require.Error(t, sdkErr)
+
+		// When
+		res := explainS3Error(sdkErr)
+
+		// Then
+		assert.Equal(t, "the bucket is in the region eu-west-3, change the region of the connection", res)
+	})
+
+	t.Run("should return an empty explanation for an unknown error", func(t *testing.T) {
+		// Given
+		c := newFakeS3Server(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<Error><Code>SomethingWeird</Code></Error>`))
+		})
+		_, sdkErr := c.ListObjectsPage(t.Context(), "", false, "")
+		require.Error(t, sdkErr)
+
+		// When
+		res := explainS3Error(sdkErr)
+
+		// Then
+		assert.Empty(t, res)
+	})
+}
@@ -12,6 +12,8 @@ import (
 type Factory interface {
 	Get(ctx context.Context, connID connection_deck.ConnectionID) (Client, error)
 	Remove(connId connection_deck.ConnectionID)
+	// New returns a new client for the connection, without caching it, as for a connection not saved yet.
+	New(conn *connection_deck.Connection) Client
 }
 
 func NewFactory(connectionRepository connection_deck.Repository, notifier notification.Repository, opts ...func(*s3.Options)) Factory {
@@ -49,20 +51,22 @@ func (f *factoryImpl) Get(ctx context.Context, connID connection_deck.Connection
 		return nil, err
 	}
 
-	var newClient Client
-	// TODO: implement a better discrimination system between connection types
-	if conn.Region() == "" {
-		// S3Like
-		newClient = NewS3LikeClient(conn, f.opts...)
-	} else {
-		// AWS
-		newClient = NewAwsClient(conn, f.opts...)
-	}
-
+	newClient := f.New(conn)
 	f.cache[connID] = newClient
 	return newClient, nil
 }
 
+func (f *factoryImpl) New(conn *connection_deck.Connection) Client {
+	// the AWS client reads the object grants, which most of the S3-like servers don't support
+	var client Client
+	if conn.Provider().Preset().SupportsACL {
+		client = NewAwsClient(conn, f.opts...)
+	} else {
+		client = NewS3LikeClient(conn, f.opts...)
+	}
+	return withRootPrefix(client, conn.RootPrefix())
+}
+
 func (f *factoryImpl) Remove(connId connection_deck.ConnectionID) {
 	delete(f.cache, connId)
 }
@@ -0,0 +1,204 @@
+package s3client
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+// rootedClient confines a client to the root prefix of its connection.
+// The keys given to the client are relative to the root prefix, which is added before reaching the server
+// and removed from the listed keys, so that the root of the browsed tree is the root prefix.
+type rootedClient struct {
+	Client
+
+	rootPrefix string
+}
+
+var _ Client = (*rootedClient)(nil)
+
+// withRootPrefix returns the client confined to the root prefix, or the client itself when the prefix is empty.
+func withRootPrefix(client Client, rootPrefix string) Client {
+	if rootPrefix == "" {
+		return client
+	}
+	return &rootedClient{Client: client, rootPrefix: rootPrefix}
+}
+
+// toKey returns the key of the server from a key relative to the root prefix.
+func (c *rootedClient) toKey(key string) string {
+	return c.rootPrefix + key
+}
+
+// fromKey returns the key relative to the root prefix from a key of the server.
+func (c *rootedClient) fromKey(key string) string {
+	return strings.TrimPrefix(key, c.rootPrefix)
+}
+
+func (c *rootedClient) fromPage(page *s3.ListObjectsV2Output) *s3.ListObjectsV2Output {
+	if page == nil {
+		return nil
+	}
+	res := *page
+	res.Prefix = aws.String(c.fromKey(aws.ToString(page.Prefix)))
+	res.Contents = make([]types.Object, len(page.Contents))
+	for i, obj := range page.Contents {
+		obj.Key = aws.String(c.fromKey(aws.ToString(obj.Key)))
+		res.Contents[i] = obj
+	}
+	res.CommonPrefixes = make([]types.CommonPrefix, len(page.CommonPrefixes))
+	for i, p := range page.CommonPrefixes {
+		p.Prefix = aws.String(c.fromKey(aws.ToString(p.Prefix)))
+		res.CommonPrefixes[i] = p
+	}
+	return &res
+}
+
+func (c *rootedClient) RootPrefix() string {
+	return c.rootPrefix
+}
+
+func (c *rootedClient) PutObject(ctx context.Context, key string, body io.Reader, opts ...Option) error {
+	return c.Client.PutObject(ctx, c.toKey(key), body, opts...)
+}
+
+func (c *rootedClient) GetObjectGrants(ctx context.Context, key string, opts ...Option) (Grants, error) {
+	return c.Client.GetObjectGrants(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) DeleteObject(ctx context.Context, key string, opts ...Option) error {
+	return c.Client.DeleteObject(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) DeleteObjects(ctx context.Context, keys []string, opts ...Option) map[string]error {
+	serverKeys := make([]string, len(keys))
+	for i, key := range keys {
+		serverKeys[i] = c.toKey(key)
+	}
+	errs := c.Client.DeleteObjects(ctx, serverKeys, opts...)
+	if errs == nil {
+		return nil
+	}
+	res := make(map[string]error, len(errs))
+	for key, err := range errs {
+		res[c.fromKey(key)] = err
+	}
+	return res
+}
+
+func (c *rootedClient) GetObject(ctx context.Context, key string, opts ...Option) (*s3.GetObjectOutput, error) {
+	return c.Client.GetObject(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) HeadObject(ctx context.Context, key string, opts ...Option) (*s3.HeadObjectOutput, error) {
+	return c.Client.HeadObject(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) GetObjectTagging(ctx context.Context, key string, opts ...Option) (map[string]string, error) {
+	return c.Client.GetObjectTagging(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) PutObjectTagging(ctx context.Context, key string, tags map[string]string, opts ...Option) error {
+	return c.Client.PutObjectTagging(ctx, c.toKey(key), tags, opts...)
+}
+
+func (c *rootedClient) DeleteObjectTagging(ctx context.Context, key string, opts ...Option) error {
+	return c.Client.DeleteObjectTagging(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) ListObjects(ctx context.Context, prefix string, recursive bool, opts ...Option) (ListObjectsResult, error) {
+	res, err := c.Client.ListObjects(ctx, c.toKey(prefix), recursive, opts...)
+	if err != nil {
+		return ListObjectsResult{}, err
+	}
+	for i, key := range res.Keys {
+		res.Keys[i] = c.fromKey(key)
+	}
+	return res, nil
+}
+
+func (c *rootedClient) ListObjectsWithCallback(ctx context.Context, prefix string, recursive bool, callback func(page *s3.ListObjectsV2Output) error, opts ...Option) error {
+	return c.Client.ListObjectsWithCallback(ctx, c.toKey(prefix), recursive, func(page *s3.ListObjectsV2Output) error {
+		return callback(c.fromPage(page))
+	}, opts...)
+}
+
+func (c *rootedClient) ListObjectsPage(ctx context.Context, prefix string, recursive bool, continuationToken string, opts ...Option) (*s3.ListObjectsV2Output, error) {
+	page, err := c.Client.ListObjectsPage(ctx, c.toKey(prefix), recursive, continuationToken, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c.fromPage(page), nil
+}
+
+func (c *rootedClient) ListObjectVersions(ctx context.Context, prefix string, recursive bool, opts ...Option) ([]ObjectVersion, error) {
+	versions, err := c.Client.ListObjectVersions(ctx, c.toKey(prefix), recursive, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		versions[i].Key = c.fromKey(versions[i].Key)
+	}
+	return versions, nil
+}
+
+func (c *rootedClient) Download(ctx context.Context, key string, writer io.WriterAt, opts ...Option) error {
+	return c.Client.Download(ctx, c.toKey(key), writer, opts...)
+}
+
+func (c *rootedClient) Upload(ctx context.Context, key string, body io.Reader, opts ...Option) error {
+	return c.Client.Upload(ctx, c.toKey(key), body, opts...)
+}
+
+func (c *rootedClient) CreateMultipartUpload(ctx context.Context, key string, opts ...Option) (string, error) {
+	return c.Client.CreateMultipartUpload(ctx, c.toKey(key), opts...)
+}
+
+func (c *rootedClient) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.ReadSeeker, opts ...Option) (CompletedPart, error) {
+	return c.Client.UploadPart(ctx, c.toKey(key), uploadID, partNumber, body, opts...)
+}
+
+func (c *rootedClient) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart, opts ...Option) error {
+	return c.Client.CompleteMultipartUpload(ctx, c.toKey(key), uploadID, parts, opts...)
+}
+
+func (c *rootedClient) AbortMultipartUpload(ctx context.Context, key, uploadID string, opts ...Option) error {
+	return c.Client.AbortMultipartUpload(ctx, c.toKey(key), uploadID, opts...)
+}
+
+func (c *rootedClient) ListMultipartUploads(ctx context.Context, prefix string, opts ...Option) ([]MultipartUpload, error) {
+	uploads, err := c.Client.ListMultipartUploads(ctx, c.toKey(prefix), opts...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range uploads {
+		uploads[i].Key = c.fromKey(uploads[i].Key)
+	}
+	return uploads, nil
+}
+
+func (c *rootedClient) CopyObject(ctx context.Context, srcKey, dstKey string, opts ...Option) error {
+	return c.Client.CopyObject(ctx, c.toKey(srcKey), c.toKey(dstKey), opts...)
+}
+
+func (c *rootedClient) RenameObject(ctx context.Context, oldKey, newKey string, opts ...Option) error {
+	return c.Client.RenameObject(ctx, c.toKey(oldKey), c.toKey(newKey), opts...)
+}
+
+func (c *rootedClient) ReplaceObjectMetadata(ctx context.Context, key string, metadata directory.ObjectMetadata, opts ...Option) error {
+	return c.Client.ReplaceObjectMetadata(ctx, c.toKey(key), metadata, opts...)
+}
+
+func (c *rootedClient) PresignGetObject(ctx context.Context, key string, expiry time.Duration, opts ...Option) (string, error) {
+	return c.Client.PresignGetObject(ctx, c.toKey(key), expiry, opts...)
+}
+
+func (c *rootedClient) PresignPutObject(ctx context.Context, key string, expiry time.Duration, opts ...Option) (string, error) {
+	return c.Client.PresignPutObject(ctx, c.toKey(key), expiry, opts...)
+}
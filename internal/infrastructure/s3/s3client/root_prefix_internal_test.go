@@ -0,0 +1,124 @@
+package s3client
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func newFakeRootedClient(t *testing.T, handler http.HandlerFunc) Client {
+	t.Helper()
+
+	base := newFakeS3Server(t, handler)
+	client := newClientImpl(base.client, base.bucket, connection_deck.EscapeCopySourceURL, base)
+	return withRootPrefix(client, "team-x/")
+}
+
+func TestWithRootPrefix(t *testing.T) {
+	t.Run("should return the client itself without root prefix", func(t *testing.T) {
+		// Given
+		client := &clientImpl{}
+
+		// When
+		res := withRootPrefix(client, "")
+
+		// Then
+		assert.Same(t, client, res)
+	})
+}
+
+func TestRootedClient_ListObjectsPage(t *testing.T) {
+	t.Run("should list the root prefix as the root of the bucket", func(t *testing.T) {
+		// Given
+		c := newFakeRootedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "team-x/", r.URL.Query().Get("prefix"))
+			_, _ = w.Write([]byte(`<ListBucketResult><IsTruncated>false</IsTruncated>` +
+				`<Prefix>team-x/</Prefix>` +
+				`<Contents><Key>team-x/a.txt</Key><Size>10</Size></Contents>` +
+				`<CommonPrefixes><Prefix>team-x/dir/</Prefix></CommonPrefixes>` +
+				`</ListBucketResult>`))
+		})
+
+		// When
+		res, err := c.ListObjectsPage(t.Context(), "", false, "")
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "", aws.ToString(res.Prefix))
+		require.Len(t, res.Contents, 1)
+		assert.Equal(t, "a.txt", aws.ToString(res.Contents[0].Key))
+		require.Len(t, res.CommonPrefixes, 1)
+		assert.Equal(t, "dir/", aws.ToString(res.CommonPrefixes[0].Prefix))
+	})
+
+	t.Run("should list a directory below the root prefix", func(t *testing.T) {
+		// Given
+		c := newFakeRootedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "team-x/dir/", r.URL.Query().Get("prefix"))
+			_, _ = w.Write([]byte(`<ListBucketResult><IsTruncated>false</IsTruncated>` +
+				`<Contents><Key>team-x/dir/b.txt</Key><Size>10</Size></Contents>` +
+				`</ListBucketResult>`))
+		})
+
+		// When
+		res, err := c.ListObjects(t.Context(), "dir/", false)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/b.txt"}, res.Keys)
+	})
+}
+
+func TestRootedClient_PutObject(t *testing.T) {
+	t.Run("should write the object below the root prefix", func(t *testing.T) {
+		// Given
+		var path string
+		c := newFakeRootedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+		})
+
+		// When
+		err := c.PutObject(t.Context(), "dir/file.txt", strings.NewReader("content"))
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, "/test-bucket/team-x/dir/file.txt", path)
+	})
+}
+
+func TestRootedClient_DeleteObjects(t *testing.T) {
+	t.Run("should delete the objects below the root prefix and return the errors by relative key", func(t *testing.T) {
+		// Given
+		var req fakeDeleteRequest
+		// the request is decoded by the server, then checked by the test
+		decodeErr := make(chan error, 1)
+		c := newFakeRootedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			err := xml.NewDecoder(r.Body).Decode(&req)
+			decodeErr <- err
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`<DeleteResult>` +
+				`<Error><Key>team-x/dir/locked.txt</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>` +
+				`</DeleteResult>`))
+		})
+
+		// When
+		res := c.DeleteObjects(t.Context(), []string{"dir/file.txt", "dir/locked.txt"})
+
+		// Then
+		require.NoError(t, <-decodeErr)
+		require.Len(t, req.Objects, 2)
+		assert.Equal(t, "team-x/dir/file.txt", req.Objects[0].Key)
+		assert.Equal(t, "team-x/dir/locked.txt", req.Objects[1].Key)
+		assert.Len(t, res, 1)
+		assert.ErrorContains(t, res["dir/locked.txt"], "AccessDenied")
+	})
+}
@@ -122,6 +122,32 @@ func TestS3Client(t *testing.T) {
 		})
 	})
 
+	t.Run("ListBuckets", func(t *testing.T) {
+		t.Parallel()
+
+		bucket := tu.FakeRandomBucketName()
+		otherBucket := tu.FakeRandomBucketName()
+		tu.SetupS3Bucket(ctx, t, testClient, bucket, nil)
+		tu.SetupS3Bucket(ctx, t, testClient, otherBucket, nil)
+
+		conn := tu.FakeAwsConnectionWithEndpoint(t, endpoint, bucket)
+		client := s3client.NewAwsClient(conn, func(o *s3.Options) {
+			o.Region = "us-east-1"
+		})
+
+		t.Run("should list all the buckets of the account", func(t *testing.T) {
+			t.Parallel()
+			// When
+			res, err := client.ListBuckets(ctx)
+
+			// Then
+			require.NoError(t, err)
+			assert.Contains(t, res, bucket)
+			assert.Contains(t, res, otherBucket)
+			assert.IsNonDecreasing(t, res)
+		})
+	})
+
 	t.Run("ListObjectsWithCallback", func(t *testing.T) {
 		t.Parallel()
 
@@ -196,6 +222,61 @@ func TestS3Client(t *testing.T) {
 		})
 	})
 
+	t.Run("MultipartUpload", func(t *testing.T) {
+		t.Parallel()
+
+		bucket := tu.FakeRandomBucketName()
+		tu.SetupS3Bucket(ctx, t, testClient, bucket, nil)
+
+		conn := tu.FakeAwsConnectionWithEndpoint(t, endpoint, bucket)
+		client := s3client.NewAwsClient(conn, func(o *s3.Options) {
+			o.Region = "us-east-1"
+		})
+
+		t.Run("should complete an upload sent in several parts", func(t *testing.T) {
+			// Given
+			key := "multipart.txt"
+			firstPart := strings.Repeat("a", 5*1024*1024) // the minimal size of a part, except the last one
+			lastPart := "the end"
+			uploadID, err := client.CreateMultipartUpload(ctx, key)
+			require.NoError(t, err)
+
+			// When
+			part1, err := client.UploadPart(ctx, key, uploadID, 1, strings.NewReader(firstPart))
+			require.NoError(t, err)
+			part2, err := client.UploadPart(ctx, key, uploadID, 2, strings.NewReader(lastPart))
+			require.NoError(t, err)
+			err = client.CompleteMultipartUpload(ctx, key, uploadID, []s3client.CompletedPart{part1, part2})
+
+			// Then
+			assert.NoError(t, err)
+			tu.AssertObjectContent(t, testClient, bucket, key, firstPart+lastPart)
+		})
+
+		t.Run("should list and abort an incomplete upload", func(t *testing.T) {
+			// Given
+			key := "incomplete/file.txt"
+			uploadID, err := client.CreateMultipartUpload(ctx, key)
+			require.NoError(t, err)
+			_, err = client.UploadPart(ctx, key, uploadID, 1, strings.NewReader("content"))
+			require.NoError(t, err)
+
+			// When
+			listed, err := client.ListMultipartUploads(ctx, "incomplete/")
+			require.NoError(t, err)
+			abortErr := client.AbortMultipartUpload(ctx, key, uploadID)
+			listedAfter, err := client.ListMultipartUploads(ctx, "incomplete/")
+			require.NoError(t, err)
+
+			// Then
+			require.Len(t, listed, 1)
+			assert.Equal(t, key, listed[0].Key)
+			assert.Equal(t, uploadID, listed[0].UploadID)
+			assert.NoError(t, abortErr)
+			assert.Empty(t, listedAfter)
+		})
+	})
+
 	t.Run("GetObjectGrants", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,120 @@
+package s3client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+var errNoCertificate = errors.New("no certificate found in the CA bundle")
+
+// newHTTPClient returns the HTTP client sending the requests of the connection, with its transport options.
+// Invalid options make all the requests fail with the reason, so that it is reported by the first operation.
+func newHTTPClient(conn *connection_deck.Connection) aws.HTTPClient {
+	transport := conn.Transport()
+	tlsConfig, err := newTLSConfig(transport)
+	if err != nil {
+		return failingHTTPClient{err: err}
+	}
+	proxy, err := newProxyFunc(transport)
+	if err != nil {
+		return failingHTTPClient{err: err}
+	}
+
+	client := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		tr.Proxy = proxy
+		if tlsConfig != nil {
+			tr.TLSClientConfig = tlsConfig
+		}
+		// the timeout bounds the wait for the server, not the transfer of the bodies,
+		// which lasts longer for the large objects
+		if transport.Timeout > 0 {
+			tr.TLSHandshakeTimeout = transport.Timeout
+			tr.ResponseHeaderTimeout = transport.Timeout
+		}
+	})
+	if transport.Timeout > 0 {
+		client = client.WithDialerOptions(func(d *net.Dialer) {
+			d.Timeout = transport.Timeout
+		})
+	}
+	return client
+}
+
+// newTLSConfig returns the TLS configuration trusting the CA bundle in addition to the system authorities,
+// nil when the default configuration fits.
+func newTLSConfig(transport connection_deck.TransportOptions) (*tls.Config, error) {
+	if transport.CABundle == "" && !transport.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: transport.InsecureSkipVerify, // nolint:gosec
+	}
+	if transport.CABundle == "" {
+		return config, nil
+	}
+
+	pem, err := readCABundle(transport.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errNoCertificate
+	}
+	config.RootCAs = pool
+	return config, nil
+}
+
+// readCABundle returns the PEM content of the bundle, given either as the content itself or as a file path.
+func readCABundle(bundle string) ([]byte, error) {
+	if strings.Contains(bundle, "-----BEGIN") {
+		return []byte(bundle), nil
+	}
+	pem, err := os.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("read the CA bundle: %w", err)
+	}
+	return pem, nil
+}
+
+// newProxyFunc returns the proxy of the connection, or the one of the environment when it has none.
+func newProxyFunc(transport connection_deck.TransportOptions) (func(*http.Request) (*url.URL, error), error) {
+	if transport.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyURL, err := url.Parse(transport.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, use http, https or socks5", proxyURL.Scheme)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// failingHTTPClient fails every request, when the transport options of the connection are invalid.
+type failingHTTPClient struct {
+	err error
+}
+
+func (c failingHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("invalid transport options: %w", c.err)
+}
@@ -0,0 +1,155 @@
+package s3client
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func newTransportTestClient(server string, useTLS bool, transport connection_deck.TransportOptions) Client {
+	conn := newTestConnection(
+		connection_deck.AsS3Like(server, useTLS),
+		connection_deck.WithTransport(transport),
+	)
+	return NewS3LikeClient(conn, func(o *s3.Options) {
+		o.RetryMaxAttempts = 1
+	})
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	server := strings.TrimPrefix(srv.URL, "https://")
+
+	t.Run("should reject a certificate of an unknown authority by default", func(t *testing.T) {
+		// Given
+		client := newTransportTestClient(server, true, connection_deck.TransportOptions{})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		require.Error(t, err)
+		assert.Contains(t, explainS3Error(err), "unknown authority")
+	})
+
+	t.Run("should trust the authorities of the CA bundle", func(t *testing.T) {
+		// Given
+		caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+		client := newTransportTestClient(server, true, connection_deck.TransportOptions{CABundle: caBundle})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("should accept any certificate when the verification is off", func(t *testing.T) {
+		// Given
+		client := newTransportTestClient(server, true, connection_deck.TransportOptions{InsecureSkipVerify: true})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail the requests when the CA bundle has no certificate", func(t *testing.T) {
+		// Given
+		client := newTransportTestClient(server, true, connection_deck.TransportOptions{
+			CABundle: "-----BEGIN CERTIFICATE-----\nnot a certificate\n-----END CERTIFICATE-----",
+		})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		assert.ErrorIs(t, err, errNoCertificate)
+	})
+
+	t.Run("should send the requests through the proxy of the connection", func(t *testing.T) {
+		// Given
+		var proxied atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Add(1)
+		}))
+		t.Cleanup(proxy.Close)
+		client := newTransportTestClient("s3.internal.example:9000", false, connection_deck.TransportOptions{
+			ProxyURL: proxy.URL,
+		})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), proxied.Load())
+	})
+
+	t.Run("should complete a response whose body lasts longer than the timeout", func(t *testing.T) {
+		// Given
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "6")
+			w.WriteHeader(http.StatusOK)
+			for _, chunk := range []string{"sl", "ow", "ly"} {
+				_, _ = w.Write([]byte(chunk))
+				w.(http.Flusher).Flush()
+				time.Sleep(100 * time.Millisecond)
+			}
+		}))
+		t.Cleanup(slow.Close)
+		client := newTransportTestClient(strings.TrimPrefix(slow.URL, "http://"), false, connection_deck.TransportOptions{
+			Timeout: 150 * time.Millisecond,
+		})
+
+		// When
+		res, err := client.GetObject(t.Context(), "file.txt")
+
+		// Then
+		require.NoError(t, err)
+		defer res.Body.Close() // nolint:errcheck
+		content, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "slowly", string(content))
+	})
+
+	t.Run("should fail a request when the server answers later than the timeout", func(t *testing.T) {
+		// Given
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(300 * time.Millisecond)
+		}))
+		t.Cleanup(slow.Close)
+		client := newTransportTestClient(strings.TrimPrefix(slow.URL, "http://"), false, connection_deck.TransportOptions{
+			Timeout: 50 * time.Millisecond,
+		})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		assert.ErrorContains(t, err, "timeout awaiting response headers")
+	})
+
+	t.Run("should fail the requests with an unsupported proxy scheme", func(t *testing.T) {
+		// Given
+		client := newTransportTestClient(server, true, connection_deck.TransportOptions{ProxyURL: "ftp://proxy:21"})
+
+		// When
+		_, err := client.HeadBucket(t.Context())
+
+		// Then
+		assert.ErrorContains(t, err, "unsupported proxy scheme")
+	})
+}
@@ -2,6 +2,7 @@ package s3client
 
 import (
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
@@ -31,5 +32,29 @@ func (r ListObjectsResult) IsEmpty() bool {
 	return len(r.Keys) == 0 || (len(r.Keys) == 1 && strings.HasSuffix(r.Keys[0], "/"))
 }
 
+// ObjectVersion is a version of an object in a versioned bucket, either a content or a delete marker.
+type ObjectVersion struct {
+	Key            string
+	VersionID      string
+	SizeBytes      int64
+	LastModified   time.Time
+	IsLatest       bool
+	IsDeleteMarker bool
+}
+
+// MultipartUpload is a multipart upload started in the bucket and neither completed nor aborted yet.
+type MultipartUpload struct {
+	Key       string
+	UploadID  string
+	Initiated time.Time
+}
+
+// CompletedPart is an uploaded part of a multipart upload, needed to complete it.
+type CompletedPart struct {
+	Number   int32
+	ETag     string
+	Checksum string
+}
+
 // Range represents a data range as described here: https://www.rfc-editor.org/rfc/rfc9110.html#name-range
 type Range struct{}
@@ -2,14 +2,21 @@ package s3_test
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsS3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	awsS3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3"
 	"github.com/thomas-marquis/s3-box/internal/tu"
@@ -231,6 +238,7 @@ func TestS3EventHandler(t *testing.T) {
 			fakeEventChan := make(chan event.Event, 1)
 			defer close(fakeEventChan)
 			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			expectTransferUpdates(mockBus)
 
 			done := make(chan struct{})
 			mockBus.EXPECT().
@@ -280,6 +288,7 @@ func TestS3EventHandler(t *testing.T) {
 			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
 
 			mockNotifRepo.EXPECT().NotifyError(gomock.Any()).Times(1)
+			expectTransferUpdates(mockBus)
 
 			done := make(chan struct{})
 			mockBus.EXPECT().
@@ -358,4 +367,871 @@ func TestS3EventHandler(t *testing.T) {
 			tu.AssertObjectContent(t, testClient, bucket, "mydir/new_file.txt", "")
 		})
 	})
+
+	t.Run("delete directory recursively", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should delete all the objects under the directory and report the progress", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "keep.txt"},
+				{Key: "mydir/"},
+				{Key: "mydir/file1.txt"},
+				{Key: "mydir/sub/file2.txt"},
+				{Key: "mydir/sub/deeper/file3.txt"},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var mydir *directory.Directory
+			rootDir := tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.IsLoaded(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithSubDirectory("mydir", tu.To(&mydir)))
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.DeleteProgress)
+					return ok
+				})).
+				MinTimes(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.DeleteSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, 4, pl.DeletedCount) &&
+						assert.Equal(t, "/mydir/", pl.Directory.Path().String())
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			evt, err := rootDir.RemoveSubDirectoryRecursively("mydir")
+			require.NoError(t, err)
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			tu.AssertObjectNotExists(t, testClient, bucket, "mydir/sub/deeper/file3.txt")
+			tu.AssertObjectNotExists(t, testClient, bucket, "mydir/")
+			tu.AssertObjectContent(t, testClient, bucket, "keep.txt", "")
+		})
+	})
+
+	t.Run("download directory", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should recreate the directory content locally", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "mydir/file1.txt", Body: strings.NewReader("one")},
+				{Key: "mydir/sub/file2.txt", Body: strings.NewReader("two")},
+				{Key: "other/file3.txt", Body: strings.NewReader("three")},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			mydir := tu.MakeDirectory(t, "mydir",
+				tu.WithRootParent(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId))
+			dstPath := t.TempDir()
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.DownloadProgress)
+					return ok
+				})).
+				MinTimes(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.DownloadSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, 2, pl.DownloadedCount) &&
+						assert.Equal(t, filepath.Join(dstPath, "mydir"), pl.DstPath)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- mydir.Download(dstPath, directory.MaterializeReplace)
+
+			// Then
+			tu.AssertEventually(t, done)
+			content, err := os.ReadFile(filepath.Join(dstPath, "mydir", "sub", "file2.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "two", string(content))
+			assert.NoFileExists(t, filepath.Join(dstPath, "mydir", "file3.txt"))
+		})
+	})
+
+	t.Run("search", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should stream the files matching the query under the directory", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "mydir/sales.csv", Body: strings.NewReader("one")},
+				{Key: "mydir/sub/q1.csv", Body: strings.NewReader("two")},
+				{Key: "mydir/readme.md", Body: strings.NewReader("three")},
+				{Key: "other/q2.csv", Body: strings.NewReader("four")},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			mydir := tu.MakeDirectory(t, "mydir",
+				tu.WithRootParent(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId))
+			evt, err := mydir.Search(directory.SearchQuery{Pattern: "*.csv"})
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			var hits []directory.SearchHit
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					pl, ok := evt.Payload().(directory.SearchProgress)
+					if ok {
+						hits = append(hits, pl.Hits...)
+					}
+					return ok
+				})).
+				MinTimes(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.SearchSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, 2, pl.HitCount) &&
+						assert.Equal(t, 3, pl.ScannedCount)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			require.Len(t, hits, 2)
+			assert.Equal(t, "/mydir/sales.csv", hits[0].FullPath())
+			assert.Equal(t, "/mydir/sub/q1.csv", hits[1].FullPath())
+		})
+	})
+
+	t.Run("compute size", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should sum the size of the objects under the directory", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "mydir/file1.txt", Body: strings.NewReader("one")},
+				{Key: "mydir/sub/file2.txt", Body: strings.NewReader("three")},
+				{Key: "other/file3.txt", Body: strings.NewReader("four")},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			mydir := tu.MakeDirectory(t, "mydir",
+				tu.WithRootParent(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId))
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.ComputeSizeProgress)
+					return ok
+				})).
+				MinTimes(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.ComputeSizeSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, directory.SizeStatsEntry{Bytes: 8, ObjectCount: 2}, pl.Stats.Total) &&
+						assert.Equal(t, directory.SizeStatsEntry{Bytes: 5, ObjectCount: 1}, pl.Stats.BySubDirectory["sub"]) &&
+						assert.Equal(t, "/mydir/sub/file2.txt", pl.Stats.LargestFiles[0].FullPath)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- mydir.ComputeSize()
+
+			// Then
+			tu.AssertEventually(t, done)
+		})
+	})
+
+	t.Run("move selection", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should move the selected files and directories into the destination", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "mydir/file1.txt", Body: strings.NewReader("one")},
+				{Key: "mydir/sub/file2.txt", Body: strings.NewReader("two")},
+				{Key: "top.txt", Body: strings.NewReader("top")},
+				{Key: "dst/", Body: strings.NewReader("")},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var (
+				mydir, dst *directory.Directory
+				top        *directory.File
+			)
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithFileTo("top.txt", &top),
+				tu.WithSubDirectory("mydir", tu.To(&mydir)),
+				tu.WithSubDirectory("dst", tu.To(&dst)))
+
+			sel := directory.NewSelection(tu.FakeAwsConnectionId)
+			require.NoError(t, sel.AddDirectory(mydir))
+			require.NoError(t, sel.AddFile(top))
+			evt, err := sel.MoveTo(dst, directory.MaterializeReplace)
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockConnRepo.EXPECT().
+				Get(gomock.AssignableToTypeOf(tu.CtxType)).
+				Return(fakeDeck, nil).
+				AnyTimes()
+
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.SelectionProgress)
+					return ok
+				})).
+				MinTimes(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.CopySelectionSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, 3, pl.CopiedCount) && assert.True(t, pl.Move)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			assert.ElementsMatch(t,
+				[]string{"dst/", "dst/mydir/file1.txt", "dst/mydir/sub/file2.txt", "dst/top.txt"},
+				tu.ListKeys(t, testClient, bucket, ""))
+		})
+	})
+
+	t.Run("copy selection to another connection", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should stream the selected objects into the other bucket", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			srcBucket := tu.FakeRandomBucketName()
+			dstBucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, srcBucket, []tu.FakeS3Object{
+				{
+					Key:         "mydir/file1.txt",
+					Body:        strings.NewReader("one"),
+					ContentType: "text/plain",
+					Metadata:    map[string]string{"owner": "team-x"},
+				},
+				{Key: "mydir/sub/file2.txt", Body: strings.NewReader("two")},
+			})
+			tu.SetupS3Bucket(ctx, t, testClient, dstBucket, []tu.FakeS3Object{
+				{Key: "backup/", Body: strings.NewReader("")},
+			})
+			dstConnID := connection_deck.NewConnectionID()
+			fakeDeck := tu.FakeDeckWithConnections(t,
+				tu.FakeAwsConnectionWithEndpoint(t, endpoint, srcBucket),
+				tu.FakeAwsConnectionWithCustomID(t, dstConnID, endpoint, dstBucket))
+
+			var mydir, backup *directory.Directory
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithSubDirectory("mydir", tu.To(&mydir)))
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(dstConnID),
+				tu.WithSubDirectory("backup", tu.To(&backup)))
+
+			evt, err := mydir.CopyTo(backup, directory.MaterializeReplace)
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockConnRepo.EXPECT().
+				Get(gomock.AssignableToTypeOf(tu.CtxType)).
+				Return(fakeDeck, nil).
+				AnyTimes()
+
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.SelectionProgress)
+					return ok
+				})).
+				MinTimes(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.CopySelectionSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, 2, pl.CopiedCount) && assert.False(t, pl.Move)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			assert.ElementsMatch(t,
+				[]string{"backup/", "backup/mydir/file1.txt", "backup/mydir/sub/file2.txt"},
+				tu.ListKeys(t, testClient, dstBucket, ""))
+			tu.AssertObjectContent(t, testClient, dstBucket, "backup/mydir/sub/file2.txt", "two")
+			assert.Len(t, tu.ListKeys(t, testClient, srcBucket, ""), 2)
+			headRes, err := testClient.HeadObject(ctx, &awsS3.HeadObjectInput{
+				Bucket: aws.String(dstBucket),
+				Key:    aws.String("backup/mydir/file1.txt"),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "text/plain", aws.ToString(headRes.ContentType))
+			assert.Equal(t, map[string]string{"owner": "team-x"}, headRes.Metadata)
+		})
+
+		t.Run("should refuse to copy into a read-only connection", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			dstConnID := connection_deck.NewConnectionID()
+			dstConn := tu.FakeAwsConnectionWithCustomID(t, dstConnID, endpoint, tu.FakeRandomBucketName())
+			dstConn.SetReadOnly(true)
+			fakeDeck := tu.FakeDeckWithConnections(t,
+				tu.FakeAwsConnectionWithEndpoint(t, endpoint, tu.FakeRandomBucketName()),
+				dstConn)
+
+			var file *directory.File
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithFileTo("file.txt", &file))
+			dstRoot := tu.MakeDirectory(t, "", tu.AsRoot(), tu.WithConnectionId(dstConnID))
+
+			evt, err := file.CopyTo(dstRoot, directory.MaterializeReplace)
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockNotifRepo.EXPECT().NotifyError(gomock.Any()).Times(1)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.CopySelectionFailed)
+					if !ok {
+						return false
+					}
+					res := assert.ErrorIs(t, pl.Err, directory.ErrReadOnly)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+		})
+	})
+	t.Run("paste", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should list the objects to paste relatively to their parent directory", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "src/mydir/file1.txt", Body: strings.NewReader("one")},
+				{Key: "src/mydir/sub/file2.txt", Body: strings.NewReader("two")},
+				{Key: "dst/", Body: strings.NewReader("")},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var mydir, dst *directory.Directory
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithSubDirectory("src", tu.WithSubDirectory("mydir", tu.To(&mydir))),
+				tu.WithSubDirectory("dst", tu.To(&dst)))
+
+			sel := directory.NewSelection(tu.FakeAwsConnectionId)
+			require.NoError(t, sel.AddDirectory(mydir))
+			evt, err := sel.PreparePaste(dst, false)
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.PastePreviewSucceeded)
+					if !ok {
+						return false
+					}
+					var keys []string
+					for _, obj := range pl.Objects {
+						keys = append(keys, obj.RelativeKey)
+					}
+					res := assert.ElementsMatch(t, []string{"mydir/file1.txt", "mydir/sub/file2.txt"}, keys)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+		})
+
+		t.Run("should keep the existing objects with the skip strategy", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "mydir/file1.txt", Body: strings.NewReader("new")},
+				{Key: "mydir/file2.txt", Body: strings.NewReader("new")},
+				{Key: "dst/mydir/file1.txt", Body: strings.NewReader("old")},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var mydir, dst *directory.Directory
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithSubDirectory("mydir", tu.To(&mydir)),
+				tu.WithSubDirectory("dst", tu.To(&dst)))
+
+			evt, err := mydir.MoveTo(dst, directory.MaterializeStrategy(directory.MaterializeSkip))
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockConnRepo.EXPECT().
+				Get(gomock.AssignableToTypeOf(tu.CtxType)).
+				Return(fakeDeck, nil).
+				AnyTimes()
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.SelectionProgress)
+					return ok
+				})).
+				AnyTimes()
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.CopySelectionSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, 1, pl.CopiedCount) && assert.Equal(t, 1, pl.SkippedCount)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			assert.ElementsMatch(t,
+				[]string{"mydir/file1.txt", "dst/mydir/file1.txt", "dst/mydir/file2.txt"},
+				tu.ListKeys(t, testClient, bucket, ""))
+		})
+	})
+	t.Run("file metadata", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should replace the content type and the user metadata in place", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "data.json", Body: strings.NewReader(`{"a": 1}`)},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var file *directory.File
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithFileTo("data.json", &file))
+
+			evt, err := file.UpdateMetadata(directory.ObjectMetadata{}.WithEditableFields(
+				"application/json", "no-cache", map[string]string{"owner": "team-data"}))
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockConnRepo.EXPECT().
+				Get(gomock.AssignableToTypeOf(tu.CtxType)).
+				Return(fakeDeck, nil).
+				AnyTimes()
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					// Then
+					pl, ok := evt.Payload().(directory.UpdateFileMetadataSucceeded)
+					if !ok {
+						return false
+					}
+					res := assert.Equal(t, "application/json", pl.Metadata.ContentType) &&
+						assert.Equal(t, "no-cache", pl.Metadata.CacheControl) &&
+						assert.Equal(t, map[string]string{"owner": "team-data"}, pl.Metadata.UserMetadata)
+					close(done)
+					return res
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			tu.AssertObjectContent(t, testClient, bucket, "data.json", `{"a": 1}`)
+		})
+	})
+	t.Run("file tags", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should replace the tags of the file", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "data.json", Body: strings.NewReader(`{"a": 1}`)},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var file *directory.File
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithFileTo("data.json", &file))
+
+			evt, err := file.UpdateTags(directory.ObjectTags{"cost-center": "42"})
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockConnRepo.EXPECT().
+				Get(gomock.AssignableToTypeOf(tu.CtxType)).
+				Return(fakeDeck, nil).
+				AnyTimes()
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.UpdateFileTagsSucceeded)
+					if ok {
+						close(done)
+					}
+					return ok
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			res, err := testClient.GetObjectTagging(ctx, &awsS3.GetObjectTaggingInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String("data.json"),
+			})
+			require.NoError(t, err)
+			require.Len(t, res.TagSet, 1)
+			assert.Equal(t, "cost-center", aws.ToString(res.TagSet[0].Key))
+			assert.Equal(t, "42", aws.ToString(res.TagSet[0].Value))
+		})
+	})
+
+	t.Run("file share link", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("should create a link allowing to download the file", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, []tu.FakeS3Object{
+				{Key: "data.json", Body: strings.NewReader(`{"a": 1}`)},
+			})
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var file *directory.File
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithFileTo("data.json", &file))
+
+			evt, err := file.CreateShareLink(directory.ShareLinkDownload, time.Hour)
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			linkChan := make(chan string, 1)
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					pl, ok := evt.Payload().(directory.CreateFileShareLinkSucceeded)
+					if ok {
+						linkChan <- pl.URL
+					}
+					return ok
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			var link string
+			select {
+			case link = <-linkChan:
+			case <-time.After(5 * time.Second):
+				t.Fatal("share link not created")
+			}
+			resp, err := http.Get(link)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"a": 1}`, string(body))
+		})
+	})
+
+	t.Run("file versions", func(t *testing.T) {
+		t.Parallel()
+
+		setupVersionedBucket := func(t *testing.T, bucket string) (previousVersionID string) {
+			t.Helper()
+			tu.SetupS3Bucket(ctx, t, testClient, bucket, nil)
+			_, err := testClient.PutBucketVersioning(ctx, &awsS3.PutBucketVersioningInput{
+				Bucket: aws.String(bucket),
+				VersioningConfiguration: &awsS3types.VersioningConfiguration{
+					Status: awsS3types.BucketVersioningStatusEnabled,
+				},
+			})
+			require.NoError(t, err)
+			out, err := testClient.PutObject(ctx, &awsS3.PutObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String("data.json"),
+				Body:   strings.NewReader(`{"version": 1}`),
+			})
+			require.NoError(t, err)
+			_, err = testClient.DeleteObject(ctx, &awsS3.DeleteObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String("data.json"),
+			})
+			require.NoError(t, err)
+			return aws.ToString(out.VersionId)
+		}
+
+		t.Run("should load the deleted files when asked", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			setupVersionedBucket(t, bucket)
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			rootDir := tu.MakeDirectory(t, "", tu.AsRoot(), tu.WithConnectionId(tu.FakeAwsConnectionId))
+			evt, err := rootDir.Load(directory.WithDeletedFiles())
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+
+			filesChan := make(chan []*directory.File, 1)
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					pl, ok := evt.Payload().(directory.LoadSucceeded)
+					if ok {
+						filesChan <- pl.Files
+					}
+					return ok
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			var files []*directory.File
+			select {
+			case files = <-filesChan:
+			case <-time.After(5 * time.Second):
+				t.Fatal("directory not loaded")
+			}
+			require.Len(t, files, 1)
+			assert.Equal(t, directory.FileName("data.json"), files[0].Name())
+			assert.True(t, files[0].IsDeleted())
+		})
+
+		t.Run("should restore a deleted file from its previous version", func(t *testing.T) {
+			t.Parallel()
+			// Given
+			bucket := tu.FakeRandomBucketName()
+			versionID := setupVersionedBucket(t, bucket)
+			fakeDeck := tu.FakeDeckWithAwsConnection(t, endpoint, bucket)
+
+			var file *directory.File
+			tu.MakeDirectory(t, "",
+				tu.AsRoot(),
+				tu.WithConnectionId(tu.FakeAwsConnectionId),
+				tu.WithFileTo("data.json", &file))
+
+			evt, err := file.RestoreVersion(directory.FileVersion{ID: versionID, SizeBytes: 14})
+			require.NoError(t, err)
+
+			fakeEventChan := make(chan event.Event, 1)
+			defer close(fakeEventChan)
+			mockBus, mockConnRepo, mockNotifRepo := setupMocks(t, fakeDeck, fakeEventChan)
+			mockConnRepo.EXPECT().
+				Get(gomock.AssignableToTypeOf(tu.CtxType)).
+				Return(fakeDeck, nil).
+				AnyTimes()
+
+			done := make(chan struct{})
+			mockBus.EXPECT().
+				Publish(gomock.Cond(func(evt event.Event) bool {
+					_, ok := evt.Payload().(directory.RestoreFileVersionSucceeded)
+					if ok {
+						close(done)
+					}
+					return ok
+				})).
+				Times(1)
+
+			s3.NewS3EventHandler(mockConnRepo, mockBus, mockNotifRepo).Listen()
+
+			// When
+			fakeEventChan <- evt
+
+			// Then
+			tu.AssertEventually(t, done)
+			res, err := testClient.GetObject(ctx, &awsS3.GetObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String("data.json"),
+			})
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"version": 1}`, string(body))
+		})
+	})
 }
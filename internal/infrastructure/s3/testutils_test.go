@@ -5,6 +5,7 @@ import (
 
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
 	"github.com/thomas-marquis/s3-box/internal/tu"
 	mocks_connection_deck "github.com/thomas-marquis/s3-box/mocks/connection_deck"
 	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
@@ -26,10 +27,22 @@ func setupMocks(t *testing.T, deck *connection_deck.Deck, events chan event.Even
 		Subscribe().
 		Return(event.NewSubscriber(events))
 
+	// the handlers read the deck to create the client, then to check the connection is writable
 	mockConnRepo.EXPECT().
 		Get(gomock.AssignableToTypeOf(tu.CtxType)).
 		Return(deck, nil).
-		Times(1)
+		MinTimes(1)
 
 	return mockBus, mockConnRepo, mockNotifRepo
 }
+
+// expectTransferUpdates accepts the updates published by the transfer queue.
+// It must be called before the other expectations on Publish, so that they don't receive these updates.
+func expectTransferUpdates(mockBus *mocks_event.MockBus) {
+	mockBus.EXPECT().
+		Publish(gomock.Cond(func(evt event.Event) bool {
+			_, ok := evt.Payload().(transfer.JobUpdated)
+			return ok
+		})).
+		AnyTimes()
+}
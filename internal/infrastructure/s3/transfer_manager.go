@@ -0,0 +1,478 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/domain/notification"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+const (
+	transferProgressInterval = 250 * time.Millisecond
+)
+
+// transferDone is called once a job is finished, with a nil error on success.
+type transferDone func(job transfer.Job, err error)
+
+type runningTransfer struct {
+	cancel      context.CancelFunc
+	transferred atomic.Uint64
+	// restart is set when the job is resumed while this run, stopped by a pause, isn't over yet
+	restart bool
+}
+
+// transferManager runs the queued transfer jobs in the background,
+// reporting their progress and retrying them on failure.
+type transferManager struct {
+	mu         sync.Mutex
+	queue      *transfer.Queue
+	running    map[transfer.JobID]*runningTransfer
+	onDone     map[transfer.JobID]transferDone
+	retryTimer *time.Timer
+
+	bus           event.Bus
+	notifier      notification.Repository
+	clientFactory s3client.Factory
+}
+
+func newTransferManager(bus event.Bus, notifier notification.Repository, clientFactory s3client.Factory) *transferManager {
+	return &transferManager{
+		queue:         transfer.NewQueue(transfer.DefaultMaxConcurrency),
+		running:       make(map[transfer.JobID]*runningTransfer),
+		onDone:        make(map[transfer.JobID]transferDone),
+		bus:           bus,
+		notifier:      notifier,
+		clientFactory: clientFactory,
+	}
+}
+
+// enqueue adds a job to the queue, onDone being called when it is finished.
+func (m *transferManager) enqueue(job transfer.Job, onDone transferDone) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.queue.Add(job); err != nil {
+		onDone(job, err)
+		return
+	}
+	m.onDone[job.ID] = onDone
+	m.publish(job)
+	m.scheduleLocked()
+}
+
+func (m *transferManager) handlePause(e event.Event) {
+	pl := e.Payload().(transfer.PauseTriggered)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.queue.Pause(pl.JobID)
+	if err != nil {
+		m.notifier.NotifyError(fmt.Errorf("failed pausing transfer: %w", err))
+		return
+	}
+	if r, ok := m.running[job.ID]; ok {
+		r.cancel()
+	}
+	m.publish(job)
+	m.scheduleLocked()
+}
+
+func (m *transferManager) handleResume(e event.Event) {
+	pl := e.Payload().(transfer.ResumeTriggered)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.queue.Resume(pl.JobID)
+	if err != nil {
+		m.notifier.NotifyError(fmt.Errorf("failed resuming transfer: %w", err))
+		return
+	}
+	m.publish(job)
+	m.scheduleLocked()
+}
+
+func (m *transferManager) handleCancel(e event.Event) {
+	pl := e.Payload().(transfer.CancelTriggered)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.queue.Cancel(pl.JobID)
+	if err != nil {
+		m.notifier.NotifyError(fmt.Errorf("failed canceling transfer: %w", err))
+		return
+	}
+	m.publish(job)
+	if r, ok := m.running[job.ID]; ok {
+		// the job will be finished once the transfer is actually stopped
+		r.cancel()
+		return
+	}
+	if job.Multipart != nil {
+		go m.abortUpload(job)
+	}
+	m.finishLocked(job, directory.ErrCanceled)
+	m.scheduleLocked()
+}
+
+func (m *transferManager) handleClearFinished(e event.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := m.queue.ClearFinished()
+	m.bus.Publish(e.NewFollowup(transfer.ClearFinishedSucceeded{JobIDs: ids}))
+}
+
+func (m *transferManager) handleRestore(e event.Event) {
+	pl := e.Payload().(transfer.RestoreTriggered)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range pl.Jobs {
+		if err := m.queue.Restore(job); err != nil {
+			m.notifier.NotifyError(fmt.Errorf("failed restoring transfer: %w", err))
+			continue
+		}
+		restored, _ := m.queue.Get(job.ID)
+		m.publish(restored)
+	}
+}
+
+func (m *transferManager) handleSetConcurrency(e event.Event) {
+	pl := e.Payload().(transfer.SetConcurrencyTriggered)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queue.SetMaxConcurrency(pl.MaxConcurrency)
+	m.scheduleLocked()
+}
+
+func (m *transferManager) schedule() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scheduleLocked()
+}
+
+// scheduleLocked starts as many jobs as allowed, then plans a new scheduling for the jobs waiting for a retry.
+func (m *transferManager) scheduleLocked() {
+	for {
+		job, ok := m.queue.Next()
+		if !ok {
+			break
+		}
+		m.publish(job)
+		if r, ok := m.running[job.ID]; ok {
+			// the run stopped by the pause of the job still holds the key and the multipart upload,
+			// the job starts over once it is over
+			r.restart = true
+			continue
+		}
+		m.start(job)
+	}
+
+	if m.retryTimer != nil {
+		m.retryTimer.Stop()
+		m.retryTimer = nil
+	}
+	if delay, ok := m.queue.NextRetryDelay(); ok {
+		m.retryTimer = time.AfterFunc(delay, m.schedule)
+	}
+}
+
+func (m *transferManager) start(job transfer.Job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &runningTransfer{cancel: cancel}
+	r.transferred.Store(job.TransferredBytes)
+	m.running[job.ID] = r
+
+	go func() {
+		stopReporting := m.reportProgress(job.ID, r)
+		err := m.transfer(ctx, job, &r.transferred)
+		stopReporting()
+		m.end(job, r, err)
+	}()
+}
+
+// end records the outcome of a transfer, unless it was stopped on purpose.
+// A job paused then resumed before the end of its stopped run starts over from there.
+func (m *transferManager) end(job transfer.Job, r *runningTransfer, transferErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r.cancel()
+	delete(m.running, job.ID)
+
+	current, err := m.queue.Get(job.ID)
+	if err != nil {
+		return
+	}
+
+	switch current.Status {
+	case transfer.StatusRunning:
+		if r.restart && transferErr != nil {
+			// the failure of a run stopped by a pause doesn't matter, the job was resumed since then
+			m.start(current)
+			break
+		}
+		switch {
+		case transferErr == nil:
+			current, err = m.queue.Succeed(job.ID)
+		case isPermanentTransferError(transferErr):
+			current, err = m.queue.FailPermanently(job.ID, transferErr)
+		default:
+			current, err = m.queue.Fail(job.ID, transferErr)
+		}
+		if err != nil {
+			m.notifier.NotifyError(err)
+			return
+		}
+		m.publish(current)
+		if current.Status.IsFinished() {
+			m.finishLocked(current, transferErr)
+		}
+	case transfer.StatusCanceled:
+		if current.Multipart != nil {
+			go m.abortUpload(current)
+		}
+		m.finishLocked(current, directory.ErrCanceled)
+	}
+
+	m.scheduleLocked()
+}
+
+func (m *transferManager) finishLocked(job transfer.Job, err error) {
+	if err != nil && !errors.Is(err, directory.ErrCanceled) {
+		m.notifier.NotifyError(fmt.Errorf("failed transferring %s: %w", job.Key, err))
+	}
+	if onDone, ok := m.onDone[job.ID]; ok {
+		delete(m.onDone, job.ID)
+		onDone(job, err)
+	}
+}
+
+// reportProgress publishes the progress of a running job at a regular interval, until the returned function is called.
+func (m *transferManager) reportProgress(id transfer.JobID, r *runningTransfer) func() {
+	stop := make(chan struct{})
+	ticker := time.NewTicker(transferProgressInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				m.mu.Lock()
+				if job, err := m.queue.UpdateProgress(id, r.transferred.Load()); err == nil {
+					m.publish(job)
+				}
+				m.mu.Unlock()
+			}
+		}
+	}()
+
+	return func() { close(stop) }
+}
+
+func (m *transferManager) transfer(ctx context.Context, job transfer.Job, transferred *atomic.Uint64) error {
+	client, err := m.clientFactory.Get(ctx, job.ConnectionID)
+	if err != nil {
+		return err
+	}
+	// the connection may have switched to another bucket or root prefix since the job was queued,
+	// its key and its multipart upload wouldn't mean anything there
+	if !job.Targets(client.Bucket(), client.RootPrefix()) {
+		return fmt.Errorf("%w: queued for the bucket %s and the root prefix %q", transfer.ErrTargetChanged, job.Bucket, job.RootPrefix)
+	}
+
+	switch job.Kind {
+	case transfer.KindUpload:
+		localFile, err := os.Open(job.LocalPath)
+		if err != nil {
+			return err
+		}
+		defer u.SkipD(localFile.Close)
+		if job.TotalBytes >= transfer.MultipartThreshold {
+			return m.uploadParts(ctx, client, job, localFile, transferred)
+		}
+		return client.Upload(ctx, job.Key, &progressReader{reader: localFile, transferred: transferred})
+
+	case transfer.KindDownload:
+		// the local file is only replaced once the download is complete, a canceled one leaves it untouched
+		return downloadToFile(job.LocalPath, func(writer io.WriterAt) error {
+			return client.Download(ctx, job.Key, &progressWriterAt{writer: writer, transferred: transferred},
+				s3client.WithVersionID(job.VersionID))
+		})
+
+	default:
+		return fmt.Errorf("unknown transfer kind %s", job.Kind)
+	}
+}
+
+// uploadParts sends the local file in several parts, saving the state of the upload after each of them
+// so that it can be resumed from the last sent part, even after a restart of the application.
+// The upload starts over when the local file was modified since the previous attempt.
+func (m *transferManager) uploadParts(ctx context.Context, client s3client.Client, job transfer.Job, localFile *os.File, transferred *atomic.Uint64) error {
+	info, err := localFile.Stat()
+	if err != nil {
+		return err
+	}
+	fingerprint := transfer.Fingerprint{SizeBytes: info.Size(), ModTime: info.ModTime()}
+
+	var state transfer.MultipartUpload
+	if job.Multipart != nil && job.Multipart.Fingerprint.Equal(fingerprint) {
+		state = job.Multipart.Clone()
+	} else {
+		if job.Multipart != nil {
+			u.Skip(client.AbortMultipartUpload(ctx, job.Key, job.Multipart.UploadID))
+		}
+		uploadID, err := client.CreateMultipartUpload(ctx, job.Key)
+		if err != nil {
+			return err
+		}
+		state = transfer.MultipartUpload{
+			UploadID:      uploadID,
+			PartSizeBytes: transfer.PartSize(uint64(info.Size())),
+			Fingerprint:   fingerprint,
+		}
+		m.checkpoint(job.ID, &state)
+	}
+
+	for offset := state.UploadedBytes(); offset < uint64(info.Size()); {
+		partSize := min(state.PartSizeBytes, info.Size()-int64(offset))
+		body := &partReader{
+			section:        io.NewSectionReader(localFile, int64(offset), partSize),
+			uploadedBefore: offset,
+			transferred:    transferred,
+		}
+		part, err := client.UploadPart(ctx, job.Key, state.UploadID, state.NextPartNumber(), body)
+		if err != nil {
+			if isNotFoundError(err) && ctx.Err() == nil {
+				// the upload was aborted or expired on the server side: not a permanent failure,
+				// the next attempt starts over
+				m.checkpoint(job.ID, nil)
+				return fmt.Errorf("the multipart upload doesn't exist anymore in the bucket: %s", err)
+			}
+			return err
+		}
+		state.Parts = append(state.Parts, transfer.Part{
+			Number:    part.Number,
+			ETag:      part.ETag,
+			Checksum:  part.Checksum,
+			SizeBytes: partSize,
+		})
+		m.checkpoint(job.ID, &state)
+		offset += uint64(partSize)
+	}
+
+	parts := make([]s3client.CompletedPart, len(state.Parts))
+	for i, p := range state.Parts {
+		parts[i] = s3client.CompletedPart{Number: p.Number, ETag: p.ETag, Checksum: p.Checksum}
+	}
+	return client.CompleteMultipartUpload(ctx, job.Key, state.UploadID, parts)
+}
+
+// checkpoint saves the state of a multipart upload in the job, or forgets it when nil.
+func (m *transferManager) checkpoint(id transfer.JobID, state *transfer.MultipartUpload) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.queue.Checkpoint(id, state)
+	if err != nil {
+		return
+	}
+	m.publish(job)
+}
+
+// abortUpload deletes from the bucket the parts already sent for a canceled upload.
+func (m *transferManager) abortUpload(job transfer.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	client, err := m.clientFactory.Get(ctx, job.ConnectionID)
+	if err == nil {
+		err = client.AbortMultipartUpload(ctx, job.Key, job.Multipart.UploadID)
+	}
+	if err != nil {
+		m.notifier.NotifyError(fmt.Errorf("failed aborting the upload of %s, its parts are still stored in the bucket: %w",
+			job.Key, err))
+	}
+}
+
+// resumableUploadIDs returns the IDs of the multipart uploads that a job can still complete.
+func (m *transferManager) resumableUploadIDs() map[string]bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make(map[string]bool)
+	for _, job := range m.queue.Jobs() {
+		if job.IsInterruptedUpload() {
+			ids[job.Multipart.UploadID] = true
+		}
+	}
+	return ids
+}
+
+func (m *transferManager) publish(job transfer.Job) {
+	m.bus.Publish(event.New(transfer.JobUpdated{Job: job}))
+}
+
+// isPermanentTransferError returns true for the errors that retrying won't fix.
+func isPermanentTransferError(err error) bool {
+	var pathErr *fs.PathError
+	return isNotFoundError(err) || errors.As(err, &pathErr) || errors.Is(err, transfer.ErrTargetChanged)
+}
+
+// progressReader counts the bytes read from the local file to upload.
+type progressReader struct {
+	reader      io.Reader
+	transferred *atomic.Uint64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.transferred.Add(uint64(n))
+	return n, err
+}
+
+// partReader reads a part of the local file to upload, reporting the progress from the bytes already sent.
+// It is seekable so that the SDK can sign the payload and retry the request.
+type partReader struct {
+	section        *io.SectionReader
+	uploadedBefore uint64
+	transferred    *atomic.Uint64
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	n, err := r.section.Read(p)
+	pos, _ := r.section.Seek(0, io.SeekCurrent)
+	r.transferred.Store(r.uploadedBefore + uint64(pos))
+	return n, err
+}
+
+func (r *partReader) Seek(offset int64, whence int) (int64, error) {
+	return r.section.Seek(offset, whence)
+}
+
+// progressWriterAt counts the bytes written in the local file being downloaded.
+type progressWriterAt struct {
+	writer      io.WriterAt
+	transferred *atomic.Uint64
+}
+
+func (w *progressWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	n, err := w.writer.WriteAt(p, off)
+	w.transferred.Add(uint64(n))
+	return n, err
+}
@@ -0,0 +1,507 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3/s3client"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
+	mocks_notification "github.com/thomas-marquis/s3-box/mocks/notification"
+	"go.uber.org/mock/gomock"
+)
+
+type fakeClientFactory struct {
+	client s3client.Client
+}
+
+func (f *fakeClientFactory) Get(context.Context, connection_deck.ConnectionID) (s3client.Client, error) {
+	return f.client, nil
+}
+
+func (f *fakeClientFactory) Remove(connection_deck.ConnectionID) {}
+
+func (f *fakeClientFactory) New(*connection_deck.Connection) s3client.Client {
+	return f.client
+}
+
+// fakeBucketClient is the base of the fake clients run by the transfer manager, which checks their bucket.
+type fakeBucketClient struct {
+	s3client.Client
+	bucket     string
+	rootPrefix string
+}
+
+func (c fakeBucketClient) Bucket() string {
+	return c.bucket
+}
+
+func (c fakeBucketClient) RootPrefix() string {
+	return c.rootPrefix
+}
+
+func TestTransferManager_Enqueue(t *testing.T) {
+	client := &fakeDownloadClient{
+		fakeBucketClient: fakeBucketClient{bucket: tu.FakeAwsBucketName},
+		contents: map[string]string{
+			"mydir/file.txt": "content",
+		},
+	}
+
+	setup := func(t *testing.T) (*transferManager, *mocks_notification.MockRepository) {
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+		mockBus.EXPECT().Publish(gomock.Any()).AnyTimes()
+		return newTransferManager(mockBus, mockNotifier, &fakeClientFactory{client: client}), mockNotifier
+	}
+
+	t.Run("should run the job and call back on success", func(t *testing.T) {
+		// Given
+		m, _ := setup(t)
+		localPath := filepath.Join(t.TempDir(), "file.txt")
+		job := transfer.NewDownloadJob(tu.FakeAwsConnectionId, "mydir/file.txt", "", localPath, 7)
+
+		done := make(chan struct{})
+		var (
+			res    transfer.Job
+			resErr error
+		)
+
+		// When
+		m.enqueue(job, func(j transfer.Job, err error) {
+			res, resErr = j, err
+			close(done)
+		})
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.NoError(t, resErr)
+		assert.Equal(t, transfer.StatusSucceeded, res.Status)
+		assert.Equal(t, uint64(7), res.TransferredBytes)
+		content, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+	})
+
+	t.Run("should fail without retrying when the object is missing", func(t *testing.T) {
+		// Given
+		m, mockNotifier := setup(t)
+		mockNotifier.EXPECT().NotifyError(gomock.Any()).Times(1)
+		localPath := filepath.Join(t.TempDir(), "missing.txt")
+		job := transfer.NewDownloadJob(tu.FakeAwsConnectionId, "mydir/missing.txt", "", localPath, 7)
+
+		done := make(chan struct{})
+		var (
+			res    transfer.Job
+			resErr error
+		)
+
+		// When
+		m.enqueue(job, func(j transfer.Job, err error) {
+			res, resErr = j, err
+			close(done)
+		})
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.ErrorIs(t, resErr, directory.ErrNotFound)
+		assert.Equal(t, transfer.StatusFailed, res.Status)
+		assert.Equal(t, 1, res.Attempts)
+	})
+
+	t.Run("should fail without retrying when the connection switched to another bucket", func(t *testing.T) {
+		// Given
+		m, mockNotifier := setup(t)
+		mockNotifier.EXPECT().NotifyError(gomock.Any()).Times(1)
+		localPath := filepath.Join(t.TempDir(), "file.txt")
+		job := transfer.NewDownloadJob(tu.FakeAwsConnectionId, "mydir/file.txt", "", localPath, 7)
+		job.Bucket = "previous-bucket"
+
+		done := make(chan struct{})
+		var (
+			res    transfer.Job
+			resErr error
+		)
+
+		// When
+		m.enqueue(job, func(j transfer.Job, err error) {
+			res, resErr = j, err
+			close(done)
+		})
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.ErrorIs(t, resErr, transfer.ErrTargetChanged)
+		assert.Equal(t, transfer.StatusFailed, res.Status)
+		assert.Equal(t, 1, res.Attempts)
+		assert.NoFileExists(t, localPath)
+	})
+
+	t.Run("should fail without retrying when the root prefix of the connection changed", func(t *testing.T) {
+		// Given
+		m, mockNotifier := setup(t)
+		mockNotifier.EXPECT().NotifyError(gomock.Any()).Times(1)
+		localPath := filepath.Join(t.TempDir(), "file.txt")
+		job := transfer.NewDownloadJob(tu.FakeAwsConnectionId, "mydir/file.txt", "", localPath, 7)
+		job.Bucket, job.RootPrefix = tu.FakeAwsBucketName, "team-x/"
+
+		done := make(chan struct{})
+		var resErr error
+
+		// When
+		m.enqueue(job, func(_ transfer.Job, err error) {
+			resErr = err
+			close(done)
+		})
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.ErrorIs(t, resErr, transfer.ErrTargetChanged)
+		assert.NoFileExists(t, localPath)
+	})
+}
+
+// fakeBlockingDownloadClient writes a part of the content then waits for the download to be canceled.
+type fakeBlockingDownloadClient struct {
+	fakeBucketClient
+	started chan struct{}
+}
+
+func (c *fakeBlockingDownloadClient) Download(ctx context.Context, _ string, writer io.WriterAt, _ ...s3client.Option) error {
+	_, _ = writer.WriteAt([]byte("partial"), 0)
+	close(c.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTransferManager_Cancel(t *testing.T) {
+	t.Run("should keep the existing local file when a download is canceled", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+		mockBus.EXPECT().Publish(gomock.Any()).AnyTimes()
+		client := &fakeBlockingDownloadClient{started: make(chan struct{})}
+		m := newTransferManager(mockBus, mockNotifier, &fakeClientFactory{client: client})
+
+		localDir := t.TempDir()
+		localPath := filepath.Join(localDir, "file.txt")
+		require.NoError(t, os.WriteFile(localPath, []byte("local work"), 0o644))
+		job := transfer.NewDownloadJob(tu.FakeAwsConnectionId, "mydir/file.txt", "", localPath, 7)
+
+		done := make(chan struct{})
+		var resErr error
+		m.enqueue(job, func(_ transfer.Job, err error) {
+			resErr = err
+			close(done)
+		})
+		tu.AssertEventually(t, client.started)
+
+		// When
+		m.handleCancel(event.New(transfer.CancelTriggered{JobID: job.ID}))
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.ErrorIs(t, resErr, directory.ErrCanceled)
+		content, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, "local work", string(content))
+		entries, err := os.ReadDir(localDir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "the temporary file should be removed")
+	})
+}
+
+// fakeSlowStoppingDownloadClient blocks its first download until it is canceled then released,
+// the next ones succeeding right away. It records the highest number of simultaneous downloads.
+type fakeSlowStoppingDownloadClient struct {
+	fakeBucketClient
+	started chan struct{}
+	release chan struct{}
+
+	mu        sync.Mutex
+	calls     int
+	active    int
+	maxActive int
+}
+
+func (c *fakeSlowStoppingDownloadClient) Download(ctx context.Context, _ string, writer io.WriterAt, _ ...s3client.Option) error {
+	c.mu.Lock()
+	c.calls++
+	first := c.calls == 1
+	c.active++
+	c.maxActive = max(c.maxActive, c.active)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.active--
+		c.mu.Unlock()
+	}()
+
+	if !first {
+		_, err := writer.WriteAt([]byte("content"), 0)
+		return err
+	}
+	_, _ = writer.WriteAt([]byte("partial"), 0)
+	close(c.started)
+	<-ctx.Done()
+	<-c.release
+	return ctx.Err()
+}
+
+func TestTransferManager_PauseResume(t *testing.T) {
+	t.Run("should start the resumed job over once its paused run is over", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+		mockBus.EXPECT().Publish(gomock.Any()).AnyTimes()
+		client := &fakeSlowStoppingDownloadClient{started: make(chan struct{}), release: make(chan struct{})}
+		m := newTransferManager(mockBus, mockNotifier, &fakeClientFactory{client: client})
+
+		localPath := filepath.Join(t.TempDir(), "file.txt")
+		job := transfer.NewDownloadJob(tu.FakeAwsConnectionId, "mydir/file.txt", "", localPath, 7)
+
+		done := make(chan struct{})
+		var (
+			res      transfer.Job
+			resErr   error
+			doneCall atomic.Int32
+		)
+		m.enqueue(job, func(j transfer.Job, err error) {
+			res, resErr = j, err
+			doneCall.Add(1)
+			close(done)
+		})
+		tu.AssertEventually(t, client.started)
+
+		// When
+		m.handlePause(event.New(transfer.PauseTriggered{JobID: job.ID}))
+		m.handleResume(event.New(transfer.ResumeTriggered{JobID: job.ID}))
+
+		// Then
+		client.mu.Lock()
+		assert.Equal(t, 1, client.calls, "the resumed job should wait for its paused run to be over")
+		client.mu.Unlock()
+
+		// When
+		close(client.release)
+
+		// Then
+		tu.AssertEventually(t, done)
+		assert.NoError(t, resErr)
+		assert.Equal(t, transfer.StatusSucceeded, res.Status)
+		assert.Equal(t, int32(1), doneCall.Load())
+		client.mu.Lock()
+		assert.Equal(t, 2, client.calls)
+		assert.Equal(t, 1, client.maxActive)
+		client.mu.Unlock()
+		content, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+	})
+}
+
+type fakeMultipartClient struct {
+	fakeBucketClient
+
+	mu       sync.Mutex
+	uploadID int
+	parts    map[string]map[int32]string
+	aborted  []string
+	objects  map[string]string
+}
+
+func newFakeMultipartClient() *fakeMultipartClient {
+	return &fakeMultipartClient{
+		parts:   make(map[string]map[int32]string),
+		objects: make(map[string]string),
+	}
+}
+
+func (c *fakeMultipartClient) CreateMultipartUpload(context.Context, string, ...s3client.Option) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uploadID++
+	id := fmt.Sprintf("upload-%d", c.uploadID)
+	c.parts[id] = make(map[int32]string)
+	return id, nil
+}
+
+func (c *fakeMultipartClient) UploadPart(_ context.Context, _, uploadID string, partNumber int32, body io.ReadSeeker, _ ...s3client.Option) (s3client.CompletedPart, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return s3client.CompletedPart{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parts, ok := c.parts[uploadID]
+	if !ok {
+		return s3client.CompletedPart{}, directory.ErrNotFound
+	}
+	parts[partNumber] = string(content)
+	return s3client.CompletedPart{Number: partNumber, ETag: fmt.Sprintf("etag-%d", partNumber)}, nil
+}
+
+func (c *fakeMultipartClient) CompleteMultipartUpload(_ context.Context, key, uploadID string, parts []s3client.CompletedPart, _ ...s3client.Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var content strings.Builder
+	for _, p := range parts {
+		content.WriteString(c.parts[uploadID][p.Number])
+	}
+	c.objects[key] = content.String()
+	delete(c.parts, uploadID)
+	return nil
+}
+
+func (c *fakeMultipartClient) AbortMultipartUpload(_ context.Context, _, uploadID string, _ ...s3client.Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.aborted = append(c.aborted, uploadID)
+	delete(c.parts, uploadID)
+	return nil
+}
+
+func TestTransferManager_UploadParts(t *testing.T) {
+	partSize := transfer.PartSize(0)
+
+	writeLocalFile := func(t *testing.T) (string, string, transfer.Fingerprint) {
+		t.Helper()
+		content := strings.Repeat("a", int(partSize)) + strings.Repeat("b", int(partSize)) + "c"
+		localPath := filepath.Join(t.TempDir(), "big.bin")
+		require.NoError(t, os.WriteFile(localPath, []byte(content), 0o644))
+		info, err := os.Stat(localPath)
+		require.NoError(t, err)
+		return localPath, content, transfer.Fingerprint{SizeBytes: info.Size(), ModTime: info.ModTime()}
+	}
+
+	setup := func(t *testing.T, client s3client.Client) *transferManager {
+		ctrl := gomock.NewController(t)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+		mockBus.EXPECT().Publish(gomock.Any()).AnyTimes()
+		return newTransferManager(mockBus, mockNotifier, &fakeClientFactory{client: client})
+	}
+
+	enqueueAndWait := func(t *testing.T, m *transferManager, job transfer.Job) (transfer.Job, error) {
+		t.Helper()
+		done := make(chan struct{})
+		var (
+			res    transfer.Job
+			resErr error
+		)
+		m.enqueue(job, func(j transfer.Job, err error) {
+			res, resErr = j, err
+			close(done)
+		})
+		tu.AssertEventually(t, done)
+		return res, resErr
+	}
+
+	t.Run("should upload the file in several parts", func(t *testing.T) {
+		// Given
+		client := newFakeMultipartClient()
+		m := setup(t, client)
+		localPath, content, _ := writeLocalFile(t)
+		job := transfer.NewUploadJob(tu.FakeAwsConnectionId, localPath, "data/big.bin", uint64(len(content)))
+
+		// When
+		res, err := enqueueAndWait(t, m, job)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusSucceeded, res.Status)
+		assert.Nil(t, res.Multipart)
+		assert.Equal(t, content, client.objects["data/big.bin"])
+	})
+
+	t.Run("should resume from the already uploaded parts", func(t *testing.T) {
+		// Given
+		client := newFakeMultipartClient()
+		m := setup(t, client)
+		localPath, content, fingerprint := writeLocalFile(t)
+		uploadID, err := client.CreateMultipartUpload(t.Context(), "data/big.bin")
+		require.NoError(t, err)
+		client.parts[uploadID][1] = content[:partSize]
+
+		job := transfer.NewUploadJob(tu.FakeAwsConnectionId, localPath, "data/big.bin", uint64(len(content)))
+		job.Multipart = &transfer.MultipartUpload{
+			UploadID:      uploadID,
+			PartSizeBytes: partSize,
+			Parts:         []transfer.Part{{Number: 1, ETag: "etag-1", SizeBytes: partSize}},
+			Fingerprint:   fingerprint,
+		}
+
+		// When
+		res, err := enqueueAndWait(t, m, job)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusSucceeded, res.Status)
+		assert.Equal(t, content, client.objects["data/big.bin"])
+		assert.Equal(t, 1, client.uploadID, "no new upload should have been created")
+	})
+
+	t.Run("should start over when the local file changed", func(t *testing.T) {
+		// Given
+		client := newFakeMultipartClient()
+		m := setup(t, client)
+		localPath, content, fingerprint := writeLocalFile(t)
+		uploadID, err := client.CreateMultipartUpload(t.Context(), "data/big.bin")
+		require.NoError(t, err)
+		client.parts[uploadID][1] = "outdated"
+
+		fingerprint.ModTime = fingerprint.ModTime.Add(-time.Hour)
+		job := transfer.NewUploadJob(tu.FakeAwsConnectionId, localPath, "data/big.bin", uint64(len(content)))
+		job.Multipart = &transfer.MultipartUpload{
+			UploadID:      uploadID,
+			PartSizeBytes: partSize,
+			Parts:         []transfer.Part{{Number: 1, ETag: "etag-1", SizeBytes: partSize}},
+			Fingerprint:   fingerprint,
+		}
+
+		// When
+		res, err := enqueueAndWait(t, m, job)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, transfer.StatusSucceeded, res.Status)
+		assert.Equal(t, content, client.objects["data/big.bin"])
+		assert.Equal(t, []string{uploadID}, client.aborted)
+	})
+}
+
+func TestProgressWriterAt(t *testing.T) {
+	t.Run("should count the written bytes", func(t *testing.T) {
+		// Given
+		localFile, err := os.Create(filepath.Join(t.TempDir(), "file.txt"))
+		require.NoError(t, err)
+		defer localFile.Close()
+		w := &progressWriterAt{writer: localFile, transferred: new(atomic.Uint64)}
+
+		// When
+		_, err = w.WriteAt([]byte("hello"), 0)
+		require.NoError(t, err)
+		_, err = w.WriteAt([]byte("world"), 5)
+		require.NoError(t, err)
+
+		// Then
+		assert.Equal(t, uint64(10), w.transferred.Load())
+	})
+}
@@ -0,0 +1,254 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	sealedBoxVersion = 1
+	// scrypt parameters recommended for interactive logins
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	sealedKeyLen = 32
+	saltLen      = 16
+)
+
+var errEmptyPassphrase = errors.New("the passphrase can't be empty")
+
+// sealedBox is a content encrypted with AES-GCM, with a key derived from a passphrase by scrypt.
+type sealedBox struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errEmptyPassphrase
+	}
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, sealedKeyLen)
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// seal encrypts the content with the key derived from the salt.
+func seal(key, salt, content []byte) (*sealedBox, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &sealedBox{
+		Version: sealedBoxVersion,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, content, nil),
+	}, nil
+}
+
+// sealWithPassphrase encrypts the content with a key derived from the passphrase and a new salt.
+func sealWithPassphrase(passphrase string, content []byte) (*sealedBox, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return seal(key, salt, content)
+}
+
+func (b *sealedBox) open(key []byte) ([]byte, error) {
+	if b.Version != sealedBoxVersion {
+		return nil, fmt.Errorf("unsupported encryption version %d", b.Version)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid encryption nonce")
+	}
+	content, err := gcm.Open(nil, b.Nonce, b.Data, nil)
+	if err != nil {
+		// the authentication fails when the key is derived from another passphrase
+		return nil, connection_deck.ErrWrongPassphrase
+	}
+	return content, nil
+}
+
+func (b *sealedBox) openWithPassphrase(passphrase string) ([]byte, error) {
+	key, err := deriveKey(passphrase, b.Salt)
+	if err != nil {
+		return nil, err
+	}
+	return b.open(key)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptedFileSecretStore keeps the secrets in a file encrypted with a master passphrase.
+// The key is derived from the passphrase once, when the store is unlocked.
+type EncryptedFileSecretStore struct {
+	mu      sync.Mutex
+	path    string
+	salt    []byte
+	key     []byte
+	secrets map[string]string
+}
+
+var _ connection_deck.SecretStore = &EncryptedFileSecretStore{}
+
+func NewEncryptedFileSecretStore(path string) *EncryptedFileSecretStore {
+	return &EncryptedFileSecretStore{path: path}
+}
+
+func (s *EncryptedFileSecretStore) IsEnabled() bool {
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+func (s *EncryptedFileSecretStore) IsLocked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.key == nil
+}
+
+func (s *EncryptedFileSecretStore) Unlock(passphrase string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt, err := newSalt()
+		if err != nil {
+			return err
+		}
+		key, err := deriveKey(passphrase, salt)
+		if err != nil {
+			return err
+		}
+		s.salt, s.key, s.secrets = salt, key, make(map[string]string)
+		return s.persist()
+	}
+	if err != nil {
+		return fmt.Errorf("read the secret store: %w", err)
+	}
+
+	var box sealedBox
+	if err := json.Unmarshal(content, &box); err != nil {
+		return fmt.Errorf("read the secret store: %w", err)
+	}
+	key, err := deriveKey(passphrase, box.Salt)
+	if err != nil {
+		return err
+	}
+	plain, err := box.open(key)
+	if err != nil {
+		return err
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("read the secret store: %w", err)
+	}
+	s.salt, s.key, s.secrets = box.Salt, key, secrets
+	return nil
+}
+
+func (s *EncryptedFileSecretStore) Get(_ context.Context, ref string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil {
+		return "", connection_deck.ErrSecretStoreLocked
+	}
+	secret, ok := s.secrets[ref]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", connection_deck.ErrSecretNotFound, ref)
+	}
+	return secret, nil
+}
+
+func (s *EncryptedFileSecretStore) Set(_ context.Context, ref, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil {
+		return connection_deck.ErrSecretStoreLocked
+	}
+	if prev, ok := s.secrets[ref]; ok && prev == secret {
+		return nil
+	}
+	s.secrets[ref] = secret
+	return s.persist()
+}
+
+func (s *EncryptedFileSecretStore) Delete(_ context.Context, ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key == nil {
+		return connection_deck.ErrSecretStoreLocked
+	}
+	if _, ok := s.secrets[ref]; !ok {
+		return nil
+	}
+	delete(s.secrets, ref)
+	return s.persist()
+}
+
+// persist writes the encrypted secrets to a temporary file renamed over the store one,
+// so that the store is never left half written.
+func (s *EncryptedFileSecretStore) persist() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+	box, err := seal(s.key, s.salt, plain)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(box)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("write the secret store: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o600); err != nil {
+		return fmt.Errorf("write the secret store: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("write the secret store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+)
+
+func TestEncryptedFileSecretStore(t *testing.T) {
+	t.Run("should create the store on the first unlock and read it back with the same passphrase", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "secrets.enc")
+		store := NewEncryptedFileSecretStore(path)
+		require.False(t, store.IsEnabled())
+
+		// When
+		require.NoError(t, store.Unlock("my passphrase"))
+		require.NoError(t, store.Set(context.TODO(), "ref", "secret"))
+
+		// Then
+		assert.True(t, store.IsEnabled())
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "secret\"")
+
+		reopened := NewEncryptedFileSecretStore(path)
+		assert.True(t, reopened.IsLocked())
+		require.NoError(t, reopened.Unlock("my passphrase"))
+		res, err := reopened.Get(context.TODO(), "ref")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", res)
+	})
+
+	t.Run("should refuse a wrong passphrase", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "secrets.enc")
+		require.NoError(t, NewEncryptedFileSecretStore(path).Unlock("my passphrase"))
+		store := NewEncryptedFileSecretStore(path)
+
+		// When
+		err := store.Unlock("another passphrase")
+
+		// Then
+		assert.ErrorIs(t, err, connection_deck.ErrWrongPassphrase)
+		assert.True(t, store.IsLocked())
+	})
+
+	t.Run("should return an error while locked", func(t *testing.T) {
+		// Given
+		store := NewEncryptedFileSecretStore(filepath.Join(t.TempDir(), "secrets.enc"))
+
+		// When
+		_, err := store.Get(context.TODO(), "ref")
+
+		// Then
+		assert.ErrorIs(t, err, connection_deck.ErrSecretStoreLocked)
+		assert.ErrorIs(t, store.Set(context.TODO(), "ref", "secret"), connection_deck.ErrSecretStoreLocked)
+	})
+
+	t.Run("should return ErrSecretNotFound for an unknown or deleted reference", func(t *testing.T) {
+		// Given
+		store := NewEncryptedFileSecretStore(filepath.Join(t.TempDir(), "secrets.enc"))
+		require.NoError(t, store.Unlock("my passphrase"))
+		require.NoError(t, store.Set(context.TODO(), "ref", "secret"))
+
+		// When
+		require.NoError(t, store.Delete(context.TODO(), "ref"))
+		_, err := store.Get(context.TODO(), "ref")
+
+		// Then
+		assert.ErrorIs(t, err, connection_deck.ErrSecretNotFound)
+	})
+}
+
+func TestParseJSONExport(t *testing.T) {
+	t.Run("should decrypt an encrypted export", func(t *testing.T) {
+		// Given
+		content := []byte(`[{"id":"6f1b1a4e-7e1a-4f5e-9f0a-0e7b4c1d2a3b","name":"conn 1","accessKey":"ak","secretKey":"sk","bucket":"b","type":"aws","region":"us-east-1","useTls":true}]`)
+		box, err := sealWithPassphrase("my passphrase", content)
+		require.NoError(t, err)
+		encrypted, err := json.Marshal(box)
+		require.NoError(t, err)
+
+		// When
+		res, err := parseJSONExport(encrypted, "my passphrase")
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, "conn 1", res[0].Name())
+		assert.Equal(t, "sk", res[0].SecretKey())
+
+		_, err = parseJSONExport(encrypted, "another passphrase")
+		assert.ErrorIs(t, err, connection_deck.ErrWrongPassphrase)
+	})
+}
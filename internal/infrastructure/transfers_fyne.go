@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/notification"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/dto"
+)
+
+const (
+	allTransfersKey = "allTransfers"
+
+	// maxSavedFinishedTransfers is the number of finished jobs kept in the history, the oldest ones being dropped first
+	maxSavedFinishedTransfers = 100
+)
+
+// FyneTransfersRepository saves the transfer jobs in the Fyne preferences each time their status changes
+// or a part of a multipart upload is sent.
+type FyneTransfersRepository struct {
+	mu       sync.Mutex
+	prefs    fyne.Preferences
+	notifier notification.Repository
+	jobs     []transfer.Job
+}
+
+var _ transfer.Repository = &FyneTransfersRepository{}
+
+func NewFyneTransfersRepository(
+	prefs fyne.Preferences,
+	bus event.Bus,
+	notifier notification.Repository,
+) *FyneTransfersRepository {
+	r := &FyneTransfersRepository{prefs: prefs, notifier: notifier}
+
+	bus.Subscribe().
+		On(event.Is(transfer.JobUpdatedType), r.handleJobUpdated).
+		On(event.Is(transfer.ClearFinishedSucceededType), r.handleClearFinished).
+		ListenWithWorkers(1)
+
+	return r
+}
+
+func (r *FyneTransfersRepository) Load() ([]transfer.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content := r.prefs.String(allTransfersKey)
+	if content == "" || content == "null" {
+		r.jobs = nil
+		return nil, nil
+	}
+
+	dtos, err := dto.NewTransferJobsDTOFromJSON([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("load transfers: %w", errors.Join(err, transfer.ErrTechnical))
+	}
+	r.jobs = dtos.ToJobs()
+	return slices.Clone(r.jobs), nil
+}
+
+func (r *FyneTransfersRepository) handleJobUpdated(evt event.Event) {
+	pl := evt.Payload().(transfer.JobUpdated)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := slices.IndexFunc(r.jobs, func(j transfer.Job) bool { return j.ID == pl.Job.ID })
+	switch {
+	case idx < 0:
+		r.jobs = append(r.jobs, pl.Job)
+	case r.jobs[idx].Status == pl.Job.Status && r.jobs[idx].Multipart == pl.Job.Multipart:
+		// progress only, not worth saving
+		return
+	default:
+		r.jobs[idx] = pl.Job
+	}
+
+	r.dropOldestFinished()
+	r.save()
+}
+
+func (r *FyneTransfersRepository) handleClearFinished(evt event.Event) {
+	pl := evt.Payload().(transfer.ClearFinishedSucceeded)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs = slices.DeleteFunc(r.jobs, func(j transfer.Job) bool {
+		return slices.Contains(pl.JobIDs, j.ID)
+	})
+	r.save()
+}
+
+func (r *FyneTransfersRepository) dropOldestFinished() {
+	finishedCount := 0
+	for _, j := range r.jobs {
+		if j.Status.IsFinished() {
+			finishedCount++
+		}
+	}
+	toDrop := finishedCount - maxSavedFinishedTransfers
+	if toDrop <= 0 {
+		return
+	}
+	r.jobs = slices.DeleteFunc(r.jobs, func(j transfer.Job) bool {
+		if toDrop > 0 && j.Status.IsFinished() {
+			toDrop--
+			return true
+		}
+		return false
+	})
+}
+
+func (r *FyneTransfersRepository) save() {
+	content, err := json.Marshal(dto.NewTransferJobsDTO(r.jobs))
+	if err != nil {
+		r.notifier.NotifyError(fmt.Errorf("save transfers: %w", errors.Join(err, transfer.ErrTechnical)))
+		return
+	}
+	r.prefs.SetString(allTransfersKey, string(content))
+}
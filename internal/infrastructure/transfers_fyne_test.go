@@ -0,0 +1,187 @@
+package infrastructure_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	mocks_event "github.com/thomas-marquis/s3-box/mocks/event"
+	mocks_fyne "github.com/thomas-marquis/s3-box/mocks/fyne"
+	mocks_notification "github.com/thomas-marquis/s3-box/mocks/notification"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFyneTransfersRepository_Load(t *testing.T) {
+	t.Run("should return the saved jobs", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockPrefs := mocks_fyne.NewMockPreferences(ctrl)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+
+		connID := connection_deck.NewConnectionID()
+		mockPrefs.EXPECT().
+			String(gomock.Eq("allTransfers")).
+			Return(`[
+				{
+					"id": "job-1",
+					"kind": "upload",
+					"connectionId": "` + connID.String() + `",
+					"bucket": "my-bucket",
+					"rootPrefix": "team-x/",
+					"key": "data/report.csv",
+					"localPath": "/tmp/report.csv",
+					"totalBytes": 1024,
+					"status": "running",
+					"attempts": 2,
+					"createdAt": "2025-03-14T10:30:00Z"
+				},
+				{
+					"id": "job-2",
+					"kind": "download",
+					"connectionId": "` + connID.String() + `",
+					"key": "data/photo.png",
+					"localPath": "/tmp/photo.png",
+					"totalBytes": 2048,
+					"status": "succeeded",
+					"createdAt": "2025-03-14T10:30:00Z"
+				}
+			]`).
+			Times(1)
+		mockBus.EXPECT().
+			Subscribe().
+			Return(event.NewSubscriber(make(chan event.Event))).
+			Times(1)
+
+		repo := infrastructure.NewFyneTransfersRepository(mockPrefs, mockBus, mockNotifier)
+
+		// When
+		res, err := repo.Load()
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Equal(t, transfer.JobID("job-1"), res[0].ID)
+		assert.Equal(t, transfer.KindUpload, res[0].Kind)
+		assert.Equal(t, connID, res[0].ConnectionID)
+		assert.Equal(t, "my-bucket", res[0].Bucket)
+		assert.Equal(t, "team-x/", res[0].RootPrefix)
+		assert.Equal(t, transfer.StatusRunning, res[0].Status)
+		assert.Equal(t, 2, res[0].Attempts)
+		assert.Equal(t, transfer.KindDownload, res[1].Kind)
+		assert.Equal(t, transfer.StatusSucceeded, res[1].Status)
+		assert.Equal(t, uint64(2048), res[1].TransferredBytes)
+	})
+}
+
+func TestFyneTransfersRepository_LoadMultipart(t *testing.T) {
+	t.Run("should return the state of the interrupted multipart upload", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockPrefs := mocks_fyne.NewMockPreferences(ctrl)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+
+		mockPrefs.EXPECT().
+			String(gomock.Eq("allTransfers")).
+			Return(`[
+				{
+					"id": "job-1",
+					"kind": "upload",
+					"connectionId": "` + connection_deck.NewConnectionID().String() + `",
+					"key": "data/big.bin",
+					"localPath": "/tmp/big.bin",
+					"totalBytes": 20971520,
+					"status": "paused",
+					"createdAt": "2025-03-14T10:30:00Z",
+					"multipart": {
+						"uploadId": "upload-1",
+						"partSizeBytes": 8388608,
+						"parts": [
+							{"number": 1, "etag": "\"etag-1\"", "checksum": "AAAAAA==", "sizeBytes": 8388608}
+						],
+						"localSizeBytes": 20971520,
+						"localModTime": "2025-03-14T10:00:00.123456789Z"
+					}
+				}
+			]`).
+			Times(1)
+		mockBus.EXPECT().
+			Subscribe().
+			Return(event.NewSubscriber(make(chan event.Event))).
+			Times(1)
+
+		repo := infrastructure.NewFyneTransfersRepository(mockPrefs, mockBus, mockNotifier)
+
+		// When
+		res, err := repo.Load()
+
+		// Then
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.NotNil(t, res[0].Multipart)
+		assert.True(t, res[0].IsInterruptedUpload())
+		assert.Equal(t, "upload-1", res[0].Multipart.UploadID)
+		assert.Equal(t, []transfer.Part{
+			{Number: 1, ETag: `"etag-1"`, Checksum: "AAAAAA==", SizeBytes: 8388608},
+		}, res[0].Multipart.Parts)
+		assert.Equal(t, transfer.Fingerprint{
+			SizeBytes: 20971520,
+			ModTime:   time.Date(2025, 3, 14, 10, 0, 0, 123456789, time.UTC),
+		}, res[0].Multipart.Fingerprint)
+	})
+}
+
+func TestFyneTransfersRepository_Save(t *testing.T) {
+	t.Run("should save the job when its status changes", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockPrefs := mocks_fyne.NewMockPreferences(ctrl)
+		mockBus := mocks_event.NewMockBus(ctrl)
+		mockNotifier := mocks_notification.NewMockRepository(ctrl)
+
+		events := make(chan event.Event)
+		defer close(events)
+		mockBus.EXPECT().
+			Subscribe().
+			Return(event.NewSubscriber(events)).
+			Times(1)
+
+		job := transfer.NewUploadJob(connection_deck.NewConnectionID(), "/tmp/report.csv", "data/report.csv", 1024)
+		job.Bucket, job.RootPrefix = "my-bucket", "team-x/"
+
+		done := make(chan struct{})
+		mockPrefs.EXPECT().
+			SetString(gomock.Eq("allTransfers"), gomock.Any()).
+			Do(func(_ string, content string) {
+				// Then
+				var saved []map[string]any
+				require.NoError(t, json.Unmarshal([]byte(content), &saved))
+				require.Len(t, saved, 1)
+				assert.Equal(t, job.ID.String(), saved[0]["id"])
+				assert.Equal(t, "queued", saved[0]["status"])
+				assert.Equal(t, "my-bucket", saved[0]["bucket"])
+				assert.Equal(t, "team-x/", saved[0]["rootPrefix"])
+				close(done)
+			}).
+			Times(1)
+
+		infrastructure.NewFyneTransfersRepository(mockPrefs, mockBus, mockNotifier)
+
+		// When
+		events <- event.New(transfer.JobUpdated{Job: job})
+		progressing := job
+		progressing.TransferredBytes = 512
+		events <- event.New(transfer.JobUpdated{Job: progressing})
+
+		// Then
+		tu.AssertEventually(t, done)
+	})
+}
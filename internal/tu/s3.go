@@ -21,9 +21,11 @@ import (
 )
 
 type FakeS3Object struct {
-	Key  string
-	Body io.Reader
-	ACL  types.ObjectCannedACL
+	Key         string
+	Body        io.Reader
+	ACL         types.ObjectCannedACL
+	ContentType string
+	Metadata    map[string]string
 }
 
 func SetupS3testContainer(ctx context.Context, t *testing.T) (string, func()) {
@@ -97,12 +99,17 @@ func FillBucket(t *testing.T, ctx context.Context, client *s3.Client, bucketName
 	}
 
 	for _, obj := range content {
-		workload <- &s3.PutObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    aws.String(obj.Key),
-			Body:   obj.Body,
-			ACL:    obj.ACL,
+		in := &s3.PutObjectInput{
+			Bucket:   aws.String(bucketName),
+			Key:      aws.String(obj.Key),
+			Body:     obj.Body,
+			ACL:      obj.ACL,
+			Metadata: obj.Metadata,
 		}
+		if obj.ContentType != "" {
+			in.ContentType = aws.String(obj.ContentType)
+		}
+		workload <- in
 	}
 	wg.Wait()
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"sync"
 
 	"fyne.io/fyne/v2"
 	fyne_app "fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/it-happened/inmemory"
@@ -30,9 +32,21 @@ const (
 	appName = "S3 Box"
 )
 
+const secretStoreFile = "secrets.enc"
+
 type Go2S3App struct {
 	appCtx    appcontext.AppContext
 	initRoute navigation.Route
+	logger    *zap.Logger
+	appViews  map[navigation.Route]appcontext.Menu
+
+	fyneApp               fyne.App
+	window                fyne.Window
+	ctx                   context.Context
+	notifier              notification.Repository
+	eventBus              event.Bus
+	secretStore           *infrastructure.EncryptedFileSecretStore
+	connectionsRepository *infrastructure.FyneConnectionsRepository
 }
 
 func New(logger *zap.Logger, initRoute navigation.Route) (*Go2S3App, error) {
@@ -58,12 +72,19 @@ func New(logger *zap.Logger, initRoute navigation.Route) (*Go2S3App, error) {
 			Route:       navigation.SettingsRoute,
 			Index:       2,
 		},
+		navigation.TransfersRoute: {
+			Label:       "Transfers",
+			IconFactory: theme.UploadIcon,
+			View:        views.GetTransfersView,
+			Route:       navigation.TransfersRoute,
+			Index:       3,
+		},
 		navigation.NotificationsRoute: {
 			Label:       "Notifications",
 			IconFactory: theme.InfoIcon,
 			View:        views.GetNotificationView,
 			Route:       navigation.NotificationsRoute,
-			Index:       3,
+			Index:       4,
 		},
 	}
 
@@ -78,9 +99,12 @@ func New(logger *zap.Logger, initRoute navigation.Route) (*Go2S3App, error) {
 
 	eventBus := inmemory.NewBus(ctx, inmemory.WithNotifier(&notifierAdapter{notifier: notifier}))
 
-	fyneSettings := a.Settings()
-
-	connectionsRepository := infrastructure.NewFyneConnectionsRepository(a.Preferences(), eventBus)
+	secretStore := newSecretStore(a)
+	connectionsRepository := infrastructure.NewFyneConnectionsRepository(
+		a.Preferences(),
+		eventBus,
+		infrastructure.WithSecretStore(secretStore),
+	)
 
 	s3.NewS3EventHandler(
 		connectionsRepository,
@@ -90,70 +114,109 @@ func New(logger *zap.Logger, initRoute navigation.Route) (*Go2S3App, error) {
 
 	settings.FyneSettingsHandler(eventBus, a.Preferences())
 
+	var one sync.Once
+	w.SetOnClosed(func() {
+		one.Do(func() {
+			cancel()
+		})
+	})
+
+	return &Go2S3App{
+		initRoute:             initRoute,
+		logger:                logger,
+		appViews:              appViews,
+		fyneApp:               a,
+		window:                w,
+		ctx:                   ctx,
+		notifier:              notifier,
+		eventBus:              eventBus,
+		secretStore:           secretStore,
+		connectionsRepository: connectionsRepository,
+	}, nil
+}
+
+// newSecretStore returns the store of the connections credentials, kept next to the application preferences.
+func newSecretStore(a fyne.App) *infrastructure.EncryptedFileSecretStore {
+	return infrastructure.NewEncryptedFileSecretStore(
+		filepath.Join(a.Storage().RootURI().Path(), secretStoreFile))
+}
+
+// initViewModels builds the view models, once the connections can be read.
+func (a *Go2S3App) initViewModels() {
+	fyneSettings := a.fyneApp.Settings()
+
+	transfersRepository := infrastructure.NewFyneTransfersRepository(a.fyneApp.Preferences(), a.eventBus, a.notifier)
+
 	appState := state.New()
 
-	notificationsViewModel := viewmodel.NewNotificationViewModel(ctx, notifier)
+	notificationsViewModel := viewmodel.NewNotificationViewModel(a.ctx, a.notifier)
 
 	settingsViewModel := viewmodel.NewSettingsViewModel(
 		fyneSettings,
-		notifier,
+		a.notifier,
 		appState,
-		eventBus)
+		a.eventBus)
 	connectionViewModel := viewmodel.NewConnectionViewModel(
-		connectionsRepository,
+		a.connectionsRepository,
 		settingsViewModel,
 		appState,
-		notifier,
-		eventBus,
+		a.notifier,
+		a.eventBus,
 	)
 	explorerViewModel := viewmodel.NewExplorerViewModel(
 		settingsViewModel,
-		notifier,
+		a.notifier,
 		connectionViewModel.Deck().SelectedConnection(),
-		eventBus,
+		a.eventBus,
 		appState,
 	)
 
-	editorViewModel := viewmodel.NewEditorViewModel(ctx, eventBus, notifier,
+	editorViewModel := viewmodel.NewEditorViewModel(a.ctx, a.eventBus, a.notifier,
 		connectionViewModel.Deck().SelectedConnection())
 
-	appCtx := appcontext.New(
+	transferViewModel := viewmodel.NewTransferViewModel(transfersRepository, appState, a.notifier, a.eventBus)
+
+	a.appCtx = appcontext.New(
 		appName,
-		w,
+		a.window,
 		explorerViewModel,
 		connectionViewModel,
 		settingsViewModel,
 		notificationsViewModel,
 		editorViewModel,
-		initRoute,
-		appViews,
-		logger,
+		transferViewModel,
+		a.initRoute,
+		a.appViews,
+		a.logger,
 		fyneSettings,
-		eventBus,
+		a.eventBus,
 		appState,
 	)
-
-	var one sync.Once
-	w.SetOnClosed(func() {
-		one.Do(func() {
-			cancel()
-		})
-	})
-
-	return &Go2S3App{
-		initRoute: initRoute,
-		appCtx:    appCtx,
-	}, nil
 }
 
 func (a *Go2S3App) Start() error {
-	a.appCtx.Window().Resize(fyne.NewSize(1200, 900))
-	a.appCtx.Window().SetContent(a.appCtx.AppContent())
+	a.window.Resize(fyne.NewSize(1200, 900))
+	if a.secretStore.IsEnabled() && a.secretStore.IsLocked() {
+		views.ShowUnlockSecretsDialog(a.window, a.secretStore.Unlock, func() {
+			if err := a.showContent(); err != nil {
+				dialog.ShowError(err, a.window)
+			}
+		}, a.fyneApp.Quit)
+	} else if err := a.showContent(); err != nil {
+		return err
+	}
+	a.window.ShowAndRun() // blocking
+	return nil
+}
+
+func (a *Go2S3App) showContent() error {
+	a.initViewModels()
+	a.window.SetContent(a.appCtx.AppContent())
 	_, err := a.appCtx.Navigate(a.initRoute)
 	if err != nil {
 		return err
 	}
-	a.appCtx.Window().ShowAndRun() // blocking
+	views.ShowInterruptedUploadsDialog(a.appCtx)
 	return nil
 }
 
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	fyne_app "fyne.io/fyne/v2/app"
+	"github.com/thomas-marquis/it-happened/inmemory"
+	"github.com/thomas-marquis/s3-box/internal/cli"
+	"github.com/thomas-marquis/s3-box/internal/domain/notification"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure"
+	"github.com/thomas-marquis/s3-box/internal/infrastructure/s3"
+	"github.com/thomas-marquis/s3-box/internal/u"
+)
+
+// RunCLI runs a command line without opening any window and returns the exit code.
+// The saved connections are the same as in the graphical interface.
+func RunCLI(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := fyne_app.NewWithID(appId)
+	notifier := infrastructure.NewNotificationPublisher(notification.LevelError)
+	eventBus := inmemory.NewBus(ctx)
+
+	secretStore := newSecretStore(a)
+	if secretStore.IsEnabled() {
+		passphrase := os.Getenv(cli.PassphraseEnv)
+		if passphrase == "" {
+			u.SkipV(fmt.Fprintf(os.Stderr, "Error: the credentials are protected, set %s to unlock them\n", cli.PassphraseEnv))
+			return 1
+		}
+		if err := secretStore.Unlock(passphrase); err != nil {
+			u.SkipV(fmt.Fprintf(os.Stderr, "Error: unlock the credentials: %s\n", err))
+			return 1
+		}
+	}
+
+	connectionsRepository := infrastructure.NewFyneConnectionsRepository(
+		a.Preferences(),
+		eventBus,
+		infrastructure.WithSecretStore(secretStore),
+	)
+
+	handler := s3.NewS3EventHandler(connectionsRepository, eventBus, notifier)
+	handler.Listen()
+	defer handler.Destroy()
+
+	err := cli.New(eventBus, connectionsRepository, os.Stdout).Run(ctx, args)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, cli.ErrUsage):
+		u.SkipV(fmt.Fprintf(os.Stderr, "Error: %s\n\n", err))
+		cli.Usage(os.Stderr)
+		return 2
+	default:
+		u.SkipV(fmt.Fprintf(os.Stderr, "Error: %s\n", err))
+		return 1
+	}
+}
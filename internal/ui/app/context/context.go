@@ -19,6 +19,7 @@ type AppContext interface {
 	SettingsViewModel() viewmodel.SettingsViewModel
 	NotificationViewModel() viewmodel.NotificationViewModel
 	EditorViewModel() viewmodel.EditorViewModel
+	TransferViewModel() viewmodel.TransferViewModel
 
 	Window() fyne.Window
 	L() *zap.Logger
@@ -35,6 +36,7 @@ type AppContextImpl struct {
 	settingsViewModel     viewmodel.SettingsViewModel
 	notificationViewModel viewmodel.NotificationViewModel
 	editorViewModel       viewmodel.EditorViewModel
+	transferViewModel     viewmodel.TransferViewModel
 
 	window       fyne.Window
 	logger       *zap.Logger
@@ -59,6 +61,7 @@ func New(
 	settingsViewModel viewmodel.SettingsViewModel,
 	notificationViewModel viewmodel.NotificationViewModel,
 	editorViewModel viewmodel.EditorViewModel,
+	transferViewModel viewmodel.TransferViewModel,
 	initialRoute navigation.Route,
 	menu map[navigation.Route]Menu,
 	logger *zap.Logger,
@@ -78,6 +81,7 @@ func New(
 		settingsViewModel:     settingsViewModel,
 		notificationViewModel: notificationViewModel,
 		editorViewModel:       editorViewModel,
+		transferViewModel:     transferViewModel,
 		window:                window,
 		logger:                logger,
 		currentRoute:          initialRoute,
@@ -120,6 +124,10 @@ func (ctx *AppContextImpl) EditorViewModel() viewmodel.EditorViewModel {
 	return ctx.editorViewModel
 }
 
+func (ctx *AppContextImpl) TransferViewModel() viewmodel.TransferViewModel {
+	return ctx.transferViewModel
+}
+
 func (ctx *AppContextImpl) Window() fyne.Window {
 	return ctx.window
 }
@@ -7,4 +7,5 @@ const (
 	ConnectionRoute    = "connection"
 	SettingsRoute      = "settings"
 	NotificationsRoute = "notifications"
+	TransfersRoute     = "transfers"
 )
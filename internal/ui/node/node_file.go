@@ -37,3 +37,10 @@ func NewFileNode(file *directory.File, opts ...Option) FileNode {
 func (n *fileNodeImpl) File() *directory.File {
 	return n.file
 }
+
+func (n *fileNodeImpl) StatusTitle() string {
+	if n.file.IsDeleted() {
+		return "deleted"
+	}
+	return ""
+}
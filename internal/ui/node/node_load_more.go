@@ -0,0 +1,50 @@
+package node
+
+import (
+	"fyne.io/fyne/v2/theme"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+// LoadMoreNode is the last child of a partially loaded directory.
+// Selecting it loads the next page of the directory content.
+type LoadMoreNode interface {
+	Node
+	Directory() *directory.Directory
+}
+
+type loadMoreNodeImpl struct {
+	baseNode
+	dir *directory.Directory
+}
+
+var (
+	_ Node         = (*loadMoreNodeImpl)(nil)
+	_ LoadMoreNode = (*loadMoreNodeImpl)(nil)
+)
+
+func NewLoadMoreNode(dir *directory.Directory) LoadMoreNode {
+	return &loadMoreNodeImpl{
+		baseNode: baseNode{
+			id:          LoadMoreNodeID(dir),
+			displayName: "Load more...",
+			icon:        theme.MoreHorizontalIcon(),
+		},
+		dir: dir,
+	}
+}
+
+// LoadMoreNodeID returns the ID of the load more node of the directory.
+func LoadMoreNodeID(dir *directory.Directory) string {
+	return dir.Path().String() + "#load-more"
+}
+
+func (n *loadMoreNodeImpl) Directory() *directory.Directory {
+	return n.dir
+}
+
+func (n *loadMoreNodeImpl) StatusTitle() string {
+	if n.dir.IsLoading() {
+		return "loading"
+	}
+	return ""
+}
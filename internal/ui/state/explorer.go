@@ -2,26 +2,44 @@ package state
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2/data/binding"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/u"
 	"github.com/thomas-marquis/s3-box/internal/ui/node"
 )
 
 type ExplorerState struct {
 	fileTree binding.Tree[node.Node]
+
+	selection       *directory.Selection
+	selectionSize   binding.Int
+	selectionAnchor string
 }
 
 func (s *ExplorerState) FileTree() binding.Tree[node.Node] {
 	return s.fileTree
 }
 
-func (s *ExplorerState) InitFileTree(rootDir *directory.Directory, bucketName string) error {
+// InitFileTree resets the tree to the root directory, labelled with the bucket and the root prefix.
+func (s *ExplorerState) InitFileTree(rootDir *directory.Directory, bucketName, rootPrefix string) error {
 	s.fileTree = binding.NewTree[node.Node](func(n1 node.Node, n2 node.Node) bool {
 		return n1.ID() == n2.ID()
 	})
 
+	s.selection = directory.NewSelection(rootDir.ConnectionID())
+	s.selectionAnchor = ""
+	u.Skip(s.selectionSize.Set(0))
+
 	displayLabel := "Bucket: " + bucketName
+	if rootPrefix != "" {
+		displayLabel += "/" + rootPrefix
+	}
+	if bucketName == "" {
+		displayLabel = "No bucket selected"
+	}
 	rootNode := node.NewDirectoryNode(rootDir, node.WithDisplayName(displayLabel))
 	if err := s.fileTree.Append("", rootNode.ID(), rootNode); err != nil {
 		return NewError("failed adding root directory to file tree", err)
@@ -39,6 +57,8 @@ func (s *ExplorerState) ClearChildren(dir *directory.Directory) {
 		subNodePaths = append(subNodePaths, f.FullPath())
 	}
 
+	subNodePaths = append(subNodePaths, node.LoadMoreNodeID(dir))
+
 	for _, p := range subNodePaths {
 		if err := s.fileTree.Remove(p); err != nil {
 			continue
@@ -47,9 +67,31 @@ func (s *ExplorerState) ClearChildren(dir *directory.Directory) {
 }
 
 func (s *ExplorerState) CreateChildren(dir *directory.Directory) {
-	files := dir.Files()
-	subDirs := dir.SubDirectories()
+	s.appendChildren(dir, dir.SubDirectories(), dir.Files())
+}
 
+// AppendPage adds the content of a newly loaded page at the end of the directory children,
+// keeping the load more node last while the directory is still partially loaded.
+func (s *ExplorerState) AppendPage(dir *directory.Directory, subDirs []*directory.Directory, files []*directory.File) {
+	u.Skip(s.fileTree.Remove(node.LoadMoreNodeID(dir)))
+
+	var newSubDirs []*directory.Directory
+	for _, subDir := range subDirs {
+		if !s.IsNodeExists(subDir.Path().String()) {
+			newSubDirs = append(newSubDirs, subDir)
+		}
+	}
+	var newFiles []*directory.File
+	for _, file := range files {
+		if !s.IsNodeExists(file.FullPath()) {
+			newFiles = append(newFiles, file)
+		}
+	}
+
+	s.appendChildren(dir, newSubDirs, newFiles)
+}
+
+func (s *ExplorerState) appendChildren(dir *directory.Directory, subDirs []*directory.Directory, files []*directory.File) {
 	for _, subDir := range subDirs {
 		subDirNode := node.NewDirectoryNode(subDir)
 		if err := s.fileTree.Append(dir.Path().String(), subDirNode.ID(), subDirNode); err != nil {
@@ -66,6 +108,13 @@ func (s *ExplorerState) CreateChildren(dir *directory.Directory) {
 			continue
 		}
 	}
+
+	if dir.HasMorePages() {
+		loadMoreNode := node.NewLoadMoreNode(dir)
+		if err := s.fileTree.Append(dir.Path().String(), loadMoreNode.ID(), loadMoreNode); err != nil {
+			logger.Printf("error appending the load more node to tree: %s", err)
+		}
+	}
 }
 
 func (s *ExplorerState) UpdateChildren(dir *directory.Directory) {
@@ -150,3 +199,169 @@ func (s *ExplorerState) GetDirectoryNode(path directory.Path) (node.DirectoryNod
 	}
 	return dirNode, nil
 }
+
+// Selection returns the files and directories selected in the file tree for bulk operations.
+func (s *ExplorerState) Selection() *directory.Selection {
+	return s.selection
+}
+
+// SelectionSize returns the number of selected files and directories.
+func (s *ExplorerState) SelectionSize() binding.Int {
+	return s.selectionSize
+}
+
+func (s *ExplorerState) IsNodeSelected(nodeID string) bool {
+	n, err := s.fileTree.GetValue(nodeID)
+	if err != nil {
+		return false
+	}
+	switch n := n.(type) {
+	case node.DirectoryNode:
+		return s.selection.ContainsDirectory(n.Directory())
+	case node.FileNode:
+		return s.selection.ContainsFile(n.File())
+	}
+	return false
+}
+
+// ToggleNodeSelection adds the node to the selection, or removes it when it is already selected.
+// The node becomes the anchor of the next range selection.
+func (s *ExplorerState) ToggleNodeSelection(nodeID string) error {
+	n, err := s.fileTree.GetValue(nodeID)
+	if err != nil {
+		return NewError(fmt.Sprintf("node '%s' not found in the file tree", nodeID), err)
+	}
+
+	switch n := n.(type) {
+	case node.DirectoryNode:
+		if s.selection.ContainsDirectory(n.Directory()) {
+			s.selection.RemoveDirectory(n.Directory())
+		} else if err := s.selection.AddDirectory(n.Directory()); err != nil {
+			return NewError("failed selecting the directory", err)
+		}
+	case node.FileNode:
+		if s.selection.ContainsFile(n.File()) {
+			s.selection.RemoveFile(n.File())
+		} else if err := s.selection.AddFile(n.File()); err != nil {
+			return NewError("failed selecting the file", err)
+		}
+	}
+
+	s.selectionAnchor = nodeID
+	u.Skip(s.selectionSize.Set(s.selection.Len()))
+	return nil
+}
+
+// SelectNodeRange adds to the selection every node between the anchor and the given node.
+// When both nodes don't share the same parent, only the given node is selected.
+func (s *ExplorerState) SelectNodeRange(nodeID string) error {
+	rangeIDs := s.siblingsBetween(s.selectionAnchor, nodeID)
+	for _, id := range rangeIDs {
+		if err := s.selectNode(id); err != nil {
+			return err
+		}
+	}
+	s.selectionAnchor = nodeID
+	u.Skip(s.selectionSize.Set(s.selection.Len()))
+	return nil
+}
+
+// SelectionOrNode returns a copy of the selection, so that it is not affected by later changes.
+// When nothing is selected, the returned selection only holds the given node.
+func (s *ExplorerState) SelectionOrNode(nodeID string) (*directory.Selection, error) {
+	res := directory.NewSelection(s.selection.ConnectionID())
+	if s.selection.IsEmpty() {
+		n, err := s.fileTree.GetValue(nodeID)
+		if err != nil {
+			return nil, NewError(fmt.Sprintf("node '%s' not found in the file tree", nodeID), err)
+		}
+		switch n := n.(type) {
+		case node.DirectoryNode:
+			if n.Directory().IsRoot() {
+				return nil, NewError("the root directory can't be selected")
+			}
+			err = res.AddDirectory(n.Directory())
+		case node.FileNode:
+			err = res.AddFile(n.File())
+		}
+		if err != nil {
+			return nil, NewError(fmt.Sprintf("failed selecting node '%s'", nodeID), err)
+		}
+		return res, nil
+	}
+
+	for _, d := range s.selection.Directories() {
+		u.Skip(res.AddDirectory(d))
+	}
+	for _, f := range s.selection.Files() {
+		u.Skip(res.AddFile(f))
+	}
+	return res, nil
+}
+
+// ClearSelection unselects every node.
+func (s *ExplorerState) ClearSelection() {
+	s.selection.Clear()
+	s.selectionAnchor = ""
+	u.Skip(s.selectionSize.Set(0))
+}
+
+func (s *ExplorerState) selectNode(nodeID string) error {
+	n, err := s.fileTree.GetValue(nodeID)
+	if err != nil {
+		return NewError(fmt.Sprintf("node '%s' not found in the file tree", nodeID), err)
+	}
+
+	switch n := n.(type) {
+	case node.DirectoryNode:
+		if n.Directory().IsRoot() {
+			return nil
+		}
+		err = s.selection.AddDirectory(n.Directory())
+	case node.FileNode:
+		err = s.selection.AddFile(n.File())
+	}
+	if err != nil {
+		return NewError(fmt.Sprintf("failed selecting node '%s'", nodeID), err)
+	}
+	return nil
+}
+
+func (s *ExplorerState) siblingsBetween(fromID, toID string) []string {
+	childIDs, _, err := s.fileTree.Get()
+	if err != nil || fromID == "" {
+		return []string{toID}
+	}
+
+	for _, siblings := range childIDs {
+		from := slices.Index(siblings, fromID)
+		to := slices.Index(siblings, toID)
+		if from < 0 || to < 0 {
+			continue
+		}
+		if from > to {
+			from, to = to, from
+		}
+		return siblings[from : to+1]
+	}
+	return []string{toID}
+}
+
+// Directories returns every directory displayed in the file tree, sorted by path.
+func (s *ExplorerState) Directories() []*directory.Directory {
+	_, values, err := s.fileTree.Get()
+	if err != nil {
+		return nil
+	}
+
+	dirs := make([]*directory.Directory, 0)
+	for _, n := range values {
+		if dirNode, ok := n.(node.DirectoryNode); ok {
+			dirs = append(dirs, dirNode.Directory())
+		}
+	}
+	slices.SortFunc(dirs, func(a, b *directory.Directory) int {
+		return strings.Compare(a.Path().String(), b.Path().String())
+	})
+	return dirs
+}
@@ -6,6 +6,7 @@ import (
 	fyne_test "fyne.io/fyne/v2/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 	"github.com/thomas-marquis/s3-box/internal/tu"
 	"github.com/thomas-marquis/s3-box/internal/ui/node"
@@ -21,7 +22,7 @@ func TestExplorerState_InitFileTree(t *testing.T) {
 		rootDir := tu.MakeDirectory(t, "", tu.AsRoot())
 
 		// When
-		err := s.Explorer().InitFileTree(rootDir, "myBucket")
+		err := s.Explorer().InitFileTree(rootDir, "myBucket", "")
 
 		// Then
 		assert.NoError(t, err)
@@ -36,6 +37,22 @@ func TestExplorerState_InitFileTree(t *testing.T) {
 			"": {"/"},
 		}, childIds)
 	})
+
+	t.Run("should show the root prefix in the label of the root node", func(t *testing.T) {
+		// Given
+		s := state.New()
+		rootDir := tu.MakeDirectory(t, "", tu.AsRoot())
+
+		// When
+		err := s.Explorer().InitFileTree(rootDir, "myBucket", "team-x/")
+
+		// Then
+		assert.NoError(t, err)
+
+		_, values, err := s.Explorer().FileTree().Get()
+		require.NoError(t, err)
+		assert.Equal(t, node.NewDirectoryNode(rootDir, node.WithDisplayName("Bucket: myBucket/team-x/")), values["/"])
+	})
 }
 
 func TestExplorerState_AppendFile(t *testing.T) {
@@ -48,7 +65,7 @@ func TestExplorerState_AppendFile(t *testing.T) {
 		rootDir := tu.MakeDirectory(t, "",
 			tu.AsRoot(),
 			tu.WithFileTo("file.txt", &f))
-		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
 
 		// When
 		err := s.Explorer().AppendFile(f)
@@ -83,7 +100,7 @@ func TestExplorerState_AppendFile(t *testing.T) {
 			tu.WithSubDirectory("src",
 				tu.To(&srcDir),
 				tu.WithFileTo("user.go", &userFile)))
-		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
 
 		require.NoError(t, s.Explorer().AppendFile(mainFile))
 		require.NoError(t, s.Explorer().AppendFile(readmeFile))
@@ -128,7 +145,7 @@ func TestExplorerState_PrependDirectory(t *testing.T) {
 			tu.IsLoaded(),
 			tu.WithSubDirectory("data",
 				tu.To(&dataDir)))
-		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
 
 		// When
 		err := s.Explorer().PrependDirectory(dataDir)
@@ -165,7 +182,7 @@ func TestExplorerState_PrependDirectory(t *testing.T) {
 				tu.WithFileTo("user.go", &userFile),
 				tu.WithSubDirectory("infra",
 					tu.To(&infraDir))))
-		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
 
 		require.NoError(t, s.Explorer().AppendFile(mainFile))
 		require.NoError(t, s.Explorer().AppendFile(readmeFile))
@@ -206,9 +223,9 @@ func TestExplorerState_PrependDirectory(t *testing.T) {
 				tu.To(&dataDir),
 				tu.WithSubDirectory("csv",
 					tu.To(&csvDir))))
-		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
 
-		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
 
 		// When
 		err := s.Explorer().PrependDirectory(csvDir)
@@ -219,3 +236,147 @@ func TestExplorerState_PrependDirectory(t *testing.T) {
 		assert.ErrorContains(t, err, "failed prepending the directory '/data/csv/' to file tree because its parents has not been found")
 	})
 }
+
+func TestExplorerState_AppendPage(t *testing.T) {
+	fyne_test.NewTempApp(t)
+
+	t.Run("should keep the load more node after the children while the directory is partially loaded", func(t *testing.T) {
+		// Given
+		s := state.New()
+		rootDir := tu.FakeNotLoadedRootDirectory(t)
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
+
+		_, err := rootDir.Load()
+		require.NoError(t, err)
+		f1, _ := directory.NewFile("a.txt", rootDir)
+		require.NoError(t, rootDir.Notify(event.New(directory.LoadSucceeded{
+			Directory:     rootDir,
+			Files:         []*directory.File{f1},
+			NextPageToken: "token-1",
+		})))
+		s.Explorer().CreateChildren(rootDir)
+
+		childIds, _, err := s.Explorer().FileTree().Get()
+		require.NoError(t, err)
+		require.Equal(t, []string{"/a.txt", "/#load-more"}, childIds["/"])
+
+		_, err = rootDir.LoadNextPage()
+		require.NoError(t, err)
+		f2, _ := directory.NewFile("b.txt", rootDir)
+		sub, _ := directory.New(rootDir.ConnectionID(), "sub", rootDir)
+		require.NoError(t, rootDir.Notify(event.New(directory.LoadPageSucceeded{
+			Directory:      rootDir,
+			Files:          []*directory.File{f2},
+			SubDirectories: []*directory.Directory{sub},
+		})))
+
+		// When
+		s.Explorer().AppendPage(rootDir, []*directory.Directory{sub}, []*directory.File{f2})
+
+		// Then
+		childIds, _, err = s.Explorer().FileTree().Get()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/a.txt", "/sub/", "/b.txt"}, childIds["/"])
+		assert.False(t, s.Explorer().IsNodeExists(node.LoadMoreNodeID(rootDir)))
+	})
+}
+
+func TestExplorerState_Selection(t *testing.T) {
+	fyne_test.NewTempApp(t)
+
+	setup := func(t *testing.T) (*state.State, *directory.Directory) {
+		s := state.New()
+		rootDir := tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.IsLoaded(),
+			tu.WithSubDirectory("mydir"),
+			tu.WithFile("a.txt"),
+			tu.WithFile("b.txt"),
+			tu.WithFile("c.txt"))
+		require.NoError(t, s.Explorer().InitFileTree(rootDir, "myBucket", ""))
+		s.Explorer().CreateChildren(rootDir)
+		return s, rootDir
+	}
+
+	t.Run("should toggle the selection of a node", func(t *testing.T) {
+		// Given
+		s, _ := setup(t)
+
+		// When
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/a.txt"))
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/mydir/"))
+
+		// Then
+		assert.True(t, s.Explorer().IsNodeSelected("/a.txt"))
+		assert.True(t, s.Explorer().IsNodeSelected("/mydir/"))
+		size, _ := s.Explorer().SelectionSize().Get()
+		assert.Equal(t, 2, size)
+
+		// When
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/a.txt"))
+
+		// Then
+		assert.False(t, s.Explorer().IsNodeSelected("/a.txt"))
+		size, _ = s.Explorer().SelectionSize().Get()
+		assert.Equal(t, 1, size)
+	})
+
+	t.Run("should select every sibling between the anchor and the clicked node", func(t *testing.T) {
+		// Given
+		s, _ := setup(t)
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/mydir/"))
+
+		// When
+		err := s.Explorer().SelectNodeRange("/b.txt")
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, s.Explorer().IsNodeSelected("/mydir/"))
+		assert.True(t, s.Explorer().IsNodeSelected("/a.txt"))
+		assert.True(t, s.Explorer().IsNodeSelected("/b.txt"))
+		assert.False(t, s.Explorer().IsNodeSelected("/c.txt"))
+	})
+
+	t.Run("should clear the selection", func(t *testing.T) {
+		// Given
+		s, _ := setup(t)
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/a.txt"))
+
+		// When
+		s.Explorer().ClearSelection()
+
+		// Then
+		assert.True(t, s.Explorer().Selection().IsEmpty())
+		size, _ := s.Explorer().SelectionSize().Get()
+		assert.Equal(t, 0, size)
+	})
+	t.Run("should return the clicked node when nothing is selected", func(t *testing.T) {
+		// Given
+		s, _ := setup(t)
+
+		// When
+		res, err := s.Explorer().SelectionOrNode("/a.txt")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res.Len())
+		assert.Equal(t, "a.txt", res.Files()[0].Name().String())
+	})
+
+	t.Run("should return a copy of the selection not affected by later changes", func(t *testing.T) {
+		// Given
+		s, _ := setup(t)
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/a.txt"))
+		require.NoError(t, s.Explorer().ToggleNodeSelection("/mydir/"))
+
+		// When
+		res, err := s.Explorer().SelectionOrNode("/c.txt")
+		s.Explorer().ClearSelection()
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, 2, res.Len())
+		assert.Len(t, res.Directories(), 1)
+		assert.Equal(t, "a.txt", res.Files()[0].Name().String())
+	})
+}
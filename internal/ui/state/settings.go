@@ -16,6 +16,7 @@ type SettingsState struct {
 	timeout    binding.Item[time.Duration]
 	fileLimit  binding.Item[uint64]
 	colorTheme binding.String
+	transfers  binding.Item[uint64]
 
 	isReady       binding.Bool
 	statusMessage binding.String
@@ -27,6 +28,7 @@ func newSettingsState() *SettingsState {
 		settings.AString(values.SettingColorTheme, values.DefaultColorTheme),
 		settings.AUint64(values.SettingEditFileSizeLimitByte, values.DefaultMaxFileSizeEditBytes),
 		settings.ADuration(values.SettingTimeoutSec, values.DefaultTimeout),
+		settings.AUint64(values.SettingMaxTransfers, values.DefaultMaxTransfers),
 	); err != nil {
 		panic(err)
 	}
@@ -36,6 +38,7 @@ func newSettingsState() *SettingsState {
 		timeout:       uu.NewSettingsBindingDuration(settingsAgg, values.SettingTimeoutSec),
 		fileLimit:     uu.NewSettingsBindingIntToUint64(settingsAgg, values.SettingEditFileSizeLimitByte),
 		colorTheme:    uu.NewSettingsBindingString(settingsAgg, values.SettingColorTheme),
+		transfers:     uu.NewSettingsBindingIntToUint64(settingsAgg, values.SettingMaxTransfers),
 		isReady:       binding.NewBool(),
 		statusMessage: binding.NewString(),
 	}
@@ -75,6 +78,11 @@ func (s *SettingsState) EditorFileSizeLimitBytesValue() uint64 {
 	return val
 }
 
+// MaxTransfers is the maximum number of files uploaded or downloaded at the same time.
+func (s *SettingsState) MaxTransfers() binding.Item[uint64] {
+	return s.transfers
+}
+
 func (s *SettingsState) ColorTheme() binding.String {
 	return s.colorTheme
 }
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"fyne.io/fyne/v2/data/binding"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 	"github.com/thomas-marquis/s3-box/internal/ui/node"
 )
 
@@ -27,6 +29,8 @@ func New() *State {
 			fileTree: binding.NewTree[node.Node](func(n1 node.Node, n2 node.Node) bool {
 				return n1.ID() == n2.ID()
 			}),
+			selection:     directory.NewSelection(connection_deck.ConnectionID{}),
+			selectionSize: binding.NewInt(),
 		},
 		settings: newSettingsState(),
 	}
@@ -14,4 +14,5 @@ const (
 	SettingColorTheme            = "app.colorTheme"
 	SettingEditFileSizeLimitByte = "app.editFileSizeLimitByte"
 	SettingTimeoutSec            = "app.timeoutSec"
+	SettingMaxTransfers          = "app.maxConcurrentTransfers"
 )
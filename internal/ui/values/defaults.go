@@ -6,4 +6,5 @@ const (
 	DefaultTimeout              = 30 * time.Second
 	DefaultMaxFileSizeEditBytes = 20 * KiB
 	DefaultColorTheme           = ColorThemeSystem
+	DefaultMaxTransfers         = 3
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	"fyne.io/fyne/v2/data/binding"
 	"github.com/thomas-marquis/it-happened/event"
@@ -46,9 +47,34 @@ type ConnectionViewModel interface {
 	Update(connID connection_deck.ConnectionID, options ...connection_deck.ConnectionOption)
 
 	// ExportAsJSON exports all connections JSON serialized.
-	// The JSON object will be written in the writer.
+	// The JSON object will be written in the writer, with or without the credentials, or encrypted, depending on the options.
 	// It's up to you to effectively write the writer into a file or whatever.
-	ExportAsJSON(writer io.Writer) error
+	ExportAsJSON(writer io.Writer, options connection_deck.ExportOptions) error
+
+	// PrepareImport reads the connections to import and compares them with the ones of the deck.
+	// When file is nil, the connections are read from the default location of the source.
+	PrepareImport(file io.Reader, options connection_deck.ImportOptions) (*connection_deck.ImportPreview, error)
+
+	// Import adds the new connections of the preview to the deck.
+	// The existing connections are updated with the imported settings only when merge is true.
+	Import(preview *connection_deck.ImportPreview, merge bool)
+
+	// ListBuckets asks the buckets the connection has access to, the connection may not be saved yet.
+	// onResult is called with the sorted bucket names once listed, or with the error.
+	ListBuckets(conn *connection_deck.Connection, onResult func(buckets []string, err error))
+
+	// Diagnose checks the connection step by step, the connection may not be saved yet.
+	// onResult is called with the steps once they all ran.
+	Diagnose(conn *connection_deck.Connection, onResult func(steps []connection_deck.DiagnosticStep))
+
+	// SwitchBucket browses another bucket with an account-level connection.
+	SwitchBucket(conn *connection_deck.Connection, bucket string)
+
+	// SecretsProtected returns true when the credentials are kept in the encrypted secret store.
+	SecretsProtected() bool
+
+	// ProtectSecrets moves the credentials to an encrypted secret store, protected by the passphrase.
+	ProtectSecrets(passphrase string) error
 }
 
 type connectionViewModelImpl struct {
@@ -62,6 +88,10 @@ type connectionViewModelImpl struct {
 	notifier             notification.Repository
 	onChangeCallbacks    []func(*connection_deck.Connection)
 	bus                  event.Bus
+
+	pendingMu sync.Mutex
+	// pending are the callbacks waiting for the result of a request, by ID of its triggering event
+	pending map[int64]func(result event.Event)
 }
 
 func NewConnectionViewModel(
@@ -102,6 +132,7 @@ func NewConnectionViewModel(
 		notifier:             notifier,
 		onChangeCallbacks:    make([]func(*connection_deck.Connection), 0),
 		bus:                  bus,
+		pending:              make(map[int64]func(event.Event)),
 	}
 
 	vm.initConnections(deck)
@@ -130,6 +161,11 @@ func NewConnectionViewModel(
 		), vm.handleUpdate).
 		On(event.Is(connection_deck.CreateConnectionSucceededType), vm.handleCreate).
 		On(event.Is(connection_deck.RemoveConnectionSucceededType), vm.handleDelete).
+		On(event.IsOneOf(
+			connection_deck.ListBucketsSucceededType,
+			connection_deck.ListBucketsFailedType,
+			connection_deck.DiagnoseSucceededType,
+		), vm.handlePendingResult).
 		ListenWithWorkers(1)
 
 	return vm
@@ -213,10 +249,100 @@ func (v *connectionViewModelImpl) handleCreate(evt event.Event) {
 	u.Skip(v.loading.Set(false))
 }
 
-func (v *connectionViewModelImpl) ExportAsJSON(writer io.Writer) error {
+func (v *connectionViewModelImpl) ExportAsJSON(writer io.Writer, options connection_deck.ExportOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), v.appState.Settings().TimeoutValue())
+	defer cancel()
+	if err := v.connectionRepository.Export(ctx, writer, options); err != nil {
+		v.notifier.NotifyError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (v *connectionViewModelImpl) PrepareImport(
+	file io.Reader,
+	options connection_deck.ImportOptions,
+) (*connection_deck.ImportPreview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), v.appState.Settings().TimeoutValue())
+	defer cancel()
+	conns, err := v.connectionRepository.Import(ctx, file, options)
+	if err != nil {
+		v.notifier.NotifyError(err)
+		return nil, err
+	}
+
+	return v.deck.PrepareImport(conns), nil
+}
+
+func (v *connectionViewModelImpl) Import(preview *connection_deck.ImportPreview, merge bool) {
+	for _, evt := range preview.Apply(merge) {
+		v.bus.Publish(evt)
+	}
+}
+
+func (v *connectionViewModelImpl) ListBuckets(
+	conn *connection_deck.Connection,
+	onResult func(buckets []string, err error),
+) {
+	v.request(conn.ListBuckets(), func(result event.Event) {
+		switch pl := result.Payload().(type) {
+		case connection_deck.ListBucketsSucceeded:
+			onResult(pl.Buckets, nil)
+		case connection_deck.ListBucketsFailed:
+			onResult(nil, pl.Error())
+		}
+	})
+}
+
+func (v *connectionViewModelImpl) Diagnose(
+	conn *connection_deck.Connection,
+	onResult func(steps []connection_deck.DiagnosticStep),
+) {
+	v.request(conn.Diagnose(), func(result event.Event) {
+		onResult(result.Payload().(connection_deck.DiagnoseSucceeded).Steps)
+	})
+}
+
+// request publishes the event and calls onResult with its follow-up result.
+func (v *connectionViewModelImpl) request(evt event.Event, onResult func(result event.Event)) {
+	v.pendingMu.Lock()
+	v.pending[evt.ID()] = onResult
+	v.pendingMu.Unlock()
+	v.bus.Publish(evt)
+}
+
+func (v *connectionViewModelImpl) handlePendingResult(evt event.Event) {
+	v.pendingMu.Lock()
+	onResult, ok := v.pending[evt.ParentID()]
+	delete(v.pending, evt.ParentID())
+	v.pendingMu.Unlock()
+	if ok {
+		onResult(evt)
+	}
+}
+
+func (v *connectionViewModelImpl) SwitchBucket(conn *connection_deck.Connection, bucket string) {
+	evt, err := v.deck.SwitchBucket(conn.ID(), bucket)
+	if err != nil {
+		v.notifier.NotifyError(err)
+		v.bus.Publish(event.New(connection_deck.UpdateConnectionFailed{
+			ConnectionPayload: connection_deck.ConnectionPayload{Conn: conn},
+			Err:               fmt.Errorf("impossible to switch the bucket of connection %s: %w", conn.ID(), err),
+		}))
+		return
+	}
+	v.bus.Publish(evt)
+}
+
+func (v *connectionViewModelImpl) SecretsProtected() bool {
+	return v.connectionRepository.SecretsProtected()
+}
+
+func (v *connectionViewModelImpl) ProtectSecrets(passphrase string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), v.appState.Settings().TimeoutValue())
 	defer cancel()
-	if err := v.connectionRepository.Export(ctx, writer); err != nil {
+	if err := v.connectionRepository.ProtectSecrets(ctx, passphrase); err != nil {
 		v.notifier.NotifyError(err)
 		return err
 	}
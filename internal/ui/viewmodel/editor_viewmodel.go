@@ -103,12 +103,16 @@ func (v *editorViewModelImpl) Open(file *directory.File) (editor.Editor, error)
 		return nil, ErrNoConnectionSelected
 	}
 
-	if e, ok := v.openedEditors[file.FullPath()]; ok {
+	if e, ok := v.openedEditors[editorKey(file)]; ok {
 		fyne.Do(e.Window().RequestFocus)
 		return e, ErrEditorAlreadyOpened
 	}
 
-	newWin := fyne.CurrentApp().NewWindow(file.Name().String())
+	title := file.Name().String()
+	if file.VersionID() != "" {
+		title += fmt.Sprintf(" (version %s, read-only)", file.VersionID())
+	}
+	newWin := fyne.CurrentApp().NewWindow(title)
 
 	var e editor.Editor
 	if strings.HasSuffix(file.Name().String(), ".csv") {
@@ -117,7 +121,7 @@ func (v *editorViewModelImpl) Open(file *directory.File) (editor.Editor, error)
 		e = v.editorFactories["text"](v.bus, newWin, file)
 	}
 
-	v.openedEditors[file.FullPath()] = e
+	v.openedEditors[editorKey(file)] = e
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -138,7 +142,7 @@ func (v *editorViewModelImpl) requestClose(e editor.Editor, cancelFunc func()) {
 func (v *editorViewModelImpl) handleFileLoadingSuccess(evt event.Event) {
 	pl := evt.Payload().(directory.LoadFileSucceeded)
 
-	e, ok := v.openedEditors[pl.File.FullPath()]
+	e, ok := v.openedEditors[editorKey(pl.File)]
 	if !ok {
 		// The editor has been closed before the file was loaded. And it's okay
 		return
@@ -154,7 +158,7 @@ func (v *editorViewModelImpl) handleFileLoadingSuccess(evt event.Event) {
 	}
 
 	v.mu.Lock()
-	v.loadedContents[pl.File.FullPath()] = pl.Content
+	v.loadedContents[editorKey(pl.File)] = pl.Content
 	v.mu.Unlock()
 
 	v.bus.Publish(evt.NewFollowup(editor.Loaded{
@@ -167,7 +171,7 @@ func (v *editorViewModelImpl) handleFileLoadingFailure(evt event.Event) {
 	pl := evt.Payload().(directory.LoadFileFailed)
 	v.notifier.NotifyError(pl.Err)
 
-	e, ok := v.openedEditors[pl.File.FullPath()]
+	e, ok := v.openedEditors[editorKey(pl.File)]
 	if !ok {
 		// The editor has been closed before the file was loaded. And it's okay
 		return
@@ -192,12 +196,12 @@ func (v *editorViewModelImpl) handleEditorCloseCanceled(evt event.Event) {
 }
 
 func (v *editorViewModelImpl) IsOpen(file *directory.File) bool {
-	_, ok := v.openedEditors[file.FullPath()]
+	_, ok := v.openedEditors[editorKey(file)]
 	return ok
 }
 
 func (v *editorViewModelImpl) unregisterEditor(file *directory.File) {
-	path := file.FullPath()
+	path := editorKey(file)
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	delete(v.openedEditors, path)
@@ -245,3 +249,11 @@ func (v *editorViewModelImpl) forceCloseAll() {
 		fyne.Do(oe.Window().Close)
 	}
 }
+
+// editorKey identifies the editor of a file, each version of a file having its own editor.
+func editorKey(file *directory.File) string {
+	if file.VersionID() == "" {
+		return file.FullPath()
+	}
+	return file.FullPath() + "?versionId=" + file.VersionID()
+}
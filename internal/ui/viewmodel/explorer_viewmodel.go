@@ -1,10 +1,15 @@
 package viewmodel
 
 import (
+	"context"
 	"errors"
 	"io/fs"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/thomas-marquis/it-happened/event"
 	"github.com/thomas-marquis/s3-box/internal/u"
@@ -17,6 +22,7 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/storage"
+	"github.com/dustin/go-humanize"
 	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 	"github.com/thomas-marquis/s3-box/internal/domain/notification"
@@ -31,6 +37,13 @@ type UploadPreviewState struct {
 	BaseUri string
 }
 
+type PastePreviewState struct {
+	Preview     *directory.Preview
+	Selection   *directory.Selection
+	Destination *directory.Directory
+	Move        bool
+}
+
 // ExplorerViewModel represents the view model for the file explorer interface.
 // It handles the tree structure display, file operations, and directory management
 // while maintaining the connection with the underlying storage system.
@@ -55,6 +68,9 @@ type ExplorerViewModel interface {
 	SetSelectedDirectory(dir *directory.Directory)
 	IsSelectedDirectoryLoading() binding.Bool
 
+	// ShowDeletedFiles tells if the files whose latest version is a delete marker are listed when loading a directory
+	ShowDeletedFiles() binding.Bool
+
 	PendingUserValidations() <-chan directory.UserValidationAsked
 
 	// AddStateListener registers a callback function to be notified of any changes in directories or files.
@@ -73,9 +89,51 @@ type ExplorerViewModel interface {
 
 	ReloadDirectory(dir *directory.Directory) error
 
+	// LoadNextPage loads the next page of a partially loaded directory and appends it to the file tree.
+	LoadNextPage(dir *directory.Directory) error
+
 	// DownloadFile downloads a file to the specified local destination
 	DownloadFile(f *directory.File, dest string)
 
+	// DownloadDirectory downloads a directory and all its content into the local directory dest.
+	// Only one directory download can run at a time.
+	DownloadDirectory(dir *directory.Directory, dest string, strategy directory.MaterializeStrategy) error
+
+	// CancelDownloadDirectory interrupts the running download of the given directory
+	CancelDownloadDirectory(dir *directory.Directory)
+
+	// DownloadProgress returns a human-readable progress of the running directory download.
+	// It is empty when no directory download is running.
+	DownloadProgress() binding.String
+
+	// ComputeDirectorySize lists recursively the directory content to compute its size statistics,
+	// cached on the directory until its next load. Only one computation can run at a time.
+	ComputeDirectorySize(dir *directory.Directory) error
+
+	// CancelComputeDirectorySize interrupts the running size computation of the given directory
+	CancelComputeDirectorySize(dir *directory.Directory)
+
+	// SizeProgress returns a human-readable progress of the running size computation.
+	// It is empty when no size computation is running.
+	SizeProgress() binding.String
+
+	// PrepareSync compares the directory with a local folder, without changing anything.
+	// The resulting plan is given to the OnSyncReady callback.
+	PrepareSync(dir *directory.Directory, localPath string, opts directory.SyncOptions) error
+
+	// OnSyncReady registers a callback function to be notified when a sync plan is ready to be applied.
+	OnSyncReady(func(plan *directory.SyncPlan))
+
+	// ApplySync copies and deletes the files of the plan. Only one sync can run at a time.
+	ApplySync(plan *directory.SyncPlan) error
+
+	// CancelSync interrupts the running sync of the given directory
+	CancelSync(dir *directory.Directory)
+
+	// SyncProgress returns a human-readable progress of the running sync.
+	// It is empty when no sync is running.
+	SyncProgress() binding.String
+
 	PrepareUpload(uris []fyne.URI, dir *directory.Directory) error
 	DoUpload(localBasePath string, preview *directory.Preview, strategy directory.MaterializeStrategy)
 	UploadOne(localPath string, dir *directory.Directory, overwrite bool) error
@@ -85,6 +143,17 @@ type ExplorerViewModel interface {
 
 	DeleteDirectory(dir *directory.Directory)
 
+	// DeleteDirectoryRecursively removes a directory and all its content from storage.
+	// Only one recursive deletion can run at a time.
+	DeleteDirectoryRecursively(dir *directory.Directory) error
+
+	// CancelDeleteDirectory interrupts the running recursive deletion of the given directory
+	CancelDeleteDirectory(dir *directory.Directory)
+
+	// DeletionProgress returns a human-readable progress of the running recursive deletion.
+	// It is empty when no recursive deletion is running.
+	DeletionProgress() binding.String
+
 	// UpdateLastDownloadLocation updates the last used save directory path
 	UpdateLastDownloadLocation(filePath string) error
 
@@ -103,11 +172,120 @@ type ExplorerViewModel interface {
 	// RenameFile renames a file
 	RenameFile(file *directory.File, newName string)
 
+	// LoadFileMetadata fetches the headers and user metadata of a file
+	LoadFileMetadata(file *directory.File)
+
+	// UpdateFileMetadata replaces the content type, cache control and user metadata of a file
+	UpdateFileMetadata(file *directory.File, metadata directory.ObjectMetadata) error
+
+	// LoadFileTags fetches the tags of a file
+	LoadFileTags(file *directory.File)
+
+	// UpdateFileTags replaces all the tags of a file
+	UpdateFileTags(file *directory.File, tags directory.ObjectTags) error
+
+	// CreateFileShareLink generates a presigned URL for a file and copies it to the clipboard once ready
+	CreateFileShareLink(file *directory.File, access directory.ShareLinkAccess, expiry time.Duration) error
+
+	// LoadFileVersions fetches all the versions of a file
+	LoadFileVersions(file *directory.File)
+
+	// RestoreFileVersion copies a previous version of a file over the current one
+	RestoreFileVersion(file *directory.File, version directory.FileVersion) error
+
+	// DeleteFileVersion permanently deletes a version of a file
+	DeleteFileVersion(file *directory.File, version directory.FileVersion) error
+
 	Validate(event directory.UserValidationAsked, validated bool)
 
 	ResumeRename(dir *directory.Directory) error
 	RollbackRename(dir *directory.Directory) error
 	AbortRename(dir *directory.Directory) error
+
+	////////////////////////
+	// Selection methods
+	// Only one bulk operation, on the selection or pasting the clipboard, can run at a time.
+	////////////////////////
+
+	// Selection returns the files and directories selected in the tree for bulk operations
+	Selection() *directory.Selection
+
+	// SelectionSize returns the number of selected files and directories
+	SelectionSize() binding.Int
+
+	// ToggleSelection adds the tree node to the selection, or removes it when already selected
+	ToggleSelection(nodeID string) error
+
+	// SelectRange adds to the selection every node between the last toggled one and the given one
+	SelectRange(nodeID string) error
+
+	ClearSelection()
+
+	// DeleteSelection removes every selected file and directory, with all their content, from storage.
+	DeleteSelection() error
+
+	// DownloadSelection downloads every selected file and directory into the local directory dest.
+	DownloadSelection(dest string, strategy directory.MaterializeStrategy) error
+
+	// CopySelection copies every selected file and directory into dst.
+	CopySelection(dst *directory.Directory, strategy directory.MaterializeStrategy) error
+
+	// MoveSelection moves every selected file and directory into dst.
+	MoveSelection(dst *directory.Directory, strategy directory.MaterializeStrategy) error
+
+	// CancelSelectionOperation interrupts the running bulk operation, if any
+	CancelSelectionOperation()
+
+	// SelectionProgress returns a human-readable progress of the running bulk operation.
+	// It is empty when no bulk operation is running.
+	SelectionProgress() binding.String
+
+	////////////////////////
+	// Search methods
+	////////////////////////
+
+	// Search looks recursively for the files under dir matching the query, the results being streamed
+	// into SearchResults. A running search is canceled when a new one starts.
+	Search(dir *directory.Directory, query directory.SearchQuery) error
+
+	// CancelSearch interrupts the running search, if any
+	CancelSearch()
+
+	// SearchResults returns the files found by the last search
+	SearchResults() binding.List[directory.SearchHit]
+
+	// SearchProgress returns a human-readable progress of the running or the last search
+	SearchProgress() binding.String
+
+	IsSearching() binding.Bool
+
+	// RevealSearchHit loads the ancestors of the found file, then gives its tree node ID to the OnReveal callback.
+	RevealSearchHit(hit directory.SearchHit) error
+
+	// OnReveal registers a callback function to be notified when a file node is ready to be revealed in the tree.
+	OnReveal(func(nodeID string))
+
+	////////////////////////
+	// Clipboard methods
+	////////////////////////
+
+	// CopyToClipboard puts the selection in the clipboard, or the given tree node when nothing is selected.
+	CopyToClipboard(nodeID string) error
+
+	// CutToClipboard does the same as CopyToClipboard, but the clipboard content will be moved when pasted.
+	CutToClipboard(nodeID string) error
+
+	HasClipboardContent() bool
+
+	// PreparePaste lists the clipboard content to build a preview of its paste into dst.
+	// The preview is given to the OnPasteReady callback.
+	PreparePaste(dst *directory.Directory) error
+
+	// OnPasteReady registers a callback function to be notified when the paste preview is ready.
+	OnPasteReady(func(previewState PastePreviewState))
+
+	// Paste copies or moves the previewed clipboard content into its destination.
+	Paste(previewState PastePreviewState, strategy directory.MaterializeStrategy) error
 }
 
 type explorerViewModelImpl struct {
@@ -123,11 +301,44 @@ type explorerViewModelImpl struct {
 
 	selectedDirectory    *directory.Directory
 	isSelectedDirLoading binding.Bool
+	showDeletedFiles     binding.Bool
 
 	pendingUserValidations chan directory.UserValidationAsked
 
+	deletingDirectory *directory.Directory
+	cancelDeletion    context.CancelFunc
+	deletionProgress  binding.String
+
+	downloadingDirectory *directory.Directory
+	cancelDownload       context.CancelFunc
+	downloadProgress     binding.String
+
+	sizingDirectory *directory.Directory
+	cancelSizing    context.CancelFunc
+	sizeProgress    binding.String
+
+	syncingDirectory *directory.Directory
+	cancelSync       context.CancelFunc
+	syncProgress     binding.String
+
+	cancelSelectionOperation context.CancelFunc
+	selectionProgress        binding.String
+
+	clipboard     *directory.Selection
+	clipboardMove bool
+
+	searchEventID  int64
+	cancelSearch   context.CancelFunc
+	searchResults  binding.List[directory.SearchHit]
+	searchProgress binding.String
+	isSearching    binding.Bool
+	pendingReveal  *directory.SearchHit
+	onReveal       func(nodeID string)
+
 	stateListeners []func()
 	onUploadReady  func(previewState UploadPreviewState)
+	onPasteReady   func(previewState PastePreviewState)
+	onSyncReady    func(plan *directory.SyncPlan)
 
 	notifier notification.Repository
 	bus      event.Bus
@@ -154,7 +365,16 @@ func NewExplorerViewModel(
 		bus:                    bus,
 		selectedDirectory:      nil,
 		isSelectedDirLoading:   binding.NewBool(),
+		showDeletedFiles:       binding.NewBool(),
 		pendingUserValidations: make(chan directory.UserValidationAsked, maxPendingUserValidations),
+		deletionProgress:       binding.NewString(),
+		downloadProgress:       binding.NewString(),
+		sizeProgress:           binding.NewString(),
+		syncProgress:           binding.NewString(),
+		selectionProgress:      binding.NewString(),
+		searchResults:          binding.NewList(compareSearchHits),
+		searchProgress:         binding.NewString(),
+		isSearching:            binding.NewBool(),
 		stateListeners:         make([]func(), 0),
 		state:                  st,
 	}
@@ -183,17 +403,61 @@ func NewExplorerViewModel(
 		On(event.Is(directory.DeleteFileFailedType), v.handleDeleteFileFailure).
 		On(event.Is(directory.DownloadFileSucceededType), v.handleDownloadFileSuccess).
 		On(event.Is(directory.DownloadFileFailedType), v.handleDownloadFileFailure).
+		On(event.Is(directory.DownloadProgressType), v.handleDownloadDirProgress).
+		On(event.Is(directory.DownloadSucceededType), v.handleDownloadDirSuccess).
+		On(event.Is(directory.DownloadFailedType), v.handleDownloadDirFailure).
+		On(event.Is(directory.ComputeSizeProgressType), v.handleComputeSizeProgress).
+		On(event.Is(directory.ComputeSizeSucceededType), v.handleComputeSizeSuccess).
+		On(event.Is(directory.ComputeSizeFailedType), v.handleComputeSizeFailure).
+		On(event.Is(directory.SyncPreviewSucceededType), v.handleSyncPreviewSuccess).
+		On(event.Is(directory.SyncPreviewFailedType), v.handleSyncPreviewFailure).
+		On(event.Is(directory.SyncProgressType), v.handleSyncProgress).
+		On(event.Is(directory.SyncSucceededType), v.handleSyncSuccess).
+		On(event.Is(directory.SyncFailedType), v.handleSyncFailure).
 		On(event.Is(directory.LoadSucceededType), v.handleLoadDirSuccess).
 		On(event.Is(directory.LoadFailedType), v.handleLoadDirFailure).
+		On(event.Is(directory.LoadPageSucceededType), v.handleLoadDirPageSuccess).
+		On(event.Is(directory.SearchProgressType), v.handleSearchProgress).
+		On(event.Is(directory.SearchSucceededType), v.handleSearchSuccess).
+		On(event.Is(directory.SearchFailedType), v.handleSearchFailure).
+		On(event.Is(directory.LoadPageFailedType), v.handleLoadDirPageFailure).
 		On(event.Is(directory.RenameSucceededType), v.handleRenameDirectorySuccess).
 		On(event.Is(directory.RenameFailedType), v.handleRenameDirectoryFailure).
 		On(event.Is(directory.RenameFileSucceededType), v.handleRenameFileSuccess).
 		On(event.Is(directory.RenameFileFailedType), v.handleRenameFileFailure).
+		On(event.IsOneOf(
+			directory.LoadFileMetadataSucceededType,
+			directory.UpdateFileMetadataSucceededType,
+		), v.handleFileMetadataSuccess).
+		On(event.Is(directory.UpdateFileMetadataFailedType), v.handleUpdateFileMetadataFailure).
+		On(event.IsOneOf(
+			directory.LoadFileTagsSucceededType,
+			directory.UpdateFileTagsSucceededType,
+		), v.handleFileTagsSuccess).
+		On(event.Is(directory.UpdateFileTagsFailedType), v.handleUpdateFileTagsFailure).
+		On(event.Is(directory.CreateFileShareLinkSucceededType), v.handleCreateFileShareLinkSuccess).
+		On(event.Is(directory.CreateFileShareLinkFailedType), v.handleCreateFileShareLinkFailure).
+		On(event.Is(directory.LoadFileVersionsSucceededType), v.handleLoadFileVersionsSuccess).
+		On(event.Is(directory.RestoreFileVersionSucceededType), v.handleRestoreFileVersionSuccess).
+		On(event.Is(directory.RestoreFileVersionFailedType), v.handleRestoreFileVersionFailure).
+		On(event.Is(directory.DeleteFileVersionSucceededType), v.handleDeleteFileVersionSuccess).
+		On(event.Is(directory.DeleteFileVersionFailedType), v.handleDeleteFileVersionFailure).
 		On(event.Is(directory.UserValidationAskedType), v.handleUserValidationRequest).
 		On(event.Is(directory.UserValidationRefusedType), v.handleUserValidationRefused).
 		On(event.Is(directory.UploadReadyType), v.handleUploadReady).
 		On(event.Is(directory.DeleteFailedType), v.handleDeleteDirectoryFailure).
 		On(event.Is(directory.DeleteSucceededType), v.handleDeleteDirectorySuccess).
+		On(event.Is(directory.DeleteProgressType), v.handleDeleteDirectoryProgress).
+		On(event.Is(directory.SelectionProgressType), v.handleSelectionProgress).
+		On(event.Is(directory.DeleteSelectionSucceededType), v.handleDeleteSelectionSuccess).
+		On(event.Is(directory.DeleteSelectionFailedType), v.handleDeleteSelectionFailure).
+		On(event.Is(directory.DownloadSelectionSucceededType), v.handleDownloadSelectionSuccess).
+		On(event.Is(directory.DownloadSelectionFailedType), v.handleDownloadSelectionFailure).
+		On(event.Is(directory.CopySelectionSucceededType), v.handleCopySelectionSuccess).
+		On(event.Is(directory.CopySelectionFailedType), v.handleCopySelectionFailure).
+		On(event.Is(directory.PastePreviewSucceededType), v.handlePastePreviewSuccess).
+		On(event.Is(directory.PastePreviewFailedType), v.handlePastePreviewFailure).
+		On(event.Is(directory.PasteReadyType), v.handlePasteReady).
 		ListenWithWorkers(3)
 
 	return v
@@ -276,6 +540,17 @@ func (v *explorerViewModelImpl) IsSelectedDirectoryLoading() binding.Bool {
 	return v.isSelectedDirLoading
 }
 
+func (v *explorerViewModelImpl) ShowDeletedFiles() binding.Bool {
+	return v.showDeletedFiles
+}
+
+func (v *explorerViewModelImpl) loadOptions() []directory.LoadOption {
+	if show, _ := v.showDeletedFiles.Get(); show {
+		return []directory.LoadOption{directory.WithDeletedFiles()}
+	}
+	return nil
+}
+
 func (v *explorerViewModelImpl) LoadDirectory(dir *directory.Directory) error {
 	if v.selectedConnectionVal == nil {
 		err := ErrNoConnectionSelected
@@ -283,7 +558,7 @@ func (v *explorerViewModelImpl) LoadDirectory(dir *directory.Directory) error {
 		return err
 	}
 
-	evt, err := dir.Load()
+	evt, err := dir.Load(v.loadOptions()...)
 	if err != nil {
 		wErr := fmt.Errorf("impossible to (re)load the directory: %w", err)
 		v.notifier.NotifyError(wErr)
@@ -302,7 +577,7 @@ func (v *explorerViewModelImpl) ReloadDirectory(dir *directory.Directory) error
 		return err
 	}
 
-	evt, err := dir.Load()
+	evt, err := dir.Load(v.loadOptions()...)
 	if err != nil {
 		wErr := fmt.Errorf("impossible to (re)load the directory: %w", err)
 		v.notifier.NotifyError(wErr)
@@ -315,6 +590,64 @@ func (v *explorerViewModelImpl) ReloadDirectory(dir *directory.Directory) error
 	return nil
 }
 
+func (v *explorerViewModelImpl) LoadNextPage(dir *directory.Directory) error {
+	if v.selectedConnectionVal == nil {
+		err := ErrNoConnectionSelected
+		v.notifier.NotifyError(err)
+		return err
+	}
+
+	evt, err := dir.LoadNextPage()
+	if err != nil {
+		wErr := fmt.Errorf("impossible to load more content: %w", err)
+		v.notifier.NotifyError(wErr)
+		return wErr
+	}
+	v.bus.Publish(evt)
+
+	if dir.Is(v.selectedDirectory) {
+		u.Skip(v.isSelectedDirLoading.Set(true))
+	}
+	v.triggerStateListeners()
+
+	return nil
+}
+
+func (v *explorerViewModelImpl) handleLoadDirPageSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.LoadPageSucceeded)
+	dir := pl.Directory
+	if err := dir.Notify(evt); err != nil {
+		v.notifier.NotifyError(err)
+		return
+	}
+
+	v.state.Explorer().AppendPage(dir, pl.SubDirectories, pl.Files)
+
+	if dir.Is(v.selectedDirectory) {
+		u.Skip(v.isSelectedDirLoading.Set(false))
+	}
+
+	v.triggerStateListeners()
+	v.resumeReveal()
+}
+
+func (v *explorerViewModelImpl) handleLoadDirPageFailure(evt event.Event) {
+	pl := evt.Payload().(directory.LoadPageFailed)
+	dir := pl.Directory
+	if err := dir.Notify(evt); err != nil {
+		v.notifier.NotifyError(err)
+		return
+	}
+	u.Skip(v.infoMessage.Set(pl.Err.Error()))
+
+	if dir.Is(v.selectedDirectory) {
+		u.Skip(v.isSelectedDirLoading.Set(false))
+	}
+
+	v.triggerStateListeners()
+	v.endReveal()
+}
+
 func (v *explorerViewModelImpl) handleLoadDirSuccess(evt event.Event) {
 	pl := evt.Payload().(directory.LoadSucceeded)
 	dir := pl.Directory
@@ -330,6 +663,7 @@ func (v *explorerViewModelImpl) handleLoadDirSuccess(evt event.Event) {
 	}
 
 	v.triggerStateListeners()
+	v.resumeReveal()
 }
 
 func (v *explorerViewModelImpl) handleLoadDirFailure(evt event.Event) {
@@ -346,6 +680,7 @@ func (v *explorerViewModelImpl) handleLoadDirFailure(evt event.Event) {
 	}
 
 	v.triggerStateListeners()
+	v.endReveal()
 }
 
 func (v *explorerViewModelImpl) DownloadFile(f *directory.File, dest string) {
@@ -366,6 +701,275 @@ func (v *explorerViewModelImpl) handleDownloadFileFailure(evt event.Event) {
 	u.Skip(v.errorMessage.Set(err.Error()))
 }
 
+func (v *explorerViewModelImpl) DownloadDirectory(dir *directory.Directory, dest string, strategy directory.MaterializeStrategy) error {
+	v.Lock()
+	if v.downloadingDirectory != nil {
+		v.Unlock()
+		return fmt.Errorf("directory %s is already being downloaded", v.downloadingDirectory.Path())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.downloadingDirectory = dir
+	v.cancelDownload = cancel
+	v.Unlock()
+
+	u.Skip(v.downloadProgress.Set(fmt.Sprintf("Downloading %s...", dir.Path())))
+	v.bus.Publish(dir.Download(dest, strategy, event.WithContext(ctx)))
+	return nil
+}
+
+func (v *explorerViewModelImpl) CancelDownloadDirectory(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.downloadingDirectory != nil && v.downloadingDirectory.Is(dir) {
+		v.cancelDownload()
+	}
+}
+
+func (v *explorerViewModelImpl) DownloadProgress() binding.String {
+	return v.downloadProgress
+}
+
+func (v *explorerViewModelImpl) handleDownloadDirProgress(evt event.Event) {
+	pl := evt.Payload().(directory.DownloadProgress)
+
+	msg := fmt.Sprintf("Downloading %s: %d objects downloaded", pl.Directory.Path(), pl.DownloadedCount)
+	if pl.SkippedCount > 0 {
+		msg += fmt.Sprintf(", %d skipped", pl.SkippedCount)
+	}
+	if pl.FailedCount > 0 {
+		msg += fmt.Sprintf(", %d failed", pl.FailedCount)
+	}
+	u.Skip(v.downloadProgress.Set(msg))
+}
+
+func (v *explorerViewModelImpl) handleDownloadDirSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.DownloadSucceeded)
+	v.endDownload(pl.Directory)
+
+	msg := fmt.Sprintf("%d objects downloaded to %s", pl.DownloadedCount, pl.DstPath)
+	if pl.SkippedCount > 0 {
+		msg += fmt.Sprintf(" (%d existing files skipped)", pl.SkippedCount)
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Directory downloaded", msg))
+}
+
+func (v *explorerViewModelImpl) handleDownloadDirFailure(evt event.Event) {
+	pl := evt.Payload().(directory.DownloadFailed)
+	v.endDownload(pl.Directory)
+
+	var uncompleted directory.UncompletedDownload
+	if errors.As(pl.Err, &uncompleted) {
+		u.Skip(v.errorMessage.Set(formatFailedKeys(
+			fmt.Sprintf("Download of %s", uncompleted.DirPath),
+			errors.Is(uncompleted, directory.ErrCanceled),
+			fmt.Sprintf("%d objects downloaded", uncompleted.DownloadedCount),
+			uncompleted.FailedKeys)))
+		return
+	}
+
+	err := fmt.Errorf("error downloading directory: %w", pl.Err)
+	v.notifier.NotifyError(err)
+	u.Skip(v.errorMessage.Set(err.Error()))
+}
+
+func (v *explorerViewModelImpl) endDownload(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.downloadingDirectory == nil || !v.downloadingDirectory.Is(dir) {
+		return
+	}
+	v.cancelDownload()
+	v.cancelDownload = nil
+	v.downloadingDirectory = nil
+	u.Skip(v.downloadProgress.Set(""))
+}
+
+func (v *explorerViewModelImpl) ComputeDirectorySize(dir *directory.Directory) error {
+	v.Lock()
+	if v.sizingDirectory != nil {
+		v.Unlock()
+		return fmt.Errorf("the size of %s is already being computed", v.sizingDirectory.Path())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.sizingDirectory = dir
+	v.cancelSizing = cancel
+	v.Unlock()
+
+	u.Skip(v.sizeProgress.Set(fmt.Sprintf("Computing the size of %s...", dir.Path())))
+	v.bus.Publish(dir.ComputeSize(event.WithContext(ctx)))
+	return nil
+}
+
+func (v *explorerViewModelImpl) CancelComputeDirectorySize(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.sizingDirectory != nil && v.sizingDirectory.Is(dir) {
+		v.cancelSizing()
+	}
+}
+
+func (v *explorerViewModelImpl) SizeProgress() binding.String {
+	return v.sizeProgress
+}
+
+func (v *explorerViewModelImpl) handleComputeSizeProgress(evt event.Event) {
+	pl := evt.Payload().(directory.ComputeSizeProgress)
+	u.Skip(v.sizeProgress.Set(fmt.Sprintf("Computing the size of %s: %d objects, %s",
+		pl.Directory.Path(), pl.ObjectCount, humanize.Bytes(pl.Bytes))))
+}
+
+func (v *explorerViewModelImpl) handleComputeSizeSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.ComputeSizeSucceeded)
+	v.endSizing(pl.Directory)
+
+	pl.Directory.SetSizeStats(pl.Stats)
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleComputeSizeFailure(evt event.Event) {
+	pl := evt.Payload().(directory.ComputeSizeFailed)
+	v.endSizing(pl.Directory)
+
+	if errors.Is(pl.Err, directory.ErrCanceled) {
+		return
+	}
+	err := fmt.Errorf("error computing the directory size: %w", pl.Err)
+	u.Skip(v.errorMessage.Set(err.Error()))
+}
+
+func (v *explorerViewModelImpl) endSizing(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.sizingDirectory == nil || !v.sizingDirectory.Is(dir) {
+		return
+	}
+	v.cancelSizing()
+	v.cancelSizing = nil
+	v.sizingDirectory = nil
+	u.Skip(v.sizeProgress.Set(""))
+}
+
+func (v *explorerViewModelImpl) PrepareSync(dir *directory.Directory, localPath string, opts directory.SyncOptions) error {
+	if localPath == "" {
+		return errors.New("no local folder to sync with")
+	}
+	v.bus.Publish(dir.PrepareSync(localPath, opts))
+	return nil
+}
+
+func (v *explorerViewModelImpl) OnSyncReady(listener func(plan *directory.SyncPlan)) {
+	v.onSyncReady = listener
+}
+
+func (v *explorerViewModelImpl) ApplySync(plan *directory.SyncPlan) error {
+	v.Lock()
+	if v.syncingDirectory != nil {
+		v.Unlock()
+		return fmt.Errorf("%s is already being synced", v.syncingDirectory.Path())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.syncingDirectory = plan.Directory
+	v.cancelSync = cancel
+	v.Unlock()
+
+	u.Skip(v.syncProgress.Set(fmt.Sprintf("Syncing %s with %s...", plan.Directory.Path(), plan.LocalPath)))
+	v.bus.Publish(plan.Apply(event.WithContext(ctx)))
+	return nil
+}
+
+func (v *explorerViewModelImpl) CancelSync(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.syncingDirectory != nil && v.syncingDirectory.Is(dir) {
+		v.cancelSync()
+	}
+}
+
+func (v *explorerViewModelImpl) SyncProgress() binding.String {
+	return v.syncProgress
+}
+
+func (v *explorerViewModelImpl) handleSyncPreviewSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.SyncPreviewSucceeded)
+	if pl.Plan.IsEmpty() {
+		u.Skip(v.infoMessage.Set(fmt.Sprintf("%s is already in sync with %s",
+			pl.Plan.Directory.Path(), pl.Plan.LocalPath)))
+		return
+	}
+	if v.onSyncReady != nil {
+		v.onSyncReady(pl.Plan)
+	}
+}
+
+func (v *explorerViewModelImpl) handleSyncPreviewFailure(evt event.Event) {
+	pl := evt.Payload().(directory.SyncPreviewFailed)
+	err := fmt.Errorf("error comparing %s with the local folder: %w", pl.Directory.Path(), pl.Err)
+	u.Skip(v.errorMessage.Set(err.Error()))
+}
+
+func (v *explorerViewModelImpl) handleSyncProgress(evt event.Event) {
+	pl := evt.Payload().(directory.SyncProgress)
+	msg := fmt.Sprintf("Syncing %s: %d/%d files", pl.Plan.Directory.Path(), pl.DoneCount, pl.Total)
+	if pl.FailedCount > 0 {
+		msg += fmt.Sprintf(", %d failed", pl.FailedCount)
+	}
+	u.Skip(v.syncProgress.Set(msg))
+}
+
+func (v *explorerViewModelImpl) handleSyncSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.SyncSucceeded)
+	v.endSync(pl.Plan)
+
+	msg := fmt.Sprintf("%d files copied, %d deleted", pl.CopiedCount, pl.DeletedCount)
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Directory synced", msg))
+}
+
+func (v *explorerViewModelImpl) handleSyncFailure(evt event.Event) {
+	pl := evt.Payload().(directory.SyncFailed)
+	v.endSync(pl.Plan)
+
+	var uncompleted directory.UncompletedSync
+	if errors.As(pl.Err, &uncompleted) {
+		u.Skip(v.errorMessage.Set(formatFailedKeys(
+			fmt.Sprintf("Sync with %s", uncompleted.LocalPath),
+			errors.Is(uncompleted, directory.ErrCanceled),
+			fmt.Sprintf("%d files synced", uncompleted.DoneCount),
+			uncompleted.FailedPaths)))
+		return
+	}
+	if errors.Is(pl.Err, directory.ErrCanceled) {
+		return
+	}
+	err := fmt.Errorf("error syncing the directory: %w", pl.Err)
+	u.Skip(v.errorMessage.Set(err.Error()))
+}
+
+// endSync reloads the directory when the bucket side was changed, even partially.
+func (v *explorerViewModelImpl) endSync(plan *directory.SyncPlan) {
+	v.Lock()
+	if v.syncingDirectory == nil || !v.syncingDirectory.Is(plan.Directory) {
+		v.Unlock()
+		return
+	}
+	v.cancelSync()
+	v.cancelSync = nil
+	v.syncingDirectory = nil
+	v.Unlock()
+
+	u.Skip(v.syncProgress.Set(""))
+	if plan.Options.Direction == directory.SyncToBucket {
+		u.Skip(v.ReloadDirectory(plan.Directory))
+	}
+}
+
 func (v *explorerViewModelImpl) DoUpload(localBasePath string, preview *directory.Preview, strategy directory.MaterializeStrategy) {
 	uploadMat := directory.NewUploadMaterializer(preview, localBasePath)
 	v.bus.Publish(uploadMat.Materialize(strategy))
@@ -556,9 +1160,13 @@ func (v *explorerViewModelImpl) handleDeleteDirectorySuccess(evt event.Event) {
 	if pl.Directory.Is(v.selectedDirectory) {
 		v.isSelectedDirLoading.Set(false) // nolint:errcheck
 	}
+	v.endDeletion(pl.Directory)
 
-	fyne.CurrentApp().SendNotification(fyne.NewNotification("Directory deleted",
-		fmt.Sprintf("Directory %s deleted", pl.Directory.Name())))
+	msg := fmt.Sprintf("Directory %s deleted", pl.Directory.Name())
+	if pl.DeletedCount > 0 {
+		msg += fmt.Sprintf(" (%d objects)", pl.DeletedCount)
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Directory deleted", msg))
 	v.triggerStateListeners()
 }
 
@@ -572,16 +1180,118 @@ func (v *explorerViewModelImpl) handleDeleteDirectoryFailure(evt event.Event) {
 	if pl.Directory.Is(v.selectedDirectory) {
 		u.Skip(v.isSelectedDirLoading.Set(false))
 	}
+	v.endDeletion(pl.Directory)
 
 	err := fmt.Errorf("error deleting directory: %w", pl.Err)
 	v.notifier.NotifyError(err)
-	u.Skip(v.errorMessage.Set(err.Error()))
+
+	var uncompleted directory.UncompletedDelete
+	if errors.As(pl.Err, &uncompleted) {
+		u.Skip(v.errorMessage.Set(formatFailedKeys(
+			fmt.Sprintf("Deletion of %s", uncompleted.DirPath),
+			errors.Is(uncompleted, directory.ErrCanceled),
+			fmt.Sprintf("%d objects deleted", uncompleted.DeletedCount),
+			uncompleted.FailedKeys)))
+		if uncompleted.DeletedCount > 0 {
+			u.Skip(v.ReloadDirectory(pl.Directory))
+		}
+	} else {
+		u.Skip(v.errorMessage.Set(err.Error()))
+	}
 	v.triggerStateListeners()
 }
 
-func (v *explorerViewModelImpl) DeleteFile(file *directory.File) {
-	dirNode, err := v.state.Explorer().GetDirectoryNode(file.DirectoryPath())
-	if err != nil {
+func (v *explorerViewModelImpl) DeleteDirectoryRecursively(dir *directory.Directory) error {
+	if directory.RootPath.Is(dir) {
+		return errors.New("cannot delete root directory")
+	}
+
+	v.Lock()
+	if v.deletingDirectory != nil {
+		v.Unlock()
+		return fmt.Errorf("directory %s is already being deleted", v.deletingDirectory.Path())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evt, err := dir.Parent().RemoveSubDirectoryRecursively(dir.Name(), event.WithContext(ctx))
+	if err != nil {
+		v.Unlock()
+		cancel()
+		return err
+	}
+
+	v.deletingDirectory = dir
+	v.cancelDeletion = cancel
+	v.Unlock()
+
+	u.Skip(v.deletionProgress.Set(fmt.Sprintf("Deleting %s...", dir.Path())))
+	u.Skip(v.isSelectedDirLoading.Set(true))
+
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) CancelDeleteDirectory(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.deletingDirectory != nil && v.deletingDirectory.Is(dir) {
+		v.cancelDeletion()
+	}
+}
+
+func (v *explorerViewModelImpl) DeletionProgress() binding.String {
+	return v.deletionProgress
+}
+
+func (v *explorerViewModelImpl) handleDeleteDirectoryProgress(evt event.Event) {
+	pl := evt.Payload().(directory.DeleteProgress)
+
+	msg := fmt.Sprintf("Deleting %s: %d objects deleted", pl.Directory.Path(), pl.DeletedCount)
+	if pl.FailedCount > 0 {
+		msg += fmt.Sprintf(", %d failed", pl.FailedCount)
+	}
+	u.Skip(v.deletionProgress.Set(msg))
+}
+
+func (v *explorerViewModelImpl) endDeletion(dir *directory.Directory) {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.deletingDirectory == nil || !v.deletingDirectory.Is(dir) {
+		return
+	}
+	v.cancelDeletion()
+	v.cancelDeletion = nil
+	v.deletingDirectory = nil
+	u.Skip(v.deletionProgress.Set(""))
+}
+
+func formatFailedKeys(operation string, canceled bool, summary string, failedKeys map[string]error) string {
+	const maxListedKeys = 10
+
+	msg := strings.Builder{}
+	if canceled {
+		u.SkipV(fmt.Fprintf(&msg, "%s canceled", operation))
+	} else {
+		u.SkipV(fmt.Fprintf(&msg, "%s uncompleted", operation))
+	}
+	u.SkipV(fmt.Fprintf(&msg, ": %s, %d failed", summary, len(failedKeys)))
+
+	keys := slices.Sorted(maps.Keys(failedKeys))
+	for i, key := range keys {
+		if i == maxListedKeys {
+			u.SkipV(fmt.Fprintf(&msg, "\n... and %d more", len(keys)-maxListedKeys))
+			break
+		}
+		u.SkipV(fmt.Fprintf(&msg, "\n- %s: %s", key, failedKeys[key]))
+	}
+	return msg.String()
+}
+
+func (v *explorerViewModelImpl) DeleteFile(file *directory.File) {
+	dirNode, err := v.state.Explorer().GetDirectoryNode(file.DirectoryPath())
+	if err != nil {
 		panic(fmt.Errorf("failed deleting file: %w", err))
 	}
 
@@ -906,6 +1616,542 @@ func (v *explorerViewModelImpl) handleRenameFileFailure(evt event.Event) {
 	v.triggerStateListeners()
 }
 
+func (v *explorerViewModelImpl) LoadFileMetadata(file *directory.File) {
+	v.bus.Publish(file.LoadMetadata())
+}
+
+func (v *explorerViewModelImpl) UpdateFileMetadata(file *directory.File, metadata directory.ObjectMetadata) error {
+	evt, err := file.UpdateMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) handleFileMetadataSuccess(evt event.Event) {
+	switch pl := evt.Payload().(type) {
+	case directory.LoadFileMetadataSucceeded:
+		pl.File.SetMetadata(pl.Metadata)
+	case directory.UpdateFileMetadataSucceeded:
+		pl.File.SetMetadata(pl.Metadata)
+		fyne.CurrentApp().SendNotification(fyne.NewNotification("Metadata updated", pl.File.FullPath()))
+	}
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleUpdateFileMetadataFailure(evt event.Event) {
+	pl := evt.Payload().(directory.UpdateFileMetadataFailed)
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("error updating the metadata of %s: %s", pl.File.FullPath(), pl.Err)))
+}
+
+func (v *explorerViewModelImpl) LoadFileTags(file *directory.File) {
+	v.bus.Publish(file.LoadTags())
+}
+
+func (v *explorerViewModelImpl) UpdateFileTags(file *directory.File, tags directory.ObjectTags) error {
+	if conn := v.CurrentSelectedConnection(); conn != nil && conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	evt, err := file.UpdateTags(tags)
+	if err != nil {
+		return err
+	}
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) handleFileTagsSuccess(evt event.Event) {
+	switch pl := evt.Payload().(type) {
+	case directory.LoadFileTagsSucceeded:
+		pl.File.SetTags(pl.Tags)
+	case directory.UpdateFileTagsSucceeded:
+		pl.File.SetTags(pl.Tags)
+		fyne.CurrentApp().SendNotification(fyne.NewNotification("Tags updated", pl.File.FullPath()))
+	}
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleUpdateFileTagsFailure(evt event.Event) {
+	pl := evt.Payload().(directory.UpdateFileTagsFailed)
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("error updating the tags of %s: %s", pl.File.FullPath(), pl.Err)))
+}
+
+func (v *explorerViewModelImpl) CreateFileShareLink(file *directory.File, access directory.ShareLinkAccess, expiry time.Duration) error {
+	if conn := v.CurrentSelectedConnection(); access == directory.ShareLinkUpload && conn != nil && conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	evt, err := file.CreateShareLink(access, expiry)
+	if err != nil {
+		return err
+	}
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) handleCreateFileShareLinkSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.CreateFileShareLinkSucceeded)
+	fyne.Do(func() {
+		fyne.CurrentApp().Clipboard().SetContent(pl.URL)
+	})
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Share link copied",
+		fmt.Sprintf("%s link to %s, valid until %s", pl.Access, pl.File.Name(), pl.ExpiresAt.Format("2006-01-02 15:04"))))
+}
+
+func (v *explorerViewModelImpl) handleCreateFileShareLinkFailure(evt event.Event) {
+	pl := evt.Payload().(directory.CreateFileShareLinkFailed)
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("error creating a share link for %s: %s", pl.File.FullPath(), pl.Err)))
+}
+
+func (v *explorerViewModelImpl) LoadFileVersions(file *directory.File) {
+	v.bus.Publish(file.LoadVersions())
+}
+
+func (v *explorerViewModelImpl) RestoreFileVersion(file *directory.File, version directory.FileVersion) error {
+	if conn := v.CurrentSelectedConnection(); conn != nil && conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	evt, err := file.RestoreVersion(version)
+	if err != nil {
+		return err
+	}
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) DeleteFileVersion(file *directory.File, version directory.FileVersion) error {
+	if conn := v.CurrentSelectedConnection(); conn != nil && conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	evt, err := file.DeleteVersion(version)
+	if err != nil {
+		return err
+	}
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) handleLoadFileVersionsSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.LoadFileVersionsSucceeded)
+	pl.File.SetVersions(pl.Versions)
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleRestoreFileVersionSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.RestoreFileVersionSucceeded)
+	pl.File.Restored(pl.SizeBytes, pl.LastModified)
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Version restored",
+		fmt.Sprintf("%s restored from version %s", pl.File.FullPath(), pl.Version.ID)))
+	v.bus.Publish(pl.File.LoadVersions())
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleRestoreFileVersionFailure(evt event.Event) {
+	pl := evt.Payload().(directory.RestoreFileVersionFailed)
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("error restoring a version of %s: %s", pl.File.FullPath(), pl.Err)))
+}
+
+func (v *explorerViewModelImpl) handleDeleteFileVersionSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.DeleteFileVersionSucceeded)
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Version deleted",
+		fmt.Sprintf("Version %s of %s permanently deleted", pl.Version.ID, pl.File.FullPath())))
+	if pl.Version.IsLatest {
+		// the current content has changed, or the file is gone
+		u.Skip(v.ReloadDirectory(pl.File.Parent()))
+		return
+	}
+	v.bus.Publish(pl.File.LoadVersions())
+}
+
+func (v *explorerViewModelImpl) handleDeleteFileVersionFailure(evt event.Event) {
+	pl := evt.Payload().(directory.DeleteFileVersionFailed)
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("error deleting a version of %s: %s", pl.File.FullPath(), pl.Err)))
+}
+
+func (v *explorerViewModelImpl) Selection() *directory.Selection {
+	return v.state.Explorer().Selection()
+}
+
+func (v *explorerViewModelImpl) SelectionSize() binding.Int {
+	return v.state.Explorer().SelectionSize()
+}
+
+func (v *explorerViewModelImpl) ToggleSelection(nodeID string) error {
+	if err := v.state.Explorer().ToggleNodeSelection(nodeID); err != nil {
+		return err
+	}
+	v.triggerStateListeners()
+	return nil
+}
+
+func (v *explorerViewModelImpl) SelectRange(nodeID string) error {
+	if err := v.state.Explorer().SelectNodeRange(nodeID); err != nil {
+		return err
+	}
+	v.triggerStateListeners()
+	return nil
+}
+
+func (v *explorerViewModelImpl) ClearSelection() {
+	v.state.Explorer().ClearSelection()
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) DeleteSelection() error {
+	return v.startSelectionOperation("Deleting", v.Selection(), func(opts ...event.Option) (event.Event, error) {
+		return v.Selection().Delete(opts...)
+	})
+}
+
+func (v *explorerViewModelImpl) DownloadSelection(dest string, strategy directory.MaterializeStrategy) error {
+	return v.startSelectionOperation("Downloading", v.Selection(), func(opts ...event.Option) (event.Event, error) {
+		return v.Selection().Download(dest, strategy, opts...)
+	})
+}
+
+func (v *explorerViewModelImpl) CopySelection(dst *directory.Directory, strategy directory.MaterializeStrategy) error {
+	return v.startSelectionOperation("Copying", v.Selection(), func(opts ...event.Option) (event.Event, error) {
+		return v.Selection().CopyTo(dst, strategy, opts...)
+	})
+}
+
+func (v *explorerViewModelImpl) MoveSelection(dst *directory.Directory, strategy directory.MaterializeStrategy) error {
+	return v.startSelectionOperation("Moving", v.Selection(), func(opts ...event.Option) (event.Event, error) {
+		return v.Selection().MoveTo(dst, strategy, opts...)
+	})
+}
+
+func (v *explorerViewModelImpl) CancelSelectionOperation() {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.cancelSelectionOperation != nil {
+		v.cancelSelectionOperation()
+	}
+}
+
+func (v *explorerViewModelImpl) SelectionProgress() binding.String {
+	return v.selectionProgress
+}
+
+func (v *explorerViewModelImpl) startSelectionOperation(
+	label string,
+	sel *directory.Selection,
+	makeEvent func(opts ...event.Option) (event.Event, error),
+) error {
+	v.Lock()
+	if v.cancelSelectionOperation != nil {
+		v.Unlock()
+		return errors.New("another operation on the selection is already running")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evt, err := makeEvent(event.WithContext(ctx))
+	if err != nil {
+		v.Unlock()
+		cancel()
+		return err
+	}
+	v.cancelSelectionOperation = cancel
+	v.Unlock()
+
+	u.Skip(v.selectionProgress.Set(fmt.Sprintf("%s %d selected items...", label, sel.Len())))
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) endSelectionOperation() {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.cancelSelectionOperation == nil {
+		return
+	}
+	v.cancelSelectionOperation()
+	v.cancelSelectionOperation = nil
+	u.Skip(v.selectionProgress.Set(""))
+}
+
+func (v *explorerViewModelImpl) handleSelectionProgress(evt event.Event) {
+	pl := evt.Payload().(directory.SelectionProgress)
+
+	msg := fmt.Sprintf("%d objects processed", pl.DoneCount)
+	if pl.SkippedCount > 0 {
+		msg += fmt.Sprintf(", %d skipped", pl.SkippedCount)
+	}
+	if pl.FailedCount > 0 {
+		msg += fmt.Sprintf(", %d failed", pl.FailedCount)
+	}
+	u.Skip(v.selectionProgress.Set(msg))
+}
+
+func (v *explorerViewModelImpl) handleDeleteSelectionSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.DeleteSelectionSucceeded)
+	v.endSelectionOperation()
+	v.removeSelectedNodes(evt, pl.Selection)
+
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Selection deleted",
+		fmt.Sprintf("%d objects deleted", pl.DeletedCount)))
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleDeleteSelectionFailure(evt event.Event) {
+	pl := evt.Payload().(directory.DeleteSelectionFailed)
+	v.endSelectionOperation()
+	v.setSelectionError("Deletion of the selection", pl.Err)
+
+	for _, dir := range selectionParents(pl.Selection) {
+		u.Skip(v.ReloadDirectory(dir))
+	}
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleDownloadSelectionSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.DownloadSelectionSucceeded)
+	v.endSelectionOperation()
+
+	msg := fmt.Sprintf("%d objects downloaded to %s", pl.DownloadedCount, pl.DstPath)
+	if pl.SkippedCount > 0 {
+		msg += fmt.Sprintf(" (%d existing files skipped)", pl.SkippedCount)
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification("Selection downloaded", msg))
+}
+
+func (v *explorerViewModelImpl) handleDownloadSelectionFailure(evt event.Event) {
+	pl := evt.Payload().(directory.DownloadSelectionFailed)
+	v.endSelectionOperation()
+	v.setSelectionError("Download of the selection", pl.Err)
+}
+
+func (v *explorerViewModelImpl) handleCopySelectionSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.CopySelectionSucceeded)
+	v.endSelectionOperation()
+
+	title := "Selection copied"
+	if pl.Move {
+		title = "Selection moved"
+		v.removeSelectedNodes(evt, pl.Selection)
+		v.clearClipboard()
+		// Skipped objects are left in the source directories.
+		if pl.SkippedCount > 0 {
+			for _, dir := range selectionParents(pl.Selection) {
+				u.Skip(v.ReloadDirectory(dir))
+			}
+		}
+	}
+	if pl.Destination.IsLoaded() {
+		u.Skip(v.ReloadDirectory(pl.Destination))
+	}
+
+	msg := fmt.Sprintf("%d objects copied to %s", pl.CopiedCount, pl.Destination.Path())
+	if pl.SkippedCount > 0 {
+		msg += fmt.Sprintf(" (%d existing objects skipped)", pl.SkippedCount)
+	}
+	fyne.CurrentApp().SendNotification(fyne.NewNotification(title, msg))
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) handleCopySelectionFailure(evt event.Event) {
+	pl := evt.Payload().(directory.CopySelectionFailed)
+	v.endSelectionOperation()
+
+	operation := "Copy of the selection"
+	if pl.Move {
+		operation = "Move of the selection"
+		for _, dir := range selectionParents(pl.Selection) {
+			u.Skip(v.ReloadDirectory(dir))
+		}
+	}
+	if pl.Destination.IsLoaded() {
+		u.Skip(v.ReloadDirectory(pl.Destination))
+	}
+	v.setSelectionError(operation, pl.Err)
+	v.triggerStateListeners()
+}
+
+func (v *explorerViewModelImpl) CopyToClipboard(nodeID string) error {
+	return v.setClipboard(nodeID, false)
+}
+
+func (v *explorerViewModelImpl) CutToClipboard(nodeID string) error {
+	if conn := v.CurrentSelectedConnection(); conn != nil && conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	return v.setClipboard(nodeID, true)
+}
+
+func (v *explorerViewModelImpl) setClipboard(nodeID string, move bool) error {
+	sel, err := v.state.Explorer().SelectionOrNode(nodeID)
+	if err != nil {
+		return err
+	}
+
+	v.Lock()
+	v.clipboard = sel
+	v.clipboardMove = move
+	v.Unlock()
+
+	v.triggerStateListeners()
+	return nil
+}
+
+func (v *explorerViewModelImpl) clearClipboard() {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.clipboardMove {
+		v.clipboard = nil
+		v.clipboardMove = false
+	}
+}
+
+func (v *explorerViewModelImpl) HasClipboardContent() bool {
+	v.Lock()
+	defer v.Unlock()
+
+	return v.clipboard != nil && !v.clipboard.IsEmpty()
+}
+
+func (v *explorerViewModelImpl) PreparePaste(dst *directory.Directory) error {
+	v.Lock()
+	sel, move := v.clipboard, v.clipboardMove
+	v.Unlock()
+
+	if sel == nil {
+		return errors.New("nothing to paste")
+	}
+
+	evt, err := sel.PreparePaste(dst, move)
+	if err != nil {
+		return err
+	}
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) OnPasteReady(listener func(previewState PastePreviewState)) {
+	v.onPasteReady = listener
+}
+
+func (v *explorerViewModelImpl) Paste(previewState PastePreviewState, strategy directory.MaterializeStrategy) error {
+	label := "Copying"
+	if previewState.Move {
+		label = "Moving"
+	}
+	return v.startSelectionOperation(label, previewState.Selection, func(opts ...event.Option) (event.Event, error) {
+		if previewState.Move {
+			return previewState.Selection.MoveTo(previewState.Destination, strategy, opts...)
+		}
+		return previewState.Selection.CopyTo(previewState.Destination, strategy, opts...)
+	})
+}
+
+// handlePastePreviewSuccess loads the destination directories covered by the paste
+// before the preview is shown, so that conflicts with existing files can be detected.
+func (v *explorerViewModelImpl) handlePastePreviewSuccess(evt event.Event) {
+	pl := evt.Payload().(directory.PastePreviewSucceeded)
+
+	prev, err := makePreviewFromObjects(pl.Objects, pl.Destination)
+	if err != nil {
+		v.notifier.NotifyError(err)
+		u.Skip(v.errorMessage.Set(err.Error()))
+		return
+	}
+
+	loadMat := directory.NewLoadMaterializer(prev, directory.PasteReady(pl), directory.PastePreviewFailed{
+		Err:         errors.New("timeout"),
+		Selection:   pl.Selection,
+		Destination: pl.Destination,
+	})
+	v.bus.Publish(loadMat.Materialize(directory.MaterializeReplace))
+}
+
+func (v *explorerViewModelImpl) handlePastePreviewFailure(evt event.Event) {
+	pl := evt.Payload().(directory.PastePreviewFailed)
+	err := fmt.Errorf("failed preparing the paste into %s: %w", pl.Destination.Path(), pl.Err)
+	v.notifier.NotifyError(err)
+	u.Skip(v.errorMessage.Set(err.Error()))
+}
+
+func (v *explorerViewModelImpl) handlePasteReady(evt event.Event) {
+	pl := evt.Payload().(directory.PasteReady)
+
+	prev, err := makePreviewFromObjects(pl.Objects, pl.Destination)
+	if err != nil {
+		v.notifier.NotifyError(err)
+		return
+	}
+	if v.onPasteReady != nil {
+		v.onPasteReady(PastePreviewState{
+			Preview:     prev,
+			Selection:   pl.Selection,
+			Destination: pl.Destination,
+			Move:        pl.Move,
+		})
+	}
+}
+
+func makePreviewFromObjects(objects []directory.SelectedObject, dst *directory.Directory) (*directory.Preview, error) {
+	if len(objects) == 0 {
+		return nil, errors.New("nothing to paste")
+	}
+
+	prev, err := dst.Preview()
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		if err := prev.AddObject(obj.RelativeKey, obj.SizeBytes, obj.LastModified); err != nil {
+			return nil, err
+		}
+	}
+	return prev, nil
+}
+
+// removeSelectedNodes forgets the selected items in their parent directories and in the file tree,
+// then clears the selection.
+func (v *explorerViewModelImpl) removeSelectedNodes(evt event.Event, sel *directory.Selection) {
+	for _, dir := range selectionParents(sel) {
+		if err := dir.Notify(evt); err != nil {
+			v.notifier.NotifyError(err)
+		}
+	}
+	for _, f := range sel.Files() {
+		u.Skip(v.state.Explorer().RemoveNode(f.FullPath()))
+	}
+	for _, dir := range sel.Directories() {
+		u.Skip(v.state.Explorer().RemoveNode(dir.Path().String()))
+	}
+	v.state.Explorer().ClearSelection()
+}
+
+func (v *explorerViewModelImpl) setSelectionError(operation string, err error) {
+	v.notifier.NotifyError(fmt.Errorf("%s failed: %w", strings.ToLower(operation), err))
+
+	var uncompleted directory.UncompletedSelection
+	if errors.As(err, &uncompleted) {
+		u.Skip(v.errorMessage.Set(formatFailedKeys(
+			operation,
+			errors.Is(uncompleted, directory.ErrCanceled),
+			fmt.Sprintf("%d objects processed", uncompleted.DoneCount),
+			uncompleted.FailedKeys)))
+		return
+	}
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("%s failed: %s", operation, err)))
+}
+
+// selectionParents returns the distinct parent directories of the selected items.
+func selectionParents(sel *directory.Selection) []*directory.Directory {
+	parents := make(map[directory.Path]*directory.Directory)
+	for _, f := range sel.Files() {
+		parents[f.DirectoryPath()] = f.Parent()
+	}
+	for _, dir := range sel.Directories() {
+		parents[dir.Parent().Path()] = dir.Parent()
+	}
+	return slices.Collect(maps.Values(parents))
+}
+
 func (v *explorerViewModelImpl) initializeTreeData(c *connection_deck.Connection) error {
 	if c == nil {
 		err := ErrNoConnectionSelected
@@ -920,11 +2166,16 @@ func (v *explorerViewModelImpl) initializeTreeData(c *connection_deck.Connection
 		return newErr
 	}
 
-	if err := v.state.Explorer().InitFileTree(rootDir, c.Bucket()); err != nil {
+	if err := v.state.Explorer().InitFileTree(rootDir, c.Bucket(), c.RootPrefix()); err != nil {
 		v.notifier.NotifyError(err)
 		return err
 	}
 
+	if c.Bucket() == "" {
+		// an account-level connection waits for a bucket to be picked
+		return nil
+	}
+
 	if err := v.LoadDirectory(rootDir); err != nil {
 		newErr := fmt.Errorf("error loading root directory: %w", err)
 		v.notifier.NotifyError(newErr)
@@ -970,3 +2221,195 @@ func (v *explorerViewModelImpl) handleConnectionRemoved(evt event.Event) {
 		u.Skip(v.selectedConnection.Set(nil))
 	}
 }
+
+func compareSearchHits(a, b directory.SearchHit) bool {
+	return a.FullPath() == b.FullPath()
+}
+
+func (v *explorerViewModelImpl) Search(dir *directory.Directory, query directory.SearchQuery) error {
+	if v.selectedConnectionVal == nil {
+		err := ErrNoConnectionSelected
+		v.notifier.NotifyError(err)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evt, err := dir.Search(query, event.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	v.Lock()
+	if v.cancelSearch != nil {
+		v.cancelSearch()
+	}
+	v.searchEventID = evt.ID()
+	v.cancelSearch = cancel
+	v.Unlock()
+
+	u.Skip(v.searchResults.Set(nil))
+	u.Skip(v.searchProgress.Set(fmt.Sprintf("Searching in %s...", dir.Path())))
+	u.Skip(v.isSearching.Set(true))
+	v.bus.Publish(evt)
+	return nil
+}
+
+func (v *explorerViewModelImpl) CancelSearch() {
+	v.Lock()
+	defer v.Unlock()
+
+	if v.cancelSearch != nil {
+		v.cancelSearch()
+	}
+}
+
+func (v *explorerViewModelImpl) SearchResults() binding.List[directory.SearchHit] {
+	return v.searchResults
+}
+
+func (v *explorerViewModelImpl) SearchProgress() binding.String {
+	return v.searchProgress
+}
+
+func (v *explorerViewModelImpl) IsSearching() binding.Bool {
+	return v.isSearching
+}
+
+// isCurrentSearch tells if the event follows the last triggered search, the previous ones being ignored.
+func (v *explorerViewModelImpl) isCurrentSearch(evt event.Event) bool {
+	v.Lock()
+	defer v.Unlock()
+	return evt.ParentID() == v.searchEventID
+}
+
+func (v *explorerViewModelImpl) handleSearchProgress(evt event.Event) {
+	if !v.isCurrentSearch(evt) {
+		return
+	}
+	pl := evt.Payload().(directory.SearchProgress)
+	for _, hit := range pl.Hits {
+		u.Skip(v.searchResults.Append(hit))
+	}
+	u.Skip(v.searchProgress.Set(fmt.Sprintf("Searching in %s: %d files found, %d objects scanned",
+		pl.Directory.Path(), v.searchResults.Length(), pl.ScannedCount)))
+}
+
+func (v *explorerViewModelImpl) handleSearchSuccess(evt event.Event) {
+	if !v.isCurrentSearch(evt) {
+		return
+	}
+	pl := evt.Payload().(directory.SearchSucceeded)
+	v.endSearch()
+	u.Skip(v.searchProgress.Set(fmt.Sprintf("%d files found in %s, %d objects scanned",
+		pl.HitCount, pl.Directory.Path(), pl.ScannedCount)))
+}
+
+func (v *explorerViewModelImpl) handleSearchFailure(evt event.Event) {
+	if !v.isCurrentSearch(evt) {
+		return
+	}
+	pl := evt.Payload().(directory.SearchFailed)
+	v.endSearch()
+	if errors.Is(pl.Err, directory.ErrCanceled) {
+		u.Skip(v.searchProgress.Set(fmt.Sprintf("Search canceled, %d files found", v.searchResults.Length())))
+		return
+	}
+	u.Skip(v.searchProgress.Set(""))
+	u.Skip(v.errorMessage.Set(fmt.Sprintf("error searching in %s: %s", pl.Directory.Path(), pl.Err)))
+}
+
+func (v *explorerViewModelImpl) endSearch() {
+	v.Lock()
+	if v.cancelSearch != nil {
+		v.cancelSearch()
+		v.cancelSearch = nil
+	}
+	v.Unlock()
+	u.Skip(v.isSearching.Set(false))
+}
+
+func (v *explorerViewModelImpl) OnReveal(listener func(nodeID string)) {
+	v.onReveal = listener
+}
+
+func (v *explorerViewModelImpl) RevealSearchHit(hit directory.SearchHit) error {
+	v.Lock()
+	v.pendingReveal = &hit
+	v.Unlock()
+	return v.continueReveal()
+}
+
+func (v *explorerViewModelImpl) resumeReveal() {
+	if err := v.continueReveal(); err != nil {
+		u.Skip(v.errorMessage.Set(err.Error()))
+	}
+}
+
+func (v *explorerViewModelImpl) endReveal() {
+	v.Lock()
+	defer v.Unlock()
+	v.pendingReveal = nil
+}
+
+// continueReveal walks down the file tree toward the file to reveal, loading the missing directories
+// and pages on the way. It is called again each time a directory or a page is loaded, until the file node exists.
+func (v *explorerViewModelImpl) continueReveal() error {
+	v.Lock()
+	hit := v.pendingReveal
+	v.Unlock()
+	if hit == nil {
+		return nil
+	}
+
+	rootNode, err := v.state.Explorer().GetDirectoryNode(directory.RootPath)
+	if err != nil {
+		v.endReveal()
+		return err
+	}
+	parent := rootNode.Directory()
+
+	for _, name := range append(hit.DirectoryPath.Split(), "") {
+		if parent.IsLoading() {
+			return nil
+		}
+		if !parent.IsLoaded() {
+			return v.revealStep(v.LoadDirectory(parent))
+		}
+
+		nodeID := hit.FullPath()
+		if name != "" {
+			nodeID = parent.Path().NewSubPath(name).String()
+		}
+		if !v.state.Explorer().IsNodeExists(nodeID) {
+			if parent.HasMorePages() {
+				return v.revealStep(v.LoadNextPage(parent))
+			}
+			v.endReveal()
+			return fmt.Errorf("%w: %s", directory.ErrNotFound, nodeID)
+		}
+
+		if name == "" {
+			break
+		}
+		dirNode, err := v.state.Explorer().GetDirectoryNode(directory.Path(nodeID))
+		if err != nil {
+			v.endReveal()
+			return err
+		}
+		parent = dirNode.Directory()
+	}
+
+	v.endReveal()
+	if v.onReveal != nil {
+		v.onReveal(hit.FullPath())
+	}
+	return nil
+}
+
+func (v *explorerViewModelImpl) revealStep(err error) error {
+	if err != nil {
+		v.endReveal()
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package viewmodel
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2/data/binding"
+	"github.com/thomas-marquis/it-happened/event"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/domain/notification"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/u"
+	"github.com/thomas-marquis/s3-box/internal/ui/state"
+	"github.com/thomas-marquis/s3-box/internal/ui/values"
+)
+
+type TransferViewModel interface {
+	// Transfers returns the active and finished transfers, the most recent one first
+	Transfers() binding.List[transfer.Job]
+
+	Pause(id transfer.JobID)
+	Resume(id transfer.JobID)
+	Cancel(id transfer.JobID)
+	// ClearFinished removes the succeeded, failed and canceled transfers from the list
+	ClearFinished()
+
+	// InterruptedUploads returns the uploads that were stopped by the closing of the application
+	// after sending some of their parts. They stay paused until resumed or canceled.
+	InterruptedUploads() []transfer.Job
+
+	// IncompleteUploads returns the multipart uploads found in the bucket by LoadIncompleteUploads
+	IncompleteUploads() binding.List[transfer.IncompleteUpload]
+	IsLoadingIncompleteUploads() binding.Bool
+	// LoadIncompleteUploads lists the multipart uploads neither completed nor aborted in the bucket of the connection
+	LoadIncompleteUploads(conn *connection_deck.Connection) error
+	// AbortIncompleteUploads deletes the parts of the given uploads from the bucket
+	AbortIncompleteUploads(conn *connection_deck.Connection, uploads []transfer.IncompleteUpload) error
+}
+
+type transferViewModelImpl struct {
+	transfers          binding.List[transfer.Job]
+	interruptedUploads []transfer.Job
+
+	incompleteUploads          binding.List[transfer.IncompleteUpload]
+	isLoadingIncompleteUploads binding.Bool
+
+	notifier notification.Repository
+	bus      event.Bus
+}
+
+func NewTransferViewModel(
+	repository transfer.Repository,
+	appState *state.State,
+	notifier notification.Repository,
+	bus event.Bus,
+) TransferViewModel {
+	vm := &transferViewModelImpl{
+		transfers: binding.NewList[transfer.Job](func(j1, j2 transfer.Job) bool {
+			return j1 == j2
+		}),
+		incompleteUploads: binding.NewList[transfer.IncompleteUpload](func(u1, u2 transfer.IncompleteUpload) bool {
+			return u1 == u2
+		}),
+		isLoadingIncompleteUploads: binding.NewBool(),
+		notifier:                   notifier,
+		bus:                        bus,
+	}
+
+	bus.Subscribe().
+		On(event.Is(transfer.JobUpdatedType), vm.handleJobUpdated).
+		On(event.Is(transfer.ClearFinishedSucceededType), vm.handleClearFinished).
+		On(event.Is(transfer.ListIncompleteUploadsSucceededType), vm.handleListIncompleteUploadsSuccess).
+		On(event.Is(transfer.ListIncompleteUploadsFailedType), vm.handleListIncompleteUploadsFailure).
+		On(event.Is(transfer.AbortIncompleteUploadsSucceededType), vm.handleAbortIncompleteUploadsSuccess).
+		On(event.Is(transfer.AbortIncompleteUploadsFailedType), vm.handleAbortIncompleteUploadsFailure).
+		ListenWithWorkers(1)
+
+	appState.Settings().Get().Observe(values.SettingMaxTransfers, func(value any) {
+		if maxTransfers, ok := value.(uint64); ok {
+			bus.Publish(event.New(transfer.SetConcurrencyTriggered{MaxConcurrency: int(maxTransfers)}))
+		}
+	})
+
+	jobs, err := repository.Load()
+	if err != nil {
+		notifier.NotifyError(fmt.Errorf("failed loading the previous transfers: %w", err))
+	} else if len(jobs) > 0 {
+		for _, job := range jobs {
+			if job.IsInterruptedUpload() {
+				vm.interruptedUploads = append(vm.interruptedUploads, job)
+			}
+		}
+		bus.Publish(event.New(transfer.RestoreTriggered{Jobs: jobs}))
+	}
+
+	return vm
+}
+
+func (v *transferViewModelImpl) Transfers() binding.List[transfer.Job] {
+	return v.transfers
+}
+
+func (v *transferViewModelImpl) Pause(id transfer.JobID) {
+	v.bus.Publish(event.New(transfer.PauseTriggered{JobID: id}))
+}
+
+func (v *transferViewModelImpl) Resume(id transfer.JobID) {
+	v.bus.Publish(event.New(transfer.ResumeTriggered{JobID: id}))
+}
+
+func (v *transferViewModelImpl) Cancel(id transfer.JobID) {
+	v.bus.Publish(event.New(transfer.CancelTriggered{JobID: id}))
+}
+
+func (v *transferViewModelImpl) ClearFinished() {
+	v.bus.Publish(event.New(transfer.ClearFinishedTriggered{}))
+}
+
+func (v *transferViewModelImpl) InterruptedUploads() []transfer.Job {
+	return slices.Clone(v.interruptedUploads)
+}
+
+func (v *transferViewModelImpl) IncompleteUploads() binding.List[transfer.IncompleteUpload] {
+	return v.incompleteUploads
+}
+
+func (v *transferViewModelImpl) IsLoadingIncompleteUploads() binding.Bool {
+	return v.isLoadingIncompleteUploads
+}
+
+func (v *transferViewModelImpl) LoadIncompleteUploads(conn *connection_deck.Connection) error {
+	if conn == nil {
+		return ErrNoConnectionSelected
+	}
+	u.Skip(v.incompleteUploads.Set(nil))
+	u.Skip(v.isLoadingIncompleteUploads.Set(true))
+	v.bus.Publish(event.New(transfer.ListIncompleteUploadsTriggered{ConnectionID: conn.ID()}))
+	return nil
+}
+
+func (v *transferViewModelImpl) AbortIncompleteUploads(conn *connection_deck.Connection, uploads []transfer.IncompleteUpload) error {
+	if conn == nil {
+		return ErrNoConnectionSelected
+	}
+	if conn.ReadOnly() {
+		return fmt.Errorf("%w: %s", directory.ErrReadOnly, conn.Name())
+	}
+	if len(uploads) == 0 {
+		return nil
+	}
+	v.bus.Publish(event.New(transfer.AbortIncompleteUploadsTriggered{
+		ConnectionID: conn.ID(),
+		Uploads:      uploads,
+	}))
+	return nil
+}
+
+func (v *transferViewModelImpl) handleJobUpdated(evt event.Event) {
+	job := evt.Payload().(transfer.JobUpdated).Job
+
+	jobs, _ := v.transfers.Get()
+	idx := slices.IndexFunc(jobs, func(j transfer.Job) bool { return j.ID == job.ID })
+	if idx < 0 {
+		u.Skip(v.transfers.Prepend(job))
+		return
+	}
+	u.Skip(v.transfers.SetValue(idx, job))
+}
+
+func (v *transferViewModelImpl) handleClearFinished(evt event.Event) {
+	ids := evt.Payload().(transfer.ClearFinishedSucceeded).JobIDs
+
+	jobs, _ := v.transfers.Get()
+	u.Skip(v.transfers.Set(slices.DeleteFunc(slices.Clone(jobs), func(j transfer.Job) bool {
+		return slices.Contains(ids, j.ID)
+	})))
+}
+
+func (v *transferViewModelImpl) handleListIncompleteUploadsSuccess(evt event.Event) {
+	pl := evt.Payload().(transfer.ListIncompleteUploadsSucceeded)
+	u.Skip(v.incompleteUploads.Set(pl.Uploads))
+	u.Skip(v.isLoadingIncompleteUploads.Set(false))
+}
+
+func (v *transferViewModelImpl) handleListIncompleteUploadsFailure(event.Event) {
+	u.Skip(v.isLoadingIncompleteUploads.Set(false))
+}
+
+func (v *transferViewModelImpl) handleAbortIncompleteUploadsSuccess(evt event.Event) {
+	pl := evt.Payload().(transfer.AbortIncompleteUploadsSucceeded)
+	v.removeIncompleteUploads(pl.Uploads)
+}
+
+func (v *transferViewModelImpl) handleAbortIncompleteUploadsFailure(evt event.Event) {
+	pl := evt.Payload().(transfer.AbortIncompleteUploadsFailed)
+	v.removeIncompleteUploads(pl.Aborted)
+}
+
+func (v *transferViewModelImpl) removeIncompleteUploads(removed []transfer.IncompleteUpload) {
+	uploads, _ := v.incompleteUploads.Get()
+	u.Skip(v.incompleteUploads.Set(slices.DeleteFunc(slices.Clone(uploads), func(upload transfer.IncompleteUpload) bool {
+		return slices.ContainsFunc(removed, func(r transfer.IncompleteUpload) bool {
+			return r.UploadID == upload.UploadID
+		})
+	})))
+}
@@ -0,0 +1,91 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	fyne_widget "fyne.io/fyne/v2/widget"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/u"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+)
+
+var (
+	exportModes      = []connection_deck.ExportMode{connection_deck.ExportWithoutSecrets, connection_deck.ExportEncrypted, connection_deck.ExportWithSecrets}
+	exportModeLabels = []string{"Without the credentials", "Encrypted with a passphrase", "With the credentials in clear"}
+)
+
+var errPassphrasesMismatch = errors.New("the passphrases don't match")
+
+// showConnectionsExport asks how to export the credentials, then where to save the export.
+func showConnectionsExport(ctx appcontext.AppContext) {
+	modeSelect := fyne_widget.NewSelect(exportModeLabels, nil)
+	passphraseEntry := fyne_widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("Passphrase")
+	confirmEntry := fyne_widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("Passphrase again")
+	modeSelect.OnChanged = func(string) {
+		if exportModes[modeSelect.SelectedIndex()] == connection_deck.ExportEncrypted {
+			passphraseEntry.Enable()
+			confirmEntry.Enable()
+		} else {
+			passphraseEntry.Disable()
+			confirmEntry.Disable()
+		}
+	}
+	modeSelect.SetSelectedIndex(0)
+
+	d := dialog.NewForm(
+		"Export connections",
+		"Export",
+		"Cancel",
+		[]*fyne_widget.FormItem{
+			fyne_widget.NewFormItem("Credentials", modeSelect),
+			fyne_widget.NewFormItem("Passphrase", passphraseEntry),
+			fyne_widget.NewFormItem("Confirmation", confirmEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			options := connection_deck.ExportOptions{Mode: exportModes[modeSelect.SelectedIndex()]}
+			if options.Mode == connection_deck.ExportEncrypted {
+				if passphraseEntry.Text != confirmEntry.Text {
+					dialog.ShowError(errPassphrasesMismatch, ctx.Window())
+					return
+				}
+				options.Passphrase = passphraseEntry.Text
+			}
+			saveConnectionsExport(ctx, options)
+		},
+		ctx.Window(),
+	)
+	d.Resize(fyne.NewSize(500, 250))
+	d.Show()
+}
+
+func saveConnectionsExport(ctx appcontext.AppContext, options connection_deck.ExportOptions) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ctx.Window())
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer u.SkipD(writer.Close)
+
+		if err := ctx.ConnectionViewModel().ExportAsJSON(writer, options); err != nil {
+			dialog.ShowError(err, ctx.Window())
+			return
+		}
+
+		deck := ctx.ConnectionViewModel().Deck()
+		msg := fmt.Sprintf("%d connection(s) exported as JSON", len(deck.Get()))
+		dialog.ShowInformation("Export", msg, ctx.Window())
+	}, ctx.Window())
+	saveDialog.SetFileName("connections.json")
+	saveDialog.Show()
+}
@@ -0,0 +1,134 @@
+package views
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	fyne_widget "fyne.io/fyne/v2/widget"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/u"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+)
+
+var (
+	importSources      = []connection_deck.ImportSource{connection_deck.ImportFromJSON, connection_deck.ImportFromAWSProfiles, connection_deck.ImportFromRclone}
+	importSourceLabels = []string{"S3 Box JSON export", "AWS CLI profiles", "rclone remotes"}
+)
+
+// showConnectionsImport asks for the file to import, then shows what would be imported.
+func showConnectionsImport(ctx appcontext.AppContext) {
+	sourceSelect := fyne_widget.NewSelect(importSourceLabels, nil)
+	sourceSelect.SetSelectedIndex(0)
+
+	fileEntry := fyne_widget.NewEntry()
+	fileEntry.SetPlaceHolder("Default location of the tool")
+	browseBtn := fyne_widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, ctx.Window())
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer u.SkipD(reader.Close)
+			fileEntry.SetText(reader.URI().Path())
+		}, ctx.Window())
+	})
+
+	passphraseEntry := fyne_widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("Only for an encrypted export")
+
+	d := dialog.NewForm(
+		"Import connections",
+		"Preview",
+		"Cancel",
+		[]*fyne_widget.FormItem{
+			fyne_widget.NewFormItem("From", sourceSelect),
+			fyne_widget.NewFormItem("File", container.NewBorder(nil, nil, nil, browseBtn, fileEntry)),
+			fyne_widget.NewFormItem("Passphrase", passphraseEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+
+			var file io.Reader
+			if path := strings.TrimSpace(fileEntry.Text); path != "" {
+				f, err := os.Open(path)
+				if err != nil {
+					dialog.ShowError(err, ctx.Window())
+					return
+				}
+				defer u.SkipD(f.Close)
+				file = f
+			}
+
+			preview, err := ctx.ConnectionViewModel().PrepareImport(file, connection_deck.ImportOptions{
+				Source:     importSources[sourceSelect.SelectedIndex()],
+				Passphrase: passphraseEntry.Text,
+			})
+			if err != nil {
+				dialog.ShowError(err, ctx.Window())
+				return
+			}
+			showConnectionsImportPreview(ctx, preview)
+		},
+		ctx.Window(),
+	)
+	d.Resize(fyne.NewSize(500, 250))
+	d.Show()
+}
+
+// showConnectionsImportPreview lists the imported connections with what is done of them, before to import them.
+func showConnectionsImportPreview(ctx appcontext.AppContext, preview *connection_deck.ImportPreview) {
+	added := preview.Count(connection_deck.ImportAdd)
+	merged := preview.Count(connection_deck.ImportMerge)
+	if added+merged == 0 {
+		dialog.ShowInformation("Import connections",
+			fmt.Sprintf("The %d connection(s) found are already saved", len(preview.Entries())), ctx.Window())
+		return
+	}
+
+	lines := make([]string, 0, len(preview.Entries()))
+	for _, e := range preview.Entries() {
+		conn := e.Connection
+		bucket := conn.Bucket()
+		if bucket == "" {
+			bucket = "no bucket"
+		}
+		endpoint := conn.Server()
+		if conn.Provider() == connection_deck.AWSProvider {
+			endpoint = "AWS " + conn.Region()
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (%s, %s)", e.Action, conn.Name(), endpoint, bucket))
+	}
+	list := fyne_widget.NewLabel(strings.Join(lines, "\n"))
+
+	mergeCheck := fyne_widget.NewCheck(
+		fmt.Sprintf("Update the %d existing connection(s) with the imported settings", merged), nil)
+	if merged == 0 {
+		mergeCheck.Hide()
+	}
+
+	content := container.NewBorder(
+		fyne_widget.NewLabel(fmt.Sprintf("%d connection(s) to add, %d to update", added, merged)),
+		mergeCheck, nil, nil,
+		container.NewScroll(list),
+	)
+
+	d := dialog.NewCustomConfirm("Import connections", "Import", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		ctx.ConnectionViewModel().Import(preview, mergeCheck.Checked)
+	}, ctx.Window())
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
+}
@@ -0,0 +1,95 @@
+package views
+
+import (
+	"errors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	fyne_widget "fyne.io/fyne/v2/widget"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+)
+
+// ShowUnlockSecretsDialog asks the master passphrase of the secret store before to show the application.
+// The passphrase is asked again while it is wrong, onCancel is called when the user gives up.
+func ShowUnlockSecretsDialog(w fyne.Window, unlock func(passphrase string) error, onUnlocked, onCancel func()) {
+	passphraseEntry := fyne_widget.NewPasswordEntry()
+	errorLabel := fyne_widget.NewLabel("")
+	errorLabel.Importance = fyne_widget.DangerImportance
+	errorLabel.Hide()
+
+	var d *dialog.FormDialog
+	submit := func(ok bool) {
+		if !ok {
+			onCancel()
+			return
+		}
+		if err := unlock(passphraseEntry.Text); err != nil {
+			if !errors.Is(err, connection_deck.ErrWrongPassphrase) {
+				dialog.ShowError(err, w)
+			}
+			errorLabel.SetText(err.Error())
+			errorLabel.Show()
+			passphraseEntry.SetText("")
+			d.Show()
+			return
+		}
+		onUnlocked()
+	}
+	d = dialog.NewForm(
+		"Unlock the credentials",
+		"Unlock",
+		"Quit",
+		[]*fyne_widget.FormItem{
+			fyne_widget.NewFormItem("Passphrase", passphraseEntry),
+			fyne_widget.NewFormItem("", errorLabel),
+		},
+		submit,
+		w,
+	)
+	passphraseEntry.OnSubmitted = func(string) {
+		d.Submit()
+	}
+	w.SetContent(container.NewStack())
+	d.Resize(fyne.NewSize(400, 150))
+	d.Show()
+}
+
+// showProtectSecrets asks the master passphrase of the secret store where the credentials are moved.
+// onProtected is called once the credentials are protected.
+func showProtectSecrets(ctx appcontext.AppContext, onProtected func()) {
+	passphraseEntry := fyne_widget.NewPasswordEntry()
+	confirmEntry := fyne_widget.NewPasswordEntry()
+	message := fyne_widget.NewLabel("The credentials are encrypted in a file apart from the settings.\n" +
+		"The passphrase is asked at each start of the application, and can't be recovered.")
+
+	d := dialog.NewForm(
+		"Protect credentials",
+		"Protect",
+		"Cancel",
+		[]*fyne_widget.FormItem{
+			fyne_widget.NewFormItem("", message),
+			fyne_widget.NewFormItem("Passphrase", passphraseEntry),
+			fyne_widget.NewFormItem("Confirmation", confirmEntry),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			if passphraseEntry.Text != confirmEntry.Text {
+				dialog.ShowError(errPassphrasesMismatch, ctx.Window())
+				return
+			}
+			if err := ctx.ConnectionViewModel().ProtectSecrets(passphraseEntry.Text); err != nil {
+				dialog.ShowError(err, ctx.Window())
+				return
+			}
+			onProtected()
+			dialog.ShowInformation("Protect credentials", "The credentials are now encrypted", ctx.Window())
+		},
+		ctx.Window(),
+	)
+	d.Resize(fyne.NewSize(500, 250))
+	d.Show()
+}
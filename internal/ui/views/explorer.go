@@ -2,8 +2,10 @@ package views
 
 import (
 	"errors"
+	"slices"
 
 	"fyne.io/fyne/v2/dialog"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 	"github.com/thomas-marquis/s3-box/internal/u"
 
@@ -15,6 +17,7 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
 	fyne_widget "fyne.io/fyne/v2/widget"
 )
 
@@ -42,8 +45,11 @@ func GetFileExplorerView(appCtx appcontext.AppContext) (*fyne.Container, error)
 
 	content := container.NewHSplit(fyne_widget.NewLabel(""), fyne_widget.NewLabel(""))
 
+	bucketSelect := newBucketSwitcher(appCtx)
+
 	vm.SelectedConnection().AddListener(binding.NewDataListener(func() {
 		conn := vm.CurrentSelectedConnection()
+		bucketSelect.setConnection(conn)
 		if conn == nil {
 			noConn.Show()
 			content.Hide()
@@ -87,6 +93,7 @@ func GetFileExplorerView(appCtx appcontext.AppContext) (*fyne.Container, error)
 	detailsContainer := container.NewVBox()
 	fileDetails := widget.NewFileDetails(appCtx)
 	dirDetails := widget.NewDirectoryDetails(appCtx)
+	selectionDetails := widget.NewSelectionDetails(appCtx)
 
 	tree := widget.NewExplorerTree(appCtx,
 		func(dir *directory.Directory) {
@@ -99,10 +106,39 @@ func GetFileExplorerView(appCtx appcontext.AppContext) (*fyne.Container, error)
 			fileDetails.Select(file)
 			detailsContainer.Objects = []fyne.CanvasObject{fileDetails}
 		},
+		func(selection *directory.Selection) {
+			vm.SetSelectedDirectory(nil)
+			selectionDetails.Select(selection)
+			detailsContainer.Objects = []fyne.CanvasObject{selectionDetails}
+			detailsContainer.Refresh()
+		},
 	)
 
+	// The tree is not shortcutable, so its clipboard shortcuts are handled at the window level.
+	onExplorerShortcut := func(fn func()) func(fyne.Shortcut) {
+		return func(fyne.Shortcut) {
+			if appCtx.CurrentRoute() == navigation.ExplorerRoute {
+				fn()
+			}
+		}
+	}
+	appCtx.Window().Canvas().AddShortcut(&fyne.ShortcutCopy{}, onExplorerShortcut(tree.CopyToClipboard))
+	appCtx.Window().Canvas().AddShortcut(&fyne.ShortcutCut{}, onExplorerShortcut(tree.CutToClipboard))
+	appCtx.Window().Canvas().AddShortcut(&fyne.ShortcutPaste{}, onExplorerShortcut(dirDetails.Paste))
+
 	vm.AddStateListener(func() {
 		tree.Refresh()
+		if isSelectionDisplayed := slices.Contains(detailsContainer.Objects, fyne.CanvasObject(selectionDetails)); isSelectionDisplayed {
+			if vm.Selection().IsEmpty() {
+				detailsContainer.Objects = nil
+			} else {
+				selectionDetails.Select(vm.Selection())
+			}
+			detailsContainer.Refresh()
+		}
+		if slices.Contains(detailsContainer.Objects, fyne.CanvasObject(fileDetails)) {
+			fileDetails.RefreshMetadata()
+		}
 		currSelected := vm.SelectedDirectory()
 		if currSelected == nil {
 			return
@@ -111,12 +147,30 @@ func GetFileExplorerView(appCtx appcontext.AppContext) (*fyne.Container, error)
 		dirDetails.Select(currSelected)
 	})
 
-	content.Leading = container.NewScroll(tree)
+	searchPanel := widget.NewSearchPanel(appCtx, func(hit directory.SearchHit) {
+		if err := vm.RevealSearchHit(hit); err != nil {
+			dialog.ShowError(err, appCtx.Window())
+		}
+	})
+	filesTab := container.NewTabItemWithIcon("Files", theme.FolderIcon(), container.NewScroll(tree))
+	leftPanels := container.NewAppTabs(
+		filesTab,
+		container.NewTabItemWithIcon("Search", theme.SearchIcon(), searchPanel),
+	)
+
+	vm.OnReveal(func(nodeID string) {
+		fyne.Do(func() {
+			leftPanels.Select(filesTab)
+			tree.Reveal(nodeID)
+		})
+	})
+
+	content.Leading = leftPanels
 	content.Trailing = detailsContainer
 
 	return container.NewBorder(
 		container.NewVBox(
-			widget.NewHeadingWithData(headingData),
+			container.NewBorder(nil, nil, nil, bucketSelect.Select, widget.NewHeadingWithData(headingData)),
 			fyne_widget.NewSeparator(),
 		),
 		nil, nil, nil,
@@ -129,3 +183,52 @@ func GetFileExplorerView(appCtx appcontext.AppContext) (*fyne.Container, error)
 		),
 	), nil
 }
+
+// bucketSwitcher lists the buckets of an account-level connection and browses the picked one.
+// It is hidden for the connections pinned to their bucket.
+type bucketSwitcher struct {
+	*fyne_widget.Select
+
+	appCtx appcontext.AppContext
+	conn   *connection_deck.Connection
+}
+
+func newBucketSwitcher(appCtx appcontext.AppContext) *bucketSwitcher {
+	s := &bucketSwitcher{appCtx: appCtx}
+	s.Select = fyne_widget.NewSelect(nil, func(bucket string) {
+		if s.conn == nil || bucket == "" || bucket == s.conn.Bucket() {
+			return
+		}
+		appCtx.ConnectionViewModel().SwitchBucket(s.conn, bucket)
+	})
+	s.PlaceHolder = "Select a bucket"
+	s.Hide()
+	return s
+}
+
+func (s *bucketSwitcher) setConnection(conn *connection_deck.Connection) {
+	s.conn = conn
+	if conn == nil || !conn.IsAccountLevel() {
+		s.Hide()
+		return
+	}
+
+	// the current bucket is set before the OnChanged callback can be fired by the user
+	s.Options = []string{}
+	if conn.Bucket() != "" {
+		s.Options = []string{conn.Bucket()}
+	}
+	s.Selected = conn.Bucket()
+	s.Show()
+	s.Refresh()
+
+	s.appCtx.ConnectionViewModel().ListBuckets(conn, func(buckets []string, err error) {
+		fyne.Do(func() {
+			if err != nil || s.conn != conn {
+				return
+			}
+			s.Options = buckets
+			s.Refresh()
+		})
+	})
+}
@@ -1,7 +1,6 @@
 package views
 
 import (
-	"fmt"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -9,7 +8,6 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 	fyne_widget "fyne.io/fyne/v2/widget"
-	"github.com/thomas-marquis/s3-box/internal/u"
 	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
 	"github.com/thomas-marquis/s3-box/internal/ui/values"
 	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
@@ -29,11 +27,15 @@ func GetSettingsView(ctx appcontext.AppContext) (*fyne.Container, error) {
 	sizeEntry := widget.NewNumericalEntry[uint64](values.KiB)
 	sizeEntry.Bind(ctx.State().Settings().EditorFileSizeLimitBytes())
 
+	transfersEntry := widget.NewNumericalEntry[uint64](1)
+	transfersEntry.Bind(ctx.State().Settings().MaxTransfers())
+
 	form := &fyne_widget.Form{
 		Items: []*fyne_widget.FormItem{
 			{Text: "Color theme", Widget: themeSelector},
 			{Text: "Preview/edit file size limit (KB)", Widget: sizeEntry},
 			{Text: "Timeout (seconds)", Widget: timeoutEntry},
+			{Text: "Max concurrent transfers", Widget: transfersEntry},
 		},
 		SubmitText: "Save",
 		OnSubmit:   ctx.SettingsViewModel().Save,
@@ -54,31 +56,31 @@ func GetSettingsView(ctx appcontext.AppContext) (*fyne.Container, error) {
 		"Export connections as JSON",
 		theme.DocumentSaveIcon(),
 		func() {
-			saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
-				if err != nil {
-					dialog.ShowError(err, ctx.Window())
-					return
-				}
-				if writer == nil {
-					return
-				}
-				defer u.SkipD(writer.Close)
-
-				if err := ctx.ConnectionViewModel().ExportAsJSON(writer); err != nil {
-					dialog.ShowError(err, ctx.Window())
-					return
-				}
-
-				deck := ctx.ConnectionViewModel().Deck()
-				msg := fmt.Sprintf("%d connection(s) exported as JSON", len(deck.Get()))
-				dialog.ShowInformation("Export", msg, ctx.Window())
-			}, ctx.Window())
-			saveDialog.SetFileName("connections.json")
-			saveDialog.Show()
+			showConnectionsExport(ctx)
 		},
 	)
 	exportConnectionsBtn.Resize(fyne.NewSize(100, 100))
 
+	importConnectionsBtn := fyne_widget.NewButtonWithIcon(
+		"Import connections",
+		theme.FolderOpenIcon(),
+		func() {
+			showConnectionsImport(ctx)
+		},
+	)
+
+	protectSecretsBtn := fyne_widget.NewButtonWithIcon(
+		"Protect credentials with a passphrase",
+		theme.VisibilityOffIcon(),
+		nil,
+	)
+	protectSecretsBtn.OnTapped = func() {
+		showProtectSecrets(ctx, protectSecretsBtn.Disable)
+	}
+	if ctx.ConnectionViewModel().SecretsProtected() {
+		protectSecretsBtn.Disable()
+	}
+
 	statusLabel := fyne_widget.NewLabelWithData(ctx.State().Settings().StatusMessage())
 
 	return container.NewBorder(
@@ -91,7 +93,8 @@ func GetSettingsView(ctx appcontext.AppContext) (*fyne.Container, error) {
 			container.NewPadded(
 				container.NewGridWrap(fyne.NewSize(700, 400), container.NewVBox(
 					form,
-					container.NewHBox(exportConnectionsBtn),
+					container.NewHBox(exportConnectionsBtn, importConnectionsBtn),
+					container.NewHBox(protectSecretsBtn),
 				)),
 			),
 		),
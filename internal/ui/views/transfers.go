@@ -0,0 +1,119 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	fyne_widget "fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
+)
+
+// GetTransfersView lists the active and finished uploads and downloads.
+// It implements the navigation.View signature type.
+func GetTransfersView(appCtx appcontext.AppContext) (*fyne.Container, error) {
+	vm := appCtx.TransferViewModel()
+
+	transferList := widget.NewTransferList(appCtx)
+	clearBtn := fyne_widget.NewButtonWithIcon("Clear finished", theme.DeleteIcon(), vm.ClearFinished)
+	cleanupBtn := fyne_widget.NewButtonWithIcon("Incomplete uploads in the bucket", theme.StorageIcon(), func() {
+		showIncompleteUploadsDialog(appCtx)
+	})
+
+	nothingToDisplay := container.NewCenter(
+		fyne_widget.NewLabelWithStyle("No transfer at the moment...",
+			fyne.TextAlignCenter,
+			fyne.TextStyle{Bold: true}))
+
+	refreshVisibility := func() {
+		if vm.Transfers().Length() == 0 {
+			transferList.Hide()
+			nothingToDisplay.Show()
+		} else {
+			transferList.Show()
+			nothingToDisplay.Hide()
+		}
+	}
+	refreshVisibility()
+	vm.Transfers().AddListener(binding.NewDataListener(refreshVisibility))
+
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewHeading("Transfers"),
+			fyne_widget.NewSeparator(),
+			container.NewHBox(clearBtn, cleanupBtn),
+		),
+		nil, nil, nil,
+		container.NewPadded(transferList),
+		nothingToDisplay,
+	), nil
+}
+
+func showIncompleteUploadsDialog(appCtx appcontext.AppContext) {
+	vm := appCtx.TransferViewModel()
+	win := appCtx.Window()
+	conn := appCtx.ExplorerViewModel().CurrentSelectedConnection()
+
+	if err := vm.LoadIncompleteUploads(conn); err != nil {
+		dialog.ShowError(err, win)
+		return
+	}
+
+	view := widget.NewIncompleteUploads(vm.IncompleteUploads(), vm.IsLoadingIncompleteUploads(), conn.ReadOnly())
+	view.OnAbort = func(uploads []transfer.IncompleteUpload) {
+		dialog.ShowConfirm("Abort uploads",
+			fmt.Sprintf("Abort %d upload(s) and delete their parts from the bucket? This can't be undone.", len(uploads)),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := vm.AbortIncompleteUploads(conn, uploads); err != nil {
+					dialog.ShowError(err, win)
+				}
+			}, win)
+	}
+
+	d := dialog.NewCustom(fmt.Sprintf("Incomplete uploads in %s", conn.Bucket()), "Close", view, win)
+	d.Resize(fyne.NewSize(750, 400))
+	d.Show()
+}
+
+// ShowInterruptedUploadsDialog offers to resume or abort the uploads
+// that were interrupted by the closing of the application.
+func ShowInterruptedUploadsDialog(appCtx appcontext.AppContext) {
+	vm := appCtx.TransferViewModel()
+	jobs := vm.InterruptedUploads()
+	if len(jobs) == 0 {
+		return
+	}
+
+	lines := make([]string, len(jobs))
+	for i, job := range jobs {
+		lines[i] = fmt.Sprintf("- %s (%s of %s sent)", job.Key,
+			humanize.Bytes(job.Multipart.UploadedBytes()), humanize.Bytes(job.TotalBytes))
+	}
+	message := fyne_widget.NewLabel(fmt.Sprintf(
+		"%d upload(s) were interrupted when the application was closed:\n%s\n\n"+
+			"Resume them from where they stopped, or abort them to delete the parts already sent?",
+		len(jobs), strings.Join(lines, "\n")))
+	message.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewCustomConfirm("Interrupted uploads", "Resume", "Abort", message, func(resume bool) {
+		for _, job := range jobs {
+			if resume {
+				vm.Resume(job.ID)
+			} else {
+				vm.Cancel(job.ID)
+			}
+		}
+	}, appCtx.Window())
+	d.Resize(fyne.NewSize(500, 300))
+	d.Show()
+}
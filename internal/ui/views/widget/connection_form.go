@@ -1,6 +1,11 @@
 package widget
 
 import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
@@ -66,7 +71,7 @@ func (w *ConnectionForm) CreateRenderer() fyne.WidgetRenderer {
 	switch w.defaultConnection.Provider() {
 	case connection_deck.AWSProvider:
 		tabs.SelectIndex(0)
-	case connection_deck.S3LikeProvider:
+	default:
 		tabs.SelectIndex(1)
 	}
 	return widget.NewSimpleRenderer(tabs)
@@ -97,12 +102,18 @@ func (w *ConnectionForm) buildAWSForm() *widget.Form {
 	bucketData := binding.NewString()
 	u.Skip(bucketData.Set(w.defaultConnection.Bucket()))
 
+	rootPrefixData := binding.NewString()
+	u.Skip(rootPrefixData.Set(w.defaultConnection.RootPrefix()))
+
 	regionData := binding.NewString()
 	u.Skip(regionData.Set(w.defaultConnection.Region()))
 
 	readOnlyData := binding.NewBool()
 	u.Skip(readOnlyData.Set(w.defaultConnection.ReadOnly()))
 
+	accountLevelData := binding.NewBool()
+	u.Skip(accountLevelData.Set(w.defaultConnection.IsAccountLevel()))
+
 	// Create Form items
 	nameFormItem := makeTextFormItemWithData(
 		nameData,
@@ -126,13 +137,6 @@ func (w *ConnectionForm) buildAWSForm() *widget.Form {
 		w.appCtx.Window(),
 	)
 
-	bucketFormItem := makeTextFormItemWithData(
-		bucketData,
-		"Bucket name",
-		"my-bucket",
-		w.enableCopy,
-		w.appCtx.Window(),
-	)
 	regionFormItem := makeTextFormItemWithData(
 		regionData,
 		"Region",
@@ -144,13 +148,48 @@ func (w *ConnectionForm) buildAWSForm() *widget.Form {
 	readOnlyCheckbox := widget.NewCheckWithData("Read only", readOnlyData)
 	readOnlyFormItem := widget.NewFormItem("Read only", readOnlyCheckbox)
 
+	accountLevelCheckbox := widget.NewCheckWithData("Browse all the buckets of the account", accountLevelData)
+	accountLevelFormItem := widget.NewFormItem("Account level", accountLevelCheckbox)
+
+	rootPrefixFormItem := makeTextFormItemWithData(
+		rootPrefixData,
+		"Root prefix",
+		"team-x/ (optional, browse only below this prefix)",
+		w.enableCopy,
+		w.appCtx.Window(),
+	)
+
+	credentialSourceFormItem, getCredentialSource := w.newCredentialSourceFormItem()
+	transportFormItem, getTransport := w.newTransportFormItem()
+
+	options := func() []connection_deck.ConnectionOption {
+		return []connection_deck.ConnectionOption{
+			connection_deck.AsAWS(uu.GetString(regionData)),
+			connection_deck.WithReadOnlyOption(uu.GetBool(readOnlyData)),
+			connection_deck.WithAccountLevel(uu.GetBool(accountLevelData)),
+			connection_deck.WithRootPrefix(uu.GetString(rootPrefixData)),
+			connection_deck.WithCredentialSource(getCredentialSource()),
+			connection_deck.WithTransport(getTransport()),
+		}
+	}
+	newConn := func() *connection_deck.Connection {
+		return newFormConnection(nameData, accessKeyData, secretKeyData, bucketData, options())
+	}
+	bucketFormItem := w.newBucketFormItem(bucketData, newConn)
+	diagnoseFormItem := w.newDiagnoseFormItem(newConn)
+
 	f := widget.NewForm(
 		nameFormItem,
+		credentialSourceFormItem,
 		accessKeyFormItem,
 		secretKeyFormItem,
-		bucketFormItem,
 		regionFormItem,
+		accountLevelFormItem,
+		bucketFormItem,
+		rootPrefixFormItem,
 		readOnlyFormItem,
+		transportFormItem,
+		diagnoseFormItem,
 	)
 	f.OnSubmit = func() {
 		w.handleOnSubmit(
@@ -158,8 +197,7 @@ func (w *ConnectionForm) buildAWSForm() *widget.Form {
 			uu.GetString(accessKeyData),
 			uu.GetString(secretKeyData),
 			uu.GetString(bucketData),
-			connection_deck.AsAWS(uu.GetString(regionData)),
-			connection_deck.WithReadOnlyOption(uu.GetBool(readOnlyData)),
+			options()...,
 		)
 	}
 
@@ -183,12 +221,23 @@ func (w *ConnectionForm) buildS3LikeForm() *widget.Form {
 	bucketData := binding.NewString()
 	u.Skip(bucketData.Set(w.defaultConnection.Bucket()))
 
+	rootPrefixData := binding.NewString()
+	u.Skip(rootPrefixData.Set(w.defaultConnection.RootPrefix()))
+
 	readOnlyData := binding.NewBool()
 	u.Skip(readOnlyData.Set(w.defaultConnection.ReadOnly()))
 
+	accountLevelData := binding.NewBool()
+	u.Skip(accountLevelData.Set(w.defaultConnection.IsAccountLevel()))
+
 	useTlsData := binding.NewBool()
 	u.Skip(useTlsData.Set(w.defaultConnection.IsTLSActivated()))
 
+	regionData := binding.NewString()
+	if w.defaultConnection.Provider() != connection_deck.AWSProvider {
+		u.Skip(regionData.Set(w.defaultConnection.Region()))
+	}
+
 	nameFormItem := makeTextFormItemWithData(
 		nameData,
 		"Connection name",
@@ -217,29 +266,72 @@ func (w *ConnectionForm) buildS3LikeForm() *widget.Form {
 		w.enableCopy,
 		w.appCtx.Window(),
 	)
-	bucketFormItem := makeTextFormItemWithData(
-		bucketData,
-		"Bucket name",
-		"my-bucket",
-		w.enableCopy,
-		w.appCtx.Window(),
-	)
+	regionEntry := widget.NewEntryWithData(regionData)
+	regionFormItem := widget.NewFormItem("Region", regionEntry)
 
 	useTlsCheckbox := widget.NewCheckWithData("Use TLS", useTlsData)
 	useTlsFormItem := widget.NewFormItem("UseTls", useTlsCheckbox)
 
+	providerSelect, getProvider := newProviderSelect(w.defaultConnection.Provider(), func(preset connection_deck.ProviderPreset) {
+		regionEntry.SetPlaceHolder(preset.DefaultRegion)
+		if endpoint := preset.Endpoint(uu.GetString(regionData)); endpoint != "" {
+			u.Skip(serverData.Set(endpoint))
+			u.Skip(useTlsData.Set(true))
+		}
+	})
+	providerFormItem := widget.NewFormItem("Provider", providerSelect)
+	regionEntry.SetPlaceHolder(getProvider().Preset().DefaultRegion)
+
 	readOnlyCheckbox := widget.NewCheckWithData("Read only", readOnlyData)
 	readOnlyFormItem := widget.NewFormItem("Read only", readOnlyCheckbox)
 
+	accountLevelCheckbox := widget.NewCheckWithData("Browse all the buckets of the account", accountLevelData)
+	accountLevelFormItem := widget.NewFormItem("Account level", accountLevelCheckbox)
+
+	rootPrefixFormItem := makeTextFormItemWithData(
+		rootPrefixData,
+		"Root prefix",
+		"team-x/ (optional, browse only below this prefix)",
+		w.enableCopy,
+		w.appCtx.Window(),
+	)
+
+	credentialSourceFormItem, getCredentialSource := w.newCredentialSourceFormItem()
+	transportFormItem, getTransport := w.newTransportFormItem()
+
+	options := func() []connection_deck.ConnectionOption {
+		return []connection_deck.ConnectionOption{
+			connection_deck.AsProvider(
+				getProvider(), uu.GetString(serverData), uu.GetString(regionData), uu.GetBool(useTlsData)),
+			connection_deck.WithReadOnlyOption(uu.GetBool(readOnlyData)),
+			connection_deck.WithAccountLevel(uu.GetBool(accountLevelData)),
+			connection_deck.WithRootPrefix(uu.GetString(rootPrefixData)),
+			connection_deck.WithCredentialSource(getCredentialSource()),
+			connection_deck.WithTransport(getTransport()),
+		}
+	}
+	newConn := func() *connection_deck.Connection {
+		return newFormConnection(nameData, accessKeyData, secretKeyData, bucketData, options())
+	}
+	bucketFormItem := w.newBucketFormItem(bucketData, newConn)
+	diagnoseFormItem := w.newDiagnoseFormItem(newConn)
+
 	// Create form
 	f := widget.NewForm(
 		nameFormItem,
+		providerFormItem,
+		credentialSourceFormItem,
 		accessKeyFormItem,
 		secretKeyFormItem,
 		serverFormItem,
-		bucketFormItem,
+		regionFormItem,
 		useTlsFormItem,
+		accountLevelFormItem,
+		bucketFormItem,
+		rootPrefixFormItem,
 		readOnlyFormItem,
+		transportFormItem,
+		diagnoseFormItem,
 	)
 	f.OnSubmit = func() {
 		w.handleOnSubmit(
@@ -247,14 +339,277 @@ func (w *ConnectionForm) buildS3LikeForm() *widget.Form {
 			uu.GetString(accessKeyData),
 			uu.GetString(secretKeyData),
 			uu.GetString(bucketData),
-			connection_deck.AsS3Like(uu.GetString(serverData), uu.GetBool(useTlsData)),
-			connection_deck.WithReadOnlyOption(uu.GetBool(readOnlyData)),
+			options()...,
 		)
 	}
 
 	return f
 }
 
+// newBucketFormItem returns the form item of the bucket, with a button listing the buckets
+// the connection being filled in has access to. The bucket is optional for an account-level connection.
+func (w *ConnectionForm) newBucketFormItem(
+	bucketData binding.String,
+	newConn func() *connection_deck.Connection,
+) *widget.FormItem {
+	bucketEntry := widget.NewSelectEntry(nil)
+	bucketEntry.Bind(bucketData)
+	bucketEntry.SetPlaceHolder("my-bucket")
+
+	var listBtn *widget.Button
+	listBtn = widget.NewButtonWithIcon("List buckets", theme.SearchIcon(), func() {
+		listBtn.Disable()
+		w.appCtx.ConnectionViewModel().ListBuckets(newConn(), func(buckets []string, err error) {
+			fyne.Do(func() {
+				listBtn.Enable()
+				if err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				bucketEntry.SetOptions(buckets)
+			})
+		})
+	})
+
+	actions := container.NewHBox(listBtn)
+	if w.enableCopy {
+		actions.Add(makeCopyBtnWithData(w.enableCopy, bucketData, w.appCtx.Window()))
+	}
+	return widget.NewFormItem("Bucket name", container.NewBorder(nil, nil, nil, actions, bucketEntry))
+}
+
+// newProviderSelect returns the select of the S3-like providers, with a function returning the selected one.
+// onPresetChanged is called with the preset of the provider picked by the user.
+func newProviderSelect(
+	current connection_deck.Provider,
+	onPresetChanged func(preset connection_deck.ProviderPreset),
+) (*widget.Select, func() connection_deck.Provider) {
+	providers := slices.DeleteFunc(slices.Clone(connection_deck.Providers), func(p connection_deck.Provider) bool {
+		return p == connection_deck.AWSProvider
+	})
+	labels := make([]string, len(providers))
+	for i, p := range providers {
+		labels[i] = p.Preset().Label
+	}
+
+	providerSelect := widget.NewSelect(labels, nil)
+	selected := max(slices.Index(providers, current), 0)
+	providerSelect.SetSelectedIndex(selected)
+	providerSelect.OnChanged = func(string) {
+		onPresetChanged(providers[providerSelect.SelectedIndex()].Preset())
+	}
+
+	return providerSelect, func() connection_deck.Provider {
+		return providers[max(providerSelect.SelectedIndex(), 0)]
+	}
+}
+
+// addressingStyleLabels are the labels of connection_deck.AddressingStyles, in the same order
+var addressingStyleLabels = []string{
+	"Provider default",
+	"Path (https://server/bucket)",
+	"Virtual-hosted (https://bucket.server)",
+}
+
+// newTransportFormItem returns the form item of the network options, folded by default,
+// and a function returning the options filled in.
+// A warning stays visible while the TLS verification is off.
+func (w *ConnectionForm) newTransportFormItem() (*widget.FormItem, func() connection_deck.TransportOptions) {
+	transport := w.defaultConnection.Transport()
+
+	caBundleEntry := widget.NewEntry()
+	caBundleEntry.SetText(transport.CABundle)
+	caBundleEntry.SetPlaceHolder("/path/to/ca.pem or the PEM content")
+
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetText(transport.ProxyURL)
+	proxyEntry.SetPlaceHolder("http://proxy:3128 or socks5://proxy:1080")
+
+	addressingSelect := widget.NewSelect(addressingStyleLabels, nil)
+	addressingSelect.SetSelectedIndex(max(slices.Index(connection_deck.AddressingStyles, transport.AddressingStyle), 0))
+
+	timeoutEntry := widget.NewEntry()
+	if transport.Timeout > 0 {
+		timeoutEntry.SetText(strconv.Itoa(int(transport.Timeout / time.Second)))
+	}
+	timeoutEntry.SetPlaceHolder("Request timeout in seconds (optional)")
+
+	insecureWarning := container.NewHBox(
+		widget.NewIcon(theme.WarningIcon()),
+		&widget.Label{
+			Text:       "TLS verification is off: the server identity isn't checked and the credentials can be intercepted",
+			Importance: widget.DangerImportance,
+			TextStyle:  fyne.TextStyle{Bold: true},
+			Wrapping:   fyne.TextWrapWord,
+		},
+	)
+	insecureWarning.Hide()
+
+	insecureCheck := widget.NewCheck("Skip the TLS certificate verification", nil)
+	insecureCheck.SetChecked(transport.InsecureSkipVerify)
+	if transport.InsecureSkipVerify {
+		insecureWarning.Show()
+	}
+	insecureCheck.OnChanged = func(checked bool) {
+		if !checked {
+			insecureWarning.Hide()
+			return
+		}
+		insecureWarning.Show()
+		dialog.ShowConfirm("Skip the TLS verification?",
+			"Any server could impersonate this one and read your credentials and data.\n"+
+				"Prefer trusting its certificate authority with a CA bundle.",
+			func(confirmed bool) {
+				if !confirmed {
+					insecureCheck.SetChecked(false)
+				}
+			}, w.appCtx.Window())
+	}
+
+	options := widget.NewForm(
+		widget.NewFormItem("CA bundle", caBundleEntry),
+		widget.NewFormItem("TLS", insecureCheck),
+		widget.NewFormItem("Proxy", proxyEntry),
+		widget.NewFormItem("Addressing", addressingSelect),
+		widget.NewFormItem("Timeout", timeoutEntry),
+	)
+
+	getTransport := func() connection_deck.TransportOptions {
+		seconds, _ := strconv.Atoi(timeoutEntry.Text)
+		return connection_deck.TransportOptions{
+			CABundle:           strings.TrimSpace(caBundleEntry.Text),
+			InsecureSkipVerify: insecureCheck.Checked,
+			ProxyURL:           strings.TrimSpace(proxyEntry.Text),
+			AddressingStyle:    connection_deck.AddressingStyles[max(addressingSelect.SelectedIndex(), 0)],
+			Timeout:            time.Duration(seconds) * time.Second,
+		}
+	}
+	content := container.NewVBox(
+		insecureWarning,
+		widget.NewAccordion(widget.NewAccordionItem("Certificates, proxy, addressing and timeout", options)),
+	)
+	return widget.NewFormItem("Network", content), getTransport
+}
+
+// newFormConnection returns a connection with the values being filled in, outside any deck.
+func newFormConnection(
+	nameData, accessKeyData, secretKeyData, bucketData binding.String,
+	options []connection_deck.ConnectionOption,
+) *connection_deck.Connection {
+	return connection_deck.New().New(
+		uu.GetString(nameData),
+		uu.GetString(accessKeyData),
+		uu.GetString(secretKeyData),
+		uu.GetString(bucketData),
+		options...,
+	).Payload().(connection_deck.CreateConnectionTriggered).Connection()
+}
+
+// newDiagnoseFormItem returns the form item with a button checking the connection being filled in,
+// step by step, before it is saved.
+func (w *ConnectionForm) newDiagnoseFormItem(newConn func() *connection_deck.Connection) *widget.FormItem {
+	var testBtn *widget.Button
+	testBtn = widget.NewButtonWithIcon("Test connection", theme.MediaPlayIcon(), func() {
+		testBtn.Disable()
+		progress := dialog.NewCustomWithoutButtons("Testing the connection...", widget.NewProgressBarInfinite(), w.appCtx.Window())
+		progress.Show()
+		w.appCtx.ConnectionViewModel().Diagnose(newConn(), func(steps []connection_deck.DiagnosticStep) {
+			fyne.Do(func() {
+				progress.Hide()
+				testBtn.Enable()
+				showDiagnostic(steps, w.appCtx.Window())
+			})
+		})
+	})
+	return widget.NewFormItem("Diagnostics", container.NewHBox(testBtn))
+}
+
+func showDiagnostic(steps []connection_deck.DiagnosticStep, win fyne.Window) {
+	rows := container.NewVBox()
+	for _, step := range steps {
+		icon := theme.ConfirmIcon()
+		switch step.Status {
+		case connection_deck.DiagnosticFailed:
+			icon = theme.ErrorIcon()
+		case connection_deck.DiagnosticSkipped:
+			icon = theme.MediaSkipNextIcon()
+		}
+		name := widget.NewLabelWithStyle(step.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		details := widget.NewLabel(step.Details)
+		details.Wrapping = fyne.TextWrapWord
+		rows.Add(container.NewBorder(nil, nil, container.NewHBox(widget.NewIcon(icon), name), nil, details))
+	}
+	d := dialog.NewCustom("Connection test", "Close", container.NewVScroll(rows), win)
+	d.Resize(fyne.NewSize(650, 450))
+	d.Show()
+}
+
+// credentialSourceLabels are the labels of connection_deck.CredentialSourceTypes, in the same order
+var credentialSourceLabels = []string{
+	"Access keys",
+	"Access keys with a session token",
+	"AWS profile",
+	"Environment variables",
+	"Assume a role",
+}
+
+// newCredentialSourceFormItem returns the form item to choose where the credentials come from,
+// with the fields of the chosen source, and a function returning the source filled in.
+func (w *ConnectionForm) newCredentialSourceFormItem() (*widget.FormItem, func() connection_deck.CredentialSource) {
+	source := w.defaultConnection.CredentialSource()
+
+	sessionTokenEntry := widget.NewPasswordEntry()
+	sessionTokenEntry.SetText(source.SessionToken)
+	sessionTokenEntry.SetPlaceHolder("Session token")
+
+	profileEntry := widget.NewEntry()
+	profileEntry.SetText(source.Profile)
+	profileEntry.SetPlaceHolder("default")
+
+	roleARNEntry := widget.NewEntry()
+	roleARNEntry.SetText(source.RoleARN)
+	roleARNEntry.SetPlaceHolder("arn:aws:iam::123456789012:role/my-role")
+	externalIDEntry := widget.NewEntry()
+	externalIDEntry.SetText(source.ExternalID)
+	externalIDEntry.SetPlaceHolder("External ID (optional)")
+	durationEntry := widget.NewEntry()
+	if source.SessionDuration > 0 {
+		durationEntry.SetText(strconv.Itoa(int(source.SessionDuration / time.Minute)))
+	}
+	durationEntry.SetPlaceHolder("Session duration in minutes (optional)")
+
+	fields := map[connection_deck.CredentialSourceType]fyne.CanvasObject{
+		connection_deck.SessionCredentials: sessionTokenEntry,
+		connection_deck.ProfileCredentials: profileEntry,
+		connection_deck.EnvCredentials: widget.NewLabel(
+			"AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN are read when connecting"),
+		connection_deck.AssumeRoleCredentials: container.NewVBox(roleARNEntry, externalIDEntry, durationEntry),
+	}
+	fieldsContainer := container.NewVBox()
+
+	sourceSelect := widget.NewSelect(credentialSourceLabels, nil)
+	sourceSelect.OnChanged = func(string) {
+		fieldsContainer.RemoveAll()
+		if f, ok := fields[connection_deck.CredentialSourceTypes[sourceSelect.SelectedIndex()]]; ok {
+			fieldsContainer.Add(f)
+		}
+	}
+	sourceSelect.SetSelectedIndex(slices.Index(connection_deck.CredentialSourceTypes, source.Type))
+
+	getSource := func() connection_deck.CredentialSource {
+		minutes, _ := strconv.Atoi(durationEntry.Text)
+		return connection_deck.CredentialSource{
+			Type:            connection_deck.CredentialSourceTypes[sourceSelect.SelectedIndex()],
+			SessionToken:    sessionTokenEntry.Text,
+			Profile:         profileEntry.Text,
+			RoleARN:         roleARNEntry.Text,
+			ExternalID:      externalIDEntry.Text,
+			SessionDuration: time.Duration(minutes) * time.Minute,
+		}
+	}
+	return widget.NewFormItem("Credentials", container.NewVBox(sourceSelect, fieldsContainer)), getSource
+}
+
 func makeCopyBtnWithData(enableCopy bool, data binding.String, w fyne.Window) *widget.Button {
 	return widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
 		if enableCopy {
@@ -20,6 +20,9 @@ type DirectoryPreview struct {
 
 	OnValidate func(strategy directory.MaterializeStrategy)
 
+	// ActionName is the name of the previewed operation, displayed in the message and on the validation button.
+	ActionName string
+
 	appCtx           appcontext.AppContext
 	data             binding.Tree[previewNodeItem]
 	preview          *directory.Preview
@@ -40,6 +43,7 @@ func NewDirectoryPreview(appCtx appcontext.AppContext, preview *directory.Previe
 		preview:    preview,
 		infoData:   binding.NewString(),
 		OnValidate: func(directory.MaterializeStrategy) {},
+		ActionName: "Upload",
 		selectedStrategy: binding.NewItem[directory.MaterializeStrategy](func(s1, s2 directory.MaterializeStrategy) bool {
 			return s1 == s2
 		}),
@@ -61,15 +65,15 @@ func (w *DirectoryPreview) makeContent(mainContent fyne.CanvasObject, validateLa
 	} else {
 		validateBtn = NewButtonWithData(
 			uu.NewBindingItemFormatter(w.selectedStrategy, func(strategy directory.MaterializeStrategy) string {
-				return "Upload: " + strategy.String()
+				return w.ActionName + ": " + strategy.String()
 			}),
 			onValidate)
 	}
 
 	return container.NewBorder(
 		container.NewBorder(nil, nil,
-			widget.NewLabel(fmt.Sprintf("You are about to upload %d directories and %d files",
-				w.preview.Count().Directories, w.preview.Count().Files)),
+			widget.NewLabel(fmt.Sprintf("You are about to %s %d directories and %d files",
+				strings.ToLower(w.ActionName), w.preview.Count().Directories, w.preview.Count().Files)),
 			widget.NewLabelWithData(w.infoData)),
 		container.NewBorder(nil, nil, nil,
 			validateBtn,
@@ -91,7 +95,7 @@ func (w *DirectoryPreview) CreateRenderer() fyne.WidgetRenderer {
 	if len(strategies) == 1 {
 		return widget.NewSimpleRenderer(w.makeContent(
 			w.makeTree(strategies[0]),
-			"Upload"))
+			w.ActionName))
 	}
 
 	var tis []*container.TabItem
@@ -3,6 +3,9 @@ package widget
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -12,7 +15,9 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/u"
 	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
 	"github.com/thomas-marquis/s3-box/internal/ui/viewmodel"
 )
@@ -32,10 +37,16 @@ type DirectoryDetails struct {
 	toolbar            *widget.Toolbar
 	newDirectoryAction *ToolbarButton
 	createFileAction   *ToolbarButton
+	pasteAction        *ToolbarButton
 	renameAction       *ToolbarButton
 	reloadAction       *ToolbarButton
+	downloadAction     *ToolbarButton
 	deleteAction       *ToolbarButton
+	sizeAction         *ToolbarButton
+	syncAction         *ToolbarButton
 	loadingBar         *widget.ProgressBarInfinite
+	showDeletedCheck   *widget.Check
+	sizeStatsLabel     *widget.Label
 
 	dropZone *DropZone
 }
@@ -50,31 +61,49 @@ func NewDirectoryDetails(appCtx appcontext.AppContext) *DirectoryDetails {
 	reloadAction := NewToolbarButton("Reload", theme.ViewRefreshIcon(), func() {})
 	createDirAction := NewToolbarButton("Create directory", theme.FolderNewIcon(), func() {})
 	createFileAction := NewToolbarButton("Create file", theme.ContentAddIcon(), func() {})
+	pasteAction := NewToolbarButton("Paste", theme.ContentPasteIcon(), func() {})
 	renameAction := NewToolbarButton("Rename", theme.FileTextIcon(), func() {})
+	downloadAction := NewToolbarButton("Download", theme.DownloadIcon(), func() {})
 	deleteAction := NewToolbarButton("Delete", theme.DeleteIcon(), func() {})
+	sizeAction := NewToolbarButton("Compute size", theme.StorageIcon(), func() {})
+	syncAction := NewToolbarButton("Sync with a local folder", theme.MediaReplayIcon(), func() {})
 	toolbar := widget.NewToolbar(
 		reloadAction,
 		createDirAction,
 		createFileAction,
+		pasteAction,
 		renameAction,
+		downloadAction,
 		deleteAction,
+		sizeAction,
+		syncAction,
 	)
 	loadingBar := widget.NewProgressBarInfinite()
 	loadingBar.Hide()
 
+	sizeStatsLabel := widget.NewLabel("")
+	sizeStatsLabel.Selectable = true
+	sizeStatsLabel.Hide()
+
 	w := &DirectoryDetails{
 		appCtx:             appCtx,
 		pathLabel:          pathLabel,
 		toolbar:            toolbar,
 		newDirectoryAction: createDirAction,
 		createFileAction:   createFileAction,
+		pasteAction:        pasteAction,
 		renameAction:       renameAction,
 		reloadAction:       reloadAction,
+		downloadAction:     downloadAction,
 		deleteAction:       deleteAction,
+		sizeAction:         sizeAction,
+		syncAction:         syncAction,
+		sizeStatsLabel:     sizeStatsLabel,
 		loadingBar:         loadingBar,
 		renameErrContent:   newRenameFailedPanel(appCtx.Window()),
 		dropZone:           NewDropZone(dropZoneInitialText, appCtx.Window()),
 	}
+	w.showDeletedCheck = widget.NewCheck("Show deleted files", w.onShowDeletedChanged)
 	w.ExtendBaseWidget(w)
 
 	appCtx.ExplorerViewModel().IsSelectedDirectoryLoading().AddListener(binding.NewDataListener(func() {
@@ -119,6 +148,11 @@ func (w *DirectoryDetails) CreateRenderer() fyne.WidgetRenderer {
 				layout.NewCustomPaddedLayout(0, 0, 5, 5),
 				w.toolbar,
 			),
+			container.New(
+				layout.NewCustomPaddedLayout(0, 0, 5, 5),
+				w.showDeletedCheck,
+			),
+			w.sizeStatsLabel,
 			container.New(
 				layout.NewCustomPaddedLayout(10, 20, 0, 0),
 				widget.NewSeparator(),
@@ -155,11 +189,25 @@ func (w *DirectoryDetails) Select(dir *directory.Directory) {
 
 	w.newDirectoryAction.SetOnTapped(w.makeOnCreateDirectory(vm, dir))
 	w.createFileAction.SetOnTapped(w.makeOnCreateFile(vm, dir))
+	w.pasteAction.SetOnTapped(w.makeOnPaste(vm, dir))
 	w.renameAction.SetOnTapped(w.makeOnRename(vm, dir))
 	w.reloadAction.SetOnTapped(w.makeOnReload(vm, dir))
+	w.downloadAction.SetOnTapped(w.makeOnDownload(vm, dir))
 	w.deleteAction.SetOnTapped(w.makeOnDelete(vm, dir))
+	w.sizeAction.SetOnTapped(w.makeOnComputeSize(vm, dir))
+	w.syncAction.SetOnTapped(w.makeOnSync(vm, dir))
 
 	w.reloadAction.Enable()
+	w.downloadAction.Enable()
+	w.sizeAction.Enable()
+	w.syncAction.Enable()
+
+	if stats, ok := dir.SizeStats(); ok {
+		w.sizeStatsLabel.SetText(formatSizeStats(stats))
+		w.sizeStatsLabel.Show()
+	} else {
+		w.sizeStatsLabel.Hide()
+	}
 
 	if dir.IsRoot() {
 		w.renameAction.Disable()
@@ -197,12 +245,18 @@ func (w *DirectoryDetails) Select(dir *directory.Directory) {
 		w.createFileAction.Disable()
 		w.renameAction.Disable()
 		w.reloadAction.Disable()
+		w.pasteAction.Disable()
 		w.dropZone.Hide()
 		w.deleteAction.Disable()
 	} else {
 		w.newDirectoryAction.Enable()
 		w.createFileAction.Enable()
 		w.dropZone.Show()
+		if vm.HasClipboardContent() {
+			w.pasteAction.Enable()
+		} else {
+			w.pasteAction.Disable()
+		}
 	}
 
 	w.dropZone.Reset()
@@ -249,6 +303,46 @@ func (w *DirectoryDetails) Select(dir *directory.Directory) {
 	})
 }
 
+// Paste previews the paste of the clipboard content into the selected directory, when allowed.
+func (w *DirectoryDetails) Paste() {
+	dir := w.appCtx.ExplorerViewModel().SelectedDirectory()
+	if dir == nil || w.pasteAction.Disabled() {
+		return
+	}
+	w.makeOnPaste(w.appCtx.ExplorerViewModel(), dir)()
+}
+
+func (w *DirectoryDetails) makeOnPaste(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func() {
+	return func() {
+		vm.OnPasteReady(func(prev viewmodel.PastePreviewState) {
+			dirPreview := NewDirectoryPreview(w.appCtx, prev.Preview)
+			dirPreview.ActionName = "Paste"
+
+			dial := dialog.NewCustom(
+				"Confirm paste",
+				"Cancel",
+				container.NewScroll(dirPreview),
+				w.appCtx.Window())
+			dial.Resize(fyne.NewSize(800, 600))
+
+			dirPreview.OnValidate = func(selectedStrategy directory.MaterializeStrategy) {
+				dial.Dismiss()
+				if err := vm.Paste(prev, selectedStrategy); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				showOperationProgress(w.appCtx.Window(), "Pasting", vm.SelectionProgress(), vm.CancelSelectionOperation)
+			}
+
+			dial.Show()
+		})
+
+		if err := vm.PreparePaste(dir); err != nil {
+			dialog.ShowError(err, w.appCtx.Window())
+		}
+	}
+}
+
 func (w *DirectoryDetails) makeOnUpload(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func(bool) {
 	return func(dropped bool) {
 		if dropped {
@@ -365,6 +459,19 @@ func (w *DirectoryDetails) makeOnRename(vm viewmodel.ExplorerViewModel, dir *dir
 	}
 }
 
+// onShowDeletedChanged reloads the selected directory to list or hide the files whose latest version is a delete marker.
+func (w *DirectoryDetails) onShowDeletedChanged(show bool) {
+	vm := w.appCtx.ExplorerViewModel()
+	u.Skip(vm.ShowDeletedFiles().Set(show))
+	dir := vm.SelectedDirectory()
+	if dir == nil || !dir.IsLoaded() {
+		return
+	}
+	if err := vm.ReloadDirectory(dir); err != nil {
+		dialog.ShowError(err, w.appCtx.Window())
+	}
+}
+
 func (w *DirectoryDetails) makeOnReload(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func() {
 	return func() {
 		if err := vm.ReloadDirectory(dir); err != nil {
@@ -375,10 +482,251 @@ func (w *DirectoryDetails) makeOnReload(vm viewmodel.ExplorerViewModel, dir *dir
 
 func (w *DirectoryDetails) makeOnDelete(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func() {
 	return func() {
-		vm.DeleteDirectory(dir)
+		// a directory not loaded yet, or not fully, has no content in state but may not be empty
+		if dir.IsLoaded() && dir.IsEmpty() && !dir.HasMorePages() {
+			vm.DeleteDirectory(dir)
+			return
+		}
+
+		dialog.ShowConfirm("Delete directory",
+			fmt.Sprintf("Directory %s is not empty. Do you want to delete it with all its content?", dir.Path()),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := vm.DeleteDirectoryRecursively(dir); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				showOperationProgress(w.appCtx.Window(), "Deleting directory", vm.DeletionProgress(), func() {
+					vm.CancelDeleteDirectory(dir)
+				})
+			},
+			w.appCtx.Window())
+	}
+}
+
+func (w *DirectoryDetails) makeOnComputeSize(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func() {
+	return func() {
+		if err := vm.ComputeDirectorySize(dir); err != nil {
+			dialog.ShowError(err, w.appCtx.Window())
+			return
+		}
+		showOperationProgress(w.appCtx.Window(), "Computing size", vm.SizeProgress(), func() {
+			vm.CancelComputeDirectorySize(dir)
+		})
+	}
+}
+
+func (w *DirectoryDetails) makeOnSync(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func() {
+	return func() {
+		vm.OnSyncReady(func(plan *directory.SyncPlan) {
+			prev, err := plan.Preview()
+			if err != nil {
+				dialog.ShowError(err, w.appCtx.Window())
+				return
+			}
+			dirPreview := NewDirectoryPreview(w.appCtx, prev)
+			dirPreview.ActionName = "Sync"
+
+			dial := dialog.NewCustom(
+				fmt.Sprintf("Confirm sync: %s", plan.Options.Direction),
+				"Cancel",
+				container.NewScroll(dirPreview),
+				w.appCtx.Window())
+			dial.Resize(fyne.NewSize(800, 600))
+
+			dirPreview.OnValidate = func(directory.MaterializeStrategy) {
+				dial.Dismiss()
+				if err := vm.ApplySync(plan); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				showOperationProgress(w.appCtx.Window(), "Syncing", vm.SyncProgress(), func() {
+					vm.CancelSync(dir)
+				})
+			}
+
+			dial.Show()
+		})
+
+		directions := []directory.SyncDirection{directory.SyncToBucket, directory.SyncToLocal}
+		if w.appCtx.ConnectionViewModel().IsReadOnly() {
+			directions = directions[1:]
+		}
+		directionLabels := make([]string, 0, len(directions))
+		for _, d := range directions {
+			directionLabels = append(directionLabels, d.String())
+		}
+
+		localPathEntry := widget.NewEntry()
+		localPathEntry.SetPlaceHolder("Local folder")
+		browseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+			folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+				if err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				if uri != nil {
+					localPathEntry.SetText(uri.Path())
+				}
+			}, w.appCtx.Window())
+			folderDialog.SetLocation(vm.LastDownloadLocation())
+			folderDialog.Show()
+		})
+		directionSelect := widget.NewSelect(directionLabels, nil)
+		directionSelect.SetSelectedIndex(0)
+		deleteCheck := widget.NewCheck("Delete the files missing from the source", nil)
+		checksumCheck := widget.NewCheck("Compare checksums instead of modification dates", nil)
+
+		d := dialog.NewForm(
+			fmt.Sprintf("Sync %s", dir.Path()),
+			"Compare",
+			"Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Local folder", container.NewBorder(nil, nil, nil, browseBtn, localPathEntry)),
+				widget.NewFormItem("Direction", directionSelect),
+				widget.NewFormItem("", deleteCheck),
+				widget.NewFormItem("", checksumCheck),
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				opts := directory.SyncOptions{
+					Direction:        directions[directionSelect.SelectedIndex()],
+					DeleteExtraneous: deleteCheck.Checked,
+					CompareChecksums: checksumCheck.Checked,
+				}
+				if err := vm.PrepareSync(dir, localPathEntry.Text, opts); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+				}
+			},
+			w.appCtx.Window(),
+		)
+		d.Resize(fyne.NewSize(500, 250))
+		d.Show()
+	}
+}
+
+// formatSizeStats renders the size statistics of a directory as a plain text summary.
+func formatSizeStats(stats directory.SizeStats) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Total: %s in %d objects (computed at %s)\n",
+		humanize.Bytes(stats.Total.Bytes), stats.Total.ObjectCount, stats.ComputedAt.Format("2006-01-02 15:04"))
+
+	sb.WriteString("\nBy storage class:\n")
+	for _, class := range stats.StorageClasses() {
+		entry := stats.ByStorageClass[class]
+		fmt.Fprintf(&sb, "  %s: %s (%d objects)\n", class, humanize.Bytes(entry.Bytes), entry.ObjectCount)
+	}
+
+	sb.WriteString("\nBy subdirectory:\n")
+	for _, name := range stats.SubDirectoryNames() {
+		entry := stats.BySubDirectory[name]
+		if name == "" {
+			name = "(files)"
+		} else {
+			name += "/"
+		}
+		fmt.Fprintf(&sb, "  %s: %s (%d objects)\n", name, humanize.Bytes(entry.Bytes), entry.ObjectCount)
+	}
+
+	sb.WriteString("\nLargest files:\n")
+	for _, file := range stats.LargestFiles {
+		fmt.Fprintf(&sb, "  %s: %s\n", file.FullPath, humanize.Bytes(file.SizeBytes))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (w *DirectoryDetails) makeOnDownload(vm viewmodel.ExplorerViewModel, dir *directory.Directory) func() {
+	return func() {
+		folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, w.appCtx.Window())
+				return
+			}
+			if uri == nil {
+				return
+			}
+
+			dest := uri.Path()
+			download := func(strategy directory.MaterializeStrategy) {
+				if err := vm.DownloadDirectory(dir, dest, strategy); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				u.Skip(vm.UpdateLastDownloadLocation(filepath.Join(dest, dir.Name())))
+				showOperationProgress(w.appCtx.Window(), "Downloading directory", vm.DownloadProgress(), func() {
+					vm.CancelDownloadDirectory(dir)
+				})
+			}
+
+			if _, err := os.Stat(filepath.Join(dest, dir.Name())); err != nil {
+				download(directory.MaterializeReplace)
+				return
+			}
+			askMaterializeStrategy(w.appCtx.Window(),
+				fmt.Sprintf("The local directory %s already exists. What should be done with the existing files?",
+					filepath.Join(dest, dir.Name())),
+				download)
+		}, w.appCtx.Window())
+
+		folderDialog.SetLocation(vm.LastDownloadLocation())
+		folderDialog.Show()
 	}
 }
 
+// askMaterializeStrategy lets the user choose whether the existing local files must be kept or replaced.
+func askMaterializeStrategy(win fyne.Window, message string, onChosen func(strategy directory.MaterializeStrategy)) {
+	var d dialog.Dialog
+	choose := func(strategy directory.MaterializeStrategy) func() {
+		return func() {
+			d.Hide()
+			onChosen(strategy)
+		}
+	}
+
+	d = dialog.NewCustomWithoutButtons("Existing files",
+		container.NewVBox(
+			widget.NewLabel(message),
+			container.NewHBox(
+				layout.NewSpacer(),
+				widget.NewButton("Cancel", func() { d.Hide() }),
+				widget.NewButton("Skip existing", choose(directory.MaterializeSkip)),
+				widget.NewButtonWithIcon("Replace existing", theme.WarningIcon(), choose(directory.MaterializeReplace)),
+			),
+		),
+		win)
+	d.Show()
+}
+
+// showOperationProgress displays the progress of a long-running operation until the progress value becomes empty.
+func showOperationProgress(win fyne.Window, title string, progress binding.String, onCancel func()) {
+	d := dialog.NewCustomConfirm(title, "Cancel", "Hide",
+		container.NewVBox(
+			widget.NewLabelWithData(progress),
+			widget.NewProgressBarInfinite(),
+		),
+		func(cancel bool) {
+			if cancel {
+				onCancel()
+			}
+		},
+		win)
+
+	var listener binding.DataListener
+	listener = binding.NewDataListener(func() {
+		if msg, _ := progress.Get(); msg == "" {
+			progress.RemoveListener(listener)
+			d.Hide()
+		}
+	})
+	progress.AddListener(listener)
+
+	d.Show()
+}
+
 func entryWithShortcuts(onSubmit, onDismiss func()) *EntryWithShortcuts {
 	return NewEntryWithShortcuts([]ActionShortcuts{
 		{
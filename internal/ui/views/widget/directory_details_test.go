@@ -2,9 +2,11 @@ package widget_test
 
 import (
 	"testing"
+	"time"
 
 	"fyne.io/fyne/v2/data/binding"
 	fyne_test "fyne.io/fyne/v2/test"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
 	"github.com/thomas-marquis/s3-box/internal/tu"
 	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
 	mocks_appcontext "github.com/thomas-marquis/s3-box/mocks/context"
@@ -70,4 +72,37 @@ func TestDirectoryDetails(t *testing.T) {
 		// Then
 		fyne_test.AssertRendersToMarkup(t, "directory_details_readonly", c)
 	})
+
+	t.Run("should display the cached size statistics", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockAppCtx := mocks_appcontext.NewMockAppContext(ctrl)
+		mockExplorerVM := mocks_viewmodel.NewMockExplorerViewModel(ctrl)
+		mockConnVM := mocks_viewmodel.NewMockConnectionViewModel(ctrl)
+
+		mockAppCtx.EXPECT().ExplorerViewModel().Return(mockExplorerVM).AnyTimes()
+		mockAppCtx.EXPECT().ConnectionViewModel().Return(mockConnVM).AnyTimes()
+		mockAppCtx.EXPECT().Window().Return(fyne_test.NewWindow(nil)).AnyTimes()
+
+		fakeIsLoadingBinding := binding.NewBool()
+		mockExplorerVM.EXPECT().IsSelectedDirectoryLoading().Return(fakeIsLoadingBinding).AnyTimes()
+		mockExplorerVM.EXPECT().OnUploadReady(gomock.Any()).AnyTimes()
+
+		dir := tu.FakeNotLoadedRootDirectory(t)
+		stats := directory.NewSizeStats()
+		stats.Add(dir.Path(), "readme.md", 1200, "")
+		stats.Add(dir.Path(), "data/2024.csv", 3_000_000, "GLACIER")
+		stats.ComputedAt = time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)
+		dir.SetSizeStats(stats)
+
+		mockConnVM.EXPECT().IsReadOnly().Return(true)
+
+		// When
+		res := widget.NewDirectoryDetails(mockAppCtx)
+		res.Select(dir)
+		c := fyne_test.NewWindow(res).Canvas()
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "directory_details_size_stats", c)
+	})
 }
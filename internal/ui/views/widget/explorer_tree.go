@@ -2,11 +2,13 @@ package widget
 
 import (
 	"fmt"
+	"path"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/data/binding"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/thomas-marquis/s3-box/internal/domain/directory"
@@ -17,22 +19,29 @@ import (
 type ExplorerTree struct {
 	widget.BaseWidget
 
-	appCtx      appcontext.AppContext
-	onFileClick func(file *directory.File)
-	onDirClick  func(directory *directory.Directory)
+	appCtx            appcontext.AppContext
+	onFileClick       func(file *directory.File)
+	onDirClick        func(directory *directory.Directory)
+	onSelectionChange func(selection *directory.Selection)
 
-	tree *widget.Tree
+	tree        *widget.Tree
+	lastClicked widget.TreeNodeID
 }
 
+// NewExplorerTree creates the tree of the bucket content.
+// A simple click on a node calls onDirClick or onFileClick, while a click with the shortcut modifier (ctrl or cmd)
+// or the shift modifier adds nodes to the selection and calls onSelectionChange.
 func NewExplorerTree(
 	appCtx appcontext.AppContext,
 	onDirClick func(directory *directory.Directory),
 	onFileClick func(file *directory.File),
+	onSelectionChange func(selection *directory.Selection),
 ) *ExplorerTree {
 	w := &ExplorerTree{
-		appCtx:      appCtx,
-		onDirClick:  onDirClick,
-		onFileClick: onFileClick,
+		appCtx:            appCtx,
+		onDirClick:        onDirClick,
+		onFileClick:       onFileClick,
+		onSelectionChange: onSelectionChange,
 	}
 
 	w.ExtendBaseWidget(w)
@@ -60,7 +69,10 @@ func (w *ExplorerTree) CreateRenderer() fyne.WidgetRenderer {
 			status := widget.NewLabel("")
 			status.Hide()
 
-			return container.NewHBox(icon, displayLabel, loading, status)
+			selected := widget.NewIcon(theme.ConfirmIcon())
+			selected.Hide()
+
+			return container.NewHBox(icon, displayLabel, loading, status, selected)
 		},
 		func(i binding.DataItem, branch bool, o fyne.CanvasObject) {
 			nodeItem, err := i.(binding.Item[node.Node]).Get()
@@ -73,6 +85,7 @@ func (w *ExplorerTree) CreateRenderer() fyne.WidgetRenderer {
 			displayLabel := c.Objects[1].(*widget.Label)
 			loading := c.Objects[2].(*widget.Icon)
 			status := c.Objects[3].(*widget.Label)
+			selected := c.Objects[4].(*widget.Icon)
 
 			displayLabel.SetText(nodeItem.DisplayName())
 
@@ -96,6 +109,12 @@ func (w *ExplorerTree) CreateRenderer() fyne.WidgetRenderer {
 			} else {
 				loading.Hide()
 			}
+
+			if w.appCtx.State().Explorer().IsNodeSelected(nodeItem.ID()) {
+				selected.Show()
+			} else {
+				selected.Hide()
+			}
 		},
 	)
 
@@ -105,6 +124,10 @@ func (w *ExplorerTree) CreateRenderer() fyne.WidgetRenderer {
 	tree.OnBranchClosed = w.makeOnBranchCallback(false, treeData)
 
 	tree.OnSelected = func(uid widget.TreeNodeID) {
+		if w.handleMultiSelection(tree, uid) {
+			return
+		}
+
 		nodeItem, err := treeData.GetValue(uid)
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("error getting value: %v", err), w.appCtx.Window())
@@ -125,6 +148,14 @@ func (w *ExplorerTree) CreateRenderer() fyne.WidgetRenderer {
 
 		case node.FileNode:
 			w.onFileClick(n.File())
+
+		case node.LoadMoreNode:
+			tree.Unselect(uid)
+			if !n.Directory().IsLoading() {
+				if err := vm.LoadNextPage(n.Directory()); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+				}
+			}
 		}
 	}
 
@@ -133,6 +164,92 @@ func (w *ExplorerTree) CreateRenderer() fyne.WidgetRenderer {
 	return widget.NewSimpleRenderer(tree)
 }
 
+// handleMultiSelection updates the selection when the node is clicked with a selection modifier.
+// It returns false for a simple click, after having cleared the selection.
+func (w *ExplorerTree) handleMultiSelection(tree *widget.Tree, uid widget.TreeNodeID) bool {
+	vm := w.appCtx.ExplorerViewModel()
+	modifiers := currentKeyModifiers()
+	isRange := modifiers&fyne.KeyModifierShift != 0
+	isToggle := modifiers&fyne.KeyModifierShortcutDefault != 0
+
+	if !isRange && !isToggle {
+		if !vm.Selection().IsEmpty() {
+			vm.ClearSelection()
+		}
+		w.lastClicked = uid
+		return false
+	}
+
+	if vm.Selection().IsEmpty() && w.lastClicked != "" && w.lastClicked != uid {
+		if err := vm.ToggleSelection(w.lastClicked); err != nil {
+			dialog.ShowError(err, w.appCtx.Window())
+		}
+	}
+
+	var err error
+	if isRange {
+		err = vm.SelectRange(uid)
+	} else {
+		err = vm.ToggleSelection(uid)
+	}
+	if err != nil {
+		dialog.ShowError(err, w.appCtx.Window())
+	}
+
+	w.lastClicked = uid
+	tree.Unselect(uid)
+	w.onSelectionChange(vm.Selection())
+	return true
+}
+
+// CopyToClipboard puts the selection, or the last clicked node when nothing is selected, in the explorer clipboard.
+func (w *ExplorerTree) CopyToClipboard() {
+	w.toClipboard(w.appCtx.ExplorerViewModel().CopyToClipboard)
+}
+
+// CutToClipboard does the same as CopyToClipboard, but the clipboard content will be moved when pasted.
+func (w *ExplorerTree) CutToClipboard() {
+	w.toClipboard(w.appCtx.ExplorerViewModel().CutToClipboard)
+}
+
+func (w *ExplorerTree) toClipboard(fn func(nodeID string) error) {
+	if w.lastClicked == "" && w.appCtx.ExplorerViewModel().Selection().IsEmpty() {
+		return
+	}
+	if err := fn(w.lastClicked); err != nil {
+		dialog.ShowError(err, w.appCtx.Window())
+	}
+}
+
+// Reveal opens the branches down to the file node, then selects it.
+// Its ancestors must already be loaded in the tree.
+func (w *ExplorerTree) Reveal(fileNodeID string) {
+	if w.tree == nil {
+		return
+	}
+
+	dirPath := directory.NewPath(path.Dir(fileNodeID))
+	branch := directory.RootPath
+	w.tree.OpenBranch(branch.String())
+	for _, name := range dirPath.Split() {
+		if name == "" {
+			continue
+		}
+		branch = branch.NewSubPath(name)
+		w.tree.OpenBranch(branch.String())
+	}
+
+	w.tree.ScrollTo(fileNodeID)
+	w.tree.Select(fileNodeID)
+}
+
+func currentKeyModifiers() fyne.KeyModifier {
+	if drv, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		return drv.CurrentKeyModifiers()
+	}
+	return 0
+}
+
 func (w *ExplorerTree) Refresh() {
 	if w.tree != nil {
 		w.tree.Refresh()
@@ -42,7 +42,10 @@ func TestExplorerTree(t *testing.T) {
 
 	t.Run("should display explorer tree", func(t *testing.T) {
 		// When
-		res := widget.NewExplorerTree(mockAppCtx, func(directory *directory.Directory) {}, func(file *directory.File) {})
+		res := widget.NewExplorerTree(mockAppCtx,
+			func(directory *directory.Directory) {},
+			func(file *directory.File) {},
+			func(selection *directory.Selection) {})
 		c := fyne_test.NewWindow(res).Canvas()
 
 		// Then
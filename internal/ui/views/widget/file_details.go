@@ -3,6 +3,10 @@ package widget
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -36,6 +40,10 @@ type FileDetails struct {
 	deleteAction   *ToolbarButton
 	editAction     *ToolbarButton
 	renameAction   *ToolbarButton
+	metadataAction *ToolbarButton
+	tagsAction     *ToolbarButton
+	shareAction    *ToolbarButton
+	versionsAction *ToolbarButton
 
 	actionToolbar *widget.Toolbar
 
@@ -43,7 +51,15 @@ type FileDetails struct {
 	lastModifiedBinding binding.String
 	maxFileSizeListener binding.DataListener
 
+	contentTypeBinding  binding.String
+	cacheControlBinding binding.String
+	etagBinding         binding.String
+	storageClassBinding binding.String
+	userMetadataBinding binding.String
+	tagsBinding         binding.String
+
 	currentSelectedFile *directory.File
+	versionsView        *FileVersions
 }
 
 func NewFileDetails(appCtx appcontext.AppContext) *FileDetails {
@@ -60,18 +76,33 @@ func NewFileDetails(appCtx appcontext.AppContext) *FileDetails {
 		lastModifiedBinding: binding.NewString(),
 		maxFileSizeListener: binding.NewDataListener(func() {}),
 
+		contentTypeBinding:  binding.NewString(),
+		cacheControlBinding: binding.NewString(),
+		etagBinding:         binding.NewString(),
+		storageClassBinding: binding.NewString(),
+		userMetadataBinding: binding.NewString(),
+		tagsBinding:         binding.NewString(),
+
 		downloadAction: NewToolbarButton("Download", theme.DownloadIcon(), func() {}),
 		deleteAction:   NewToolbarButton("Delete", theme.DeleteIcon(), func() {}),
 		editAction:     NewToolbarButton("Edit", theme.DocumentCreateIcon(), func() {}),
 		renameAction:   NewToolbarButton("Rename", theme.FileTextIcon(), func() {}),
+		metadataAction: NewToolbarButton("Metadata", theme.SettingsIcon(), func() {}),
+		tagsAction:     NewToolbarButton("Tags", theme.ListIcon(), func() {}),
+		shareAction:    NewToolbarButton("Share link", theme.MailForwardIcon(), func() {}),
+		versionsAction: NewToolbarButton("Versions", theme.HistoryIcon(), func() {}),
 
 		currentSelectedFile: nil,
 	}
 
 	w.actionToolbar = widget.NewToolbar(
 		w.downloadAction,
+		w.shareAction,
 		w.editAction,
 		w.renameAction,
+		w.metadataAction,
+		w.tagsAction,
+		w.versionsAction,
 		w.deleteAction,
 	)
 
@@ -98,6 +129,18 @@ func (w *FileDetails) CreateRenderer() fyne.WidgetRenderer {
 		fileSize,
 		widget.NewLabelWithStyle("Last modified", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
 		lastModified,
+		widget.NewLabelWithStyle("Content type", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		newSelectableLabelWithData(w.contentTypeBinding),
+		widget.NewLabelWithStyle("Cache control", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		newSelectableLabelWithData(w.cacheControlBinding),
+		widget.NewLabelWithStyle("ETag", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		newSelectableLabelWithData(w.etagBinding),
+		widget.NewLabelWithStyle("Storage class", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		newSelectableLabelWithData(w.storageClassBinding),
+		widget.NewLabelWithStyle("User metadata", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		newSelectableLabelWithData(w.userMetadataBinding),
+		widget.NewLabelWithStyle("Tags", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		newSelectableLabelWithData(w.tagsBinding),
 	)
 
 	return widget.NewSimpleRenderer(
@@ -146,12 +189,24 @@ func (w *FileDetails) Select(file *directory.File) {
 	u.Skip(w.lastModifiedBinding.Set(file.LastModified().Format("2006-01-02 15:04:05")))
 	u.Skip(w.fileSizeBinding.Set(humanize.Bytes(file.SizeBytes())))
 
+	w.RefreshMetadata()
+	if _, loaded := file.Metadata(); !loaded && !file.IsDeleted() {
+		exVm.LoadFileMetadata(file)
+	}
+	w.metadataAction.SetOnTapped(w.makeOnEditMetadata(exVm, file))
+	if _, loaded := file.Tags(); !loaded && !file.IsDeleted() {
+		exVm.LoadFileTags(file)
+	}
+	w.tagsAction.SetOnTapped(w.makeOnEditTags(exVm, file))
+	w.shareAction.SetOnTapped(w.makeOnShareLink(exVm, file))
+	w.versionsAction.SetOnTapped(w.makeOnShowVersions(exVm, edVm, file))
+
 	w.appCtx.State().Settings().EditorFileSizeLimitBytes().RemoveListener(w.maxFileSizeListener)
 	dl := binding.NewDataListener(func() {
 		if file.SizeBytes() > w.appCtx.State().Settings().EditorFileSizeLimitBytesValue() {
 			w.editAction.Disable()
 		} else {
-			if w.appCtx.ConnectionViewModel().IsReadOnly() {
+			if w.appCtx.ConnectionViewModel().IsReadOnly() || file.IsDeleted() {
 				w.editAction.Disable()
 			} else {
 				w.editAction.Enable()
@@ -162,38 +217,11 @@ func (w *FileDetails) Select(file *directory.File) {
 	w.maxFileSizeListener = dl
 
 	w.editAction.SetOnTapped(func() {
-		ed, err := edVm.Open(file)
-		if err != nil && !errors.Is(err, viewmodel.ErrEditorAlreadyOpened) {
-			dialog.ShowError(err, w.appCtx.Window())
-		}
-
-		ed.Window().SetContent(ed.CreateWidget())
-		ed.Window().SetFixedSize(false)
-		ed.Window().Resize(fyne.NewSize(700, 500))
-		ed.Window().Show()
-
-		ed.Window().RequestFocus()
+		w.openInEditor(edVm, file)
 	})
 
 	w.downloadAction.SetOnTapped(func() {
-		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
-			if err != nil {
-				dialog.ShowError(fmt.Errorf("error saving file: %w", err), w.appCtx.Window())
-				return
-			}
-			if writer == nil {
-				return
-			}
-			localDestFilePath := writer.URI().Path()
-			exVm.DownloadFile(file, localDestFilePath)
-			if err := exVm.UpdateLastDownloadLocation(localDestFilePath); err != nil { //nolint:staticcheck
-				// TODO: handle error
-			}
-			fyne.CurrentApp().SendNotification(fyne.NewNotification("File download", "success"))
-		}, w.appCtx.Window())
-		saveDialog.SetFileName(file.Name().String())
-		saveDialog.SetLocation(exVm.LastDownloadLocation())
-		saveDialog.Show()
+		w.showDownloadDialog(exVm, file)
 	})
 
 	w.renameAction.SetOnTapped(func() {
@@ -246,5 +274,318 @@ func (w *FileDetails) Select(file *directory.File) {
 		w.deleteAction.Disable()
 		w.editAction.Disable()
 		w.renameAction.Disable()
+		w.metadataAction.Disable()
+		w.tagsAction.Disable()
+	} else {
+		w.metadataAction.Enable()
+		w.tagsAction.Enable()
+	}
+
+	// a deleted file can only be restored from its versions
+	if file.IsDeleted() {
+		w.downloadAction.Disable()
+		w.shareAction.Disable()
+		w.editAction.Disable()
+		w.renameAction.Disable()
+		w.metadataAction.Disable()
+		w.tagsAction.Disable()
+		w.deleteAction.Disable()
+	} else {
+		w.downloadAction.Enable()
+		w.shareAction.Enable()
+	}
+}
+
+func (w *FileDetails) openInEditor(edVm viewmodel.EditorViewModel, file *directory.File) {
+	ed, err := edVm.Open(file)
+	if err != nil && !errors.Is(err, viewmodel.ErrEditorAlreadyOpened) {
+		dialog.ShowError(err, w.appCtx.Window())
+	}
+
+	ed.Window().SetContent(ed.CreateWidget())
+	ed.Window().SetFixedSize(false)
+	ed.Window().Resize(fyne.NewSize(700, 500))
+	ed.Window().Show()
+
+	ed.Window().RequestFocus()
+}
+
+func (w *FileDetails) showDownloadDialog(exVm viewmodel.ExplorerViewModel, file *directory.File) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("error saving file: %w", err), w.appCtx.Window())
+			return
+		}
+		if writer == nil {
+			return
+		}
+		localDestFilePath := writer.URI().Path()
+		exVm.DownloadFile(file, localDestFilePath)
+		if err := exVm.UpdateLastDownloadLocation(localDestFilePath); err != nil { //nolint:staticcheck
+			// TODO: handle error
+		}
+		fyne.CurrentApp().SendNotification(fyne.NewNotification("File download", "success"))
+	}, w.appCtx.Window())
+	saveDialog.SetFileName(file.Name().String())
+	saveDialog.SetLocation(exVm.LastDownloadLocation())
+	saveDialog.Show()
+}
+
+// RefreshMetadata displays the metadata and the tags of the selected file, once loaded.
+func (w *FileDetails) RefreshMetadata() {
+	if w.currentSelectedFile == nil {
+		return
+	}
+	md, loaded := w.currentSelectedFile.Metadata()
+	if loaded {
+		u.Skip(w.lastModifiedBinding.Set(w.currentSelectedFile.LastModified().Format("2006-01-02 15:04:05")))
+	}
+	u.Skip(w.contentTypeBinding.Set(valueOrDash(md.ContentType)))
+	u.Skip(w.cacheControlBinding.Set(valueOrDash(md.CacheControl)))
+	u.Skip(w.etagBinding.Set(valueOrDash(md.ETag)))
+	u.Skip(w.storageClassBinding.Set(valueOrDash(md.StorageClass)))
+	u.Skip(w.userMetadataBinding.Set(valueOrDash(formatUserMetadata(md.UserMetadata))))
+
+	tags, _ := w.currentSelectedFile.Tags()
+	u.Skip(w.tagsBinding.Set(valueOrDash(formatUserMetadata(tags))))
+
+	if w.versionsView != nil {
+		w.versionsView.SetVersions(w.currentSelectedFile.Versions())
+	}
+}
+
+func (w *FileDetails) makeOnEditMetadata(vm viewmodel.ExplorerViewModel, file *directory.File) func() {
+	return func() {
+		md, loaded := file.Metadata()
+		if !loaded {
+			dialog.ShowInformation("Metadata", "The metadata of the file are still loading, please retry in a moment.", w.appCtx.Window())
+			return
+		}
+
+		contentTypeEntry := widget.NewEntry()
+		contentTypeEntry.SetText(md.ContentType)
+		cacheControlEntry := widget.NewEntry()
+		cacheControlEntry.SetText(md.CacheControl)
+		cacheControlEntry.SetPlaceHolder("max-age=3600")
+		userMetadataEntry := widget.NewMultiLineEntry()
+		userMetadataEntry.SetText(formatUserMetadata(md.UserMetadata))
+		userMetadataEntry.SetPlaceHolder("key=value")
+		userMetadataEntry.SetMinRowsVisible(4)
+
+		userMetadataItem := widget.NewFormItem("User metadata", userMetadataEntry)
+		userMetadataItem.HintText = "One key=value per line"
+
+		d := dialog.NewForm(
+			"Edit metadata",
+			"Save",
+			"Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Content type", contentTypeEntry),
+				widget.NewFormItem("Cache control", cacheControlEntry),
+				userMetadataItem,
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				userMetadata, err := parseUserMetadata(userMetadataEntry.Text)
+				if err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				newMd := md.WithEditableFields(contentTypeEntry.Text, cacheControlEntry.Text, userMetadata)
+				if err := vm.UpdateFileMetadata(file, newMd); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+				}
+			},
+			w.appCtx.Window(),
+		)
+		d.Resize(fyne.NewSize(500, 350))
+		d.Show()
+	}
+}
+
+func (w *FileDetails) makeOnEditTags(vm viewmodel.ExplorerViewModel, file *directory.File) func() {
+	return func() {
+		tags, loaded := file.Tags()
+		if !loaded {
+			dialog.ShowInformation("Tags", "The tags of the file are still loading, please retry in a moment.", w.appCtx.Window())
+			return
+		}
+
+		editor := NewTagsEditor(tags)
+		d := dialog.NewCustomConfirm(
+			"Edit tags",
+			"Save",
+			"Cancel",
+			container.NewVScroll(editor),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := vm.UpdateFileTags(file, editor.Tags()); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+				}
+			},
+			w.appCtx.Window(),
+		)
+		d.Resize(fyne.NewSize(500, 400))
+		d.Show()
+	}
+}
+
+func (w *FileDetails) makeOnShowVersions(exVm viewmodel.ExplorerViewModel, edVm viewmodel.EditorViewModel, file *directory.File) func() {
+	return func() {
+		win := w.appCtx.Window()
+		view := NewFileVersions(w.appCtx.ConnectionViewModel().IsReadOnly())
+
+		atVersion := func(version directory.FileVersion, do func(*directory.File)) {
+			versioned, err := file.AtVersion(version)
+			if err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+			do(versioned)
+		}
+		view.OnDownload = func(version directory.FileVersion) {
+			atVersion(version, func(f *directory.File) { w.showDownloadDialog(exVm, f) })
+		}
+		view.OnOpen = func(version directory.FileVersion) {
+			atVersion(version, func(f *directory.File) { w.openInEditor(edVm, f) })
+		}
+		view.OnRestore = func(version directory.FileVersion) {
+			dialog.ShowConfirm("Restore version",
+				fmt.Sprintf("Restore version %s of '%s'? It will become the current content.", version.ID, file.Name()),
+				func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := exVm.RestoreFileVersion(file, version); err != nil {
+						dialog.ShowError(err, win)
+					}
+				}, win)
+		}
+		view.OnDelete = func(version directory.FileVersion) {
+			dialog.ShowConfirm("Delete version",
+				fmt.Sprintf("Permanently delete version %s of '%s'? This can't be undone.", version.ID, file.Name()),
+				func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := exVm.DeleteFileVersion(file, version); err != nil {
+						dialog.ShowError(err, win)
+					}
+				}, win)
+		}
+
+		w.versionsView = view
+		exVm.LoadFileVersions(file)
+
+		d := dialog.NewCustom(fmt.Sprintf("Versions of %s", file.Name()), "Close", view, win)
+		d.SetOnClosed(func() {
+			if w.versionsView == view {
+				w.versionsView = nil
+			}
+		})
+		d.Resize(fyne.NewSize(750, 400))
+		d.Show()
+	}
+}
+
+var shareLinkExpiries = []struct {
+	label  string
+	expiry time.Duration
+}{
+	{"15 minutes", 15 * time.Minute},
+	{"1 hour", time.Hour},
+	{"1 day", 24 * time.Hour},
+	{"7 days", directory.ShareLinkMaxExpiry},
+}
+
+func (w *FileDetails) makeOnShareLink(vm viewmodel.ExplorerViewModel, file *directory.File) func() {
+	return func() {
+		expiryLabels := make([]string, len(shareLinkExpiries))
+		for i, e := range shareLinkExpiries {
+			expiryLabels[i] = e.label
+		}
+		expirySelect := widget.NewSelect(expiryLabels, nil)
+		expirySelect.SetSelectedIndex(1)
+
+		accessOptions := []string{directory.ShareLinkDownload.String()}
+		if !w.appCtx.ConnectionViewModel().IsReadOnly() {
+			accessOptions = append(accessOptions, directory.ShareLinkUpload.String())
+		}
+		accessRadio := widget.NewRadioGroup(accessOptions, nil)
+		accessRadio.Horizontal = true
+		accessRadio.Required = true
+		accessRadio.SetSelected(directory.ShareLinkDownload.String())
+
+		accessItem := widget.NewFormItem("Access", accessRadio)
+		accessItem.HintText = "Anyone with the link can use it until it expires"
+
+		d := dialog.NewForm(
+			"Share link",
+			"Copy link",
+			"Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Expires in", expirySelect),
+				accessItem,
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				access := directory.ShareLinkDownload
+				if accessRadio.Selected == directory.ShareLinkUpload.String() {
+					access = directory.ShareLinkUpload
+				}
+				expiry := shareLinkExpiries[expirySelect.SelectedIndex()].expiry
+				if err := vm.CreateFileShareLink(file, access, expiry); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+				}
+			},
+			w.appCtx.Window(),
+		)
+		d.Resize(fyne.NewSize(400, 200))
+		d.Show()
+	}
+}
+
+func newSelectableLabelWithData(data binding.String) *widget.Label {
+	l := widget.NewLabelWithData(data)
+	l.Selectable = true
+	return l
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatUserMetadata returns the user metadata as sorted key=value lines.
+func formatUserMetadata(metadata map[string]string) string {
+	lines := make([]string, 0, len(metadata))
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		lines = append(lines, key+"="+metadata[key])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseUserMetadata reads key=value lines, ignoring the blank ones.
+func parseUserMetadata(text string) (map[string]string, error) {
+	res := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid user metadata line '%s': expected key=value", line)
+		}
+		res[strings.TrimSpace(key)] = strings.TrimSpace(value)
 	}
+	return res, nil
 }
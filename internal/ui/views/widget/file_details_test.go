@@ -53,6 +53,8 @@ func setupFileDetailsMocksWithLimit(t *testing.T, limitBytes uint64) fileDetails
 	m.mockAppCtx.EXPECT().EditorViewModel().Return(m.mockEditorVM).AnyTimes()
 	m.mockAppCtx.EXPECT().Window().Return(fyne_test.NewWindow(nil)).AnyTimes()
 	m.mockAppCtx.EXPECT().State().Return(m.mockState).AnyTimes()
+	m.mockExplorerVM.EXPECT().LoadFileMetadata(gomock.Any()).AnyTimes()
+	m.mockExplorerVM.EXPECT().LoadFileTags(gomock.Any()).AnyTimes()
 
 	// Register the settings that file_details needs
 	u.Skip(m.mockState.Settings().Get().Register(
@@ -86,6 +88,32 @@ func TestFileDetails(t *testing.T) {
 		fyne_test.AssertRendersToMarkup(t, "file_details", c)
 	})
 
+	t.Run("should display the loaded metadata", func(t *testing.T) {
+		// Given
+		m := setupFileDetailsMocks(t)
+		m.mockConnVM.EXPECT().IsReadOnly().Return(false).AnyTimes()
+
+		fileWithMetadata, _ := directory.NewFile("data.json", rootDir,
+			directory.WithFileSize(fakeFileSizeLimitKB),
+			directory.WithFileLastModified(lastModified),
+		)
+		fileWithMetadata.SetMetadata(directory.ObjectMetadata{
+			ContentType:  "application/json",
+			CacheControl: "no-cache",
+			ETag:         "d41d8cd98f00b204e9800998ecf8427e",
+			StorageClass: "STANDARD",
+			UserMetadata: map[string]string{"owner": "team-data", "env": "prod"},
+		})
+
+		// When
+		res := widget.NewFileDetails(m.mockAppCtx)
+		res.Select(fileWithMetadata)
+		c := fyne_test.NewWindow(res).Canvas()
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "file_details_metadata", c)
+	})
+
 	t.Run("should disable preview if file is too large", func(t *testing.T) {
 		// Given
 		m := setupFileDetailsMocksWithLimit(t, 512)
@@ -113,4 +141,43 @@ func TestFileDetails(t *testing.T) {
 		// Then
 		fyne_test.AssertRendersToMarkup(t, "file_details_readonly", c)
 	})
+
+	t.Run("should display tags but disable their edition if read-only", func(t *testing.T) {
+		// Given
+		m := setupFileDetailsMocks(t)
+		m.mockConnVM.EXPECT().IsReadOnly().Return(true).AnyTimes()
+
+		taggedFile, _ := directory.NewFile("report.csv", rootDir,
+			directory.WithFileSize(fakeFileSizeLimitKB),
+			directory.WithFileLastModified(lastModified),
+		)
+		taggedFile.SetTags(directory.ObjectTags{"project": "alpha", "confidential": "true"})
+
+		// When
+		res := widget.NewFileDetails(m.mockAppCtx)
+		res.Select(taggedFile)
+		c := fyne_test.NewWindow(res).Canvas()
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "file_details_tags_readonly", c)
+	})
+
+	t.Run("should only allow to browse the versions of a deleted file", func(t *testing.T) {
+		// Given
+		m := setupFileDetailsMocks(t)
+		m.mockConnVM.EXPECT().IsReadOnly().Return(false).AnyTimes()
+
+		deletedFile, _ := directory.NewFile("old.txt", rootDir,
+			directory.WithFileLastModified(lastModified),
+			directory.WithFileDeleted(true),
+		)
+
+		// When
+		res := widget.NewFileDetails(m.mockAppCtx)
+		res.Select(deletedFile)
+		c := fyne_test.NewWindow(res).Canvas()
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "file_details_deleted", c)
+	})
 }
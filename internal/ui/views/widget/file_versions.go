@@ -0,0 +1,131 @@
+package widget
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+// FileVersions lists the versions of a file, from the most recent one,
+// with actions to download, open, restore or delete each of them.
+type FileVersions struct {
+	widget.BaseWidget
+
+	versions []directory.FileVersion
+	readOnly bool
+
+	list        *widget.List
+	placeholder *widget.Label
+
+	OnDownload func(version directory.FileVersion)
+	OnOpen     func(version directory.FileVersion)
+	OnRestore  func(version directory.FileVersion)
+	OnDelete   func(version directory.FileVersion)
+}
+
+func NewFileVersions(readOnly bool) *FileVersions {
+	w := &FileVersions{
+		readOnly:    readOnly,
+		placeholder: widget.NewLabel("Loading versions..."),
+	}
+
+	w.list = widget.NewList(
+		func() int {
+			return len(w.versions)
+		},
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButtonWithIcon("", theme.DownloadIcon(), nil),
+					widget.NewButtonWithIcon("", theme.DocumentIcon(), nil),
+					widget.NewButtonWithIcon("", theme.HistoryIcon(), nil),
+					widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				),
+				widget.NewLabel(""),
+			)
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			version := w.versions[id]
+			c := o.(*fyne.Container)
+			label := c.Objects[0].(*widget.Label)
+			buttons := c.Objects[1].(*fyne.Container)
+			downloadBtn := buttons.Objects[0].(*widget.Button)
+			openBtn := buttons.Objects[1].(*widget.Button)
+			restoreBtn := buttons.Objects[2].(*widget.Button)
+			deleteBtn := buttons.Objects[3].(*widget.Button)
+
+			label.SetText(formatFileVersion(version))
+
+			downloadBtn.OnTapped = func() { callVersionHandler(w.OnDownload, version) }
+			openBtn.OnTapped = func() { callVersionHandler(w.OnOpen, version) }
+			restoreBtn.OnTapped = func() { callVersionHandler(w.OnRestore, version) }
+			deleteBtn.OnTapped = func() { callVersionHandler(w.OnDelete, version) }
+
+			setEnabled(downloadBtn, !version.IsDeleteMarker)
+			setEnabled(openBtn, !version.IsDeleteMarker)
+			setEnabled(restoreBtn, !w.readOnly && !version.IsDeleteMarker && !version.IsLatest)
+			setEnabled(deleteBtn, !w.readOnly)
+		},
+	)
+	w.list.Hide()
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *FileVersions) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+	return widget.NewSimpleRenderer(container.NewStack(w.placeholder, w.list))
+}
+
+// SetVersions displays the versions of the file, once loaded.
+func (w *FileVersions) SetVersions(versions []directory.FileVersion, loaded bool) {
+	w.versions = versions
+	switch {
+	case !loaded:
+		w.placeholder.SetText("Loading versions...")
+		w.placeholder.Show()
+		w.list.Hide()
+	case len(versions) == 0:
+		w.placeholder.SetText("No version found, versioning may be disabled on this bucket")
+		w.placeholder.Show()
+		w.list.Hide()
+	default:
+		w.placeholder.Hide()
+		w.list.Show()
+	}
+	w.list.Refresh()
+}
+
+func formatFileVersion(version directory.FileVersion) string {
+	date := version.LastModified.Format("2006-01-02 15:04:05")
+	var desc string
+	if version.IsDeleteMarker {
+		desc = fmt.Sprintf("%s  delete marker  %s", date, version.ID)
+	} else {
+		desc = fmt.Sprintf("%s  %s  %s", date, humanize.Bytes(version.SizeBytes), version.ID)
+	}
+	if version.IsLatest {
+		desc += "  (current)"
+	}
+	return desc
+}
+
+func callVersionHandler(handler func(directory.FileVersion), version directory.FileVersion) {
+	if handler != nil {
+		handler(version)
+	}
+}
+
+func setEnabled(w fyne.Disableable, enabled bool) {
+	if enabled {
+		w.Enable()
+	} else {
+		w.Disable()
+	}
+}
@@ -0,0 +1,48 @@
+package widget_test
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	fyne_test "fyne.io/fyne/v2/test"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
+)
+
+func TestFileVersions(t *testing.T) {
+	fyne_test.NewApp()
+
+	lastModified := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	versions := []directory.FileVersion{
+		{ID: "v3", LastModified: lastModified, IsLatest: true, IsDeleteMarker: true},
+		{ID: "v2", SizeBytes: 2048, LastModified: lastModified.Add(-time.Hour)},
+		{ID: "v1", SizeBytes: 1024, LastModified: lastModified.Add(-2 * time.Hour)},
+	}
+
+	t.Run("should list the versions with their actions", func(t *testing.T) {
+		// Given
+		res := widget.NewFileVersions(false)
+
+		// When
+		res.SetVersions(versions, true)
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(700, 300))
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "file_versions", w.Canvas())
+	})
+
+	t.Run("should disable restore and delete in read-only mode", func(t *testing.T) {
+		// Given
+		res := widget.NewFileVersions(true)
+
+		// When
+		res.SetVersions(versions, true)
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(700, 300))
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "file_versions_readonly", w.Canvas())
+	})
+}
@@ -0,0 +1,121 @@
+package widget
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+)
+
+// IncompleteUploads lists the multipart uploads left in a bucket,
+// with an action to abort each of them or all the ones no local transfer can resume.
+type IncompleteUploads struct {
+	widget.BaseWidget
+
+	uploads  binding.List[transfer.IncompleteUpload]
+	readOnly bool
+
+	list        *widget.List
+	placeholder *widget.Label
+	abortAllBtn *widget.Button
+
+	// OnAbort is called with the uploads to abort
+	OnAbort func(uploads []transfer.IncompleteUpload)
+}
+
+func NewIncompleteUploads(uploads binding.List[transfer.IncompleteUpload], isLoading binding.Bool, readOnly bool) *IncompleteUploads {
+	w := &IncompleteUploads{
+		uploads:     uploads,
+		readOnly:    readOnly,
+		placeholder: widget.NewLabel(""),
+	}
+
+	w.list = widget.NewListWithData(uploads,
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				widget.NewButtonWithIcon("Abort", theme.DeleteIcon(), nil),
+				widget.NewLabel(""),
+			)
+		},
+		func(i binding.DataItem, o fyne.CanvasObject) {
+			upload, err := i.(binding.Item[transfer.IncompleteUpload]).Get()
+			if err != nil {
+				return
+			}
+			c := o.(*fyne.Container)
+			c.Objects[0].(*widget.Label).SetText(formatIncompleteUpload(upload))
+			abortBtn := c.Objects[1].(*widget.Button)
+			abortBtn.OnTapped = func() { w.abort([]transfer.IncompleteUpload{upload}) }
+			setEnabled(abortBtn, !w.readOnly)
+		})
+
+	w.abortAllBtn = widget.NewButtonWithIcon("Abort all orphaned uploads", theme.DeleteIcon(), func() {
+		w.abort(w.orphans())
+	})
+
+	refresh := func() {
+		loading, _ := isLoading.Get()
+		switch {
+		case loading:
+			w.placeholder.SetText("Loading incomplete uploads...")
+			w.placeholder.Show()
+			w.list.Hide()
+		case uploads.Length() == 0:
+			w.placeholder.SetText("No incomplete upload in this bucket")
+			w.placeholder.Show()
+			w.list.Hide()
+		default:
+			w.placeholder.Hide()
+			w.list.Show()
+		}
+		setEnabled(w.abortAllBtn, !w.readOnly && !loading && len(w.orphans()) > 0)
+	}
+	refresh()
+	uploads.AddListener(binding.NewDataListener(refresh))
+	isLoading.AddListener(binding.NewDataListener(refresh))
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *IncompleteUploads) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+	return widget.NewSimpleRenderer(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel("Parts of incomplete uploads are stored, and billed, until they are aborted."),
+			container.NewHBox(w.abortAllBtn),
+		),
+		nil, nil, nil,
+		container.NewStack(w.placeholder, w.list),
+	))
+}
+
+// orphans returns the uploads that no local transfer can resume.
+func (w *IncompleteUploads) orphans() []transfer.IncompleteUpload {
+	uploads, _ := w.uploads.Get()
+	orphans := make([]transfer.IncompleteUpload, 0, len(uploads))
+	for _, upload := range uploads {
+		if !upload.Resumable {
+			orphans = append(orphans, upload)
+		}
+	}
+	return orphans
+}
+
+func (w *IncompleteUploads) abort(uploads []transfer.IncompleteUpload) {
+	if w.OnAbort != nil && len(uploads) > 0 {
+		w.OnAbort(uploads)
+	}
+}
+
+func formatIncompleteUpload(upload transfer.IncompleteUpload) string {
+	desc := fmt.Sprintf("%s  %s", upload.InitiatedAt.Format("2006-01-02 15:04:05"), upload.Key)
+	if upload.Resumable {
+		desc += "  (resumable)"
+	}
+	return desc
+}
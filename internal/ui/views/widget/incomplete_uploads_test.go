@@ -0,0 +1,60 @@
+package widget_test
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	fyne_test "fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
+)
+
+func TestIncompleteUploads(t *testing.T) {
+	fyne_test.NewApp()
+
+	initiatedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	setup := func(t *testing.T) binding.List[transfer.IncompleteUpload] {
+		t.Helper()
+		uploads := binding.NewList[transfer.IncompleteUpload](func(u1, u2 transfer.IncompleteUpload) bool { return u1 == u2 })
+		require.NoError(t, uploads.Set([]transfer.IncompleteUpload{
+			{Key: "data/big.bin", UploadID: "upload-1", InitiatedAt: initiatedAt, Resumable: true},
+			{Key: "data/old.bin", UploadID: "upload-2", InitiatedAt: initiatedAt.Add(-24 * time.Hour)},
+		}))
+		return uploads
+	}
+
+	t.Run("should list the uploads with their actions", func(t *testing.T) {
+		// Given
+		uploads := setup(t)
+
+		// When
+		res := widget.NewIncompleteUploads(uploads, binding.NewBool(), false)
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(700, 300))
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "incomplete_uploads", w.Canvas())
+	})
+
+	t.Run("should abort the orphaned uploads only", func(t *testing.T) {
+		// Given
+		uploads := setup(t)
+		res := widget.NewIncompleteUploads(uploads, binding.NewBool(), false)
+		var aborted []transfer.IncompleteUpload
+		res.OnAbort = func(u []transfer.IncompleteUpload) { aborted = u }
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(700, 300))
+
+		// When
+		objects := fyne_test.LaidOutObjects(w.Canvas().Content())
+		fyne_test.Tap(findButtonByText(t, objects, "Abort all orphaned uploads"))
+
+		// Then
+		require.Len(t, aborted, 1)
+		assert.Equal(t, "upload-2", aborted[0].UploadID)
+	})
+}
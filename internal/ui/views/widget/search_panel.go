@@ -0,0 +1,207 @@
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+)
+
+const searchDateLayout = "2006-01-02"
+
+// SearchPanel searches the files by key pattern, size and modification date
+// under the selected directory, or the whole bucket when no directory is selected.
+type SearchPanel struct {
+	widget.BaseWidget
+
+	appCtx      appcontext.AppContext
+	onHitSelect func(hit directory.SearchHit)
+
+	patternEntry        *widget.Entry
+	regexCheck          *widget.Check
+	minSizeEntry        *widget.Entry
+	maxSizeEntry        *widget.Entry
+	modifiedAfterEntry  *widget.Entry
+	modifiedBeforeEntry *widget.Entry
+
+	searchButton *widget.Button
+	cancelButton *widget.Button
+	results      *widget.List
+}
+
+// NewSearchPanel creates the search panel, onHitSelect being called when a found file is selected.
+func NewSearchPanel(appCtx appcontext.AppContext, onHitSelect func(hit directory.SearchHit)) *SearchPanel {
+	vm := appCtx.ExplorerViewModel()
+
+	w := &SearchPanel{
+		appCtx:              appCtx,
+		onHitSelect:         onHitSelect,
+		patternEntry:        widget.NewEntry(),
+		regexCheck:          widget.NewCheck("Regular expression", nil),
+		minSizeEntry:        widget.NewEntry(),
+		maxSizeEntry:        widget.NewEntry(),
+		modifiedAfterEntry:  widget.NewEntry(),
+		modifiedBeforeEntry: widget.NewEntry(),
+	}
+	w.patternEntry.SetPlaceHolder("*.csv")
+	w.patternEntry.OnSubmitted = func(string) { w.search() }
+	w.minSizeEntry.SetPlaceHolder("min, e.g. 10 MB")
+	w.maxSizeEntry.SetPlaceHolder("max")
+	w.modifiedAfterEntry.SetPlaceHolder("from " + searchDateLayout)
+	w.modifiedBeforeEntry.SetPlaceHolder("to " + searchDateLayout)
+
+	w.searchButton = widget.NewButtonWithIcon("Search", theme.SearchIcon(), w.search)
+	w.cancelButton = widget.NewButtonWithIcon("Cancel", theme.CancelIcon(), vm.CancelSearch)
+	w.cancelButton.Hide()
+
+	w.results = widget.NewListWithData(vm.SearchResults(),
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(i binding.DataItem, o fyne.CanvasObject) {
+			hit, err := i.(binding.Item[directory.SearchHit]).Get()
+			if err != nil {
+				return
+			}
+			o.(*widget.Label).SetText(formatSearchHit(hit))
+		})
+	w.results.OnSelected = func(id widget.ListItemID) {
+		w.results.Unselect(id)
+		hit, err := vm.SearchResults().GetValue(id)
+		if err != nil {
+			return
+		}
+		w.onHitSelect(hit)
+	}
+
+	vm.IsSearching().AddListener(binding.NewDataListener(func() {
+		isSearching, _ := vm.IsSearching().Get()
+		if isSearching {
+			w.searchButton.Disable()
+			w.cancelButton.Show()
+		} else {
+			w.searchButton.Enable()
+			w.cancelButton.Hide()
+		}
+	}))
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *SearchPanel) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+	vm := w.appCtx.ExplorerViewModel()
+
+	progress := widget.NewLabelWithData(vm.SearchProgress())
+	progress.Wrapping = fyne.TextWrapWord
+
+	form := container.NewVBox(
+		w.patternEntry,
+		w.regexCheck,
+		container.NewGridWithColumns(2, w.minSizeEntry, w.maxSizeEntry),
+		container.NewGridWithColumns(2, w.modifiedAfterEntry, w.modifiedBeforeEntry),
+		container.NewHBox(w.searchButton, w.cancelButton),
+		progress,
+	)
+
+	return widget.NewSimpleRenderer(container.NewBorder(form, nil, nil, nil, w.results))
+}
+
+func (w *SearchPanel) search() {
+	vm := w.appCtx.ExplorerViewModel()
+
+	query, err := w.query()
+	if err != nil {
+		dialog.ShowError(err, w.appCtx.Window())
+		return
+	}
+
+	dir := vm.SelectedDirectory()
+	if dir == nil {
+		rootNode, err := w.appCtx.State().Explorer().GetDirectoryNode(directory.RootPath)
+		if err != nil {
+			dialog.ShowError(err, w.appCtx.Window())
+			return
+		}
+		dir = rootNode.Directory()
+	}
+
+	if err := vm.Search(dir, query); err != nil {
+		dialog.ShowError(err, w.appCtx.Window())
+	}
+}
+
+func (w *SearchPanel) query() (directory.SearchQuery, error) {
+	query := directory.SearchQuery{
+		Pattern:     strings.TrimSpace(w.patternEntry.Text),
+		PatternKind: directory.SearchGlob,
+	}
+	if w.regexCheck.Checked {
+		query.PatternKind = directory.SearchRegex
+	}
+
+	var err error
+	if query.MinSizeBytes, err = parseSearchSize(w.minSizeEntry.Text); err != nil {
+		return query, err
+	}
+	if query.MaxSizeBytes, err = parseSearchSize(w.maxSizeEntry.Text); err != nil {
+		return query, err
+	}
+	if query.ModifiedAfter, err = parseSearchDate(w.modifiedAfterEntry.Text); err != nil {
+		return query, err
+	}
+	if query.ModifiedBefore, err = parseSearchDate(w.modifiedBeforeEntry.Text); err != nil {
+		return query, err
+	}
+	if !query.ModifiedBefore.IsZero() {
+		// the whole last day is included
+		query.ModifiedBefore = query.ModifiedBefore.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	if _, err := query.Matcher(); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+func parseSearchSize(text string) (uint64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(text)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid size %q", directory.ErrInvalidSearch, text)
+	}
+	return size, nil
+}
+
+func parseSearchDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(searchDateLayout, text, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q, expected format is %s",
+			directory.ErrInvalidSearch, text, searchDateLayout)
+	}
+	return date, nil
+}
+
+func formatSearchHit(hit directory.SearchHit) string {
+	return fmt.Sprintf("%s  %s  %s",
+		hit.FullPath(), humanize.Bytes(hit.SizeBytes), hit.LastModified.Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,116 @@
+package widget_test
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	fyne_test "fyne.io/fyne/v2/test"
+	fyne_widget "fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
+	mocks_appcontext "github.com/thomas-marquis/s3-box/mocks/context"
+	mocks_viewmodel "github.com/thomas-marquis/s3-box/mocks/viewmodel"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchPanel(t *testing.T) {
+	fyne_test.NewApp()
+
+	setup := func(t *testing.T) (*mocks_appcontext.MockAppContext, *mocks_viewmodel.MockExplorerViewModel, binding.List[directory.SearchHit]) {
+		ctrl := gomock.NewController(t)
+		mockAppCtx := mocks_appcontext.NewMockAppContext(ctrl)
+		mockExplorerVM := mocks_viewmodel.NewMockExplorerViewModel(ctrl)
+
+		mockAppCtx.EXPECT().ExplorerViewModel().Return(mockExplorerVM).AnyTimes()
+		mockAppCtx.EXPECT().Window().Return(fyne_test.NewWindow(nil)).AnyTimes()
+
+		results := binding.NewList(func(a, b directory.SearchHit) bool { return a.FullPath() == b.FullPath() })
+		progress := binding.NewString()
+		mockExplorerVM.EXPECT().SearchResults().Return(results).AnyTimes()
+		mockExplorerVM.EXPECT().SearchProgress().Return(progress).AnyTimes()
+		mockExplorerVM.EXPECT().IsSearching().Return(binding.NewBool()).AnyTimes()
+
+		return mockAppCtx, mockExplorerVM, results
+	}
+
+	t.Run("should display the search results", func(t *testing.T) {
+		// Given
+		mockAppCtx, mockExplorerVM, results := setup(t)
+		lastModified := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		require.NoError(t, results.Set([]directory.SearchHit{
+			{DirectoryPath: "/data/", Name: "sales.csv", SizeBytes: 2048, LastModified: lastModified},
+			{DirectoryPath: "/data/2024/", Name: "q1.csv", SizeBytes: 1024, LastModified: lastModified},
+		}))
+		require.NoError(t, mockExplorerVM.SearchProgress().Set("2 files found in /data/, 12 objects scanned"))
+
+		// When
+		res := widget.NewSearchPanel(mockAppCtx, func(directory.SearchHit) {})
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(500, 500))
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "search_panel", w.Canvas())
+	})
+
+	t.Run("should search under the selected directory with the filled filters", func(t *testing.T) {
+		// Given
+		mockAppCtx, mockExplorerVM, _ := setup(t)
+		dir := tu.NewNotLoadedDirectory(t, "data", directory.RootPath)
+		mockExplorerVM.EXPECT().SelectedDirectory().Return(dir)
+
+		var query directory.SearchQuery
+		mockExplorerVM.EXPECT().
+			Search(dir, gomock.Any()).
+			DoAndReturn(func(_ *directory.Directory, q directory.SearchQuery) error {
+				query = q
+				return nil
+			}).
+			Times(1)
+
+		res := widget.NewSearchPanel(mockAppCtx, func(directory.SearchHit) {})
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(500, 500))
+
+		// When
+		objects := fyne_test.LaidOutObjects(w.Canvas().Content())
+		fyne_test.Type(findEntryByPlaceHolder(t, objects, "*.csv"), "*.csv")
+		fyne_test.Type(findEntryByPlaceHolder(t, objects, "min, e.g. 10 MB"), "1 KB")
+		fyne_test.Type(findEntryByPlaceHolder(t, objects, "from 2006-01-02"), "2024-01-01")
+		fyne_test.Tap(findButtonByText(t, objects, "Search"))
+
+		// Then
+		assert.Equal(t, "*.csv", query.Pattern)
+		assert.Equal(t, directory.SearchGlob, query.PatternKind)
+		assert.Equal(t, uint64(1000), query.MinSizeBytes)
+		assert.Equal(t, uint64(0), query.MaxSizeBytes)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), query.ModifiedAfter)
+		assert.True(t, query.ModifiedBefore.IsZero())
+	})
+}
+
+func findEntryByPlaceHolder(t *testing.T, objects []fyne.CanvasObject, placeHolder string) *fyne_widget.Entry {
+	t.Helper()
+	for _, o := range objects {
+		if e, ok := o.(*fyne_widget.Entry); ok && e.PlaceHolder == placeHolder {
+			return e
+		}
+	}
+	require.Failf(t, "entry not found", "no entry with the placeholder %q", placeHolder)
+	return nil
+}
+
+func findButtonByText(t *testing.T, objects []fyne.CanvasObject, text string) *fyne_widget.Button {
+	t.Helper()
+	for _, o := range objects {
+		if b, ok := o.(*fyne_widget.Button); ok && b.Text == text {
+			return b
+		}
+	}
+	require.Failf(t, "button not found", "no button with the text %q", text)
+	return nil
+}
@@ -0,0 +1,337 @@
+package widget
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/u"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+	"github.com/thomas-marquis/s3-box/internal/ui/viewmodel"
+)
+
+const (
+	maxListedSelectedItems = 20
+)
+
+// SelectionDetails displays the files and directories selected together in the explorer tree
+// and the bulk actions available on them.
+type SelectionDetails struct {
+	widget.BaseWidget
+
+	appCtx appcontext.AppContext
+
+	titleBinding binding.String
+	itemsLabel   *widget.Label
+
+	toolbar        *widget.Toolbar
+	downloadAction *ToolbarButton
+	copyAction     *ToolbarButton
+	moveAction     *ToolbarButton
+	deleteAction   *ToolbarButton
+	clearAction    *ToolbarButton
+}
+
+func NewSelectionDetails(appCtx appcontext.AppContext) *SelectionDetails {
+	w := &SelectionDetails{
+		appCtx:         appCtx,
+		titleBinding:   binding.NewString(),
+		itemsLabel:     widget.NewLabel(""),
+		downloadAction: NewToolbarButton("Download", theme.DownloadIcon(), func() {}),
+		copyAction:     NewToolbarButton("Copy to", theme.ContentCopyIcon(), func() {}),
+		moveAction:     NewToolbarButton("Move to", theme.ContentCutIcon(), func() {}),
+		deleteAction:   NewToolbarButton("Delete", theme.DeleteIcon(), func() {}),
+		clearAction:    NewToolbarButton("Clear selection", theme.ContentClearIcon(), func() {}),
+	}
+	w.itemsLabel.Truncation = fyne.TextTruncateEllipsis
+
+	w.toolbar = widget.NewToolbar(
+		w.downloadAction,
+		w.copyAction,
+		w.moveAction,
+		w.deleteAction,
+		w.clearAction,
+	)
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *SelectionDetails) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+
+	return widget.NewSimpleRenderer(
+		container.NewVBox(
+			container.NewHBox(
+				widget.NewIcon(theme.ListIcon()),
+				widget.NewLabelWithData(w.titleBinding),
+			),
+			container.New(
+				layout.NewCustomPaddedLayout(10, 20, 0, 0),
+				widget.NewSeparator(),
+			),
+			container.New(
+				layout.NewCustomPaddedLayout(0, 0, 5, 5),
+				w.toolbar,
+			),
+			container.New(
+				layout.NewCustomPaddedLayout(10, 20, 0, 0),
+				widget.NewSeparator(),
+			),
+			w.itemsLabel,
+		),
+	)
+}
+
+// Select refreshes the panel with the current content of the selection.
+func (w *SelectionDetails) Select(sel *directory.Selection) {
+	vm := w.appCtx.ExplorerViewModel()
+
+	u.Skip(w.titleBinding.Set(fmt.Sprintf("%d items selected", sel.Len())))
+	w.itemsLabel.SetText(formatSelectedItems(sel))
+
+	w.downloadAction.SetOnTapped(w.makeOnDownload(vm))
+	w.copyAction.SetOnTapped(w.makeOnTransfer(vm, false))
+	w.moveAction.SetOnTapped(w.makeOnTransfer(vm, true))
+	w.deleteAction.SetOnTapped(w.makeOnDelete(vm, sel))
+	w.clearAction.SetOnTapped(vm.ClearSelection)
+
+	// A read-only selection can still be copied into another connection.
+	if len(w.writableConnections()) == 0 {
+		w.copyAction.Disable()
+	} else {
+		w.copyAction.Enable()
+	}
+	if w.appCtx.ConnectionViewModel().IsReadOnly() {
+		w.moveAction.Disable()
+		w.deleteAction.Disable()
+	} else {
+		w.moveAction.Enable()
+		w.deleteAction.Enable()
+	}
+}
+
+// writableConnections returns the connections the selection can be copied or moved into.
+func (w *SelectionDetails) writableConnections() []*connection_deck.Connection {
+	var res []*connection_deck.Connection
+	for _, conn := range w.appCtx.ConnectionViewModel().Deck().Get() {
+		if !conn.ReadOnly() {
+			res = append(res, conn)
+		}
+	}
+	return res
+}
+
+func (w *SelectionDetails) makeOnDelete(vm viewmodel.ExplorerViewModel, sel *directory.Selection) func() {
+	return func() {
+		dialog.ShowConfirm("Delete selection",
+			fmt.Sprintf("Are you sure you want to delete the %d selected items with all their content?", sel.Len()),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				if err := vm.DeleteSelection(); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				showOperationProgress(w.appCtx.Window(), "Deleting selection", vm.SelectionProgress(), vm.CancelSelectionOperation)
+			},
+			w.appCtx.Window())
+	}
+}
+
+func (w *SelectionDetails) makeOnDownload(vm viewmodel.ExplorerViewModel) func() {
+	return func() {
+		folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, w.appCtx.Window())
+				return
+			}
+			if uri == nil {
+				return
+			}
+
+			dest := uri.Path()
+			download := func(strategy directory.MaterializeStrategy) {
+				if err := vm.DownloadSelection(dest, strategy); err != nil {
+					dialog.ShowError(err, w.appCtx.Window())
+					return
+				}
+				u.Skip(vm.UpdateLastDownloadLocation(filepath.Join(dest, selectedItemNames(vm.Selection())[0])))
+				showOperationProgress(w.appCtx.Window(), "Downloading selection", vm.SelectionProgress(), vm.CancelSelectionOperation)
+			}
+
+			if !hasExistingLocalItems(vm.Selection(), dest) {
+				download(directory.MaterializeReplace)
+				return
+			}
+			askMaterializeStrategy(w.appCtx.Window(),
+				fmt.Sprintf("Some selected items already exist in %s. What should be done with the existing files?", dest),
+				download)
+		}, w.appCtx.Window())
+
+		folderDialog.SetLocation(vm.LastDownloadLocation())
+		folderDialog.Show()
+	}
+}
+
+func (w *SelectionDetails) makeOnTransfer(vm viewmodel.ExplorerViewModel, move bool) func() {
+	return func() {
+		currentConnID := vm.Selection().ConnectionID()
+		conns := w.writableConnections()
+		connNames := make([]string, 0, len(conns))
+		for _, conn := range conns {
+			connNames = append(connNames, conn.Name())
+		}
+		connSelect := widget.NewSelect(connNames, nil)
+
+		// Directories of the current connection are picked in the explorer tree,
+		// while those of another connection are given by their path.
+		dirs := w.appCtx.State().Explorer().Directories()
+		paths := make([]string, 0, len(dirs))
+		for _, d := range dirs {
+			paths = append(paths, d.Path().String())
+		}
+		destSelect := widget.NewSelect(paths, nil)
+		destEntry := widget.NewEntry()
+		destEntry.SetPlaceHolder("/path/to/directory/")
+		destEntry.Hide()
+
+		connSelect.OnChanged = func(string) {
+			if conns[connSelect.SelectedIndex()].ID() == currentConnID {
+				destEntry.Hide()
+				destSelect.Show()
+			} else {
+				destSelect.Hide()
+				destEntry.Show()
+			}
+		}
+		for i, conn := range conns {
+			if conn.ID() == currentConnID {
+				connSelect.SetSelectedIndex(i)
+			}
+		}
+
+		title, confirm := "Copy selection", "Copy"
+		if move {
+			title, confirm = "Move selection", "Move"
+		}
+
+		d := dialog.NewForm(title, confirm, "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Connection", connSelect),
+				widget.NewFormItem("Destination", container.NewStack(destSelect, destEntry)),
+			},
+			func(ok bool) {
+				if !ok || connSelect.SelectedIndex() < 0 {
+					return
+				}
+
+				var dst *directory.Directory
+				if conn := conns[connSelect.SelectedIndex()]; conn.ID() == currentConnID {
+					if destSelect.SelectedIndex() < 0 {
+						return
+					}
+					dst = dirs[destSelect.SelectedIndex()]
+				} else {
+					var err error
+					dst, err = directory.NewFromPath(conn.ID(), directory.NewPath(destEntry.Text))
+					if err != nil {
+						dialog.ShowError(err, w.appCtx.Window())
+						return
+					}
+				}
+
+				transfer := func(strategy directory.MaterializeStrategy) {
+					var err error
+					if move {
+						err = vm.MoveSelection(dst, strategy)
+					} else {
+						err = vm.CopySelection(dst, strategy)
+					}
+					if err != nil {
+						dialog.ShowError(err, w.appCtx.Window())
+						return
+					}
+					showOperationProgress(w.appCtx.Window(), title, vm.SelectionProgress(), vm.CancelSelectionOperation)
+				}
+
+				// The content of an unloaded destination is unknown, so conflicts are possible.
+				if dst.IsLoaded() && !hasExistingRemoteItems(vm.Selection(), dst) {
+					transfer(directory.MaterializeReplace)
+					return
+				}
+				askMaterializeStrategy(w.appCtx.Window(),
+					fmt.Sprintf("Some selected items may already exist in %s. What should be done with the existing files?", dst.Path()),
+					transfer)
+			},
+			w.appCtx.Window())
+		d.Resize(fyne.NewSize(400, 200))
+		d.Show()
+	}
+}
+
+func formatSelectedItems(sel *directory.Selection) string {
+	names := make([]string, 0, sel.Len())
+	for _, d := range sel.Directories() {
+		names = append(names, d.Path().String())
+	}
+	for _, f := range sel.Files() {
+		names = append(names, f.FullPath())
+	}
+
+	var res string
+	for i, name := range names {
+		if i == maxListedSelectedItems {
+			res += fmt.Sprintf("... and %d more\n", len(names)-maxListedSelectedItems)
+			break
+		}
+		res += name + "\n"
+	}
+	return res
+}
+
+func selectedItemNames(sel *directory.Selection) []string {
+	names := make([]string, 0, sel.Len())
+	for _, d := range sel.Directories() {
+		names = append(names, d.Name())
+	}
+	for _, f := range sel.Files() {
+		names = append(names, f.Name().String())
+	}
+	return names
+}
+
+// hasExistingLocalItems tells whether one of the selected items already exists in the local directory dest.
+func hasExistingLocalItems(sel *directory.Selection, dest string) bool {
+	for _, name := range selectedItemNames(sel) {
+		if _, err := os.Stat(filepath.Join(dest, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasExistingRemoteItems tells whether one of the selected items already exists in the directory dst.
+func hasExistingRemoteItems(sel *directory.Selection, dst *directory.Directory) bool {
+	for _, d := range sel.Directories() {
+		if dst.IsSubDirectoryExists(d.Name()) {
+			return true
+		}
+	}
+	for _, f := range sel.Files() {
+		if dst.IsFileExists(f.Name()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package widget_test
+
+import (
+	"testing"
+
+	fyne_test "fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
+	mocks_appcontext "github.com/thomas-marquis/s3-box/mocks/context"
+	mocks_viewmodel "github.com/thomas-marquis/s3-box/mocks/viewmodel"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSelectionDetails(t *testing.T) {
+	fyne_test.NewApp()
+
+	makeSelection := func(t *testing.T) *directory.Selection {
+		var (
+			top    *directory.File
+			subDir *directory.Directory
+		)
+		tu.MakeDirectory(t, "",
+			tu.AsRoot(),
+			tu.WithFileTo("top.txt", &top),
+			tu.WithSubDirectory("mydir", tu.To(&subDir)))
+
+		sel := directory.NewSelection(tu.FakeAwsConnectionId)
+		require.NoError(t, sel.AddDirectory(subDir))
+		require.NoError(t, sel.AddFile(top))
+		return sel
+	}
+
+	t.Run("should display the selected items and the bulk actions", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockAppCtx := mocks_appcontext.NewMockAppContext(ctrl)
+		mockExplorerVM := mocks_viewmodel.NewMockExplorerViewModel(ctrl)
+		mockConnVM := mocks_viewmodel.NewMockConnectionViewModel(ctrl)
+
+		mockAppCtx.EXPECT().ExplorerViewModel().Return(mockExplorerVM).AnyTimes()
+		mockAppCtx.EXPECT().ConnectionViewModel().Return(mockConnVM).AnyTimes()
+		mockAppCtx.EXPECT().Window().Return(fyne_test.NewWindow(nil)).AnyTimes()
+
+		deck := connection_deck.New()
+		deck.New("connection 1", "ak", "sk", "myBucket")
+		mockConnVM.EXPECT().Deck().Return(deck).AnyTimes()
+		mockConnVM.EXPECT().IsReadOnly().Return(false)
+
+		sel := makeSelection(t)
+
+		// When
+		res := widget.NewSelectionDetails(mockAppCtx)
+		res.Select(sel)
+		c := fyne_test.NewWindow(res).Canvas()
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "selection_details", c)
+	})
+
+	t.Run("should disable the actions changing the bucket in read-only mode", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockAppCtx := mocks_appcontext.NewMockAppContext(ctrl)
+		mockExplorerVM := mocks_viewmodel.NewMockExplorerViewModel(ctrl)
+		mockConnVM := mocks_viewmodel.NewMockConnectionViewModel(ctrl)
+
+		mockAppCtx.EXPECT().ExplorerViewModel().Return(mockExplorerVM).AnyTimes()
+		mockAppCtx.EXPECT().ConnectionViewModel().Return(mockConnVM).AnyTimes()
+		mockAppCtx.EXPECT().Window().Return(fyne_test.NewWindow(nil)).AnyTimes()
+
+		deck := connection_deck.New()
+		deck.New("connection 1", "ak", "sk", "myBucket", connection_deck.WithReadOnlyOption(true))
+		mockConnVM.EXPECT().Deck().Return(deck).AnyTimes()
+		mockConnVM.EXPECT().IsReadOnly().Return(true)
+
+		sel := makeSelection(t)
+
+		// When
+		res := widget.NewSelectionDetails(mockAppCtx)
+		res.Select(sel)
+		c := fyne_test.NewWindow(res).Canvas()
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "selection_details_readonly", c)
+	})
+}
@@ -0,0 +1,94 @@
+package widget
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/thomas-marquis/s3-box/internal/domain/directory"
+)
+
+type tagRow struct {
+	key   *widget.Entry
+	value *widget.Entry
+}
+
+// TagsEditor is a key/value table to edit the tags of a file.
+type TagsEditor struct {
+	widget.BaseWidget
+
+	rows      []*tagRow
+	rowsBox   *fyne.Container
+	addButton *widget.Button
+}
+
+func NewTagsEditor(tags directory.ObjectTags) *TagsEditor {
+	w := &TagsEditor{
+		rowsBox: container.NewVBox(),
+	}
+	w.addButton = widget.NewButtonWithIcon("Add tag", theme.ContentAddIcon(), func() {
+		w.addRow("", "")
+	})
+
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		w.addRow(key, tags[key])
+	}
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *TagsEditor) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+
+	header := container.NewBorder(nil, nil, nil,
+		widget.NewButtonWithIcon("", theme.DeleteIcon(), nil), // keeps the header aligned with the rows
+		container.NewGridWithColumns(2,
+			widget.NewLabelWithStyle("Key", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewLabelWithStyle("Value", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		))
+	header.Objects[1].Hide()
+
+	return widget.NewSimpleRenderer(container.NewVBox(
+		header,
+		w.rowsBox,
+		container.NewHBox(w.addButton),
+	))
+}
+
+// Tags returns the edited tags, ignoring the rows without key.
+func (w *TagsEditor) Tags() directory.ObjectTags {
+	res := make(directory.ObjectTags, len(w.rows))
+	for _, r := range w.rows {
+		if key := strings.TrimSpace(r.key.Text); key != "" {
+			res[key] = strings.TrimSpace(r.value.Text)
+		}
+	}
+	return res
+}
+
+func (w *TagsEditor) addRow(key, value string) {
+	r := &tagRow{
+		key:   widget.NewEntry(),
+		value: widget.NewEntry(),
+	}
+	r.key.SetText(key)
+	r.key.SetPlaceHolder("key")
+	r.value.SetText(value)
+	r.value.SetPlaceHolder("value")
+
+	var rowObj fyne.CanvasObject
+	removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		w.rows = slices.DeleteFunc(w.rows, func(other *tagRow) bool { return other == r })
+		w.rowsBox.Remove(rowObj)
+	})
+	rowObj = container.NewBorder(nil, nil, nil, removeBtn,
+		container.NewGridWithColumns(2, r.key, r.value))
+
+	w.rows = append(w.rows, r)
+	w.rowsBox.Add(rowObj)
+}
@@ -37,3 +37,7 @@ func (t *ToolbarButton) Disable() {
 func (t *ToolbarButton) Enable() {
 	t.button.Enable()
 }
+
+func (t *ToolbarButton) Disabled() bool {
+	return t.button.Disabled()
+}
@@ -0,0 +1,138 @@
+package widget
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/dustin/go-humanize"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	appcontext "github.com/thomas-marquis/s3-box/internal/ui/app/context"
+	"github.com/thomas-marquis/s3-box/internal/ui/viewmodel"
+)
+
+// TransferList displays the uploads and downloads with their progress,
+// and lets the user pause, resume or cancel each of them.
+type TransferList struct {
+	widget.BaseWidget
+
+	appCtx appcontext.AppContext
+	list   *widget.List
+}
+
+func NewTransferList(appCtx appcontext.AppContext) *TransferList {
+	vm := appCtx.TransferViewModel()
+
+	w := &TransferList{appCtx: appCtx}
+	w.list = widget.NewListWithData(vm.Transfers(),
+		func() fyne.CanvasObject {
+			return newTransferItem()
+		},
+		func(i binding.DataItem, o fyne.CanvasObject) {
+			job, err := i.(binding.Item[transfer.Job]).Get()
+			if err != nil {
+				return
+			}
+			o.(*transferItem).update(job, vm)
+		})
+
+	w.ExtendBaseWidget(w)
+	return w
+}
+
+func (w *TransferList) CreateRenderer() fyne.WidgetRenderer {
+	w.ExtendBaseWidget(w)
+	return widget.NewSimpleRenderer(w.list)
+}
+
+type transferItem struct {
+	widget.BaseWidget
+
+	icon        *widget.Icon
+	nameLabel   *widget.Label
+	statusLabel *widget.Label
+	progress    *widget.ProgressBar
+	toggleBtn   *widget.Button
+	cancelBtn   *widget.Button
+}
+
+func newTransferItem() *transferItem {
+	item := &transferItem{
+		icon:        widget.NewIcon(theme.UploadIcon()),
+		nameLabel:   widget.NewLabel(""),
+		statusLabel: widget.NewLabel(""),
+		progress:    widget.NewProgressBar(),
+		toggleBtn:   widget.NewButtonWithIcon("", theme.MediaPauseIcon(), nil),
+		cancelBtn:   widget.NewButtonWithIcon("", theme.CancelIcon(), nil),
+	}
+	item.nameLabel.Truncation = fyne.TextTruncateEllipsis
+	item.nameLabel.TextStyle = fyne.TextStyle{Bold: true}
+	item.statusLabel.Truncation = fyne.TextTruncateEllipsis
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (i *transferItem) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewBorder(nil, nil,
+		i.icon,
+		container.NewHBox(i.toggleBtn, i.cancelBtn),
+		container.NewVBox(i.nameLabel, i.progress, i.statusLabel),
+	))
+}
+
+func (i *transferItem) update(job transfer.Job, vm viewmodel.TransferViewModel) {
+	if job.Kind == transfer.KindDownload {
+		i.icon.SetResource(theme.DownloadIcon())
+	} else {
+		i.icon.SetResource(theme.UploadIcon())
+	}
+	i.nameLabel.SetText(job.Key)
+	i.progress.SetValue(job.Progress())
+	i.statusLabel.SetText(formatTransferStatus(job))
+
+	switch job.Status {
+	case transfer.StatusQueued, transfer.StatusRunning:
+		i.toggleBtn.SetIcon(theme.MediaPauseIcon())
+		i.toggleBtn.OnTapped = func() { vm.Pause(job.ID) }
+		i.toggleBtn.Enable()
+	case transfer.StatusPaused, transfer.StatusFailed:
+		i.toggleBtn.SetIcon(theme.MediaPlayIcon())
+		i.toggleBtn.OnTapped = func() { vm.Resume(job.ID) }
+		i.toggleBtn.Enable()
+	default:
+		i.toggleBtn.SetIcon(theme.MediaPlayIcon())
+		i.toggleBtn.OnTapped = nil
+		i.toggleBtn.Disable()
+	}
+
+	if job.Status.IsFinished() {
+		i.cancelBtn.OnTapped = nil
+		i.cancelBtn.Disable()
+	} else {
+		i.cancelBtn.OnTapped = func() { vm.Cancel(job.ID) }
+		i.cancelBtn.Enable()
+	}
+}
+
+func formatTransferStatus(job transfer.Job) string {
+	size := humanize.Bytes(job.TotalBytes)
+	switch job.Status {
+	case transfer.StatusRunning:
+		return fmt.Sprintf("%s in progress: %s of %s",
+			job.Kind, humanize.Bytes(job.TransferredBytes), size)
+	case transfer.StatusQueued:
+		if !job.RetryAt.IsZero() {
+			return fmt.Sprintf("retrying (attempt %d failed: %s)", job.Attempts, job.LastError)
+		}
+		return fmt.Sprintf("%s of %s queued", job.Kind, size)
+	case transfer.StatusFailed:
+		return fmt.Sprintf("failed after %d attempt(s): %s", job.Attempts, job.LastError)
+	case transfer.StatusSucceeded:
+		return fmt.Sprintf("%s of %s done, local file: %s", job.Kind, size, job.LocalPath)
+	default:
+		return fmt.Sprintf("%s of %s %s", job.Kind, size, job.Status)
+	}
+}
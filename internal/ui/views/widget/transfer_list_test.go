@@ -0,0 +1,98 @@
+package widget_test
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	fyne_test "fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	fyne_widget "fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/require"
+	"github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	"github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	"github.com/thomas-marquis/s3-box/internal/tu"
+	"github.com/thomas-marquis/s3-box/internal/ui/views/widget"
+	mocks_appcontext "github.com/thomas-marquis/s3-box/mocks/context"
+	mocks_viewmodel "github.com/thomas-marquis/s3-box/mocks/viewmodel"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransferList(t *testing.T) {
+	fyne_test.NewApp()
+
+	t.Run("should display the transfers with their progress", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockAppCtx := mocks_appcontext.NewMockAppContext(ctrl)
+		mockTransferVM := mocks_viewmodel.NewMockTransferViewModel(ctrl)
+
+		mockAppCtx.EXPECT().TransferViewModel().Return(mockTransferVM).AnyTimes()
+
+		connID := connection_deck.NewConnectionID()
+		running := transfer.NewUploadJob(connID, "/tmp/report.csv", "data/report.csv", 4_000_000)
+		running.Status = transfer.StatusRunning
+		running.Attempts = 1
+		running.TransferredBytes = 1_000_000
+		failed := transfer.NewDownloadJob(connID, "data/photo.png", "", "/tmp/photo.png", 2_000)
+		failed.Status = transfer.StatusFailed
+		failed.Attempts = 5
+		failed.LastError = "connection reset"
+		done := transfer.NewDownloadJob(connID, "data/notes.txt", "", "/tmp/notes.txt", 1_200)
+		done.Status = transfer.StatusSucceeded
+		done.TransferredBytes = done.TotalBytes
+
+		jobs := binding.NewList[transfer.Job](func(j1, j2 transfer.Job) bool { return j1 == j2 })
+		require.NoError(t, jobs.Set([]transfer.Job{running, failed, done}))
+		mockTransferVM.EXPECT().Transfers().Return(jobs).AnyTimes()
+
+		// When
+		res := widget.NewTransferList(mockAppCtx)
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(600, 400))
+
+		// Then
+		fyne_test.AssertRendersToMarkup(t, "transfer_list", w.Canvas())
+	})
+
+	t.Run("should pause a running transfer", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		mockAppCtx := mocks_appcontext.NewMockAppContext(ctrl)
+		mockTransferVM := mocks_viewmodel.NewMockTransferViewModel(ctrl)
+
+		mockAppCtx.EXPECT().TransferViewModel().Return(mockTransferVM).AnyTimes()
+
+		running := transfer.NewUploadJob(connection_deck.NewConnectionID(), "/tmp/report.csv", "data/report.csv", 100)
+		running.Status = transfer.StatusRunning
+
+		jobs := binding.NewList[transfer.Job](func(j1, j2 transfer.Job) bool { return j1 == j2 })
+		require.NoError(t, jobs.Set([]transfer.Job{running}))
+		mockTransferVM.EXPECT().Transfers().Return(jobs).AnyTimes()
+
+		paused := make(chan struct{})
+		mockTransferVM.EXPECT().Pause(running.ID).Do(func(transfer.JobID) { close(paused) }).Times(1)
+
+		res := widget.NewTransferList(mockAppCtx)
+		w := fyne_test.NewWindow(res)
+		w.Resize(fyne.NewSize(600, 400))
+
+		// When
+		objects := fyne_test.LaidOutObjects(w.Canvas().Content())
+		fyne_test.Tap(findButtonByIcon(t, objects, theme.MediaPauseIcon()))
+
+		// Then
+		tu.AssertEventually(t, paused)
+	})
+}
+
+func findButtonByIcon(t *testing.T, objects []fyne.CanvasObject, icon fyne.Resource) *fyne_widget.Button {
+	t.Helper()
+	for _, o := range objects {
+		if b, ok := o.(*fyne_widget.Button); ok && b.Icon != nil && b.Icon.Name() == icon.Name() {
+			return b
+		}
+	}
+	require.Failf(t, "button not found", "no button with the icon %q", icon.Name())
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/thomas-marquis/s3-box/internal/u"
 	"github.com/thomas-marquis/s3-box/internal/ui/app"
 	"github.com/thomas-marquis/s3-box/internal/ui/app/navigation"
@@ -9,6 +11,11 @@ import (
 )
 
 func main() {
+	// with arguments, the command line runs headless, without the graphical interface
+	if len(os.Args) > 1 {
+		os.Exit(app.RunCLI(os.Args[1:]))
+	}
+
 	logCfg := zap.NewDevelopmentConfig()
 	logCfg.Level.SetLevel(zap.DebugLevel)
 	logger, err := logCfg.Build()
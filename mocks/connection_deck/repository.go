@@ -43,17 +43,17 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Export mocks base method.
-func (m *MockRepository) Export(ctx context.Context, file io.Writer) error {
+func (m *MockRepository) Export(ctx context.Context, file io.Writer, options connection_deck.ExportOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Export", ctx, file)
+	ret := m.ctrl.Call(m, "Export", ctx, file, options)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Export indicates an expected call of Export.
-func (mr *MockRepositoryMockRecorder) Export(ctx, file any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Export(ctx, file, options any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockRepository)(nil).Export), ctx, file)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockRepository)(nil).Export), ctx, file, options)
 }
 
 // Get mocks base method.
@@ -70,3 +70,46 @@ func (mr *MockRepositoryMockRecorder) Get(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx)
 }
+
+// Import mocks base method.
+func (m *MockRepository) Import(ctx context.Context, file io.Reader, options connection_deck.ImportOptions) ([]*connection_deck.Connection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, file, options)
+	ret0, _ := ret[0].([]*connection_deck.Connection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockRepositoryMockRecorder) Import(ctx, file, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockRepository)(nil).Import), ctx, file, options)
+}
+
+// ProtectSecrets mocks base method.
+func (m *MockRepository) ProtectSecrets(ctx context.Context, passphrase string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtectSecrets", ctx, passphrase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProtectSecrets indicates an expected call of ProtectSecrets.
+func (mr *MockRepositoryMockRecorder) ProtectSecrets(ctx, passphrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtectSecrets", reflect.TypeOf((*MockRepository)(nil).ProtectSecrets), ctx, passphrase)
+}
+
+// SecretsProtected mocks base method.
+func (m *MockRepository) SecretsProtected() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecretsProtected")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SecretsProtected indicates an expected call of SecretsProtected.
+func (mr *MockRepositoryMockRecorder) SecretsProtected() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecretsProtected", reflect.TypeOf((*MockRepository)(nil).SecretsProtected))
+}
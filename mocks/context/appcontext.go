@@ -214,6 +214,20 @@ func (mr *MockAppContextMockRecorder) State() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockAppContext)(nil).State))
 }
 
+// TransferViewModel mocks base method.
+func (m *MockAppContext) TransferViewModel() viewmodel.TransferViewModel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferViewModel")
+	ret0, _ := ret[0].(viewmodel.TransferViewModel)
+	return ret0
+}
+
+// TransferViewModel indicates an expected call of TransferViewModel.
+func (mr *MockAppContextMockRecorder) TransferViewModel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferViewModel", reflect.TypeOf((*MockAppContext)(nil).TransferViewModel))
+}
+
 // Window mocks base method.
 func (m *MockAppContext) Window() fyne.Window {
 	m.ctrl.T.Helper()
@@ -99,6 +99,18 @@ func (mr *MockConnectionViewModelMockRecorder) Delete(conn any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockConnectionViewModel)(nil).Delete), conn)
 }
 
+// Diagnose mocks base method.
+func (m *MockConnectionViewModel) Diagnose(conn *connection_deck.Connection, onResult func([]connection_deck.DiagnosticStep)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Diagnose", conn, onResult)
+}
+
+// Diagnose indicates an expected call of Diagnose.
+func (mr *MockConnectionViewModelMockRecorder) Diagnose(conn, onResult any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diagnose", reflect.TypeOf((*MockConnectionViewModel)(nil).Diagnose), conn, onResult)
+}
+
 // ErrorMessage mocks base method.
 func (m *MockConnectionViewModel) ErrorMessage() binding.String {
 	m.ctrl.T.Helper()
@@ -114,17 +126,29 @@ func (mr *MockConnectionViewModelMockRecorder) ErrorMessage() *gomock.Call {
 }
 
 // ExportAsJSON mocks base method.
-func (m *MockConnectionViewModel) ExportAsJSON(writer io.Writer) error {
+func (m *MockConnectionViewModel) ExportAsJSON(writer io.Writer, options connection_deck.ExportOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExportAsJSON", writer)
+	ret := m.ctrl.Call(m, "ExportAsJSON", writer, options)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ExportAsJSON indicates an expected call of ExportAsJSON.
-func (mr *MockConnectionViewModelMockRecorder) ExportAsJSON(writer any) *gomock.Call {
+func (mr *MockConnectionViewModelMockRecorder) ExportAsJSON(writer, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAsJSON", reflect.TypeOf((*MockConnectionViewModel)(nil).ExportAsJSON), writer, options)
+}
+
+// Import mocks base method.
+func (m *MockConnectionViewModel) Import(preview *connection_deck.ImportPreview, merge bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Import", preview, merge)
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockConnectionViewModelMockRecorder) Import(preview, merge any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAsJSON", reflect.TypeOf((*MockConnectionViewModel)(nil).ExportAsJSON), writer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockConnectionViewModel)(nil).Import), preview, merge)
 }
 
 // InfoMessage mocks base method.
@@ -169,6 +193,18 @@ func (mr *MockConnectionViewModelMockRecorder) IsReadOnly() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReadOnly", reflect.TypeOf((*MockConnectionViewModel)(nil).IsReadOnly))
 }
 
+// ListBuckets mocks base method.
+func (m *MockConnectionViewModel) ListBuckets(conn *connection_deck.Connection, onResult func([]string, error)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListBuckets", conn, onResult)
+}
+
+// ListBuckets indicates an expected call of ListBuckets.
+func (mr *MockConnectionViewModelMockRecorder) ListBuckets(conn, onResult any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBuckets", reflect.TypeOf((*MockConnectionViewModel)(nil).ListBuckets), conn, onResult)
+}
+
 // Loading mocks base method.
 func (m *MockConnectionViewModel) Loading() binding.Bool {
 	m.ctrl.T.Helper()
@@ -183,6 +219,49 @@ func (mr *MockConnectionViewModelMockRecorder) Loading() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Loading", reflect.TypeOf((*MockConnectionViewModel)(nil).Loading))
 }
 
+// PrepareImport mocks base method.
+func (m *MockConnectionViewModel) PrepareImport(file io.Reader, options connection_deck.ImportOptions) (*connection_deck.ImportPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareImport", file, options)
+	ret0, _ := ret[0].(*connection_deck.ImportPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareImport indicates an expected call of PrepareImport.
+func (mr *MockConnectionViewModelMockRecorder) PrepareImport(file, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareImport", reflect.TypeOf((*MockConnectionViewModel)(nil).PrepareImport), file, options)
+}
+
+// ProtectSecrets mocks base method.
+func (m *MockConnectionViewModel) ProtectSecrets(passphrase string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtectSecrets", passphrase)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProtectSecrets indicates an expected call of ProtectSecrets.
+func (mr *MockConnectionViewModelMockRecorder) ProtectSecrets(passphrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtectSecrets", reflect.TypeOf((*MockConnectionViewModel)(nil).ProtectSecrets), passphrase)
+}
+
+// SecretsProtected mocks base method.
+func (m *MockConnectionViewModel) SecretsProtected() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecretsProtected")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SecretsProtected indicates an expected call of SecretsProtected.
+func (mr *MockConnectionViewModelMockRecorder) SecretsProtected() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecretsProtected", reflect.TypeOf((*MockConnectionViewModel)(nil).SecretsProtected))
+}
+
 // Select mocks base method.
 func (m *MockConnectionViewModel) Select(conn *connection_deck.Connection) {
 	m.ctrl.T.Helper()
@@ -195,6 +274,18 @@ func (mr *MockConnectionViewModelMockRecorder) Select(conn any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Select", reflect.TypeOf((*MockConnectionViewModel)(nil).Select), conn)
 }
 
+// SwitchBucket mocks base method.
+func (m *MockConnectionViewModel) SwitchBucket(conn *connection_deck.Connection, bucket string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SwitchBucket", conn, bucket)
+}
+
+// SwitchBucket indicates an expected call of SwitchBucket.
+func (mr *MockConnectionViewModelMockRecorder) SwitchBucket(conn, bucket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchBucket", reflect.TypeOf((*MockConnectionViewModel)(nil).SwitchBucket), conn, bucket)
+}
+
 // Update mocks base method.
 func (m *MockConnectionViewModel) Update(connID connection_deck.ConnectionID, options ...connection_deck.ConnectionOption) {
 	m.ctrl.T.Helper()
@@ -11,6 +11,7 @@ package mocks_viewmodel
 
 import (
 	reflect "reflect"
+	time "time"
 
 	fyne "fyne.io/fyne/v2"
 	binding "fyne.io/fyne/v2/data/binding"
@@ -70,6 +71,146 @@ func (mr *MockExplorerViewModelMockRecorder) AddStateListener(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStateListener", reflect.TypeOf((*MockExplorerViewModel)(nil).AddStateListener), arg0)
 }
 
+// ApplySync mocks base method.
+func (m *MockExplorerViewModel) ApplySync(plan *directory.SyncPlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplySync", plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplySync indicates an expected call of ApplySync.
+func (mr *MockExplorerViewModelMockRecorder) ApplySync(plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplySync", reflect.TypeOf((*MockExplorerViewModel)(nil).ApplySync), plan)
+}
+
+// CancelComputeDirectorySize mocks base method.
+func (m *MockExplorerViewModel) CancelComputeDirectorySize(dir *directory.Directory) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelComputeDirectorySize", dir)
+}
+
+// CancelComputeDirectorySize indicates an expected call of CancelComputeDirectorySize.
+func (mr *MockExplorerViewModelMockRecorder) CancelComputeDirectorySize(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelComputeDirectorySize", reflect.TypeOf((*MockExplorerViewModel)(nil).CancelComputeDirectorySize), dir)
+}
+
+// CancelDeleteDirectory mocks base method.
+func (m *MockExplorerViewModel) CancelDeleteDirectory(dir *directory.Directory) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelDeleteDirectory", dir)
+}
+
+// CancelDeleteDirectory indicates an expected call of CancelDeleteDirectory.
+func (mr *MockExplorerViewModelMockRecorder) CancelDeleteDirectory(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelDeleteDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).CancelDeleteDirectory), dir)
+}
+
+// CancelDownloadDirectory mocks base method.
+func (m *MockExplorerViewModel) CancelDownloadDirectory(dir *directory.Directory) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelDownloadDirectory", dir)
+}
+
+// CancelDownloadDirectory indicates an expected call of CancelDownloadDirectory.
+func (mr *MockExplorerViewModelMockRecorder) CancelDownloadDirectory(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelDownloadDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).CancelDownloadDirectory), dir)
+}
+
+// CancelSearch mocks base method.
+func (m *MockExplorerViewModel) CancelSearch() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelSearch")
+}
+
+// CancelSearch indicates an expected call of CancelSearch.
+func (mr *MockExplorerViewModelMockRecorder) CancelSearch() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSearch", reflect.TypeOf((*MockExplorerViewModel)(nil).CancelSearch))
+}
+
+// CancelSelectionOperation mocks base method.
+func (m *MockExplorerViewModel) CancelSelectionOperation() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelSelectionOperation")
+}
+
+// CancelSelectionOperation indicates an expected call of CancelSelectionOperation.
+func (mr *MockExplorerViewModelMockRecorder) CancelSelectionOperation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSelectionOperation", reflect.TypeOf((*MockExplorerViewModel)(nil).CancelSelectionOperation))
+}
+
+// CancelSync mocks base method.
+func (m *MockExplorerViewModel) CancelSync(dir *directory.Directory) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelSync", dir)
+}
+
+// CancelSync indicates an expected call of CancelSync.
+func (mr *MockExplorerViewModelMockRecorder) CancelSync(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSync", reflect.TypeOf((*MockExplorerViewModel)(nil).CancelSync), dir)
+}
+
+// ClearSelection mocks base method.
+func (m *MockExplorerViewModel) ClearSelection() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearSelection")
+}
+
+// ClearSelection indicates an expected call of ClearSelection.
+func (mr *MockExplorerViewModelMockRecorder) ClearSelection() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearSelection", reflect.TypeOf((*MockExplorerViewModel)(nil).ClearSelection))
+}
+
+// ComputeDirectorySize mocks base method.
+func (m *MockExplorerViewModel) ComputeDirectorySize(dir *directory.Directory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ComputeDirectorySize", dir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ComputeDirectorySize indicates an expected call of ComputeDirectorySize.
+func (mr *MockExplorerViewModelMockRecorder) ComputeDirectorySize(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ComputeDirectorySize", reflect.TypeOf((*MockExplorerViewModel)(nil).ComputeDirectorySize), dir)
+}
+
+// CopySelection mocks base method.
+func (m *MockExplorerViewModel) CopySelection(dst *directory.Directory, strategy directory.MaterializeStrategy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopySelection", dst, strategy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopySelection indicates an expected call of CopySelection.
+func (mr *MockExplorerViewModelMockRecorder) CopySelection(dst, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySelection", reflect.TypeOf((*MockExplorerViewModel)(nil).CopySelection), dst, strategy)
+}
+
+// CopyToClipboard mocks base method.
+func (m *MockExplorerViewModel) CopyToClipboard(nodeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyToClipboard", nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyToClipboard indicates an expected call of CopyToClipboard.
+func (mr *MockExplorerViewModelMockRecorder) CopyToClipboard(nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyToClipboard", reflect.TypeOf((*MockExplorerViewModel)(nil).CopyToClipboard), nodeID)
+}
+
 // CreateEmptyDirectory mocks base method.
 func (m *MockExplorerViewModel) CreateEmptyDirectory(parent *directory.Directory, name string) {
 	m.ctrl.T.Helper()
@@ -94,6 +235,20 @@ func (mr *MockExplorerViewModelMockRecorder) CreateEmptyFile(parent, name any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmptyFile", reflect.TypeOf((*MockExplorerViewModel)(nil).CreateEmptyFile), parent, name)
 }
 
+// CreateFileShareLink mocks base method.
+func (m *MockExplorerViewModel) CreateFileShareLink(file *directory.File, access directory.ShareLinkAccess, expiry time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFileShareLink", file, access, expiry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFileShareLink indicates an expected call of CreateFileShareLink.
+func (mr *MockExplorerViewModelMockRecorder) CreateFileShareLink(file, access, expiry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFileShareLink", reflect.TypeOf((*MockExplorerViewModel)(nil).CreateFileShareLink), file, access, expiry)
+}
+
 // CurrentSelectedConnection mocks base method.
 func (m *MockExplorerViewModel) CurrentSelectedConnection() *connection_deck.Connection {
 	m.ctrl.T.Helper()
@@ -108,6 +263,20 @@ func (mr *MockExplorerViewModelMockRecorder) CurrentSelectedConnection() *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentSelectedConnection", reflect.TypeOf((*MockExplorerViewModel)(nil).CurrentSelectedConnection))
 }
 
+// CutToClipboard mocks base method.
+func (m *MockExplorerViewModel) CutToClipboard(nodeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CutToClipboard", nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CutToClipboard indicates an expected call of CutToClipboard.
+func (mr *MockExplorerViewModelMockRecorder) CutToClipboard(nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CutToClipboard", reflect.TypeOf((*MockExplorerViewModel)(nil).CutToClipboard), nodeID)
+}
+
 // DeleteDirectory mocks base method.
 func (m *MockExplorerViewModel) DeleteDirectory(dir *directory.Directory) {
 	m.ctrl.T.Helper()
@@ -120,6 +289,20 @@ func (mr *MockExplorerViewModelMockRecorder) DeleteDirectory(dir any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).DeleteDirectory), dir)
 }
 
+// DeleteDirectoryRecursively mocks base method.
+func (m *MockExplorerViewModel) DeleteDirectoryRecursively(dir *directory.Directory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDirectoryRecursively", dir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDirectoryRecursively indicates an expected call of DeleteDirectoryRecursively.
+func (mr *MockExplorerViewModelMockRecorder) DeleteDirectoryRecursively(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectoryRecursively", reflect.TypeOf((*MockExplorerViewModel)(nil).DeleteDirectoryRecursively), dir)
+}
+
 // DeleteFile mocks base method.
 func (m *MockExplorerViewModel) DeleteFile(file *directory.File) {
 	m.ctrl.T.Helper()
@@ -132,6 +315,48 @@ func (mr *MockExplorerViewModelMockRecorder) DeleteFile(file any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockExplorerViewModel)(nil).DeleteFile), file)
 }
 
+// DeleteFileVersion mocks base method.
+func (m *MockExplorerViewModel) DeleteFileVersion(file *directory.File, version directory.FileVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFileVersion", file, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFileVersion indicates an expected call of DeleteFileVersion.
+func (mr *MockExplorerViewModelMockRecorder) DeleteFileVersion(file, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFileVersion", reflect.TypeOf((*MockExplorerViewModel)(nil).DeleteFileVersion), file, version)
+}
+
+// DeleteSelection mocks base method.
+func (m *MockExplorerViewModel) DeleteSelection() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSelection")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSelection indicates an expected call of DeleteSelection.
+func (mr *MockExplorerViewModelMockRecorder) DeleteSelection() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSelection", reflect.TypeOf((*MockExplorerViewModel)(nil).DeleteSelection))
+}
+
+// DeletionProgress mocks base method.
+func (m *MockExplorerViewModel) DeletionProgress() binding.String {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletionProgress")
+	ret0, _ := ret[0].(binding.String)
+	return ret0
+}
+
+// DeletionProgress indicates an expected call of DeletionProgress.
+func (mr *MockExplorerViewModelMockRecorder) DeletionProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletionProgress", reflect.TypeOf((*MockExplorerViewModel)(nil).DeletionProgress))
+}
+
 // DoUpload mocks base method.
 func (m *MockExplorerViewModel) DoUpload(localBasePath string, preview *directory.Preview, strategy directory.MaterializeStrategy) {
 	m.ctrl.T.Helper()
@@ -144,6 +369,20 @@ func (mr *MockExplorerViewModelMockRecorder) DoUpload(localBasePath, preview, st
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoUpload", reflect.TypeOf((*MockExplorerViewModel)(nil).DoUpload), localBasePath, preview, strategy)
 }
 
+// DownloadDirectory mocks base method.
+func (m *MockExplorerViewModel) DownloadDirectory(dir *directory.Directory, dest string, strategy directory.MaterializeStrategy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadDirectory", dir, dest, strategy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadDirectory indicates an expected call of DownloadDirectory.
+func (mr *MockExplorerViewModelMockRecorder) DownloadDirectory(dir, dest, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).DownloadDirectory), dir, dest, strategy)
+}
+
 // DownloadFile mocks base method.
 func (m *MockExplorerViewModel) DownloadFile(f *directory.File, dest string) {
 	m.ctrl.T.Helper()
@@ -156,6 +395,34 @@ func (mr *MockExplorerViewModelMockRecorder) DownloadFile(f, dest any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockExplorerViewModel)(nil).DownloadFile), f, dest)
 }
 
+// DownloadProgress mocks base method.
+func (m *MockExplorerViewModel) DownloadProgress() binding.String {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadProgress")
+	ret0, _ := ret[0].(binding.String)
+	return ret0
+}
+
+// DownloadProgress indicates an expected call of DownloadProgress.
+func (mr *MockExplorerViewModelMockRecorder) DownloadProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadProgress", reflect.TypeOf((*MockExplorerViewModel)(nil).DownloadProgress))
+}
+
+// DownloadSelection mocks base method.
+func (m *MockExplorerViewModel) DownloadSelection(dest string, strategy directory.MaterializeStrategy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadSelection", dest, strategy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadSelection indicates an expected call of DownloadSelection.
+func (mr *MockExplorerViewModelMockRecorder) DownloadSelection(dest, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadSelection", reflect.TypeOf((*MockExplorerViewModel)(nil).DownloadSelection), dest, strategy)
+}
+
 // ErrorMessage mocks base method.
 func (m *MockExplorerViewModel) ErrorMessage() binding.String {
 	m.ctrl.T.Helper()
@@ -170,6 +437,20 @@ func (mr *MockExplorerViewModelMockRecorder) ErrorMessage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErrorMessage", reflect.TypeOf((*MockExplorerViewModel)(nil).ErrorMessage))
 }
 
+// HasClipboardContent mocks base method.
+func (m *MockExplorerViewModel) HasClipboardContent() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasClipboardContent")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasClipboardContent indicates an expected call of HasClipboardContent.
+func (mr *MockExplorerViewModelMockRecorder) HasClipboardContent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasClipboardContent", reflect.TypeOf((*MockExplorerViewModel)(nil).HasClipboardContent))
+}
+
 // InfoMessage mocks base method.
 func (m *MockExplorerViewModel) InfoMessage() binding.String {
 	m.ctrl.T.Helper()
@@ -198,6 +479,20 @@ func (mr *MockExplorerViewModelMockRecorder) IsLoading() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoading", reflect.TypeOf((*MockExplorerViewModel)(nil).IsLoading))
 }
 
+// IsSearching mocks base method.
+func (m *MockExplorerViewModel) IsSearching() binding.Bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSearching")
+	ret0, _ := ret[0].(binding.Bool)
+	return ret0
+}
+
+// IsSearching indicates an expected call of IsSearching.
+func (mr *MockExplorerViewModelMockRecorder) IsSearching() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSearching", reflect.TypeOf((*MockExplorerViewModel)(nil).IsSearching))
+}
+
 // IsSelectedDirectoryLoading mocks base method.
 func (m *MockExplorerViewModel) IsSelectedDirectoryLoading() binding.Bool {
 	m.ctrl.T.Helper()
@@ -254,6 +549,56 @@ func (mr *MockExplorerViewModelMockRecorder) LoadDirectory(dir any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).LoadDirectory), dir)
 }
 
+// LoadFileMetadata mocks base method.
+func (m *MockExplorerViewModel) LoadFileMetadata(file *directory.File) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LoadFileMetadata", file)
+}
+
+// LoadFileMetadata indicates an expected call of LoadFileMetadata.
+func (mr *MockExplorerViewModelMockRecorder) LoadFileMetadata(file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFileMetadata", reflect.TypeOf((*MockExplorerViewModel)(nil).LoadFileMetadata), file)
+}
+
+// LoadFileTags mocks base method.
+func (m *MockExplorerViewModel) LoadFileTags(file *directory.File) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LoadFileTags", file)
+}
+
+// LoadFileTags indicates an expected call of LoadFileTags.
+func (mr *MockExplorerViewModelMockRecorder) LoadFileTags(file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFileTags", reflect.TypeOf((*MockExplorerViewModel)(nil).LoadFileTags), file)
+}
+
+// LoadFileVersions mocks base method.
+func (m *MockExplorerViewModel) LoadFileVersions(file *directory.File) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "LoadFileVersions", file)
+}
+
+// LoadFileVersions indicates an expected call of LoadFileVersions.
+func (mr *MockExplorerViewModelMockRecorder) LoadFileVersions(file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadFileVersions", reflect.TypeOf((*MockExplorerViewModel)(nil).LoadFileVersions), file)
+}
+
+// LoadNextPage mocks base method.
+func (m *MockExplorerViewModel) LoadNextPage(dir *directory.Directory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadNextPage", dir)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadNextPage indicates an expected call of LoadNextPage.
+func (mr *MockExplorerViewModelMockRecorder) LoadNextPage(dir any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadNextPage", reflect.TypeOf((*MockExplorerViewModel)(nil).LoadNextPage), dir)
+}
+
 // Loading mocks base method.
 func (m *MockExplorerViewModel) Loading() binding.Bool {
 	m.ctrl.T.Helper()
@@ -268,6 +613,56 @@ func (mr *MockExplorerViewModelMockRecorder) Loading() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Loading", reflect.TypeOf((*MockExplorerViewModel)(nil).Loading))
 }
 
+// MoveSelection mocks base method.
+func (m *MockExplorerViewModel) MoveSelection(dst *directory.Directory, strategy directory.MaterializeStrategy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveSelection", dst, strategy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveSelection indicates an expected call of MoveSelection.
+func (mr *MockExplorerViewModelMockRecorder) MoveSelection(dst, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveSelection", reflect.TypeOf((*MockExplorerViewModel)(nil).MoveSelection), dst, strategy)
+}
+
+// OnPasteReady mocks base method.
+func (m *MockExplorerViewModel) OnPasteReady(arg0 func(viewmodel.PastePreviewState)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnPasteReady", arg0)
+}
+
+// OnPasteReady indicates an expected call of OnPasteReady.
+func (mr *MockExplorerViewModelMockRecorder) OnPasteReady(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnPasteReady", reflect.TypeOf((*MockExplorerViewModel)(nil).OnPasteReady), arg0)
+}
+
+// OnReveal mocks base method.
+func (m *MockExplorerViewModel) OnReveal(arg0 func(string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnReveal", arg0)
+}
+
+// OnReveal indicates an expected call of OnReveal.
+func (mr *MockExplorerViewModelMockRecorder) OnReveal(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnReveal", reflect.TypeOf((*MockExplorerViewModel)(nil).OnReveal), arg0)
+}
+
+// OnSyncReady mocks base method.
+func (m *MockExplorerViewModel) OnSyncReady(arg0 func(*directory.SyncPlan)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnSyncReady", arg0)
+}
+
+// OnSyncReady indicates an expected call of OnSyncReady.
+func (mr *MockExplorerViewModelMockRecorder) OnSyncReady(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnSyncReady", reflect.TypeOf((*MockExplorerViewModel)(nil).OnSyncReady), arg0)
+}
+
 // OnUploadReady mocks base method.
 func (m *MockExplorerViewModel) OnUploadReady(arg0 func(viewmodel.UploadPreviewState)) {
 	m.ctrl.T.Helper()
@@ -280,6 +675,20 @@ func (mr *MockExplorerViewModelMockRecorder) OnUploadReady(arg0 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnUploadReady", reflect.TypeOf((*MockExplorerViewModel)(nil).OnUploadReady), arg0)
 }
 
+// Paste mocks base method.
+func (m *MockExplorerViewModel) Paste(previewState viewmodel.PastePreviewState, strategy directory.MaterializeStrategy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Paste", previewState, strategy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Paste indicates an expected call of Paste.
+func (mr *MockExplorerViewModelMockRecorder) Paste(previewState, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Paste", reflect.TypeOf((*MockExplorerViewModel)(nil).Paste), previewState, strategy)
+}
+
 // PendingUserValidations mocks base method.
 func (m *MockExplorerViewModel) PendingUserValidations() <-chan directory.UserValidationAsked {
 	m.ctrl.T.Helper()
@@ -294,6 +703,34 @@ func (mr *MockExplorerViewModelMockRecorder) PendingUserValidations() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingUserValidations", reflect.TypeOf((*MockExplorerViewModel)(nil).PendingUserValidations))
 }
 
+// PreparePaste mocks base method.
+func (m *MockExplorerViewModel) PreparePaste(dst *directory.Directory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreparePaste", dst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreparePaste indicates an expected call of PreparePaste.
+func (mr *MockExplorerViewModelMockRecorder) PreparePaste(dst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreparePaste", reflect.TypeOf((*MockExplorerViewModel)(nil).PreparePaste), dst)
+}
+
+// PrepareSync mocks base method.
+func (m *MockExplorerViewModel) PrepareSync(dir *directory.Directory, localPath string, opts directory.SyncOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareSync", dir, localPath, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrepareSync indicates an expected call of PrepareSync.
+func (mr *MockExplorerViewModelMockRecorder) PrepareSync(dir, localPath, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareSync", reflect.TypeOf((*MockExplorerViewModel)(nil).PrepareSync), dir, localPath, opts)
+}
+
 // PrepareUpload mocks base method.
 func (m *MockExplorerViewModel) PrepareUpload(uris []fyne.URI, dir *directory.Directory) error {
 	m.ctrl.T.Helper()
@@ -346,6 +783,20 @@ func (mr *MockExplorerViewModelMockRecorder) RenameFile(file, newName any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameFile", reflect.TypeOf((*MockExplorerViewModel)(nil).RenameFile), file, newName)
 }
 
+// RestoreFileVersion mocks base method.
+func (m *MockExplorerViewModel) RestoreFileVersion(file *directory.File, version directory.FileVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFileVersion", file, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreFileVersion indicates an expected call of RestoreFileVersion.
+func (mr *MockExplorerViewModelMockRecorder) RestoreFileVersion(file, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFileVersion", reflect.TypeOf((*MockExplorerViewModel)(nil).RestoreFileVersion), file, version)
+}
+
 // ResumeRename mocks base method.
 func (m *MockExplorerViewModel) ResumeRename(dir *directory.Directory) error {
 	m.ctrl.T.Helper()
@@ -360,6 +811,20 @@ func (mr *MockExplorerViewModelMockRecorder) ResumeRename(dir any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeRename", reflect.TypeOf((*MockExplorerViewModel)(nil).ResumeRename), dir)
 }
 
+// RevealSearchHit mocks base method.
+func (m *MockExplorerViewModel) RevealSearchHit(hit directory.SearchHit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevealSearchHit", hit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevealSearchHit indicates an expected call of RevealSearchHit.
+func (mr *MockExplorerViewModelMockRecorder) RevealSearchHit(hit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevealSearchHit", reflect.TypeOf((*MockExplorerViewModel)(nil).RevealSearchHit), hit)
+}
+
 // RollbackRename mocks base method.
 func (m *MockExplorerViewModel) RollbackRename(dir *directory.Directory) error {
 	m.ctrl.T.Helper()
@@ -374,6 +839,62 @@ func (mr *MockExplorerViewModelMockRecorder) RollbackRename(dir any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackRename", reflect.TypeOf((*MockExplorerViewModel)(nil).RollbackRename), dir)
 }
 
+// Search mocks base method.
+func (m *MockExplorerViewModel) Search(dir *directory.Directory, query directory.SearchQuery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", dir, query)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockExplorerViewModelMockRecorder) Search(dir, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockExplorerViewModel)(nil).Search), dir, query)
+}
+
+// SearchProgress mocks base method.
+func (m *MockExplorerViewModel) SearchProgress() binding.String {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProgress")
+	ret0, _ := ret[0].(binding.String)
+	return ret0
+}
+
+// SearchProgress indicates an expected call of SearchProgress.
+func (mr *MockExplorerViewModelMockRecorder) SearchProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProgress", reflect.TypeOf((*MockExplorerViewModel)(nil).SearchProgress))
+}
+
+// SearchResults mocks base method.
+func (m *MockExplorerViewModel) SearchResults() binding.List[directory.SearchHit] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchResults")
+	ret0, _ := ret[0].(binding.List[directory.SearchHit])
+	return ret0
+}
+
+// SearchResults indicates an expected call of SearchResults.
+func (mr *MockExplorerViewModelMockRecorder) SearchResults() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchResults", reflect.TypeOf((*MockExplorerViewModel)(nil).SearchResults))
+}
+
+// SelectRange mocks base method.
+func (m *MockExplorerViewModel) SelectRange(nodeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectRange", nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SelectRange indicates an expected call of SelectRange.
+func (mr *MockExplorerViewModelMockRecorder) SelectRange(nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectRange", reflect.TypeOf((*MockExplorerViewModel)(nil).SelectRange), nodeID)
+}
+
 // SelectedConnection mocks base method.
 func (m *MockExplorerViewModel) SelectedConnection() binding.Item[*connection_deck.Connection] {
 	m.ctrl.T.Helper()
@@ -402,6 +923,48 @@ func (mr *MockExplorerViewModelMockRecorder) SelectedDirectory() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectedDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).SelectedDirectory))
 }
 
+// Selection mocks base method.
+func (m *MockExplorerViewModel) Selection() *directory.Selection {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Selection")
+	ret0, _ := ret[0].(*directory.Selection)
+	return ret0
+}
+
+// Selection indicates an expected call of Selection.
+func (mr *MockExplorerViewModelMockRecorder) Selection() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Selection", reflect.TypeOf((*MockExplorerViewModel)(nil).Selection))
+}
+
+// SelectionProgress mocks base method.
+func (m *MockExplorerViewModel) SelectionProgress() binding.String {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectionProgress")
+	ret0, _ := ret[0].(binding.String)
+	return ret0
+}
+
+// SelectionProgress indicates an expected call of SelectionProgress.
+func (mr *MockExplorerViewModelMockRecorder) SelectionProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectionProgress", reflect.TypeOf((*MockExplorerViewModel)(nil).SelectionProgress))
+}
+
+// SelectionSize mocks base method.
+func (m *MockExplorerViewModel) SelectionSize() binding.Int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectionSize")
+	ret0, _ := ret[0].(binding.Int)
+	return ret0
+}
+
+// SelectionSize indicates an expected call of SelectionSize.
+func (mr *MockExplorerViewModelMockRecorder) SelectionSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectionSize", reflect.TypeOf((*MockExplorerViewModel)(nil).SelectionSize))
+}
+
 // SetSelectedDirectory mocks base method.
 func (m *MockExplorerViewModel) SetSelectedDirectory(dir *directory.Directory) {
 	m.ctrl.T.Helper()
@@ -414,6 +977,90 @@ func (mr *MockExplorerViewModelMockRecorder) SetSelectedDirectory(dir any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSelectedDirectory", reflect.TypeOf((*MockExplorerViewModel)(nil).SetSelectedDirectory), dir)
 }
 
+// ShowDeletedFiles mocks base method.
+func (m *MockExplorerViewModel) ShowDeletedFiles() binding.Bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowDeletedFiles")
+	ret0, _ := ret[0].(binding.Bool)
+	return ret0
+}
+
+// ShowDeletedFiles indicates an expected call of ShowDeletedFiles.
+func (mr *MockExplorerViewModelMockRecorder) ShowDeletedFiles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowDeletedFiles", reflect.TypeOf((*MockExplorerViewModel)(nil).ShowDeletedFiles))
+}
+
+// SizeProgress mocks base method.
+func (m *MockExplorerViewModel) SizeProgress() binding.String {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SizeProgress")
+	ret0, _ := ret[0].(binding.String)
+	return ret0
+}
+
+// SizeProgress indicates an expected call of SizeProgress.
+func (mr *MockExplorerViewModelMockRecorder) SizeProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SizeProgress", reflect.TypeOf((*MockExplorerViewModel)(nil).SizeProgress))
+}
+
+// SyncProgress mocks base method.
+func (m *MockExplorerViewModel) SyncProgress() binding.String {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncProgress")
+	ret0, _ := ret[0].(binding.String)
+	return ret0
+}
+
+// SyncProgress indicates an expected call of SyncProgress.
+func (mr *MockExplorerViewModelMockRecorder) SyncProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncProgress", reflect.TypeOf((*MockExplorerViewModel)(nil).SyncProgress))
+}
+
+// ToggleSelection mocks base method.
+func (m *MockExplorerViewModel) ToggleSelection(nodeID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToggleSelection", nodeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ToggleSelection indicates an expected call of ToggleSelection.
+func (mr *MockExplorerViewModelMockRecorder) ToggleSelection(nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleSelection", reflect.TypeOf((*MockExplorerViewModel)(nil).ToggleSelection), nodeID)
+}
+
+// UpdateFileMetadata mocks base method.
+func (m *MockExplorerViewModel) UpdateFileMetadata(file *directory.File, metadata directory.ObjectMetadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileMetadata", file, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileMetadata indicates an expected call of UpdateFileMetadata.
+func (mr *MockExplorerViewModelMockRecorder) UpdateFileMetadata(file, metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileMetadata", reflect.TypeOf((*MockExplorerViewModel)(nil).UpdateFileMetadata), file, metadata)
+}
+
+// UpdateFileTags mocks base method.
+func (m *MockExplorerViewModel) UpdateFileTags(file *directory.File, tags directory.ObjectTags) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFileTags", file, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFileTags indicates an expected call of UpdateFileTags.
+func (mr *MockExplorerViewModelMockRecorder) UpdateFileTags(file, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFileTags", reflect.TypeOf((*MockExplorerViewModel)(nil).UpdateFileTags), file, tags)
+}
+
 // UpdateLastDownloadLocation mocks base method.
 func (m *MockExplorerViewModel) UpdateLastDownloadLocation(filePath string) error {
 	m.ctrl.T.Helper()
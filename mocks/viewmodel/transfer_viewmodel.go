@@ -0,0 +1,175 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/thomas-marquis/s3-box/internal/ui/viewmodel (interfaces: TransferViewModel)
+//
+// Generated by this command:
+//
+//	mockgen -package mocks_viewmodel -destination mocks/viewmodel/transfer_viewmodel.go github.com/thomas-marquis/s3-box/internal/ui/viewmodel TransferViewModel
+//
+
+// Package mocks_viewmodel is a generated GoMock package.
+package mocks_viewmodel
+
+import (
+	reflect "reflect"
+
+	binding "fyne.io/fyne/v2/data/binding"
+	connection_deck "github.com/thomas-marquis/s3-box/internal/domain/connection_deck"
+	transfer "github.com/thomas-marquis/s3-box/internal/domain/transfer"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransferViewModel is a mock of TransferViewModel interface.
+type MockTransferViewModel struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferViewModelMockRecorder
+	isgomock struct{}
+}
+
+// MockTransferViewModelMockRecorder is the mock recorder for MockTransferViewModel.
+type MockTransferViewModelMockRecorder struct {
+	mock *MockTransferViewModel
+}
+
+// NewMockTransferViewModel creates a new mock instance.
+func NewMockTransferViewModel(ctrl *gomock.Controller) *MockTransferViewModel {
+	mock := &MockTransferViewModel{ctrl: ctrl}
+	mock.recorder = &MockTransferViewModelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferViewModel) EXPECT() *MockTransferViewModelMockRecorder {
+	return m.recorder
+}
+
+// AbortIncompleteUploads mocks base method.
+func (m *MockTransferViewModel) AbortIncompleteUploads(conn *connection_deck.Connection, uploads []transfer.IncompleteUpload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortIncompleteUploads", conn, uploads)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortIncompleteUploads indicates an expected call of AbortIncompleteUploads.
+func (mr *MockTransferViewModelMockRecorder) AbortIncompleteUploads(conn, uploads any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortIncompleteUploads", reflect.TypeOf((*MockTransferViewModel)(nil).AbortIncompleteUploads), conn, uploads)
+}
+
+// Cancel mocks base method.
+func (m *MockTransferViewModel) Cancel(id transfer.JobID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Cancel", id)
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockTransferViewModelMockRecorder) Cancel(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockTransferViewModel)(nil).Cancel), id)
+}
+
+// ClearFinished mocks base method.
+func (m *MockTransferViewModel) ClearFinished() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearFinished")
+}
+
+// ClearFinished indicates an expected call of ClearFinished.
+func (mr *MockTransferViewModelMockRecorder) ClearFinished() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearFinished", reflect.TypeOf((*MockTransferViewModel)(nil).ClearFinished))
+}
+
+// IncompleteUploads mocks base method.
+func (m *MockTransferViewModel) IncompleteUploads() binding.List[transfer.IncompleteUpload] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncompleteUploads")
+	ret0, _ := ret[0].(binding.List[transfer.IncompleteUpload])
+	return ret0
+}
+
+// IncompleteUploads indicates an expected call of IncompleteUploads.
+func (mr *MockTransferViewModelMockRecorder) IncompleteUploads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncompleteUploads", reflect.TypeOf((*MockTransferViewModel)(nil).IncompleteUploads))
+}
+
+// InterruptedUploads mocks base method.
+func (m *MockTransferViewModel) InterruptedUploads() []transfer.Job {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InterruptedUploads")
+	ret0, _ := ret[0].([]transfer.Job)
+	return ret0
+}
+
+// InterruptedUploads indicates an expected call of InterruptedUploads.
+func (mr *MockTransferViewModelMockRecorder) InterruptedUploads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InterruptedUploads", reflect.TypeOf((*MockTransferViewModel)(nil).InterruptedUploads))
+}
+
+// IsLoadingIncompleteUploads mocks base method.
+func (m *MockTransferViewModel) IsLoadingIncompleteUploads() binding.Bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLoadingIncompleteUploads")
+	ret0, _ := ret[0].(binding.Bool)
+	return ret0
+}
+
+// IsLoadingIncompleteUploads indicates an expected call of IsLoadingIncompleteUploads.
+func (mr *MockTransferViewModelMockRecorder) IsLoadingIncompleteUploads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoadingIncompleteUploads", reflect.TypeOf((*MockTransferViewModel)(nil).IsLoadingIncompleteUploads))
+}
+
+// LoadIncompleteUploads mocks base method.
+func (m *MockTransferViewModel) LoadIncompleteUploads(conn *connection_deck.Connection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadIncompleteUploads", conn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadIncompleteUploads indicates an expected call of LoadIncompleteUploads.
+func (mr *MockTransferViewModelMockRecorder) LoadIncompleteUploads(conn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadIncompleteUploads", reflect.TypeOf((*MockTransferViewModel)(nil).LoadIncompleteUploads), conn)
+}
+
+// Pause mocks base method.
+func (m *MockTransferViewModel) Pause(id transfer.JobID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Pause", id)
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockTransferViewModelMockRecorder) Pause(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockTransferViewModel)(nil).Pause), id)
+}
+
+// Resume mocks base method.
+func (m *MockTransferViewModel) Resume(id transfer.JobID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Resume", id)
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockTransferViewModelMockRecorder) Resume(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockTransferViewModel)(nil).Resume), id)
+}
+
+// Transfers mocks base method.
+func (m *MockTransferViewModel) Transfers() binding.List[transfer.Job] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfers")
+	ret0, _ := ret[0].(binding.List[transfer.Job])
+	return ret0
+}
+
+// Transfers indicates an expected call of Transfers.
+func (mr *MockTransferViewModelMockRecorder) Transfers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfers", reflect.TypeOf((*MockTransferViewModel)(nil).Transfers))
+}